@@ -0,0 +1,155 @@
+// Command montecarlo runs a Monte Carlo study on headless simulators and
+// writes its report as JSON.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/airspace"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/api/validation"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/config"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/montecarlo"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/observability"
+)
+
+func main() {
+	// Parse command line flags
+	configPath := flag.String("config", "configs/config.yaml", "Path to configuration file")
+	studyPath := flag.String("study", "", "Path to the study file (required)")
+	runs := flag.Int("runs", 0, "Number of runs, overrides the study")
+	seed := flag.Int64("seed", 0, "Random seed, overrides the study")
+	workers := flag.Int("workers", 0, "Parallel runs, overrides the study (default: number of CPUs)")
+	outputPath := flag.String("output", "", "Write the JSON report to this file instead of stdout")
+	flag.Parse()
+
+	if *studyPath == "" {
+		fmt.Fprintln(os.Stderr, "Usage: montecarlo -study <file> [-config <file>] [-runs N] [-seed N] [-workers N] [-output <file>]")
+		os.Exit(2)
+	}
+
+	// Load configuration
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+	logger := observability.NewLogger(cfg.Logging)
+
+	study, err := montecarlo.Load(*studyPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load study: %v\n", err)
+		os.Exit(1)
+	}
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "runs":
+			study.Runs = *runs
+		case "seed":
+			study.Seed = *seed
+		case "workers":
+			study.Workers = *workers
+		}
+	})
+	if err := study.Validate(cfg.Simulation, cfg.Environment); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	base := montecarlo.Base{Simulation: cfg.Simulation, Environment: cfg.Environment}
+	if dir := cfg.Simulation.Geofence.AirspaceDir; dir != "" && cfg.Simulation.Geofence.Enabled {
+		base.Geofences = loadAirspace(dir, logger)
+	}
+
+	// Stop on interrupt
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger.Info("Monte Carlo study started",
+		"scenario", study.Scenario.Name,
+		"runs", study.Runs,
+		"seed", study.Seed,
+	)
+	report, err := montecarlo.Run(ctx, study, base, func(completed int) {
+		if completed%max(study.Runs/10, 1) == 0 {
+			logger.Info("Monte Carlo progress", "completed", completed, "runs", study.Runs)
+		}
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Study stopped: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := writeReport(report, *outputPath); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write report: %v\n", err)
+		os.Exit(1)
+	}
+	printSummary(os.Stderr, report)
+}
+
+// loadAirspace loads the geofences of the airspace directory.
+func loadAirspace(dir string, logger *slog.Logger) []models.Geofence {
+	result, err := airspace.LoadDir(dir)
+	if err != nil {
+		logger.Error("Failed to load airspace", "dir", dir, "error", err)
+		return nil
+	}
+
+	var zones []models.Geofence
+	for _, zone := range result.Zones {
+		if err := validation.ValidateGeofence(&zone); err != nil {
+			logger.Warn("Skipping invalid airspace", "name", zone.Name, "source", zone.Source, "error", err)
+			continue
+		}
+		zones = append(zones, zone)
+	}
+	logger.Info("Airspace loaded", "dir", dir, "zones", len(zones))
+	return zones
+}
+
+// writeReport writes the report as JSON to path, or to stdout.
+func writeReport(report *models.StudyReport, path string) error {
+	out := io.Writer(os.Stdout)
+	if path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// printSummary prints the main statistics of a report.
+func printSummary(w io.Writer, report *models.StudyReport) {
+	fmt.Fprintf(w, "Scenario %q: %d runs in %.1f s\n", report.Scenario, report.Runs, report.WallTime)
+	for _, state := range []models.ScenarioState{models.ScenarioPassed, models.ScenarioFailed, models.ScenarioError} {
+		fmt.Fprintf(w, "  %-8s %d\n", state, report.Outcomes[state])
+	}
+	fmt.Fprintf(w, "  arrived  %d\n", report.Arrived)
+	printDistribution(w, "arrival time (s)", report.ArrivalTime)
+	printDistribution(w, "max cross-track (m)", report.CrossTrack)
+	printDistribution(w, "fuel at landing (kg)", report.FuelAtLanding)
+	fmt.Fprintf(w, "  terrain violations:  %d in %d runs\n", report.TerrainViolations.Total, report.TerrainViolations.Runs)
+	fmt.Fprintf(w, "  geofence violations: %d in %d runs\n", report.GeofenceViolations.Total, report.GeofenceViolations.Runs)
+}
+
+// printDistribution prints one distribution line.
+func printDistribution(w io.Writer, name string, d *models.Distribution) {
+	if d == nil {
+		return
+	}
+	fmt.Fprintf(w, "  %-20s p5 %.1f  p50 %.1f  p95 %.1f  p99 %.1f  max %.1f\n", name+":", d.P5, d.P50, d.P95, d.P99, d.Max)
+}
@@ -4,16 +4,27 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/signal"
 	"sync"
 	"syscall"
 	"time"
 
+	"github.com/meiron-tzhori/Flight-Simulator/internal/airspace"
 	"github.com/meiron-tzhori/Flight-Simulator/internal/api"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/api/validation"
 	"github.com/meiron-tzhori/Flight-Simulator/internal/config"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/grpcapi"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/montecarlo"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/mqttapi"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/navdata"
 	"github.com/meiron-tzhori/Flight-Simulator/internal/observability"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/scenario"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/session"
 	"github.com/meiron-tzhori/Flight-Simulator/internal/simulator"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/snapshot"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/webhook"
 )
 
 var (
@@ -24,6 +35,8 @@ func main() {
 	// Parse command line flags
 	configPath := flag.String("config", "configs/config.yaml", "Path to configuration file")
 	showVersion := flag.Bool("version", false, "Show version and exit")
+	replayPath := flag.String("replay", "", "Replay a session log instead of running a live simulation")
+	replaySpeed := flag.Float64("replay-speed", 1, "Replay speed factor (1 = real time)")
 	flag.Parse()
 
 	if *showVersion {
@@ -50,13 +63,48 @@ func main() {
 	defer cancel()
 
 	// Initialize components
-	sim, err := simulator.New(cfg.Simulation, cfg.Environment, logger)
-	if err != nil {
-		logger.Error("Failed to create simulator", "error", err)
-		os.Exit(1)
+	var sim *simulator.Simulator
+	var sessionLog *session.Writer
+	if *replayPath != "" {
+		sim, err = newReplay(*replayPath, *replaySpeed, logger)
+		if err != nil {
+			logger.Error("Failed to load session replay", "error", err)
+			os.Exit(1)
+		}
+	} else {
+		sim, err = simulator.New(cfg.Simulation, cfg.Environment, logger)
+		if err != nil {
+			logger.Error("Failed to create simulator", "error", err)
+			os.Exit(1)
+		}
+
+		// Load airspace definitions
+		if dir := cfg.Simulation.Geofence.AirspaceDir; dir != "" && sim.GetGeofences() != nil {
+			loadAirspace(dir, sim, logger)
+		}
+
+		// Record the session for replay
+		if cfg.Session.Enabled {
+			sessionLog = startSession(cfg.Session.Dir, sim, logger)
+		}
 	}
 
-	server := api.NewServer(cfg.Server, cfg.Simulation, sim, logger)
+	// Load navigation database
+	nav := loadNavdata(cfg.Navdata.Files, logger)
+
+	// Load saved state snapshots
+	snapshots := loadSnapshots(cfg.Snapshots.Dir, logger)
+
+	// Scenarios drive the simulator through its API
+	scenarios := scenario.NewManager(sim, cfg.Simulation, logger)
+
+	// Monte Carlo studies run on headless simulators with the live geofences
+	studies := montecarlo.NewManager(cfg.Simulation, cfg.Environment, sim.GetGeofences(), logger)
+
+	// Webhooks and command callbacks get the simulator's events
+	webhooks := webhook.NewManager(cfg.Webhooks, sim, logger)
+
+	server := api.NewServer(cfg.Server, cfg.Simulation, cfg.Streaming, sim, nav, snapshots, scenarios, studies, webhooks, logger)
 
 	// Start components
 	var wg sync.WaitGroup
@@ -70,6 +118,15 @@ func main() {
 		}
 	}()
 
+	// Start webhook deliveries
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := webhooks.Run(ctx); err != nil {
+			logger.Error("Webhook error", "error", err)
+		}
+	}()
+
 	// Start HTTP server
 	wg.Add(1)
 	go func() {
@@ -79,8 +136,36 @@ func main() {
 		}
 	}()
 
+	// Start gRPC server
+	if cfg.GRPC.Enabled {
+		grpcServer := grpcapi.NewServer(cfg.GRPC, cfg.Simulation, cfg.Streaming, sim, nav, webhooks, logger)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := grpcServer.Start(ctx); err != nil {
+				logger.Error("gRPC server error", "error", err)
+			}
+		}()
+	}
+
+	// Start MQTT broker
+	if cfg.MQTT.Enabled {
+		mqttServer := mqttapi.NewServer(cfg.MQTT, cfg.Simulation, cfg.Streaming, sim, nav, webhooks, logger)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := mqttServer.Start(ctx); err != nil {
+				logger.Error("MQTT broker error", "error", err)
+			}
+		}()
+	}
+
 	logger.Info("Flight Simulator is running",
 		"http_port", cfg.Server.Port,
+		"grpc_enabled", cfg.GRPC.Enabled,
+		"grpc_port", cfg.GRPC.Port,
+		"mqtt_enabled", cfg.MQTT.Enabled,
+		"mqtt_port", cfg.MQTT.Port,
 		"tick_rate_hz", cfg.Simulation.TickRateHz,
 	)
 
@@ -107,4 +192,92 @@ func main() {
 	case <-time.After(30 * time.Second):
 		logger.Error("Shutdown timeout exceeded, forcing exit")
 	}
+
+	if err := sessionLog.Close(); err != nil {
+		logger.Error("Failed to write session log", "path", sessionLog.Path(), "error", err)
+	}
+}
+
+// newReplay creates a simulator that replays the session log at path.
+func newReplay(path string, speed float64, logger *slog.Logger) (*simulator.Simulator, error) {
+	if speed <= 0 || speed > simulator.MaxReplaySpeed {
+		return nil, fmt.Errorf("replay speed must be greater than 0 and at most %g", simulator.MaxReplaySpeed)
+	}
+
+	log, err := session.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return simulator.NewReplay(log, speed, logger)
+}
+
+// startSession creates a session log in dir and starts recording to it. On
+// failure the simulator runs without a session log.
+func startSession(dir string, sim *simulator.Simulator, logger *slog.Logger) *session.Writer {
+	sessionLog, err := session.Create(dir, sim.SessionHeader())
+	if err != nil {
+		logger.Error("Failed to create session log", "dir", dir, "error", err)
+		return nil
+	}
+
+	sim.RecordSession(sessionLog)
+	logger.Info("Recording session", "path", sessionLog.Path())
+	return sessionLog
+}
+
+// loadAirspace imports the airspace files in dir into the simulator's geofences.
+func loadAirspace(dir string, sim *simulator.Simulator, logger *slog.Logger) {
+	result, err := airspace.LoadDir(dir)
+	if err != nil {
+		logger.Error("Failed to load airspace", "dir", dir, "error", err)
+		return
+	}
+
+	imported := 0
+	for _, zone := range result.Zones {
+		if err := validation.ValidateGeofence(&zone); err != nil {
+			logger.Warn("Skipping invalid airspace", "name", zone.Name, "source", zone.Source, "error", err)
+			continue
+		}
+		sim.GetGeofences().Add(zone)
+		imported++
+	}
+	for _, warning := range result.Warnings {
+		logger.Warn("Airspace import warning", "warning", warning)
+	}
+
+	logger.Info("Airspace loaded", "dir", dir, "zones", imported)
+}
+
+// loadNavdata loads the navigation database files. On failure the simulator
+// runs with an empty database, so fix references are rejected.
+func loadNavdata(files []string, logger *slog.Logger) *navdata.Database {
+	if len(files) == 0 {
+		return navdata.NewDatabase()
+	}
+
+	nav, err := navdata.Load(files...)
+	if err != nil {
+		logger.Error("Failed to load navdata", "error", err)
+		return navdata.NewDatabase()
+	}
+
+	logger.Info("Navdata loaded", "files", len(files), "fixes", nav.Count())
+	return nav
+}
+
+// loadSnapshots opens the snapshot store. On failure snapshots are kept in
+// memory only.
+func loadSnapshots(dir string, logger *slog.Logger) *snapshot.Store {
+	snapshots, err := snapshot.NewStore(dir)
+	if err != nil {
+		logger.Error("Failed to load snapshots", "dir", dir, "error", err)
+		snapshots, _ = snapshot.NewStore("")
+		return snapshots
+	}
+
+	if dir != "" {
+		logger.Info("Snapshots loaded", "dir", dir, "snapshots", snapshots.Count())
+	}
+	return snapshots
 }
@@ -0,0 +1,158 @@
+// Package airspace imports airspace definitions from OpenAir and GeoJSON
+// files into the simulator's geofence model.
+package airspace
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/pkg/geo"
+)
+
+// Format identifies an airspace file format.
+type Format string
+
+const (
+	FormatOpenAir Format = "openair"
+	FormatGeoJSON Format = "geojson"
+)
+
+// Result holds the zones parsed from airspace files and any non-fatal warnings.
+type Result struct {
+	Zones    []models.Geofence `json:"zones"`
+	Warnings []string          `json:"warnings,omitempty"`
+}
+
+// exclusionClasses are the airspace classes the aircraft must stay out of.
+// All other classes are imported as advisory zones.
+var exclusionClasses = map[string]bool{
+	"R":          true, // restricted
+	"P":          true, // prohibited
+	"D":          true, // danger
+	"Q":          true, // danger (alternate)
+	"TRA":        true, // temporary reserved
+	"TSA":        true, // temporary segregated
+	"RESTRICTED": true,
+	"PROHIBITED": true,
+	"DANGER":     true,
+}
+
+// Parse parses airspace data in the given format.
+func Parse(data []byte, format Format, source string) (*Result, error) {
+	switch format {
+	case FormatOpenAir:
+		return ParseOpenAir(bytes.NewReader(data), source)
+	case FormatGeoJSON:
+		return ParseGeoJSON(data, source)
+	default:
+		return nil, fmt.Errorf("unsupported airspace format %q", format)
+	}
+}
+
+// DetectFormat guesses the format of an airspace file from its name and content.
+func DetectFormat(filename string, data []byte) Format {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json", ".geojson":
+		return FormatGeoJSON
+	case ".txt", ".air", ".openair":
+		return FormatOpenAir
+	}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		return FormatGeoJSON
+	}
+	return FormatOpenAir
+}
+
+// LoadDir parses every airspace file in a directory. Files that fail to
+// parse are reported as warnings so one bad file does not block startup.
+func LoadDir(dir string) (*Result, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read airspace directory: %w", err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Type().IsRegular() {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	result := &Result{}
+	for _, name := range names {
+		switch strings.ToLower(filepath.Ext(name)) {
+		case ".json", ".geojson", ".txt", ".air", ".openair":
+		default:
+			continue
+		}
+
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			result.warnf("%s: %v", name, err)
+			continue
+		}
+
+		parsed, err := Parse(data, DetectFormat(name, data), name)
+		if err != nil {
+			result.warnf("%s: %v", name, err)
+			continue
+		}
+		result.Zones = append(result.Zones, parsed.Zones...)
+		result.Warnings = append(result.Warnings, parsed.Warnings...)
+	}
+
+	return result, nil
+}
+
+// warnf records a non-fatal import warning.
+func (r *Result) warnf(format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// parseLimit parses an optional altitude limit. Empty text, surface and
+// unlimited limits return nil. AGL limits are kept but reported, since
+// geofence limits are MSL.
+func (r *Result) parseLimit(text, zoneName, which string) (*float64, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+	l, err := parseAltitude(text)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", which, err)
+	}
+	if l.agl {
+		r.warnf("airspace %q: %s %q is AGL, treated as MSL", zoneName, which, text)
+	}
+	if l.meters != nil && *l.meters == 0 && which == "floor" {
+		return nil, nil
+	}
+	return l.meters, nil
+}
+
+// modeForClass returns the geofence mode used for an airspace class.
+func modeForClass(class string) models.GeofenceMode {
+	if exclusionClasses[strings.ToUpper(class)] {
+		return models.GeofenceModeExclusion
+	}
+	return models.GeofenceModeAdvisory
+}
+
+// toCoordinates converts geo points to model coordinates, dropping a
+// closing vertex that repeats the first one.
+func toCoordinates(points []geo.Point) []models.Coordinate {
+	if n := len(points); n > 1 && points[0] == points[n-1] {
+		points = points[:n-1]
+	}
+	coords := make([]models.Coordinate, len(points))
+	for i, p := range points {
+		coords[i] = models.Coordinate{Latitude: p.Lat, Longitude: p.Lon}
+	}
+	return coords
+}
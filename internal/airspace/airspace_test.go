@@ -0,0 +1,230 @@
+package airspace
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/pkg/geo"
+)
+
+const testOpenAir = `* Test airspace
+AC R
+AN LLR-1 RESTRICTED
+AL SFC
+AH FL100
+DP 32:00:00 N 034:48:00 E
+DP 32:00:00 N 034:54:00 E
+DP 32:06:00 N 034:54:00 E
+DP 32:06:00 N 034:48:00 E
+
+AC CTR
+AN BEN GURION CTR
+AL GND
+AH 3500ft MSL
+V X=32:00:36 N 034:53:12 E
+DC 5
+
+AC D
+AN ARC ZONE
+AL 1000ft AGL
+AH UNL
+V X=32:30:00 N 035:00:00 E
+V D=+
+DA 10,0,90
+DP 32:30:00 N 035:00:00 E
+`
+
+func TestParseOpenAir(t *testing.T) {
+	result, err := ParseOpenAir(strings.NewReader(testOpenAir), "test.txt")
+	if err != nil {
+		t.Fatalf("ParseOpenAir() error = %v", err)
+	}
+	if len(result.Zones) != 3 {
+		t.Fatalf("ParseOpenAir() returned %d zones, want 3", len(result.Zones))
+	}
+
+	polygon := result.Zones[0]
+	if polygon.Shape != models.GeofenceShapePolygon || len(polygon.Vertices) != 4 {
+		t.Errorf("Zone 0 shape = %s with %d vertices, want polygon with 4", polygon.Shape, len(polygon.Vertices))
+	}
+	if polygon.Mode != models.GeofenceModeExclusion {
+		t.Errorf("Zone 0 mode = %s, want exclusion", polygon.Mode)
+	}
+	if polygon.FloorM != nil {
+		t.Errorf("Zone 0 floor = %v, want nil (surface)", *polygon.FloorM)
+	}
+	if polygon.CeilingM == nil || math.Abs(*polygon.CeilingM-3048) > 0.1 {
+		t.Errorf("Zone 0 ceiling = %v, want 3048 (FL100)", polygon.CeilingM)
+	}
+	if polygon.Vertices[2].Latitude != 32.1 || polygon.Vertices[2].Longitude != 34.9 {
+		t.Errorf("Zone 0 vertex 2 = %+v, want (32.1, 34.9)", polygon.Vertices[2])
+	}
+
+	circle := result.Zones[1]
+	if circle.Shape != models.GeofenceShapeCircle || math.Abs(circle.RadiusM-5*metersPerNM) > 0.1 {
+		t.Errorf("Zone 1 = %s radius %.1f, want circle radius %.1f", circle.Shape, circle.RadiusM, 5*metersPerNM)
+	}
+	if circle.Mode != models.GeofenceModeAdvisory {
+		t.Errorf("Zone 1 mode = %s, want advisory", circle.Mode)
+	}
+
+	arc := result.Zones[2]
+	if arc.Shape != models.GeofenceShapePolygon {
+		t.Fatalf("Zone 2 shape = %s, want polygon", arc.Shape)
+	}
+	// Every arc vertex lies 10 NM from the center
+	for _, v := range arc.Vertices[:len(arc.Vertices)-1] {
+		d := geo.Haversine(32.5, 35.0, v.Latitude, v.Longitude)
+		if math.Abs(d-10*metersPerNM) > 1 {
+			t.Errorf("Arc vertex %+v is %.1f m from center, want %.1f", v, d, 10*metersPerNM)
+		}
+	}
+	if arc.CeilingM != nil {
+		t.Errorf("Zone 2 ceiling = %v, want nil (unlimited)", *arc.CeilingM)
+	}
+
+	// The AGL floor is reported as a warning
+	if len(result.Warnings) != 1 {
+		t.Errorf("Warnings = %v, want 1 AGL warning", result.Warnings)
+	}
+}
+
+func TestParseOpenAir_InvalidCoordinate(t *testing.T) {
+	_, err := ParseOpenAir(strings.NewReader("AC R\nDP 32:00:00 034:48:00 E\n"), "bad.txt")
+	if err == nil {
+		t.Error("ParseOpenAir() expected error for coordinate without N/S, got nil")
+	}
+}
+
+const testGeoJSON = `{
+  "type": "FeatureCollection",
+  "features": [
+    {
+      "type": "Feature",
+      "properties": {"name": "Range", "class": "R", "lower_limit": "SFC", "upper_limit": "FL95"},
+      "geometry": {"type": "Polygon", "coordinates": [[[34.8, 32.0], [34.9, 32.0], [34.9, 32.1], [34.8, 32.0]]]}
+    },
+    {
+      "type": "Feature",
+      "properties": {"name": "Stadium", "mode": "exclusion", "radius_m": 1500, "ceiling_m": 600},
+      "geometry": {"type": "Point", "coordinates": [34.78, 32.08]}
+    },
+    {
+      "type": "Feature",
+      "properties": {"name": "Route"},
+      "geometry": {"type": "LineString", "coordinates": [[34.8, 32.0], [34.9, 32.1]]}
+    }
+  ]
+}`
+
+func TestParseGeoJSON(t *testing.T) {
+	result, err := ParseGeoJSON([]byte(testGeoJSON), "test.geojson")
+	if err != nil {
+		t.Fatalf("ParseGeoJSON() error = %v", err)
+	}
+	if len(result.Zones) != 2 {
+		t.Fatalf("ParseGeoJSON() returned %d zones, want 2", len(result.Zones))
+	}
+
+	polygon := result.Zones[0]
+	if len(polygon.Vertices) != 3 {
+		t.Errorf("Polygon has %d vertices, want 3 (closing vertex dropped)", len(polygon.Vertices))
+	}
+	if polygon.Mode != models.GeofenceModeExclusion {
+		t.Errorf("Polygon mode = %s, want exclusion for class R", polygon.Mode)
+	}
+	if polygon.CeilingM == nil || math.Abs(*polygon.CeilingM-9500*metersPerFoot) > 0.1 {
+		t.Errorf("Polygon ceiling = %v, want FL95", polygon.CeilingM)
+	}
+
+	circle := result.Zones[1]
+	if circle.Shape != models.GeofenceShapeCircle || circle.Center.Latitude != 32.08 || circle.RadiusM != 1500 {
+		t.Errorf("Circle = %+v, want center 32.08 radius 1500", circle)
+	}
+
+	// The LineString feature is skipped with a warning
+	if len(result.Warnings) != 1 {
+		t.Errorf("Warnings = %v, want 1", result.Warnings)
+	}
+}
+
+func TestParseAltitude(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected *float64
+		agl      bool
+	}{
+		{input: "SFC", expected: nil},
+		{input: "UNL", expected: nil},
+		{input: "FL95", expected: ptr(9500 * metersPerFoot)},
+		{input: "FL 100", expected: ptr(10000 * metersPerFoot)},
+		{input: "3500ft MSL", expected: ptr(3500 * metersPerFoot)},
+		{input: "3500 AMSL", expected: ptr(3500 * metersPerFoot)},
+		{input: "1500 AGL", expected: ptr(1500 * metersPerFoot), agl: true},
+		{input: "1000m", expected: ptr(1000)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, err := parseAltitude(tt.input)
+			if err != nil {
+				t.Fatalf("parseAltitude() error = %v", err)
+			}
+			switch {
+			case tt.expected == nil && result.meters != nil:
+				t.Errorf("parseAltitude() = %.1f, want nil", *result.meters)
+			case tt.expected != nil && (result.meters == nil || math.Abs(*result.meters-*tt.expected) > 0.01):
+				t.Errorf("parseAltitude() = %v, want %.1f", result.meters, *tt.expected)
+			}
+			if result.agl != tt.agl {
+				t.Errorf("parseAltitude() agl = %v, want %v", result.agl, tt.agl)
+			}
+		})
+	}
+
+	if _, err := parseAltitude("high"); err == nil {
+		t.Error("parseAltitude(\"high\") expected error, got nil")
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"restricted.txt":  testOpenAir,
+		"zones.geojson":   testGeoJSON,
+		"broken.json":     "{not json",
+		"readme.markdown": "ignored",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := LoadDir(dir)
+	if err != nil {
+		t.Fatalf("LoadDir() error = %v", err)
+	}
+	if len(result.Zones) != 5 {
+		t.Errorf("LoadDir() returned %d zones, want 5", len(result.Zones))
+	}
+
+	// broken.json produces a warning rather than failing the load
+	found := false
+	for _, w := range result.Warnings {
+		if strings.HasPrefix(w, "broken.json") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("LoadDir() warnings = %v, want entry for broken.json", result.Warnings)
+	}
+}
+
+func ptr(f float64) *float64 {
+	return &f
+}
@@ -0,0 +1,75 @@
+package airspace
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	metersPerFoot = 0.3048
+	metersPerNM   = 1852.0
+)
+
+// limit is a parsed altitude limit. A nil value means surface (floor) or
+// unlimited (ceiling).
+type limit struct {
+	meters *float64
+	agl    bool // limit was given above ground level
+}
+
+// parseAltitude parses an airspace altitude limit such as "SFC", "GND",
+// "UNL", "FL95", "3500ft MSL", "1500 AGL" or "1000m". Plain numbers are feet.
+// AGL limits are returned as-is in meters and flagged so the caller can warn.
+func parseAltitude(s string) (limit, error) {
+	text := strings.ToUpper(strings.TrimSpace(s))
+	if text == "" {
+		return limit{}, fmt.Errorf("empty altitude")
+	}
+
+	switch text {
+	case "SFC", "GND", "SURFACE", "0":
+		return limit{}, nil
+	case "UNL", "UNLTD", "UNLIM", "UNLIMITED":
+		return limit{}, nil
+	}
+
+	// Flight level: FL95 or FL 95
+	if strings.HasPrefix(text, "FL") {
+		level, err := strconv.ParseFloat(strings.TrimSpace(text[2:]), 64)
+		if err != nil {
+			return limit{}, fmt.Errorf("invalid flight level %q", s)
+		}
+		meters := level * 100 * metersPerFoot
+		return limit{meters: &meters}, nil
+	}
+
+	// Datum suffix
+	agl := false
+	for _, suffix := range []string{"AMSL", "MSL", "ALT", "AGL", "AGND", "GND", "SFC"} {
+		if strings.HasSuffix(text, suffix) {
+			agl = suffix == "AGL" || suffix == "AGND" || suffix == "GND" || suffix == "SFC"
+			text = strings.TrimSpace(strings.TrimSuffix(text, suffix))
+			break
+		}
+	}
+
+	// Unit suffix (feet by default)
+	factor := metersPerFoot
+	switch {
+	case strings.HasSuffix(text, "FT"):
+		text = strings.TrimSuffix(text, "FT")
+	case strings.HasSuffix(text, "F"):
+		text = strings.TrimSuffix(text, "F")
+	case strings.HasSuffix(text, "M"):
+		text = strings.TrimSuffix(text, "M")
+		factor = 1
+	}
+
+	value, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+	if err != nil {
+		return limit{}, fmt.Errorf("invalid altitude %q", s)
+	}
+	meters := value * factor
+	return limit{meters: &meters, agl: agl}, nil
+}
@@ -0,0 +1,188 @@
+package airspace
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/pkg/geo"
+)
+
+// geoJSONObject covers FeatureCollection, Feature and bare geometry objects.
+type geoJSONObject struct {
+	Type        string                 `json:"type"`
+	Features    []geoJSONObject        `json:"features,omitempty"`
+	Geometry    *geoJSONObject         `json:"geometry,omitempty"`
+	Properties  map[string]interface{} `json:"properties,omitempty"`
+	Coordinates json.RawMessage        `json:"coordinates,omitempty"`
+}
+
+// ParseGeoJSON parses airspace zones from a GeoJSON FeatureCollection,
+// Feature or geometry.
+//
+// Polygon and MultiPolygon features become polygon zones (outer ring only).
+// Point features with a "radius_m" (or "radius", meters) property become
+// circles. Recognized properties: name, class, mode, breach_action,
+// floor_m/ceiling_m (meters MSL) and lower_limit/upper_limit (text such as
+// "SFC" or "FL95").
+func ParseGeoJSON(data []byte, source string) (*Result, error) {
+	var root geoJSONObject
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("invalid GeoJSON: %w", err)
+	}
+
+	var features []geoJSONObject
+	switch root.Type {
+	case "FeatureCollection":
+		features = root.Features
+	case "Feature":
+		features = []geoJSONObject{root}
+	default:
+		features = []geoJSONObject{{Type: "Feature", Geometry: &root}}
+	}
+
+	result := &Result{}
+	for i, feature := range features {
+		zones, err := featureToGeofences(feature, source, result)
+		if err != nil {
+			result.warnf("%s: feature %d: %v", source, i, err)
+			continue
+		}
+		result.Zones = append(result.Zones, zones...)
+	}
+
+	return result, nil
+}
+
+// featureToGeofences converts one feature into one or more zones.
+func featureToGeofences(feature geoJSONObject, source string, result *Result) ([]models.Geofence, error) {
+	if feature.Geometry == nil {
+		return nil, fmt.Errorf("missing geometry")
+	}
+	props := feature.Properties
+
+	base := models.Geofence{
+		Name:         stringProp(props, "name", "NAME"),
+		Class:        strings.ToUpper(stringProp(props, "class", "type", "CLASS")),
+		BreachAction: models.BreachAction(stringProp(props, "breach_action")),
+		Source:       source,
+	}
+	base.Mode = modeForClass(base.Class)
+	if mode := stringProp(props, "mode"); mode != "" {
+		base.Mode = models.GeofenceMode(mode)
+	}
+
+	var err error
+	if base.FloorM, err = limitProp(props, result, base.Name, "floor", "floor_m", "lower_limit"); err != nil {
+		return nil, err
+	}
+	if base.CeilingM, err = limitProp(props, result, base.Name, "ceiling", "ceiling_m", "upper_limit"); err != nil {
+		return nil, err
+	}
+
+	geometry := feature.Geometry
+	switch geometry.Type {
+	case "Polygon":
+		var rings [][][]float64
+		if err := json.Unmarshal(geometry.Coordinates, &rings); err != nil {
+			return nil, fmt.Errorf("invalid polygon coordinates: %w", err)
+		}
+		zone, err := polygonZone(base, rings, result)
+		if err != nil {
+			return nil, err
+		}
+		return []models.Geofence{zone}, nil
+
+	case "MultiPolygon":
+		var polygons [][][][]float64
+		if err := json.Unmarshal(geometry.Coordinates, &polygons); err != nil {
+			return nil, fmt.Errorf("invalid multipolygon coordinates: %w", err)
+		}
+		zones := make([]models.Geofence, 0, len(polygons))
+		for i, rings := range polygons {
+			part := base
+			if base.Name != "" {
+				part.Name = fmt.Sprintf("%s (%d)", base.Name, i+1)
+			}
+			zone, err := polygonZone(part, rings, result)
+			if err != nil {
+				return nil, fmt.Errorf("polygon %d: %w", i, err)
+			}
+			zones = append(zones, zone)
+		}
+		return zones, nil
+
+	case "Point":
+		var coords []float64
+		if err := json.Unmarshal(geometry.Coordinates, &coords); err != nil || len(coords) < 2 {
+			return nil, fmt.Errorf("invalid point coordinates")
+		}
+		radius, ok := floatProp(props, "radius_m", "radius")
+		if !ok || radius <= 0 {
+			return nil, fmt.Errorf("point feature requires a positive radius_m property")
+		}
+		base.Shape = models.GeofenceShapeCircle
+		base.Center = &models.Coordinate{Latitude: coords[1], Longitude: coords[0]}
+		base.RadiusM = radius
+		return []models.Geofence{base}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported geometry type %q", geometry.Type)
+	}
+}
+
+// polygonZone builds a polygon zone from GeoJSON rings ([lon, lat] order).
+func polygonZone(base models.Geofence, rings [][][]float64, result *Result) (models.Geofence, error) {
+	if len(rings) == 0 {
+		return base, fmt.Errorf("polygon has no rings")
+	}
+	if len(rings) > 1 {
+		// The zone model has no holes
+		result.warnf("%s: airspace %q: polygon holes ignored", base.Source, base.Name)
+	}
+
+	points := make([]geo.Point, 0, len(rings[0]))
+	for _, c := range rings[0] {
+		if len(c) < 2 {
+			return base, fmt.Errorf("invalid position")
+		}
+		points = append(points, geo.Point{Lat: c[1], Lon: c[0]})
+	}
+
+	base.Shape = models.GeofenceShapePolygon
+	base.Vertices = toCoordinates(points)
+	if len(base.Vertices) < 3 {
+		return base, fmt.Errorf("polygon requires at least 3 vertices")
+	}
+	return base, nil
+}
+
+// stringProp returns the first non-empty string property among keys.
+func stringProp(props map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if s, ok := props[key].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// floatProp returns the first numeric property among keys.
+func floatProp(props map[string]interface{}, keys ...string) (float64, bool) {
+	for _, key := range keys {
+		if v, ok := props[key].(float64); ok {
+			return v, true
+		}
+	}
+	return 0, false
+}
+
+// limitProp reads an altitude limit from a numeric meters property or a
+// textual limit property.
+func limitProp(props map[string]interface{}, result *Result, zoneName, which, metersKey, textKey string) (*float64, error) {
+	if v, ok := floatProp(props, metersKey); ok {
+		return &v, nil
+	}
+	return result.parseLimit(stringProp(props, textKey), zoneName, which)
+}
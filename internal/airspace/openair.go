@@ -0,0 +1,291 @@
+package airspace
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/pkg/geo"
+)
+
+// arcStepDegrees is the angular spacing of the vertices generated for arcs.
+const arcStepDegrees = 5.0
+
+// openAirRecord accumulates the lines of one OpenAir airspace block.
+type openAirRecord struct {
+	line      int
+	class     string
+	name      string
+	floor     string
+	ceiling   string
+	center    *geo.Point
+	clockwise bool
+	radiusM   float64 // set by DC
+	points    []geo.Point
+}
+
+// ParseOpenAir parses airspace definitions in OpenAir format.
+// Supported records: AC, AN, AL, AH, DP, V X=, V D=, DA, DB and DC.
+// Other records (AT, SP, SB, ...) are ignored.
+func ParseOpenAir(r io.Reader, source string) (*Result, error) {
+	result := &Result{}
+	var rec *openAirRecord
+
+	flush := func() {
+		if rec == nil {
+			return
+		}
+		zone, err := rec.toGeofence(source, result)
+		if err != nil {
+			result.warnf("%s:%d: skipping airspace %q: %v", source, rec.line, rec.name, err)
+		} else {
+			result.Zones = append(result.Zones, zone)
+		}
+		rec = nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "*") {
+			continue
+		}
+
+		// Split record type from its argument
+		keyword, arg, _ := strings.Cut(line, " ")
+		keyword = strings.ToUpper(keyword)
+		arg = strings.TrimSpace(arg)
+
+		if keyword == "AC" {
+			flush()
+			rec = &openAirRecord{line: lineNum, class: strings.ToUpper(arg), clockwise: true}
+			continue
+		}
+		if rec == nil {
+			result.warnf("%s:%d: %s outside of an airspace block", source, lineNum, keyword)
+			continue
+		}
+
+		var err error
+		switch keyword {
+		case "AN":
+			rec.name = arg
+		case "AL":
+			rec.floor = arg
+		case "AH":
+			rec.ceiling = arg
+		case "DP":
+			var p geo.Point
+			if p, err = parseOpenAirCoordinate(arg); err == nil {
+				rec.points = append(rec.points, p)
+			}
+		case "V":
+			err = rec.parseVariable(arg)
+		case "DA":
+			err = rec.parseArcAngles(arg)
+		case "DB":
+			err = rec.parseArcPoints(arg)
+		case "DC":
+			var radius float64
+			if radius, err = strconv.ParseFloat(arg, 64); err == nil {
+				rec.radiusM = radius * metersPerNM
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %s: %w", source, lineNum, keyword, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", source, err)
+	}
+	flush()
+
+	return result, nil
+}
+
+// parseVariable handles "V X=<coordinate>" and "V D=+|-".
+func (r *openAirRecord) parseVariable(arg string) error {
+	name, value, ok := strings.Cut(arg, "=")
+	if !ok {
+		return fmt.Errorf("malformed variable %q", arg)
+	}
+	value = strings.TrimSpace(value)
+
+	switch strings.ToUpper(strings.TrimSpace(name)) {
+	case "X":
+		center, err := parseOpenAirCoordinate(value)
+		if err != nil {
+			return err
+		}
+		r.center = &center
+	case "D":
+		r.clockwise = value != "-"
+	}
+	return nil
+}
+
+// parseArcAngles handles "DA radius, startAngle, endAngle".
+func (r *openAirRecord) parseArcAngles(arg string) error {
+	if r.center == nil {
+		return fmt.Errorf("arc without center")
+	}
+	fields := strings.Split(arg, ",")
+	if len(fields) != 3 {
+		return fmt.Errorf("expected radius, start and end angle")
+	}
+
+	values := make([]float64, 3)
+	for i, f := range fields {
+		v, err := strconv.ParseFloat(strings.TrimSpace(f), 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", f)
+		}
+		values[i] = v
+	}
+
+	r.points = append(r.points, arcPoints(*r.center, values[0]*metersPerNM, values[1], values[2], r.clockwise)...)
+	return nil
+}
+
+// parseArcPoints handles "DB coordinate1, coordinate2".
+func (r *openAirRecord) parseArcPoints(arg string) error {
+	if r.center == nil {
+		return fmt.Errorf("arc without center")
+	}
+	first, second, ok := strings.Cut(arg, ",")
+	if !ok {
+		return fmt.Errorf("expected two coordinates")
+	}
+	start, err := parseOpenAirCoordinate(first)
+	if err != nil {
+		return err
+	}
+	end, err := parseOpenAirCoordinate(second)
+	if err != nil {
+		return err
+	}
+
+	c := *r.center
+	radius := geo.Haversine(c.Lat, c.Lon, start.Lat, start.Lon)
+	startAngle := geo.Bearing(c.Lat, c.Lon, start.Lat, start.Lon)
+	endAngle := geo.Bearing(c.Lat, c.Lon, end.Lat, end.Lon)
+
+	r.points = append(r.points, start)
+	r.points = append(r.points, arcPoints(c, radius, startAngle, endAngle, r.clockwise)...)
+	r.points = append(r.points, end)
+	return nil
+}
+
+// toGeofence converts a completed record into a zone.
+func (r *openAirRecord) toGeofence(source string, result *Result) (models.Geofence, error) {
+	zone := models.Geofence{
+		Name:   r.name,
+		Class:  r.class,
+		Mode:   modeForClass(r.class),
+		Source: source,
+	}
+
+	var err error
+	if zone.FloorM, err = result.parseLimit(r.floor, r.name, "floor"); err != nil {
+		return zone, err
+	}
+	if zone.CeilingM, err = result.parseLimit(r.ceiling, r.name, "ceiling"); err != nil {
+		return zone, err
+	}
+
+	switch {
+	case len(r.points) >= 3:
+		zone.Shape = models.GeofenceShapePolygon
+		zone.Vertices = toCoordinates(r.points)
+	case r.radiusM > 0 && r.center != nil:
+		zone.Shape = models.GeofenceShapeCircle
+		zone.Center = &models.Coordinate{Latitude: r.center.Lat, Longitude: r.center.Lon}
+		zone.RadiusM = r.radiusM
+	default:
+		return zone, fmt.Errorf("no geometry")
+	}
+
+	return zone, nil
+}
+
+// arcPoints generates vertices along an arc around center, excluding the
+// start and end angles themselves.
+func arcPoints(center geo.Point, radius, startAngle, endAngle float64, clockwise bool) []geo.Point {
+	// Angular sweep in the direction of travel
+	sweep := math.Mod(endAngle-startAngle+360, 360)
+	if !clockwise {
+		sweep = math.Mod(startAngle-endAngle+360, 360)
+	}
+
+	steps := int(math.Ceil(sweep / arcStepDegrees))
+	points := make([]geo.Point, 0, steps+1)
+	for i := 0; i <= steps; i++ {
+		delta := math.Min(float64(i)*arcStepDegrees, sweep)
+		angle := startAngle + delta
+		if !clockwise {
+			angle = startAngle - delta
+		}
+		lat, lon := geo.Destination(center.Lat, center.Lon, angle, radius)
+		points = append(points, geo.Point{Lat: lat, Lon: lon})
+	}
+	return points
+}
+
+// parseOpenAirCoordinate parses coordinates such as "32:00:00 N 034:48:00 E",
+// "32:00.5N 034:48.25E" or "32:00:00.0 N 034:48:00.0 E".
+func parseOpenAirCoordinate(s string) (geo.Point, error) {
+	text := strings.ToUpper(strings.TrimSpace(s))
+
+	latEnd := strings.IndexAny(text, "NS")
+	if latEnd < 0 {
+		return geo.Point{}, fmt.Errorf("invalid coordinate %q: missing N/S", s)
+	}
+	lonPart := strings.TrimSpace(text[latEnd+1:])
+	lonEnd := strings.IndexAny(lonPart, "EW")
+	if lonEnd < 0 {
+		return geo.Point{}, fmt.Errorf("invalid coordinate %q: missing E/W", s)
+	}
+
+	lat, err := parseDMS(text[:latEnd])
+	if err != nil {
+		return geo.Point{}, fmt.Errorf("invalid coordinate %q: %w", s, err)
+	}
+	lon, err := parseDMS(lonPart[:lonEnd])
+	if err != nil {
+		return geo.Point{}, fmt.Errorf("invalid coordinate %q: %w", s, err)
+	}
+
+	if text[latEnd] == 'S' {
+		lat = -lat
+	}
+	if lonPart[lonEnd] == 'W' {
+		lon = -lon
+	}
+	return geo.Point{Lat: lat, Lon: lon}, nil
+}
+
+// parseDMS parses "DD:MM:SS", "DD:MM.mmm" or "DD.ddd" into decimal degrees.
+func parseDMS(s string) (float64, error) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("too many components in %q", s)
+	}
+
+	degrees := 0.0
+	scale := 1.0
+	for _, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number %q", part)
+		}
+		degrees += v / scale
+		scale *= 60
+	}
+	return degrees, nil
+}
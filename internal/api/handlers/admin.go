@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/simulator"
+)
+
+// AdminHandler handles requests about the running server.
+type AdminHandler struct {
+	simulator *simulator.Simulator
+	logger    *slog.Logger
+}
+
+// NewAdminHandler creates a new admin handler.
+func NewAdminHandler(sim *simulator.Simulator, logger *slog.Logger) *AdminHandler {
+	return &AdminHandler{
+		simulator: sim,
+		logger:    logger,
+	}
+}
+
+// Subscribers handles GET /admin/subscribers
+// Returns the channel subscribers of the state, event and environment
+// brokers with their topics and slow subscriber policy, the messages queued
+// and dropped, and how far they are behind. SSE clients and the state
+// updates of WebSocket clients read the brokers' kept messages instead of
+// subscribing and are not listed; see Stream for SSE clients that fall
+// behind.
+func (h *AdminHandler) Subscribers(c *gin.Context) {
+	subscribers := h.simulator.GetPublisher().Subscribers()
+	subscribers = append(subscribers, h.simulator.GetEventBroker().Subscribers()...)
+	subscribers = append(subscribers, h.simulator.GetEnvironmentBroker().Subscribers()...)
+	sort.SliceStable(subscribers, func(i, j int) bool {
+		return subscribers[i].SubscribedAt.Before(subscribers[j].SubscribedAt)
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"subscribers": subscribers,
+	})
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/airspace"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/api/validation"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/geofence"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/simulator"
+)
+
+// AirspaceHandler handles airspace query and import requests.
+type AirspaceHandler struct {
+	simulator *simulator.Simulator
+	geofences *geofence.Manager
+	logger    *slog.Logger
+}
+
+// NewAirspaceHandler creates a new airspace handler.
+func NewAirspaceHandler(sim *simulator.Simulator, logger *slog.Logger) *AirspaceHandler {
+	return &AirspaceHandler{
+		simulator: sim,
+		geofences: sim.GetGeofences(),
+		logger:    logger,
+	}
+}
+
+// AirspaceImportResponse represents the response to an airspace import.
+type AirspaceImportResponse struct {
+	Imported int               `json:"imported"`
+	Zones    []models.Geofence `json:"zones"`
+	Warnings []string          `json:"warnings,omitempty"`
+}
+
+// Query handles GET /airspace?lat=&lon=[&alt=]
+// Returns all zones containing the point, including advisory airspace.
+func (h *AirspaceHandler) Query(c *gin.Context) {
+	if !h.enabled(c) {
+		return
+	}
+
+	lat, err := strconv.ParseFloat(c.Query("lat"), 64)
+	if err != nil {
+		h.writeBadRequest(c, "INVALID_LATITUDE", "lat query parameter must be a number")
+		return
+	}
+	lon, err := strconv.ParseFloat(c.Query("lon"), 64)
+	if err != nil {
+		h.writeBadRequest(c, "INVALID_LONGITUDE", "lon query parameter must be a number")
+		return
+	}
+
+	var alt *float64
+	if altText := c.Query("alt"); altText != "" {
+		value, err := strconv.ParseFloat(altText, 64)
+		if err != nil {
+			h.writeBadRequest(c, "INVALID_ALTITUDE", "alt query parameter must be a number")
+			return
+		}
+		alt = &value
+	}
+
+	if err := validation.ValidatePosition(models.Position{Latitude: lat, Longitude: lon}); err != nil {
+		h.writeBadRequest(c, getErrorCode(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"zones": h.geofences.Query(lat, lon, alt),
+	})
+}
+
+// Import handles POST /airspace/import[?format=openair|geojson]
+// The file is sent either as the raw request body or as a multipart "file" field.
+func (h *AirspaceHandler) Import(c *gin.Context) {
+	if !h.enabled(c) {
+		return
+	}
+
+	filename, data, err := readUpload(c)
+	if err != nil {
+		h.writeBadRequest(c, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	format := airspace.Format(c.Query("format"))
+	if format == "" {
+		format = airspace.DetectFormat(filename, data)
+	}
+
+	result, err := airspace.Parse(data, format, filename)
+	if err != nil {
+		h.logger.Warn("Airspace import failed", "error", err)
+		h.writeBadRequest(c, "INVALID_AIRSPACE", err.Error())
+		return
+	}
+
+	response := AirspaceImportResponse{
+		Zones:    make([]models.Geofence, 0, len(result.Zones)),
+		Warnings: result.Warnings,
+	}
+	for _, zone := range result.Zones {
+		if err := validation.ValidateGeofence(&zone); err != nil {
+			response.Warnings = append(response.Warnings, zone.Name+": "+err.Error())
+			continue
+		}
+		stored, err := h.simulator.AddGeofence(c.Request.Context(), zone)
+		if err != nil {
+			h.writeAddError(c, err)
+			return
+		}
+		response.Zones = append(response.Zones, stored)
+	}
+	response.Imported = len(response.Zones)
+
+	h.logger.Info("Airspace imported",
+		"source", filename,
+		"format", format,
+		"zones", response.Imported,
+		"warnings", len(response.Warnings),
+	)
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// enabled writes a 503 response if geofencing is disabled.
+func (h *AirspaceHandler) enabled(c *gin.Context) bool {
+	if h.geofences == nil {
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "GEOFENCE_DISABLED",
+				Message: "Geofencing is disabled in the simulator configuration",
+			},
+		})
+		return false
+	}
+	return true
+}
+
+// writeAddError writes the response for a zone the simulator did not add.
+// The zones added before it are kept.
+func (h *AirspaceHandler) writeAddError(c *gin.Context, err error) {
+	if errors.Is(err, models.ErrReplayActive) {
+		writeReplayActive(c)
+		return
+	}
+	h.logger.Error("Airspace import failed", "error", err)
+	c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+		Error: models.ErrorDetail{
+			Code:    "SIMULATOR_NOT_RUNNING",
+			Message: "Simulator did not apply the imported airspace",
+		},
+	})
+}
+
+// writeBadRequest writes a 400 error response.
+func (h *AirspaceHandler) writeBadRequest(c *gin.Context, code, message string) {
+	c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		Error: models.ErrorDetail{
+			Code:    code,
+			Message: message,
+		},
+	})
+}
@@ -1,28 +1,41 @@
 package handlers
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/meiron-tzhori/Flight-Simulator/internal/api/validation"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/flightplan"
 	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/navdata"
 	"github.com/meiron-tzhori/Flight-Simulator/internal/simulator"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/webhook"
 	"github.com/meiron-tzhori/Flight-Simulator/pkg/geo"
 )
 
 // CommandHandler handles command requests.
 type CommandHandler struct {
 	simulator *simulator.Simulator
+	navdata   *navdata.Database
+	webhooks  *webhook.Manager
 	logger    *slog.Logger
 	maxSpeed  float64
 }
 
-// NewCommandHandler creates a new command handler.
-func NewCommandHandler(sim *simulator.Simulator, logger *slog.Logger, maxSpeed float64) *CommandHandler {
+// NewCommandHandler creates a new command handler. The navigation database
+// resolves fix identifiers in requests and may be nil. The webhook manager
+// calls the callback URLs of commands; without one, requests with a
+// callback URL are rejected.
+func NewCommandHandler(sim *simulator.Simulator, nav *navdata.Database, webhooks *webhook.Manager, logger *slog.Logger, maxSpeed float64) *CommandHandler {
 	return &CommandHandler{
 		simulator: sim,
+		navdata:   nav,
+		webhooks:  webhooks,
 		logger:    logger,
 		maxSpeed:  maxSpeed,
 	}
@@ -30,10 +43,15 @@ func NewCommandHandler(sim *simulator.Simulator, logger *slog.Logger, maxSpeed f
 
 // GoToRequest represents the request body for go-to command.
 type GoToRequest struct {
-	Lat   float64  `json:"lat" binding:"required"`
-	Lon   float64  `json:"lon" binding:"required"`
-	Alt   float64  `json:"alt" binding:"required"`
-	Speed *float64 `json:"speed,omitempty"`
+	Lat    float64  `json:"lat" binding:"required_without=Fix"`
+	Lon    float64  `json:"lon" binding:"required_without=Fix"`
+	Fix    string   `json:"fix,omitempty"` // navdata identifier, instead of lat/lon
+	Alt    float64  `json:"alt" binding:"required"`
+	Speed  *float64 `json:"speed,omitempty"`
+	AltRef string   `json:"alt_ref,omitempty"` // "msl" (default) or "agl"
+
+	CallbackURL    string `json:"callback_url,omitempty"`    // POSTed the event that ends the command
+	CallbackSecret string `json:"callback_secret,omitempty"` // signs the callback, default webhooks.callback_secret
 }
 
 // GoTo handles POST /command/goto
@@ -50,6 +68,23 @@ func (h *CommandHandler) GoTo(c *gin.Context) {
 		return
 	}
 
+	response, cmdErr := h.goTo(c.Request.Context(), req)
+	if cmdErr != nil {
+		writeCommandError(c, cmdErr)
+		return
+	}
+
+	// Success
+	c.JSON(http.StatusOK, response)
+}
+
+// goTo validates a go-to request, checks it against the flight phase,
+// terrain and geofences, and submits it to the simulator.
+func (h *CommandHandler) goTo(ctx context.Context, req GoToRequest) (models.CommandResponse, *CommandError) {
+	if cmdErr := h.checkCallbackURL(req.CallbackURL, req.CallbackSecret); cmdErr != nil {
+		return models.CommandResponse{}, cmdErr
+	}
+
 	// Create command
 	cmd := models.NewCommand(models.CommandTypeGoTo)
 	cmd.GoTo = &models.GoToCommand{
@@ -58,44 +93,51 @@ func (h *CommandHandler) GoTo(c *gin.Context) {
 			Longitude: req.Lon,
 			Altitude:  req.Alt,
 		},
-		Speed: req.Speed,
+		Speed:       req.Speed,
+		AltitudeRef: models.AltitudeReference(req.AltRef),
+		Fix:         req.Fix,
 	}
 
-	// Validate
+	// Resolve fix reference and validate
+	target, err := validation.ResolvePosition(req.Fix, cmd.GoTo.Target, h.navdata)
+	if err != nil {
+		h.logger.Warn("Validation failed", "error", err)
+		cmdErr := newCommandError(http.StatusBadRequest, getErrorCode(err), err.Error())
+		cmdErr.Response.Error.Field = "fix"
+		return models.CommandResponse{}, cmdErr
+	}
+	cmd.GoTo.Target = target
+
 	if err := validation.ValidateGoToCommand(cmd.GoTo, h.maxSpeed); err != nil {
 		h.logger.Warn("Validation failed", "error", err)
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error: models.ErrorDetail{
-				Code:    getErrorCode(err),
-				Message: err.Error(),
-			},
-		})
-		return
+		return models.CommandResponse{}, newCommandError(http.StatusBadRequest, getErrorCode(err), err.Error())
+	}
+
+	// Check terrain clearance at the target
+	terrain := h.simulator.GetEnvironment().GetTerrain()
+	if err := validation.ValidateTerrainClearance(cmd.GoTo.Target, cmd.GoTo.AltitudeRef, terrain); err != nil {
+		h.logger.Warn("Terrain conflict", "error", err)
+		return models.CommandResponse{}, &CommandError{Status: http.StatusUnprocessableEntity, Response: terrainConflictResponse(err)}
+	}
+
+	// Go-to requires an airborne aircraft
+	if err := h.checkFlightPhase(ctx, true); err != nil {
+		return models.CommandResponse{}, h.flightPhaseError(err)
+	}
+
+	// Check the planned path against geofences
+	waypoint := models.Waypoint{Position: cmd.GoTo.Target, AltitudeRef: cmd.GoTo.AltitudeRef}
+	if err := h.checkGeofencePath(ctx, []models.Waypoint{waypoint}); err != nil {
+		return models.CommandResponse{}, h.geofencePathError(err)
 	}
 
 	// Submit to simulator
-	if err := h.simulator.SubmitCommand(c.Request.Context(), cmd); err != nil {
-		h.logger.Error("Failed to submit command", "error", err)
-		if errors.Is(err, models.ErrCommandQueueFull) {
-			c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
-				Error: models.ErrorDetail{
-					Code:    "QUEUE_FULL",
-					Message: "Command queue is full, please retry",
-				},
-			})
-		} else {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-				Error: models.ErrorDetail{
-					Code:    "INTERNAL_ERROR",
-					Message: "Failed to submit command",
-				},
-			})
-		}
-		return
+	if err := h.submitWatched(ctx, cmd, req.CallbackURL, req.CallbackSecret); err != nil {
+		return models.CommandResponse{}, h.submitError(err, "Failed to submit command")
 	}
 
 	// Get current state for ETA calculation
-	state, err := h.simulator.GetState(c.Request.Context())
+	state, err := h.simulator.GetState(ctx)
 	if err != nil {
 		h.logger.Warn("Failed to get state for ETA calculation", "error", err)
 	}
@@ -118,27 +160,32 @@ func (h *CommandHandler) GoTo(c *gin.Context) {
 		}
 	}
 
-	// Success
-	c.JSON(http.StatusOK, models.CommandResponse{
+	return models.CommandResponse{
 		Status:     "accepted",
 		CommandID:  cmd.ID,
 		Message:    "Go-to command accepted",
 		Target:     &cmd.GoTo.Target,
 		ETASeconds: etaSeconds,
-	})
+	}, nil
 }
 
 // TrajectoryRequest represents the request body for trajectory command.
 type TrajectoryRequest struct {
-	Waypoints []WaypointRequest `json:"waypoints" binding:"required,min=1"`
-	Loop      bool              `json:"loop"`
+	Waypoints      []WaypointRequest `json:"waypoints" binding:"required,min=1"`
+	Loop           bool              `json:"loop"`
+	ReturnToHome   bool              `json:"return_to_home,omitempty"`  // return home after the last waypoint
+	CallbackURL    string            `json:"callback_url,omitempty"`    // POSTed the event that ends the command
+	CallbackSecret string            `json:"callback_secret,omitempty"` // signs the callback, default webhooks.callback_secret
 }
 
 type WaypointRequest struct {
-	Lat   float64  `json:"lat" binding:"required"`
-	Lon   float64  `json:"lon" binding:"required"`
-	Alt   float64  `json:"alt" binding:"required"`
-	Speed *float64 `json:"speed,omitempty"`
+	Lat    float64  `json:"lat" binding:"required_without=Fix"`
+	Lon    float64  `json:"lon" binding:"required_without=Fix"`
+	Fix    string   `json:"fix,omitempty"` // navdata identifier, instead of lat/lon
+	Alt    float64  `json:"alt" binding:"required"`
+	Speed  *float64 `json:"speed,omitempty"`
+	AltRef string   `json:"alt_ref,omitempty"` // "msl" (default) or "agl"
+	Hold   float64  `json:"hold_seconds,omitempty"`
 }
 
 // Trajectory handles POST /command/trajectory
@@ -155,123 +202,275 @@ func (h *CommandHandler) Trajectory(c *gin.Context) {
 		return
 	}
 
+	response, cmdErr := h.trajectory(c.Request.Context(), req)
+	if cmdErr != nil {
+		writeCommandError(c, cmdErr)
+		return
+	}
+
+	// Success
+	c.JSON(http.StatusOK, response)
+}
+
+// trajectory resolves the waypoints of a trajectory request and submits it
+// like submitTrajectory.
+func (h *CommandHandler) trajectory(ctx context.Context, req TrajectoryRequest) (models.CommandResponse, *CommandError) {
+	if cmdErr := h.checkCallbackURL(req.CallbackURL, req.CallbackSecret); cmdErr != nil {
+		return models.CommandResponse{}, cmdErr
+	}
+
 	// Create command
 	cmd := models.NewCommand(models.CommandTypeTrajectory)
 	waypoints := make([]models.Waypoint, len(req.Waypoints))
 	for i, wp := range req.Waypoints {
+		position, err := validation.ResolvePosition(wp.Fix, models.Position{
+			Latitude:  wp.Lat,
+			Longitude: wp.Lon,
+			Altitude:  wp.Alt,
+		}, h.navdata)
+		if err != nil {
+			h.logger.Warn("Validation failed", "error", err, "waypoint_index", i)
+			cmdErr := newCommandError(http.StatusBadRequest, getErrorCode(err), fmt.Sprintf("waypoint %d: %s", i, err))
+			cmdErr.Response.Error.Field = fmt.Sprintf("waypoints[%d].fix", i)
+			return models.CommandResponse{}, cmdErr
+		}
 		waypoints[i] = models.Waypoint{
-			Position: models.Position{
-				Latitude:  wp.Lat,
-				Longitude: wp.Lon,
-				Altitude:  wp.Alt,
-			},
-			Speed: wp.Speed,
+			Position:    position,
+			Speed:       wp.Speed,
+			AltitudeRef: models.AltitudeReference(wp.AltRef),
+			Fix:         wp.Fix,
+			HoldSeconds: wp.Hold,
 		}
 	}
 	cmd.Trajectory = &models.TrajectoryCommand{
-		Waypoints: waypoints,
-		Loop:      req.Loop,
+		Waypoints:    waypoints,
+		Loop:         req.Loop,
+		ReturnToHome: req.ReturnToHome,
 	}
 
-	// Validate
-	if err := validation.ValidateTrajectoryCommand(cmd.Trajectory, h.maxSpeed); err != nil {
-		h.logger.Warn("Validation failed", "error", err)
+	if cmdErr := h.sendTrajectory(ctx, cmd, req.CallbackURL, req.CallbackSecret); cmdErr != nil {
+		return models.CommandResponse{}, cmdErr
+	}
+
+	return models.CommandResponse{
+		Status:        "accepted",
+		CommandID:     cmd.ID,
+		Message:       "Trajectory command accepted",
+		WaypointCount: len(waypoints),
+	}, nil
+}
+
+// RouteRequest represents the request body for route command.
+type RouteRequest struct {
+	Route string   `json:"route" binding:"required"` // ICAO Item 15 route string
+	Alt   *float64 `json:"alt,omitempty"`            // meters MSL, until the route sets a level
+	Speed *float64 `json:"speed,omitempty"`          // m/s, until the route sets a speed
+	Loop  bool     `json:"loop"`
+}
+
+// Route handles POST /command/route
+func (h *CommandHandler) Route(c *gin.Context) {
+	var req RouteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Invalid request", "error", err)
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Error: models.ErrorDetail{
-				Code:    getErrorCode(err),
+				Code:    "INVALID_REQUEST",
 				Message: err.Error(),
 			},
 		})
 		return
 	}
 
-	// Submit to simulator
-	if err := h.simulator.SubmitCommand(c.Request.Context(), cmd); err != nil {
-		h.logger.Error("Failed to submit command", "error", err)
-		if errors.Is(err, models.ErrCommandQueueFull) {
-			c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
-				Error: models.ErrorDetail{
-					Code:    "QUEUE_FULL",
-					Message: "Command queue is full, please retry",
-				},
-			})
-		} else {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-				Error: models.ErrorDetail{
-					Code:    "INTERNAL_ERROR",
-					Message: "Failed to submit command",
-				},
-			})
-		}
+	trajectory, err := flightplan.ParseRoute(req.Route, h.navdata, flightplan.RouteOptions{
+		Altitude: req.Alt,
+		Speed:    req.Speed,
+	})
+	if err != nil {
+		h.logger.Warn("Invalid route", "error", err)
+		c.JSON(http.StatusBadRequest, routeErrorResponse(err))
+		return
+	}
+	trajectory.Loop = req.Loop
+
+	cmd := models.NewCommand(models.CommandTypeTrajectory)
+	cmd.Trajectory = trajectory
+	if !h.submitTrajectory(c, cmd) {
 		return
 	}
 
-	// Success
 	c.JSON(http.StatusOK, models.CommandResponse{
 		Status:        "accepted",
 		CommandID:     cmd.ID,
-		Message:       "Trajectory command accepted",
-		WaypointCount: len(waypoints),
+		Message:       "Route command accepted",
+		WaypointCount: len(trajectory.Waypoints),
+		Waypoints:     trajectory.Waypoints,
 	})
 }
 
+// routeErrorResponse builds the 400 response body for an invalid route,
+// listing every invalid token.
+func routeErrorResponse(err error) models.ErrorResponse {
+	response := models.ErrorResponse{
+		Error: models.ErrorDetail{
+			Code:    "INVALID_ROUTE",
+			Message: err.Error(),
+			Field:   "route",
+		},
+	}
+
+	var routeErr *flightplan.RouteError
+	if errors.As(err, &routeErr) {
+		response.Error.Field = fmt.Sprintf("route[%d]", routeErr.Tokens[0].Index)
+		response.Error.Details = map[string]interface{}{
+			"tokens": routeErr.Tokens,
+		}
+	}
+
+	return response
+}
+
+// submitTrajectory validates a trajectory command, checks it against the
+// flight phase, terrain and geofences, and submits it to the simulator.
+// It writes the error response and returns false on failure.
+func (h *CommandHandler) submitTrajectory(c *gin.Context, cmd *models.Command) bool {
+	if cmdErr := h.sendTrajectory(c.Request.Context(), cmd, "", ""); cmdErr != nil {
+		writeCommandError(c, cmdErr)
+		return false
+	}
+	return true
+}
+
+// sendTrajectory is submitTrajectory without the response, calling
+// callbackURL, if set, when the command ends, signed with callbackSecret.
+func (h *CommandHandler) sendTrajectory(ctx context.Context, cmd *models.Command, callbackURL, callbackSecret string) *CommandError {
+	// Validate
+	if err := validation.ValidateTrajectoryCommand(cmd.Trajectory, h.maxSpeed); err != nil {
+		h.logger.Warn("Validation failed", "error", err)
+		return newCommandError(http.StatusBadRequest, getErrorCode(err), err.Error())
+	}
+
+	// Check terrain clearance at every waypoint
+	terrain := h.simulator.GetEnvironment().GetTerrain()
+	for i, wp := range cmd.Trajectory.Waypoints {
+		if err := validation.ValidateTerrainClearance(wp.Position, wp.AltitudeRef, terrain); err != nil {
+			h.logger.Warn("Terrain conflict", "error", err, "waypoint_index", i)
+			response := terrainConflictResponse(err)
+			response.Error.Field = fmt.Sprintf("waypoints[%d]", i)
+			return &CommandError{Status: http.StatusUnprocessableEntity, Response: response}
+		}
+	}
+
+	// Trajectories require an airborne aircraft
+	if err := h.checkFlightPhase(ctx, true); err != nil {
+		return h.flightPhaseError(err)
+	}
+
+	// Check the planned path against geofences
+	if err := h.checkGeofencePath(ctx, cmd.Trajectory.Waypoints); err != nil {
+		return h.geofencePathError(err)
+	}
+
+	// Submit to simulator
+	if err := h.submitWatched(ctx, cmd, callbackURL, callbackSecret); err != nil {
+		return h.submitError(err, "Failed to submit command")
+	}
+
+	return nil
+}
+
+// checkCallbackURL validates the callback URL of a command request, if set,
+// and checks that its deliveries can be signed.
+func (h *CommandHandler) checkCallbackURL(callbackURL, callbackSecret string) *CommandError {
+	if callbackURL == "" {
+		return nil
+	}
+
+	var err error
+	if h.webhooks == nil {
+		err = fmt.Errorf("%w: callbacks are not available", models.ErrInvalidCallbackURL)
+	} else {
+		err = h.webhooks.CheckCallback(callbackURL, callbackSecret)
+	}
+	if err != nil {
+		h.logger.Warn("Validation failed", "error", err)
+		cmdErr := newCommandError(http.StatusBadRequest, getErrorCode(err), err.Error())
+		cmdErr.Response.Error.Field = "callback_url"
+		if errors.Is(err, models.ErrMissingCallbackSecret) {
+			cmdErr.Response.Error.Field = "callback_secret"
+		}
+		return cmdErr
+	}
+	return nil
+}
+
+// submitWatched submits a command to the simulator. A callback URL, if set,
+// is watched from before the submission, so the command cannot end before
+// it is.
+func (h *CommandHandler) submitWatched(ctx context.Context, cmd *models.Command, callbackURL, callbackSecret string) error {
+	if callbackURL == "" {
+		return h.simulator.SubmitCommand(ctx, cmd)
+	}
+
+	h.webhooks.WatchCommand(cmd.ID, callbackURL, callbackSecret)
+	if err := h.simulator.SubmitCommand(ctx, cmd); err != nil {
+		h.webhooks.UnwatchCommand(cmd.ID)
+		return err
+	}
+	return nil
+}
+
 // Stop handles POST /command/stop
 func (h *CommandHandler) Stop(c *gin.Context) {
+	response, cmdErr := h.stop(c.Request.Context())
+	if cmdErr != nil {
+		writeCommandError(c, cmdErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// stop submits a stop command.
+func (h *CommandHandler) stop(ctx context.Context) (models.CommandResponse, *CommandError) {
 	cmd := models.NewCommand(models.CommandTypeStop)
 
-	if err := h.simulator.SubmitCommand(c.Request.Context(), cmd); err != nil {
-		h.logger.Error("Failed to submit command", "error", err)
-		if errors.Is(err, models.ErrCommandQueueFull) {
-			c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
-				Error: models.ErrorDetail{
-					Code:    "QUEUE_FULL",
-					Message: "Command queue is full, please retry",
-				},
-			})
-		} else {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-				Error: models.ErrorDetail{
-					Code:    "INTERNAL_ERROR",
-					Message: "Failed to submit stop command",
-				},
-			})
-		}
-		return
+	if err := h.simulator.SubmitCommand(ctx, cmd); err != nil {
+		return models.CommandResponse{}, h.submitError(err, "Failed to submit stop command")
 	}
 
-	c.JSON(http.StatusOK, models.CommandResponse{
+	return models.CommandResponse{
 		Status:    "accepted",
 		CommandID: cmd.ID,
 		Message:   "Stop command accepted",
-	})
+	}, nil
 }
 
 // Hold handles POST /command/hold
 func (h *CommandHandler) Hold(c *gin.Context) {
+	response, cmdErr := h.hold(c.Request.Context())
+	if cmdErr != nil {
+		writeCommandError(c, cmdErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// hold checks the flight phase and submits a hold command.
+func (h *CommandHandler) hold(ctx context.Context) (models.CommandResponse, *CommandError) {
+	if err := h.checkFlightPhase(ctx, true); err != nil {
+		return models.CommandResponse{}, h.flightPhaseError(err)
+	}
+
 	cmd := models.NewCommand(models.CommandTypeHold)
 
-	if err := h.simulator.SubmitCommand(c.Request.Context(), cmd); err != nil {
-		h.logger.Error("Failed to submit command", "error", err)
-		if errors.Is(err, models.ErrCommandQueueFull) {
-			c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
-				Error: models.ErrorDetail{
-					Code:    "QUEUE_FULL",
-					Message: "Command queue is full, please retry",
-				},
-			})
-		} else {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-				Error: models.ErrorDetail{
-					Code:    "INTERNAL_ERROR",
-					Message: "Failed to submit hold command",
-				},
-			})
-		}
-		return
+	if err := h.simulator.SubmitCommand(ctx, cmd); err != nil {
+		return models.CommandResponse{}, h.submitError(err, "Failed to submit hold command")
 	}
 
 	// Get current position for response
-	state, err := h.simulator.GetState(c.Request.Context())
+	state, err := h.simulator.GetState(ctx)
 	if err != nil {
 		h.logger.Warn("Failed to get state for hold response", "error", err)
 	}
@@ -287,9 +486,431 @@ func (h *CommandHandler) Hold(c *gin.Context) {
 		response.OrbitRadiusM = 0 // Simple hold, no orbit
 	}
 
+	return response, nil
+}
+
+// RTHRequest is the optional body of POST /command/rth.
+type RTHRequest struct {
+	Alt *float64 `json:"alt,omitempty"` // return altitude, meters MSL
+}
+
+// RTH handles POST /command/rth
+func (h *CommandHandler) RTH(c *gin.Context) {
+	var req RTHRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			h.logger.Warn("Invalid request", "error", err)
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: models.ErrorDetail{
+					Code:    "INVALID_REQUEST",
+					Message: err.Error(),
+				},
+			})
+			return
+		}
+	}
+
+	response, cmdErr := h.rth(c.Request.Context(), req)
+	if cmdErr != nil {
+		writeCommandError(c, cmdErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// rth validates a return-to-home request, checks it against the flight
+// phase and submits it to the simulator.
+func (h *CommandHandler) rth(ctx context.Context, req RTHRequest) (models.CommandResponse, *CommandError) {
+	if req.Alt != nil && *req.Alt < 0 {
+		cmdErr := newCommandError(http.StatusBadRequest, "INVALID_ALTITUDE",
+			fmt.Sprintf("%s: %f", models.ErrInvalidAltitude, *req.Alt))
+		cmdErr.Response.Error.Field = "alt"
+		return models.CommandResponse{}, cmdErr
+	}
+
+	if err := h.checkFlightPhase(ctx, true); err != nil {
+		return models.CommandResponse{}, h.flightPhaseError(err)
+	}
+
+	cmd := models.NewCommand(models.CommandTypeRTH)
+	cmd.RTH = &models.RTHCommand{Altitude: req.Alt}
+
+	if err := h.simulator.SubmitCommand(ctx, cmd); err != nil {
+		return models.CommandResponse{}, h.submitError(err, "Failed to submit return-to-home command")
+	}
+
+	home := h.simulator.GetHome()
+	return models.CommandResponse{
+		Status:    "accepted",
+		CommandID: cmd.ID,
+		Message:   "Return-to-home command accepted",
+		Target:    &home,
+	}, nil
+}
+
+// RunwayRequest describes a runway in takeoff and land requests.
+type RunwayRequest struct {
+	Lat       float64  `json:"lat" binding:"required"`
+	Lon       float64  `json:"lon" binding:"required"`
+	Elevation *float64 `json:"elevation,omitempty"` // meters MSL (default: terrain elevation)
+	Heading   *float64 `json:"heading" binding:"required"`
+}
+
+// TakeoffRequest represents the request body for takeoff command.
+type TakeoffRequest struct {
+	Runway *RunwayRequest `json:"runway,omitempty"`
+	Alt    float64        `json:"alt" binding:"required"` // climb-out altitude, meters MSL
+	Speed  *float64       `json:"speed,omitempty"`
+}
+
+// LandRequest represents the optional request body for land command.
+type LandRequest struct {
+	Runway     *RunwayRequest `json:"runway,omitempty"`
+	GlideSlope float64        `json:"glide_slope,omitempty"` // degrees
+}
+
+// Takeoff handles POST /command/takeoff
+func (h *CommandHandler) Takeoff(c *gin.Context) {
+	var req TakeoffRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Invalid request", "error", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "INVALID_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	response, cmdErr := h.takeoff(c.Request.Context(), req)
+	if cmdErr != nil {
+		writeCommandError(c, cmdErr)
+		return
+	}
+
 	c.JSON(http.StatusOK, response)
 }
 
+// takeoff validates a takeoff request, checks that the aircraft is on the
+// ground and submits it to the simulator.
+func (h *CommandHandler) takeoff(ctx context.Context, req TakeoffRequest) (models.CommandResponse, *CommandError) {
+	cmd := models.NewCommand(models.CommandTypeTakeoff)
+	cmd.Takeoff = &models.TakeoffCommand{
+		Runway:   h.toRunway(req.Runway),
+		Altitude: req.Alt,
+		Speed:    req.Speed,
+	}
+
+	if err := validation.ValidateTakeoffCommand(cmd.Takeoff, h.maxSpeed); err != nil {
+		h.logger.Warn("Validation failed", "error", err)
+		return models.CommandResponse{}, newCommandError(http.StatusBadRequest, getErrorCode(err), err.Error())
+	}
+
+	// Takeoff requires a parked or landed aircraft
+	if err := h.checkFlightPhase(ctx, false); err != nil {
+		return models.CommandResponse{}, h.flightPhaseError(err)
+	}
+
+	if err := h.simulator.SubmitCommand(ctx, cmd); err != nil {
+		return models.CommandResponse{}, h.submitError(err, "Failed to submit takeoff command")
+	}
+
+	return models.CommandResponse{
+		Status:    "accepted",
+		CommandID: cmd.ID,
+		Message:   "Takeoff command accepted",
+	}, nil
+}
+
+// Land handles POST /command/land
+func (h *CommandHandler) Land(c *gin.Context) {
+	var req LandRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			h.logger.Warn("Invalid request", "error", err)
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: models.ErrorDetail{
+					Code:    "INVALID_REQUEST",
+					Message: err.Error(),
+				},
+			})
+			return
+		}
+	}
+
+	response, cmdErr := h.land(c.Request.Context(), req)
+	if cmdErr != nil {
+		writeCommandError(c, cmdErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// land validates a land request and submits it to the simulator.
+func (h *CommandHandler) land(ctx context.Context, req LandRequest) (models.CommandResponse, *CommandError) {
+	cmd := models.NewCommand(models.CommandTypeLand)
+	cmd.Land = &models.LandCommand{
+		Runway:     h.toRunway(req.Runway),
+		GlideSlope: req.GlideSlope,
+	}
+
+	if err := validation.ValidateLandCommand(cmd.Land); err != nil {
+		h.logger.Warn("Validation failed", "error", err)
+		return models.CommandResponse{}, newCommandError(http.StatusBadRequest, getErrorCode(err), err.Error())
+	}
+
+	if err := h.simulator.SubmitCommand(ctx, cmd); err != nil {
+		return models.CommandResponse{}, h.submitError(err, "Failed to submit land command")
+	}
+
+	response := models.CommandResponse{
+		Status:    "accepted",
+		CommandID: cmd.ID,
+		Message:   "Land command accepted",
+	}
+	if cmd.Land.Runway != nil {
+		response.Target = &cmd.Land.Runway.Threshold
+	}
+
+	return response, nil
+}
+
+// toRunway converts a runway request to a runway, defaulting the threshold
+// elevation to the terrain elevation.
+func (h *CommandHandler) toRunway(req *RunwayRequest) *models.Runway {
+	if req == nil {
+		return nil
+	}
+
+	elevation := h.simulator.GetEnvironment().GetTerrain().GetAltitude(req.Lat, req.Lon)
+	if req.Elevation != nil {
+		elevation = *req.Elevation
+	}
+
+	return &models.Runway{
+		Threshold: models.Position{
+			Latitude:  req.Lat,
+			Longitude: req.Lon,
+			Altitude:  elevation,
+		},
+		Heading: *req.Heading,
+	}
+}
+
+// checkFlightPhase checks that the aircraft is airborne (or on the ground
+// when airborne is false).
+func (h *CommandHandler) checkFlightPhase(ctx context.Context, airborne bool) error {
+	state, err := h.simulator.GetState(ctx)
+	if err != nil {
+		return err
+	}
+
+	if state.Phase.OnGround() == airborne {
+		return fmt.Errorf("%w: aircraft is %s", models.ErrInvalidFlightPhase, state.Phase)
+	}
+	return nil
+}
+
+// flightPhaseError builds the error for a failed flight phase check.
+func (h *CommandHandler) flightPhaseError(err error) *CommandError {
+	if errors.Is(err, models.ErrInvalidFlightPhase) {
+		h.logger.Warn("Command rejected in current flight phase", "error", err)
+		return newCommandError(http.StatusConflict, "INVALID_FLIGHT_PHASE", err.Error())
+	}
+
+	h.logger.Error("Failed to check flight phase", "error", err)
+	return newCommandError(http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to check flight phase")
+}
+
+// submitError builds the error for a command that could not be submitted
+// to the simulator.
+func (h *CommandHandler) submitError(err error, message string) *CommandError {
+	if errors.Is(err, models.ErrReplayActive) {
+		return &CommandError{Status: http.StatusConflict, Response: replayActiveResponse()}
+	}
+
+	h.logger.Error("Failed to submit command", "error", err)
+	if errors.Is(err, models.ErrCommandQueueFull) {
+		return newCommandError(http.StatusServiceUnavailable, "QUEUE_FULL", "Command queue is full, please retry")
+	}
+
+	return newCommandError(http.StatusInternalServerError, "INTERNAL_ERROR", message)
+}
+
+// Heartbeat handles POST /heartbeat
+func (h *CommandHandler) Heartbeat(c *gin.Context) {
+	if err := h.simulator.Heartbeat(c.Request.Context()); err != nil {
+		if errors.Is(err, models.ErrReplayActive) {
+			writeReplayActive(c)
+			return
+		}
+		h.logger.Error("Failed to record heartbeat", "error", err)
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "SIMULATOR_NOT_RUNNING",
+				Message: "Failed to record heartbeat",
+			},
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// checkGeofencePath checks the path from the current aircraft position
+// through the given waypoints against the geofences.
+func (h *CommandHandler) checkGeofencePath(ctx context.Context, waypoints []models.Waypoint) error {
+	geofences := h.simulator.GetGeofences()
+	if geofences.Count() == 0 {
+		return nil
+	}
+
+	state, err := h.simulator.GetState(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Geofence floors and ceilings are MSL, so convert AGL targets
+	terrain := h.simulator.GetEnvironment().GetTerrain()
+	path := make([]models.Position, 0, len(waypoints)+1)
+	path = append(path, state.Position)
+	for _, wp := range waypoints {
+		pos := wp.Position
+		if wp.AltitudeRef == models.AltitudeReferenceAGL {
+			pos.Altitude += terrain.GetAltitude(pos.Latitude, pos.Longitude)
+		}
+		path = append(path, pos)
+	}
+
+	return geofences.CheckPath(path)
+}
+
+// geofencePathError builds the error for a failed geofence path check.
+func (h *CommandHandler) geofencePathError(err error) *CommandError {
+	if errors.Is(err, models.ErrGeofenceConflict) {
+		h.logger.Warn("Geofence conflict", "error", err)
+		return &CommandError{Status: http.StatusUnprocessableEntity, Response: geofenceConflictResponse(err)}
+	}
+
+	h.logger.Error("Failed to check geofences", "error", err)
+	return newCommandError(http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to check geofences")
+}
+
+// CommandRequest is a command for any command type, with its parameters
+// given under the command type as in the body of the command endpoint. It is
+// how the WebSocket and gRPC APIs submit commands.
+type CommandRequest struct {
+	Type       string             `json:"type"` // goto, trajectory, hold, stop, rth, takeoff or land
+	GoTo       *GoToRequest       `json:"goto,omitempty"`
+	Trajectory *TrajectoryRequest `json:"trajectory,omitempty"`
+	RTH        *RTHRequest        `json:"rth,omitempty"`
+	Takeoff    *TakeoffRequest    `json:"takeoff,omitempty"`
+	Land       *LandRequest       `json:"land,omitempty"`
+}
+
+// Submit validates and submits a command with the same checks as its
+// command endpoint.
+func (h *CommandHandler) Submit(ctx context.Context, req CommandRequest) (models.CommandResponse, *CommandError) {
+	switch models.CommandType(req.Type) {
+	case models.CommandTypeGoTo:
+		if req.GoTo == nil {
+			return models.CommandResponse{}, missingParams(req.Type)
+		}
+		if cmdErr := validateParams(req.GoTo); cmdErr != nil {
+			return models.CommandResponse{}, cmdErr
+		}
+		return h.goTo(ctx, *req.GoTo)
+	case models.CommandTypeTrajectory:
+		if req.Trajectory == nil {
+			return models.CommandResponse{}, missingParams(req.Type)
+		}
+		if cmdErr := validateParams(req.Trajectory); cmdErr != nil {
+			return models.CommandResponse{}, cmdErr
+		}
+		return h.trajectory(ctx, *req.Trajectory)
+	case models.CommandTypeHold:
+		return h.hold(ctx)
+	case models.CommandTypeStop:
+		return h.stop(ctx)
+	case models.CommandTypeRTH:
+		// Parameters are optional, as the request body of the endpoint
+		var params RTHRequest
+		if req.RTH != nil {
+			params = *req.RTH
+		}
+		return h.rth(ctx, params)
+	case models.CommandTypeTakeoff:
+		if req.Takeoff == nil {
+			return models.CommandResponse{}, missingParams(req.Type)
+		}
+		if cmdErr := validateParams(req.Takeoff); cmdErr != nil {
+			return models.CommandResponse{}, cmdErr
+		}
+		return h.takeoff(ctx, *req.Takeoff)
+	case models.CommandTypeLand:
+		var params LandRequest
+		if req.Land != nil {
+			if cmdErr := validateParams(req.Land); cmdErr != nil {
+				return models.CommandResponse{}, cmdErr
+			}
+			params = *req.Land
+		}
+		return h.land(ctx, params)
+	default:
+		return models.CommandResponse{}, newCommandError(http.StatusBadRequest, "INVALID_REQUEST",
+			fmt.Sprintf("unknown command type %q, want goto, trajectory, hold, stop, rth, takeoff or land", req.Type))
+	}
+}
+
+// missingParams is the error for a command request without its parameters.
+func missingParams(commandType string) *CommandError {
+	return newCommandError(http.StatusBadRequest, "INVALID_REQUEST",
+		fmt.Sprintf("%s parameters are required", commandType))
+}
+
+// validateParams checks command parameters like ShouldBindJSON checks
+// request bodies.
+func validateParams(params any) *CommandError {
+	if err := binding.Validator.ValidateStruct(params); err != nil {
+		return newCommandError(http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+	}
+	return nil
+}
+
+// CommandError is a rejected command request: the HTTP status and body of
+// its error response. The command logic is shared with the WebSocket and
+// gRPC APIs, so it returns errors instead of writing responses.
+type CommandError struct {
+	Status   int
+	Response models.ErrorResponse
+}
+
+// Error implements error.
+func (e *CommandError) Error() string {
+	return e.Response.Error.Message
+}
+
+// newCommandError creates a command error.
+func newCommandError(status int, code, message string) *CommandError {
+	return &CommandError{
+		Status: status,
+		Response: models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    code,
+				Message: message,
+			},
+		},
+	}
+}
+
+// writeCommandError writes the response for a rejected command request.
+func writeCommandError(c *gin.Context, cmdErr *CommandError) {
+	c.JSON(cmdErr.Status, cmdErr.Response)
+}
+
 // getErrorCode extracts error code from error.
 func getErrorCode(err error) string {
 	switch {
@@ -303,9 +924,49 @@ func getErrorCode(err error) string {
 		return "INVALID_SPEED"
 	case errors.Is(err, models.ErrEmptyWaypoints):
 		return "EMPTY_WAYPOINTS"
+	case errors.Is(err, models.ErrInvalidWaypoint):
+		return "INVALID_WAYPOINT"
 	case errors.Is(err, models.ErrSpeedExceedsMax):
 		return "SPEED_EXCEEDS_MAX"
+	case errors.Is(err, models.ErrInvalidVerticalSpeed):
+		return "INVALID_VERTICAL_SPEED"
+	case errors.Is(err, models.ErrInvalidAltitudeReference):
+		return "INVALID_ALTITUDE_REFERENCE"
+	case errors.Is(err, models.ErrInvalidGeofence):
+		return "INVALID_GEOFENCE"
+	case errors.Is(err, models.ErrInvalidHeading):
+		return "INVALID_HEADING"
+	case errors.Is(err, models.ErrInvalidGlideSlope):
+		return "INVALID_GLIDE_SLOPE"
+	case errors.Is(err, models.ErrUnknownFix):
+		return "UNKNOWN_FIX"
+	case errors.Is(err, models.ErrInvalidCallbackURL):
+		return "INVALID_CALLBACK_URL"
+	case errors.Is(err, models.ErrMissingCallbackSecret):
+		return "MISSING_CALLBACK_SECRET"
 	default:
 		return "VALIDATION_ERROR"
 	}
 }
+
+// terrainConflictResponse builds the 422 response body for a terrain conflict.
+func terrainConflictResponse(err error) models.ErrorResponse {
+	response := models.ErrorResponse{
+		Error: models.ErrorDetail{
+			Code:    "TERRAIN_CONFLICT",
+			Message: "Target altitude below terrain safety margin",
+		},
+	}
+
+	var conflict *validation.TerrainConflictError
+	if errors.As(err, &conflict) {
+		response.Error.Details = map[string]interface{}{
+			"terrain_altitude":   conflict.TerrainAltitude,
+			"safety_margin":      conflict.SafetyMargin,
+			"minimum_altitude":   conflict.MinimumAltitude,
+			"requested_altitude": conflict.RequestedAltitude,
+		}
+	}
+
+	return response
+}
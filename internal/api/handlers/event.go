@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/simulator"
+)
+
+// EventHandler handles simulation event requests.
+type EventHandler struct {
+	simulator *simulator.Simulator
+	logger    *slog.Logger
+}
+
+// NewEventHandler creates a new event handler.
+func NewEventHandler(sim *simulator.Simulator, logger *slog.Logger) *EventHandler {
+	return &EventHandler{
+		simulator: sim,
+		logger:    logger,
+	}
+}
+
+// List handles GET /events
+// Returns the recent simulation events, oldest first. With ?since=<id> only
+// the events after that ID are returned, so clients can poll with the
+// last_id of the previous response.
+func (h *EventHandler) List(c *gin.Context) {
+	var since uint64
+	if text := c.Query("since"); text != "" {
+		parsed, err := strconv.ParseUint(text, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: models.ErrorDetail{
+					Code:    "INVALID_REQUEST",
+					Message: "since query parameter must be an event ID",
+				},
+			})
+			return
+		}
+		since = parsed
+	}
+
+	events, lastID := h.simulator.GetEvents().Since(since)
+	c.JSON(http.StatusOK, gin.H{
+		"events":  events,
+		"last_id": lastID,
+	})
+}
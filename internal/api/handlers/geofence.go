@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/api/validation"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/geofence"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/simulator"
+)
+
+// GeofenceHandler handles geofence CRUD requests.
+type GeofenceHandler struct {
+	simulator *simulator.Simulator
+	geofences *geofence.Manager
+	logger    *slog.Logger
+}
+
+// NewGeofenceHandler creates a new geofence handler.
+func NewGeofenceHandler(sim *simulator.Simulator, logger *slog.Logger) *GeofenceHandler {
+	return &GeofenceHandler{
+		simulator: sim,
+		geofences: sim.GetGeofences(),
+		logger:    logger,
+	}
+}
+
+// Create handles POST /geofences
+func (h *GeofenceHandler) Create(c *gin.Context) {
+	if !h.enabled(c) {
+		return
+	}
+
+	zone, ok := h.bindGeofence(c)
+	if !ok {
+		return
+	}
+
+	zone, err := h.simulator.AddGeofence(c.Request.Context(), zone)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	h.logger.Info("Geofence created", "zone_id", zone.ID, "name", zone.Name, "mode", zone.Mode)
+
+	c.JSON(http.StatusCreated, zone)
+}
+
+// List handles GET /geofences
+func (h *GeofenceHandler) List(c *gin.Context) {
+	if !h.enabled(c) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"geofences": h.geofences.List(),
+	})
+}
+
+// Get handles GET /geofences/:id
+func (h *GeofenceHandler) Get(c *gin.Context) {
+	if !h.enabled(c) {
+		return
+	}
+
+	zone, err := h.geofences.Get(c.Param("id"))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, zone)
+}
+
+// Update handles PUT /geofences/:id
+func (h *GeofenceHandler) Update(c *gin.Context) {
+	if !h.enabled(c) {
+		return
+	}
+
+	zone, ok := h.bindGeofence(c)
+	if !ok {
+		return
+	}
+
+	zone, err := h.simulator.UpdateGeofence(c.Request.Context(), c.Param("id"), zone)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	h.logger.Info("Geofence updated", "zone_id", zone.ID)
+
+	c.JSON(http.StatusOK, zone)
+}
+
+// Delete handles DELETE /geofences/:id
+func (h *GeofenceHandler) Delete(c *gin.Context) {
+	if !h.enabled(c) {
+		return
+	}
+
+	id := c.Param("id")
+	if err := h.simulator.DeleteGeofence(c.Request.Context(), id); err != nil {
+		h.writeError(c, err)
+		return
+	}
+	h.logger.Info("Geofence deleted", "zone_id", id)
+
+	c.Status(http.StatusNoContent)
+}
+
+// bindGeofence parses and validates a geofence request body.
+func (h *GeofenceHandler) bindGeofence(c *gin.Context) (models.Geofence, bool) {
+	var zone models.Geofence
+	if err := c.ShouldBindJSON(&zone); err != nil {
+		h.logger.Warn("Invalid request", "error", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "INVALID_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return zone, false
+	}
+
+	if err := validation.ValidateGeofence(&zone); err != nil {
+		h.logger.Warn("Validation failed", "error", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    getErrorCode(err),
+				Message: err.Error(),
+			},
+		})
+		return zone, false
+	}
+
+	return zone, true
+}
+
+// enabled writes a 503 response if geofencing is disabled.
+func (h *GeofenceHandler) enabled(c *gin.Context) bool {
+	if h.geofences == nil {
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "GEOFENCE_DISABLED",
+				Message: "Geofencing is disabled in the simulator configuration",
+			},
+		})
+		return false
+	}
+	return true
+}
+
+// writeError writes the response for a failed geofence operation.
+func (h *GeofenceHandler) writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, models.ErrGeofenceNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "GEOFENCE_NOT_FOUND",
+				Message: err.Error(),
+			},
+		})
+	case errors.Is(err, models.ErrReplayActive):
+		writeReplayActive(c)
+	case errors.Is(err, models.ErrTimeout):
+		h.logger.Error("Geofence operation failed", "error", err)
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "SIMULATOR_NOT_RUNNING",
+				Message: "Simulator did not apply the geofence change",
+			},
+		})
+	default:
+		h.logger.Error("Geofence operation failed", "error", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "INTERNAL_ERROR",
+				Message: "Geofence operation failed",
+			},
+		})
+	}
+}
+
+// geofenceConflictResponse builds the 422 response body for a path that
+// crosses a geofence.
+func geofenceConflictResponse(err error) models.ErrorResponse {
+	response := models.ErrorResponse{
+		Error: models.ErrorDetail{
+			Code:    "GEOFENCE_CONFLICT",
+			Message: err.Error(),
+		},
+	}
+
+	var conflict *geofence.ConflictError
+	if errors.As(err, &conflict) {
+		response.Error.Details = map[string]interface{}{
+			"zone_id":       conflict.ZoneID,
+			"zone_name":     conflict.Name,
+			"mode":          conflict.Mode,
+			"segment_index": conflict.SegmentIndex,
+			"position":      conflict.Position,
+		}
+	}
+
+	return response
+}
@@ -1,20 +1,34 @@
 package handlers
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"log/slog"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
 	"github.com/meiron-tzhori/Flight-Simulator/internal/config"
 	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/montecarlo"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/navdata"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/pubsub"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/scenario"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/session"
 	"github.com/meiron-tzhori/Flight-Simulator/internal/simulator"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/snapshot"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/webhook"
+	"github.com/ugorji/go/codec"
 )
 
 // Helper function to create a test simulator
@@ -41,6 +55,8 @@ func createTestSimulator(t *testing.T) *simulator.Simulator {
 		PositionTolerance: 10.0,
 		HeadingChangeRate: 30.0,
 		SpeedChangeRate:   50.0,
+		Geofence:          config.GeofenceConfig{Enabled: true},
+		Recorder:          config.RecorderConfig{Enabled: true, MaxSamples: 100},
 	}
 	
 	envCfg := config.EnvironmentConfig{
@@ -70,18 +86,77 @@ func setupRouter(sim *simulator.Simulator) *gin.Engine {
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelWarn}))
 	
 	// Setup handlers with correct constructors
-	cmdHandler := NewCommandHandler(sim, logger, 250.0)
-	stateHandler := NewStateHandler(sim, logger)
+	nav := navdata.NewDatabase()
+	nav.Add(navdata.Fix{Ident: "LLBG", Name: "Ben Gurion International Airport", Type: "large_airport", Latitude: 32.0114, Longitude: 34.8867, Elevation: 41, IATA: "TLV"})
+	nav.Add(navdata.Fix{Ident: "DAFNA", Type: navdata.TypeFix, Latitude: 32.1, Longitude: 34.0})
+	
+	webhooks := webhook.NewManager(config.WebhookConfig{}, sim, logger)
+	cmdHandler := NewCommandHandler(sim, nav, webhooks, logger, 250.0)
+	simCfg := config.SimulationConfig{TickRateHz: 10.0, MaxSpeed: 250.0, DefaultSpeed: 100.0, MaxClimbRate: 15.0, MaxDescentRate: 10.0, PositionTolerance: 10.0, HeadingChangeRate: 30.0, SpeedChangeRate: 50.0}
+	stateHandler := NewStateHandler(sim, logger, simCfg)
 	healthHandler := NewHealthHandler(sim, logger, 10.0) // tickRate = 10 Hz
-	streamHandler := NewStreamHandler(sim, logger)
+	streamHandler := NewStreamHandler(sim, logger, config.StreamingConfig{UpdateRateHz: 10, BufferSize: 10, MaxClients: 2}, 10.0)
+	eventHandler := NewEventHandler(sim, logger)
+	webSocketHandler := NewWebSocketHandler(cmdHandler, sim, logger, config.StreamingConfig{UpdateRateHz: 10}, 10.0)
+	geofenceHandler := NewGeofenceHandler(sim, logger)
+	airspaceHandler := NewAirspaceHandler(sim, logger)
+	navdataHandler := NewNavdataHandler(nav, logger)
+	missionHandler := NewMissionHandler(cmdHandler, sim, logger, 100.0)
+	trackHandler := NewTrackHandler(sim, logger)
+	replayHandler := NewReplayHandler(sim, logger)
+	snapshots, _ := snapshot.NewStore("")
+	snapshotHandler := NewSnapshotHandler(sim, snapshots, logger)
+	scenarioHandler := NewScenarioHandler(scenario.NewManager(sim, simCfg, logger), logger)
+	monteCarloHandler := NewMonteCarloHandler(montecarlo.NewManager(simCfg, config.EnvironmentConfig{}, sim.GetGeofences(), logger), logger)
 	
 	router.GET("/health", healthHandler.Health)
 	router.GET("/state", stateHandler.GetState)
+	router.PUT("/state", stateHandler.SetState)
 	router.POST("/command/goto", cmdHandler.GoTo)
 	router.POST("/command/trajectory", cmdHandler.Trajectory)
+	router.POST("/command/trajectory/import", cmdHandler.ImportTrajectory)
+	router.POST("/command/route", cmdHandler.Route)
 	router.POST("/command/stop", cmdHandler.Stop)
 	router.POST("/command/hold", cmdHandler.Hold)
+	router.POST("/command/rth", cmdHandler.RTH)
+	router.POST("/command/takeoff", cmdHandler.Takeoff)
+	router.POST("/command/land", cmdHandler.Land)
+	router.POST("/heartbeat", cmdHandler.Heartbeat)
 	router.GET("/stream", streamHandler.Stream)
+	router.GET("/events", eventHandler.List)
+	router.GET("/ws", webSocketHandler.Connect)
+	router.POST("/geofences", geofenceHandler.Create)
+	router.GET("/geofences", geofenceHandler.List)
+	router.DELETE("/geofences/:id", geofenceHandler.Delete)
+	router.GET("/airspace", airspaceHandler.Query)
+	router.POST("/airspace/import", airspaceHandler.Import)
+	router.GET("/navdata/search", navdataHandler.Search)
+	router.POST("/mission/import", missionHandler.Import)
+	router.GET("/mission/export", missionHandler.Export)
+	router.GET("/track", trackHandler.Export)
+	router.GET("/replay", replayHandler.Status)
+	router.POST("/replay/seek", replayHandler.Seek)
+	router.POST("/replay/speed", replayHandler.Speed)
+	router.POST("/snapshots", snapshotHandler.Create)
+	router.GET("/snapshots", snapshotHandler.List)
+	router.GET("/snapshots/:id", snapshotHandler.Get)
+	router.DELETE("/snapshots/:id", snapshotHandler.Delete)
+	router.POST("/snapshots/:id/restore", snapshotHandler.Restore)
+	router.POST("/scenarios/run", scenarioHandler.Run)
+	router.GET("/scenarios", scenarioHandler.List)
+	router.GET("/scenarios/:id", scenarioHandler.Get)
+	router.POST("/scenarios/:id/abort", scenarioHandler.Abort)
+	router.POST("/montecarlo/run", monteCarloHandler.Run)
+	router.GET("/montecarlo", monteCarloHandler.List)
+	router.GET("/montecarlo/:id", monteCarloHandler.Get)
+	router.POST("/montecarlo/:id/abort", monteCarloHandler.Abort)
+	webhookHandler := NewWebhookHandler(webhooks, logger)
+	router.POST("/webhooks", webhookHandler.Create)
+	router.GET("/webhooks", webhookHandler.List)
+	router.GET("/webhooks/deliveries", webhookHandler.Deliveries)
+	router.GET("/webhooks/:id", webhookHandler.Get)
+	router.DELETE("/webhooks/:id", webhookHandler.Delete)
+	router.GET("/admin/subscribers", NewAdminHandler(sim, logger).Subscribers)
 	
 	return router
 }
@@ -253,6 +328,328 @@ func TestHoldCommandHandler(t *testing.T) {
 	}
 }
 
+func TestRTHCommandHandler(t *testing.T) {
+	sim := createTestSimulator(t)
+	router := setupRouter(sim)
+	
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{name: "no body", body: "", wantStatus: http.StatusOK},
+		{name: "with altitude", body: `{"alt": 1500}`, wantStatus: http.StatusOK},
+		{name: "negative altitude", body: `{"alt": -10}`, wantStatus: http.StatusBadRequest},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/command/rth", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			
+			router.ServeHTTP(w, req)
+			
+			if w.Code != tt.wantStatus {
+				t.Errorf("RTH() status = %d, want %d, body: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestFlightPhaseCommands(t *testing.T) {
+	sim := createTestSimulator(t)
+	router := setupRouter(sim)
+	
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{name: "takeoff while airborne", path: "/command/takeoff", body: `{"alt": 500}`, wantStatus: http.StatusConflict},
+		{name: "takeoff without altitude", path: "/command/takeoff", body: `{}`, wantStatus: http.StatusBadRequest},
+		{name: "land with invalid heading", path: "/command/land", body: `{"runway": {"lat": 32.1, "lon": 34.0, "heading": 400}}`, wantStatus: http.StatusBadRequest},
+		{name: "land on runway", path: "/command/land", body: `{"runway": {"lat": 32.1, "lon": 34.0, "elevation": 20, "heading": 0}, "glide_slope": 3}`, wantStatus: http.StatusOK},
+		{name: "land in place", path: "/command/land", body: "", wantStatus: http.StatusOK},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			
+			router.ServeHTTP(w, req)
+			
+			if w.Code != tt.wantStatus {
+				t.Errorf("%s status = %d, want %d, body: %s", tt.path, w.Code, tt.wantStatus, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestFixReferences(t *testing.T) {
+	sim := createTestSimulator(t)
+	router := setupRouter(sim)
+	
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "goto fix", path: "/command/goto", body: `{"fix": "dafna", "alt": 1000}`, wantStatus: http.StatusOK},
+		{name: "goto unknown fix", path: "/command/goto", body: `{"fix": "NOWHERE", "alt": 1000}`, wantStatus: http.StatusBadRequest, wantCode: "UNKNOWN_FIX"},
+		{name: "goto without coordinates or fix", path: "/command/goto", body: `{"alt": 1000}`, wantStatus: http.StatusBadRequest, wantCode: "INVALID_REQUEST"},
+		{name: "trajectory mixing fixes and coordinates", path: "/command/trajectory", body: `{"waypoints": [{"fix": "DAFNA", "alt": 1000}, {"lat": 32.05, "lon": 34.1, "alt": 1200}]}`, wantStatus: http.StatusOK},
+		{name: "trajectory unknown fix", path: "/command/trajectory", body: `{"waypoints": [{"fix": "NOWHERE", "alt": 1000}]}`, wantStatus: http.StatusBadRequest, wantCode: "UNKNOWN_FIX"},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			
+			router.ServeHTTP(w, req)
+			
+			if w.Code != tt.wantStatus {
+				t.Fatalf("%s status = %d, want %d, body: %s", tt.path, w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantCode != "" {
+				var response models.ErrorResponse
+				json.Unmarshal(w.Body.Bytes(), &response)
+				if response.Error.Code != tt.wantCode {
+					t.Errorf("error code = %s, want %s", response.Error.Code, tt.wantCode)
+				}
+			}
+		})
+	}
+}
+
+func TestRouteCommandHandler(t *testing.T) {
+	sim := createTestSimulator(t)
+	router := setupRouter(sim)
+	
+	body := `{"route": "N0200F050 DAFNA DCT 3205N03410E"}`
+	req := httptest.NewRequest(http.MethodPost, "/command/route", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	
+	router.ServeHTTP(w, req)
+	
+	if w.Code != http.StatusOK {
+		t.Fatalf("Route() status = %d, want %d, body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	
+	var response models.CommandResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if response.WaypointCount != 2 || response.Waypoints[0].Fix != "DAFNA" {
+		t.Errorf("Route() response = %+v, want 2 waypoints starting at DAFNA", response)
+	}
+	
+	body = `{"route": "N0200F050 DAFNA DCT BOGUS"}`
+	req = httptest.NewRequest(http.MethodPost, "/command/route", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	
+	router.ServeHTTP(w, req)
+	
+	var errResponse models.ErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &errResponse)
+	if w.Code != http.StatusBadRequest || errResponse.Error.Code != "INVALID_ROUTE" || errResponse.Error.Field != "route[3]" {
+		t.Errorf("Route() with unknown fix = %d %+v, want 400 INVALID_ROUTE at route[3]", w.Code, errResponse.Error)
+	}
+}
+
+func TestImportTrajectoryHandler(t *testing.T) {
+	sim := createTestSimulator(t)
+	router := setupRouter(sim)
+	
+	kml := `<kml><Placemark><LineString><coordinates>34.0,32.0 34.01,32.01 34.02,32.0</coordinates></LineString></Placemark></kml>`
+	req := httptest.NewRequest(http.MethodPost, "/command/trajectory/import?alt=1000&speed=80&max_waypoints=2", bytes.NewBufferString(kml))
+	w := httptest.NewRecorder()
+	
+	router.ServeHTTP(w, req)
+	
+	if w.Code != http.StatusOK {
+		t.Fatalf("ImportTrajectory() status = %d, want %d, body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	
+	var response models.CommandResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if response.WaypointCount != 2 || response.Waypoints[1].Position.Altitude != 1000 || *response.Waypoints[1].Speed != 80 {
+		t.Errorf("ImportTrajectory() response = %+v, want 2 waypoints at 1000m and 80 m/s", response)
+	}
+	
+	tests := []struct {
+		name  string
+		query string
+		body  string
+		code  string
+	}{
+		{"no altitude", "", kml, "INVALID_TRACK"},
+		{"bad max_waypoints", "?alt=1000&max_waypoints=1", kml, "INVALID_REQUEST"},
+		{"speed too high", "?alt=1000&speed=900", kml, "SPEED_EXCEEDS_MAX"},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/command/trajectory/import"+tt.query, bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			
+			var errResponse models.ErrorResponse
+			json.Unmarshal(w.Body.Bytes(), &errResponse)
+			if w.Code != http.StatusBadRequest || errResponse.Error.Code != tt.code {
+				t.Errorf("ImportTrajectory() = %d %+v, want 400 %s", w.Code, errResponse.Error, tt.code)
+			}
+		})
+	}
+}
+
+func TestMissionImportExport(t *testing.T) {
+	sim := createTestSimulator(t)
+	router := setupRouter(sim)
+	
+	req := httptest.NewRequest(http.MethodGet, "/mission/export", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Export() without mission status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	
+	wpl := "QGC WPL 110\n" +
+		"0\t1\t0\t16\t0\t0\t0\t0\t32.0\t34.0\t0\t1\n" +
+		"1\t0\t3\t16\t0\t0\t0\t0\t32.01\t34.0\t1000\t1\n" +
+		"2\t0\t0\t21\t0\t0\t0\t0\t32.02\t34.0\t0\t1\n"
+	req = httptest.NewRequest(http.MethodPost, "/mission/import", bytes.NewBufferString(wpl))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	
+	if w.Code != http.StatusOK {
+		t.Fatalf("Import() status = %d, want %d, body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	
+	var response models.CommandResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if response.WaypointCount != 1 || len(response.Warnings) != 1 {
+		t.Errorf("Import() response = %+v, want 1 waypoint and a NAV_LAND warning", response)
+	}
+	
+	req = httptest.NewRequest(http.MethodGet, "/mission/export?format=wpl", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Body.String(), "QGC WPL 110") {
+		t.Errorf("Export(wpl) = %d %q, want a QGC WPL 110 file", w.Code, w.Body.String())
+	}
+	
+	req = httptest.NewRequest(http.MethodPost, "/mission/import?format=plan", bytes.NewBufferString(`{"fileType": "Plan"}`))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	
+	var errResponse models.ErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &errResponse)
+	if w.Code != http.StatusBadRequest || errResponse.Error.Code != "INVALID_MISSION" {
+		t.Errorf("Import() of empty plan = %d %+v, want 400 INVALID_MISSION", w.Code, errResponse.Error)
+	}
+}
+
+func TestTrackHandler(t *testing.T) {
+	sim := createTestSimulator(t)
+	router := setupRouter(sim)
+	
+	time.Sleep(300 * time.Millisecond) // Let the recorder collect a few ticks
+	
+	req := httptest.NewRequest(http.MethodGet, "/track?format=kml", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	
+	if w.Code != http.StatusOK {
+		t.Fatalf("Export() status = %d, want %d, body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/vnd.google-earth.kml+xml" {
+		t.Errorf("Export(kml) Content-Type = %q", ct)
+	}
+	if !strings.Contains(w.Body.String(), "<gx:Track>") {
+		t.Errorf("Export(kml) body has no gx:Track: %s", w.Body.String())
+	}
+	
+	tests := []struct {
+		name   string
+		query  string
+		status int
+		code   string
+	}{
+		{"unknown format", "?format=shp", http.StatusBadRequest, "INVALID_FORMAT"},
+		{"invalid from", "?from=yesterday", http.StatusBadRequest, "INVALID_REQUEST"},
+		{"empty range", "?from=3600", http.StatusNotFound, "NO_TRACK_DATA"},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/track"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			
+			var errResponse models.ErrorResponse
+			json.Unmarshal(w.Body.Bytes(), &errResponse)
+			if w.Code != tt.status || errResponse.Error.Code != tt.code {
+				t.Errorf("Export() = %d %+v, want %d %s", w.Code, errResponse.Error, tt.status, tt.code)
+			}
+		})
+	}
+}
+
+func TestNavdataSearchHandler(t *testing.T) {
+	sim := createTestSimulator(t)
+	router := setupRouter(sim)
+	
+	req := httptest.NewRequest(http.MethodGet, "/navdata/search?q=tlv", nil)
+	w := httptest.NewRecorder()
+	
+	router.ServeHTTP(w, req)
+	
+	if w.Code != http.StatusOK {
+		t.Fatalf("Search() status = %d, want %d", w.Code, http.StatusOK)
+	}
+	
+	var response struct {
+		Results []navdata.Fix `json:"results"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if len(response.Results) != 1 || response.Results[0].Ident != "LLBG" {
+		t.Errorf("Search(tlv) = %+v, want LLBG", response.Results)
+	}
+	
+	req = httptest.NewRequest(http.MethodGet, "/navdata/search", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Search() without q status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestHeartbeatHandler(t *testing.T) {
+	sim := createTestSimulator(t)
+	router := setupRouter(sim)
+	
+	req := httptest.NewRequest(http.MethodPost, "/heartbeat", nil)
+	w := httptest.NewRecorder()
+	
+	router.ServeHTTP(w, req)
+	
+	if w.Code != http.StatusNoContent {
+		t.Errorf("Heartbeat() status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+}
+
 func TestCommandSequence(t *testing.T) {
 	sim := createTestSimulator(t)
 	router := setupRouter(sim)
@@ -304,6 +701,1091 @@ func TestCommandSequence(t *testing.T) {
 	}
 }
 
-func ptr(f float64) *float64 {
-	return &f
+func TestGeofenceHandler_BlocksGoTo(t *testing.T) {
+	sim := createTestSimulator(t)
+	router := setupRouter(sim)
+	
+	// Create an exclusion zone between the aircraft and the target
+	zone := models.Geofence{
+		Name:  "restricted",
+		Shape: models.GeofenceShapeCircle,
+		Mode:  models.GeofenceModeExclusion,
+		Center: &models.Coordinate{Latitude: 32.05, Longitude: 34.0},
+		RadiusM: 2000,
+	}
+	body, _ := json.Marshal(zone)
+	req := httptest.NewRequest(http.MethodPost, "/geofences", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Create geofence status = %d, want %d. Body: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+	
+	var created models.Geofence
+	if err := json.NewDecoder(w.Body).Decode(&created); err != nil {
+		t.Fatalf("Failed to decode geofence: %v", err)
+	}
+	
+	// Go-to straight through the zone is rejected
+	body, _ = json.Marshal(GoToRequest{Lat: 32.1, Lon: 34.0, Alt: 1000.0})
+	req = httptest.NewRequest(http.MethodPost, "/command/goto", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("GoTo() through zone status = %d, want %d. Body: %s", w.Code, http.StatusUnprocessableEntity, w.Body.String())
+	}
+	
+	// After deleting the zone the same go-to is accepted
+	req = httptest.NewRequest(http.MethodDelete, "/geofences/"+created.ID, nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("Delete geofence status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	
+	req = httptest.NewRequest(http.MethodPost, "/command/goto", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	
+	if w.Code != http.StatusOK {
+		t.Errorf("GoTo() after delete status = %d, want %d. Body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+}
+
+func TestAirspaceHandler_ImportAndQuery(t *testing.T) {
+	sim := createTestSimulator(t)
+	router := setupRouter(sim)
+	
+	openAir := "AC CTR\nAN TEST CTR\nAL SFC\nAH 3500ft\nV X=32:00:00 N 034:00:00 E\nDC 2\n"
+	req := httptest.NewRequest(http.MethodPost, "/airspace/import?format=openair", bytes.NewReader([]byte(openAir)))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Import() status = %d, want %d. Body: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+	
+	var imported AirspaceImportResponse
+	if err := json.NewDecoder(w.Body).Decode(&imported); err != nil {
+		t.Fatalf("Failed to decode import response: %v", err)
+	}
+	if imported.Imported != 1 {
+		t.Errorf("Import() imported = %d, want 1", imported.Imported)
+	}
+	
+	tests := []struct {
+		name      string
+		query     string
+		wantCode  int
+		wantZones int
+	}{
+		{name: "Inside zone", query: "lat=32.01&lon=34.0", wantCode: http.StatusOK, wantZones: 1},
+		{name: "Above ceiling", query: "lat=32.01&lon=34.0&alt=5000", wantCode: http.StatusOK, wantZones: 0},
+		{name: "Outside zone", query: "lat=33.0&lon=34.0", wantCode: http.StatusOK, wantZones: 0},
+		{name: "Missing longitude", query: "lat=32.0", wantCode: http.StatusBadRequest},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/airspace?"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			
+			if w.Code != tt.wantCode {
+				t.Fatalf("Query() status = %d, want %d", w.Code, tt.wantCode)
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			
+			var response struct {
+				Zones []models.Geofence `json:"zones"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode query response: %v", err)
+			}
+			if len(response.Zones) != tt.wantZones {
+				t.Errorf("Query() returned %d zones, want %d", len(response.Zones), tt.wantZones)
+			}
+		})
+	}
+}
+
+func TestReplayHandler(t *testing.T) {
+	// A live simulator has no replay controls
+	router := setupRouter(createTestSimulator(t))
+	
+	req := httptest.NewRequest(http.MethodGet, "/replay", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	
+	var errResponse models.ErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &errResponse)
+	if w.Code != http.StatusServiceUnavailable || errResponse.Error.Code != "REPLAY_DISABLED" {
+		t.Errorf("Status() on live simulator = %d %+v, want 503 REPLAY_DISABLED", w.Code, errResponse.Error)
+	}
+	
+	// Replay a five-second session with one go-to command, paused
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	live, err := simulator.New(config.SimulationConfig{
+		TickRateHz:        10.0,
+		CommandQueueSize:  10,
+		InitialPosition:   config.PositionConfig{Latitude: 32.0, Longitude: 34.0, Altitude: 1000.0},
+		DefaultSpeed:      100.0,
+		MaxSpeed:          250.0,
+		MaxClimbRate:      15.0,
+		MaxDescentRate:    10.0,
+		PositionTolerance: 10.0,
+		HeadingChangeRate: 30.0,
+		SpeedChangeRate:   50.0,
+	}, config.EnvironmentConfig{}, logger)
+	if err != nil {
+		t.Fatalf("Failed to create simulator: %v", err)
+	}
+	
+	cmd := models.NewCommand(models.CommandTypeGoTo)
+	cmd.GoTo = &models.GoToCommand{Target: models.Position{Latitude: 32.1, Longitude: 34.0, Altitude: 1000}}
+	log := &session.Log{
+		Header: live.SessionHeader(),
+		Entries: []session.Entry{
+			{Tick: 0, Type: session.EntryCommand, Command: cmd},
+			{Tick: 50, Type: session.EntryEnd},
+		},
+	}
+	replay, err := simulator.NewReplay(log, 0, logger)
+	if err != nil {
+		t.Fatalf("NewReplay() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go replay.Run(ctx)
+	router = setupRouter(replay)
+	
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+		code   string
+	}{
+		{"status", http.MethodGet, "/replay", "", http.StatusOK, ""},
+		{"seek", http.MethodPost, "/replay/seek", `{"sim_time": 2}`, http.StatusOK, ""},
+		{"negative seek", http.MethodPost, "/replay/seek", `{"sim_time": -1}`, http.StatusBadRequest, "INVALID_REQUEST"},
+		{"missing seek time", http.MethodPost, "/replay/seek", `{}`, http.StatusBadRequest, "INVALID_REQUEST"},
+		{"speed too high", http.MethodPost, "/replay/speed", `{"speed": 1000}`, http.StatusBadRequest, "INVALID_REQUEST"},
+		{"command during replay", http.MethodPost, "/command/hold", `{}`, http.StatusConflict, "REPLAY_ACTIVE"},
+		{"heartbeat during replay", http.MethodPost, "/heartbeat", "", http.StatusConflict, "REPLAY_ACTIVE"},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			
+			var errResponse models.ErrorResponse
+			json.Unmarshal(w.Body.Bytes(), &errResponse)
+			if w.Code != tt.status || errResponse.Error.Code != tt.code {
+				t.Errorf("%s %s = %d %+v, want %d %s", tt.method, tt.path, w.Code, errResponse.Error, tt.status, tt.code)
+			}
+		})
+	}
+	
+	req = httptest.NewRequest(http.MethodGet, "/replay", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	
+	var status models.ReplayStatus
+	if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+		t.Fatalf("Failed to parse status: %v", err)
+	}
+	if math.Abs(status.SimTime-2) > 1e-9 || status.Duration == nil || math.Abs(*status.Duration-5) > 1e-9 {
+		t.Errorf("Status() after seek = %+v, want sim_time 2 of 5", status)
+	}
+	if status.Speed != 0 || status.EntriesApplied != 1 || status.Entries != 2 {
+		t.Errorf("Status() after seek = %+v, want paused with 1 of 2 entries applied", status)
+	}
+	
+	state, err := replay.GetState(ctx)
+	if err != nil {
+		t.Fatalf("GetState() error = %v", err)
+	}
+	if state.Position.Latitude <= 32.0 || state.Velocity.GroundSpeed == 0 {
+		t.Errorf("state after seek = %+v, want the aircraft moving north", state.Position)
+	}
+}
+
+func TestSetStateHandler(t *testing.T) {
+	sim := createTestSimulator(t)
+	router := setupRouter(sim)
+	
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		code   string
+	}{
+		{"position only", `{"lat": 31.5, "lon": 34.5, "alt": 500}`, http.StatusOK, ""},
+		{"full state", `{"lat": 31.5, "lon": 34.5, "alt": 0, "heading": 270, "ground_speed": 0, "vertical_speed": 0, "clear_command": true}`, http.StatusOK, ""},
+		{"missing altitude", `{"lat": 31.5, "lon": 34.5}`, http.StatusBadRequest, "INVALID_REQUEST"},
+		{"invalid latitude", `{"lat": 91, "lon": 34.5, "alt": 500}`, http.StatusBadRequest, "INVALID_LATITUDE"},
+		{"invalid heading", `{"lat": 31.5, "lon": 34.5, "alt": 500, "heading": 400}`, http.StatusBadRequest, "INVALID_HEADING"},
+		{"speed exceeds max", `{"lat": 31.5, "lon": 34.5, "alt": 500, "ground_speed": 300}`, http.StatusBadRequest, "SPEED_EXCEEDS_MAX"},
+		{"descent exceeds max", `{"lat": 31.5, "lon": 34.5, "alt": 500, "vertical_speed": -20}`, http.StatusBadRequest, "INVALID_VERTICAL_SPEED"},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/state", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			
+			if w.Code != tt.status {
+				t.Fatalf("SetState() status = %d, want %d, body: %s", w.Code, tt.status, w.Body.String())
+			}
+			if tt.code != "" {
+				var errResponse models.ErrorResponse
+				json.Unmarshal(w.Body.Bytes(), &errResponse)
+				if errResponse.Error.Code != tt.code {
+					t.Errorf("SetState() code = %s, want %s", errResponse.Error.Code, tt.code)
+				}
+			}
+		})
+	}
+	
+	// The last accepted update parked the aircraft
+	state, err := sim.GetState(context.Background())
+	if err != nil {
+		t.Fatalf("GetState() error = %v", err)
+	}
+	if state.Position.Latitude != 31.5 || state.Heading != 270 || state.Phase != models.FlightPhaseParked {
+		t.Errorf("state = %+v, want parked at 31.5, 34.5 heading 270", state)
+	}
+}
+
+func TestSnapshotHandler(t *testing.T) {
+	sim := createTestSimulator(t)
+	router := setupRouter(sim)
+	
+	req := httptest.NewRequest(http.MethodPost, "/snapshots", strings.NewReader(`{"name": "start"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Create() status = %d, want %d, body: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+	var snap models.Snapshot
+	if err := json.Unmarshal(w.Body.Bytes(), &snap); err != nil {
+		t.Fatalf("Failed to parse snapshot: %v", err)
+	}
+	if snap.ID == "" || snap.Name != "start" || snap.State.Position.Altitude != 1000.0 {
+		t.Errorf("Create() = %+v, want a named snapshot of the initial state", snap)
+	}
+	
+	// Fly away, then restore
+	cmd := models.NewCommand(models.CommandTypeGoTo)
+	cmd.GoTo = &models.GoToCommand{Target: models.Position{Latitude: 32.1, Longitude: 34.0, Altitude: 1200}}
+	if err := sim.SubmitCommand(context.Background(), cmd); err != nil {
+		t.Fatalf("SubmitCommand() error = %v", err)
+	}
+	time.Sleep(300 * time.Millisecond)
+	
+	req = httptest.NewRequest(http.MethodPost, "/snapshots/"+snap.ID+"/restore", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	
+	if w.Code != http.StatusOK {
+		t.Fatalf("Restore() status = %d, want %d, body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var restored RestoreResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &restored); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if restored.State.Position != snap.State.Position || restored.State.ActiveCommand != nil {
+		t.Errorf("Restore() state = %+v, want the snapshot position without a command", restored.State)
+	}
+	
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+		code   string
+	}{
+		{"list", http.MethodGet, "/snapshots", http.StatusOK, ""},
+		{"get", http.MethodGet, "/snapshots/" + snap.ID, http.StatusOK, ""},
+		{"restore unknown", http.MethodPost, "/snapshots/unknown/restore", http.StatusNotFound, "SNAPSHOT_NOT_FOUND"},
+		{"delete", http.MethodDelete, "/snapshots/" + snap.ID, http.StatusNoContent, ""},
+		{"get deleted", http.MethodGet, "/snapshots/" + snap.ID, http.StatusNotFound, "SNAPSHOT_NOT_FOUND"},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			
+			var errResponse models.ErrorResponse
+			json.Unmarshal(w.Body.Bytes(), &errResponse)
+			if w.Code != tt.status || errResponse.Error.Code != tt.code {
+				t.Errorf("%s %s = %d %+v, want %d %s", tt.method, tt.path, w.Code, errResponse.Error, tt.status, tt.code)
+			}
+		})
+	}
+}
+
+func TestScenarioHandler(t *testing.T) {
+	sim := createTestSimulator(t)
+	router := setupRouter(sim)
+	
+	body := `
+name: hold-altitude
+duration: 0.5s
+events:
+  - at: 0s
+    command: {type: hold}
+assertions:
+  - always: altitude > 900
+  - at_end: command == hold
+`
+	req := httptest.NewRequest(http.MethodPost, "/scenarios/run", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/yaml")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("Run() status = %d, want %d, body: %s", w.Code, http.StatusAccepted, w.Body.String())
+	}
+	var started models.ScenarioStatus
+	if err := json.Unmarshal(w.Body.Bytes(), &started); err != nil {
+		t.Fatalf("Failed to parse status: %v", err)
+	}
+	if started.ID == "" || started.State != models.ScenarioRunning || len(started.Assertions) != 2 {
+		t.Errorf("Run() = %+v, want a running scenario with 2 assertions", started)
+	}
+	
+	// Poll until the run finishes
+	var status models.ScenarioStatus
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(50 * time.Millisecond) {
+		req = httptest.NewRequest(http.MethodGet, "/scenarios/"+started.ID, nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+			t.Fatalf("Failed to parse status: %v", err)
+		}
+		if status.State != models.ScenarioRunning {
+			break
+		}
+	}
+	if status.State != models.ScenarioPassed || status.EventsFired != 1 {
+		t.Errorf("Get() = %+v, want passed with 1 event", status)
+	}
+	
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+		code   string
+	}{
+		{"list", http.MethodGet, "/scenarios", "", http.StatusOK, ""},
+		{"invalid yaml", http.MethodPost, "/scenarios/run", "name: [", http.StatusBadRequest, "INVALID_SCENARIO"},
+		{"unknown variable", http.MethodPost, "/scenarios/run", "assertions: [{always: fuel > 0}]", http.StatusBadRequest, "INVALID_SCENARIO"},
+		{"get unknown", http.MethodGet, "/scenarios/unknown", "", http.StatusNotFound, "SCENARIO_NOT_FOUND"},
+		{"abort unknown", http.MethodPost, "/scenarios/unknown/abort", "", http.StatusNotFound, "SCENARIO_NOT_FOUND"},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			
+			var errResponse models.ErrorResponse
+			json.Unmarshal(w.Body.Bytes(), &errResponse)
+			if w.Code != tt.status || errResponse.Error.Code != tt.code {
+				t.Errorf("%s %s = %d %+v, want %d %s", tt.method, tt.path, w.Code, errResponse.Error, tt.status, tt.code)
+			}
+		})
+	}
+}
+
+func TestMonteCarloHandler(t *testing.T) {
+	sim := createTestSimulator(t)
+	router := setupRouter(sim)
+	
+	body := `
+runs: 5
+seed: 1
+dispersion: {position: 100, performance: 0.05}
+scenario:
+  name: short-leg
+  duration: 2m
+  until: command == none
+  events:
+    - at: 0s
+      command: {type: goto, goto: {target: {latitude: 32.01, longitude: 34.0, altitude: 1000}, speed: 100}}
+`
+	req := httptest.NewRequest(http.MethodPost, "/montecarlo/run", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("Run() status = %d, want %d, body: %s", w.Code, http.StatusAccepted, w.Body.String())
+	}
+	var started models.StudyStatus
+	if err := json.Unmarshal(w.Body.Bytes(), &started); err != nil {
+		t.Fatalf("Failed to parse status: %v", err)
+	}
+	if started.ID == "" || started.Runs != 5 {
+		t.Errorf("Run() = %+v, want a study of 5 runs", started)
+	}
+	
+	// Poll until the study finishes
+	var status models.StudyStatus
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(20 * time.Millisecond) {
+		req = httptest.NewRequest(http.MethodGet, "/montecarlo/"+started.ID, nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+			t.Fatalf("Failed to parse status: %v", err)
+		}
+		if status.State != models.StudyRunning {
+			break
+		}
+	}
+	if status.State != models.StudyCompleted || status.Completed != 5 || status.Report == nil {
+		t.Fatalf("Get() = %+v, want a completed study", status)
+	}
+	if report := status.Report; report.Arrived != 5 || report.ArrivalTime == nil || len(report.Results) != 5 {
+		t.Errorf("report = %+v, want 5 arrivals", report)
+	}
+	
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+		code   string
+	}{
+		{"list", http.MethodGet, "/montecarlo", "", http.StatusOK, ""},
+		{"invalid yaml", http.MethodPost, "/montecarlo/run", "runs: [", http.StatusBadRequest, "INVALID_STUDY"},
+		{"no scenario", http.MethodPost, "/montecarlo/run", "runs: 10", http.StatusBadRequest, "INVALID_STUDY"},
+		{"wind without environment", http.MethodPost, "/montecarlo/run", "dispersion: {wind_speed: 2}\nscenario: {name: x}", http.StatusBadRequest, "INVALID_STUDY"},
+		{"get unknown", http.MethodGet, "/montecarlo/unknown", "", http.StatusNotFound, "STUDY_NOT_FOUND"},
+		{"abort unknown", http.MethodPost, "/montecarlo/unknown/abort", "", http.StatusNotFound, "STUDY_NOT_FOUND"},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			
+			var errResponse models.ErrorResponse
+			json.Unmarshal(w.Body.Bytes(), &errResponse)
+			if w.Code != tt.status || errResponse.Error.Code != tt.code {
+				t.Errorf("%s %s = %d %+v, want %d %s", tt.method, tt.path, w.Code, errResponse.Error, tt.status, tt.code)
+			}
+		})
+	}
+}
+
+func TestEventHandler(t *testing.T) {
+	sim := createTestSimulator(t)
+	router := setupRouter(sim)
+	
+	getEvents := func(query string) (int, []models.Event, uint64) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/events"+query, nil))
+		var response struct {
+			Events []models.Event `json:"events"`
+			LastID uint64         `json:"last_id"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w.Code, response.Events, response.LastID
+	}
+	submit := func(payload GoToRequest) string {
+		body, _ := json.Marshal(payload)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/command/goto", bytes.NewReader(body)))
+		var response models.CommandResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return response.CommandID
+	}
+	
+	// A far target superseded by a near one, which completes
+	far := submit(GoToRequest{Lat: 32.5, Lon: 34.0, Alt: 1000.0})
+	near := submit(GoToRequest{Lat: 32.0003, Lon: 34.0, Alt: 1000.0})
+	time.Sleep(1500 * time.Millisecond)
+	
+	status, events, lastID := getEvents("")
+	if status != http.StatusOK {
+		t.Fatalf("GET /events status = %d", status)
+	}
+	var got []string
+	for _, event := range events {
+		if event.Command != nil {
+			got = append(got, string(event.Type)+" "+event.Command.ID)
+		}
+	}
+	want := []string{
+		"command_accepted " + far, "command_started " + far,
+		"command_accepted " + near, "command_superseded " + far, "command_started " + near,
+		"command_completed " + near,
+	}
+	if strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Errorf("command events = %v, want %v", got, want)
+	}
+	if len(events) == 0 || events[len(events)-1].ID != lastID {
+		t.Errorf("last_id = %d, want the ID of the last event", lastID)
+	}
+	
+	// Polling from the last ID returns only newer events
+	if _, events, _ := getEvents("?since=" + strconv.FormatUint(lastID, 10)); len(events) != 0 {
+		t.Errorf("GET /events?since=%d = %d events, want none", lastID, len(events))
+	}
+	if status, _, _ := getEvents("?since=abc"); status != http.StatusBadRequest {
+		t.Errorf("GET /events?since=abc status = %d, want %d", status, http.StatusBadRequest)
+	}
+}
+
+func TestWebSocketHandler(t *testing.T) {
+	sim := createTestSimulator(t)
+	server := httptest.NewServer(setupRouter(sim))
+	defer server.Close()
+	
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", nil)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer conn.Close()
+	
+	requests := []string{
+		`{"id":"r1","type":"goto","goto":{"lat":32.1,"lon":34.1,"alt":1500}}`,
+		`{"id":"r2","type":"goto","goto":{"lat":95,"lon":34.1,"alt":1500}}`,
+		`{"id":"r3","type":"trajectory"}`,
+		`{"id":"r4","type":"takeoff"}`,
+		`{"id":"r5","type":"hold"}`,
+		`not json`,
+	}
+	for _, request := range requests {
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(request)); err != nil {
+			t.Fatalf("WriteMessage() error = %v", err)
+		}
+	}
+	
+	// Replies come in request order, interleaved with states and events
+	var replies []string
+	var states, accepted int
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for len(replies) < len(requests) || states == 0 || accepted < 2 {
+		var msg WSMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			t.Fatalf("ReadJSON() error = %v (replies %v, states %d, accepted %d)", err, replies, states, accepted)
+		}
+		switch msg.Type {
+		case "ack":
+			if msg.Response == nil || msg.Response.CommandID == "" {
+				t.Errorf("ack %s = %+v, want a command response", msg.ID, msg)
+			}
+			replies = append(replies, msg.ID+" ack")
+		case "error":
+			replies = append(replies, msg.ID+" "+msg.Error.Code)
+		case "state":
+			states++
+		case "event":
+			if msg.Event.Type == models.EventCommandAccepted {
+				accepted++
+			}
+		}
+	}
+	
+	want := []string{"r1 ack", "r2 INVALID_LATITUDE", "r3 INVALID_REQUEST", "r4 INVALID_REQUEST", "r5 ack", " MALFORMED_JSON"}
+	if strings.Join(replies, ", ") != strings.Join(want, ", ") {
+		t.Errorf("replies = %v, want %v", replies, want)
+	}
+}
+
+func TestWebSocketHandlerRate(t *testing.T) {
+	sim := createTestSimulator(t)
+	server := httptest.NewServer(setupRouter(sim))
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws"
+	
+	for _, query := range []string{"rate_hz=0", "rate_hz=11", "rate_hz=fast"} {
+		_, resp, err := websocket.DefaultDialer.Dial(url+"?"+query, nil)
+		if err == nil || resp == nil || resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Dial(/ws?%s) = %v, want 400 INVALID_REQUEST", query, err)
+		}
+	}
+	
+	conn, _, err := websocket.DefaultDialer.Dial(url+"?rate_hz=2", nil)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer conn.Close()
+	
+	// States come at the client's rate, not the tick rate
+	var received []time.Time
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for len(received) < 3 {
+		var msg WSMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			t.Fatalf("ReadJSON() error = %v", err)
+		}
+		if msg.Type == "state" {
+			received = append(received, time.Now())
+		}
+	}
+	if gap := received[2].Sub(received[1]); gap < 400*time.Millisecond {
+		t.Errorf("state interval = %v, want about 500ms at 2 Hz", gap)
+	}
+}
+
+func ptr(f float64) *float64 {
+	return &f
+}
+
+func TestStreamHandlerOptions(t *testing.T) {
+	sim := createTestSimulator(t)
+	server := httptest.NewServer(setupRouter(sim))
+	defer server.Close()
+	
+	t.Run("invalid options", func(t *testing.T) {
+		for _, query := range []string{"rate_hz=0", "rate_hz=11", "rate_hz=fast", "fields=position,altitude", "format=xml", "topics=aircraft/**/state"} {
+			resp, err := http.Get(server.URL + "/stream?" + query)
+			if err != nil {
+				t.Fatalf("GET /stream?%s error = %v", query, err)
+			}
+			var errResp models.ErrorResponse
+			json.NewDecoder(resp.Body).Decode(&errResp)
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusBadRequest || errResp.Error.Code != "INVALID_REQUEST" {
+				t.Errorf("GET /stream?%s = %d %s, want 400 INVALID_REQUEST", query, resp.StatusCode, errResp.Error.Code)
+			}
+		}
+	})
+	
+	t.Run("fields and format", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/stream?rate_hz=2&fields=position,heading&format=msgpack", nil)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("GET /stream error = %v", err)
+		}
+		defer resp.Body.Close()
+		
+		// Read two states to check the field selection and the rate
+		reader := bufio.NewReader(resp.Body)
+		var times []time.Time
+		event := ""
+		for len(times) < 2 {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				t.Fatalf("ReadString() error = %v", err)
+			}
+			line = strings.TrimSpace(line)
+			if name, ok := strings.CutPrefix(line, "event: "); ok {
+				event = name
+				continue
+			}
+			data, ok := strings.CutPrefix(line, "data: ")
+			if !ok || event != "state" {
+				continue
+			}
+			times = append(times, time.Now())
+			
+			raw, err := base64.StdEncoding.DecodeString(data)
+			if err != nil {
+				t.Fatalf("state data is not base64: %v", err)
+			}
+			var state map[string]any
+			if err := codec.NewDecoderBytes(raw, &codec.MsgpackHandle{}).Decode(&state); err != nil {
+				t.Fatalf("state data is not msgpack: %v", err)
+			}
+			if len(state) != 2 || state["position"] == nil || state["heading"] == nil {
+				t.Errorf("state = %v, want position and heading only", state)
+			}
+		}
+		
+		if gap := times[1].Sub(times[0]); gap < 400*time.Millisecond {
+			t.Errorf("states %v apart, want about 500ms at 2 Hz", gap)
+		}
+	})
+	
+	t.Run("topics", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/stream?topics=aircraft/*/events/command_accepted", nil)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("GET /stream error = %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("status = %d, want 200", resp.StatusCode)
+		}
+		
+		reader := bufio.NewReader(resp.Body)
+		readSSE(t, reader) // retry
+		readSSE(t, reader) // connected
+		
+		cmdResp, err := http.Post(server.URL+"/command/goto", "application/json", strings.NewReader(`{"lat": 32.1, "lon": 34.1, "alt": 1500}`))
+		if err != nil {
+			t.Fatalf("POST /command/goto error = %v", err)
+		}
+		cmdResp.Body.Close()
+		
+		// States are not on the topics, so the command's event comes first
+		if msg := readSSE(t, reader); msg.event != string(models.EventCommandAccepted) {
+			t.Errorf("first message = %+v, want command_accepted", msg)
+		}
+	})
+	
+	t.Run("max clients", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		
+		// The test router allows 2 clients; a new router does not count the
+		// clients of the other subtests
+		server := httptest.NewServer(setupRouter(sim))
+		defer server.Close()
+		for i := 0; i < 2; i++ {
+			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/stream", nil)
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("GET /stream error = %v", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("client %d status = %d, want 200", i+1, resp.StatusCode)
+			}
+		}
+		
+		resp, err := http.Get(server.URL + "/stream")
+		if err != nil {
+			t.Fatalf("GET /stream error = %v", err)
+		}
+		defer resp.Body.Close()
+		var errResp models.ErrorResponse
+		json.NewDecoder(resp.Body).Decode(&errResp)
+		if resp.StatusCode != http.StatusServiceUnavailable || errResp.Error.Code != "TOO_MANY_CLIENTS" {
+			t.Errorf("third client = %d %s, want 503 TOO_MANY_CLIENTS", resp.StatusCode, errResp.Error.Code)
+		}
+	})
+}
+
+// sseMessage is a message read from an SSE stream.
+type sseMessage struct {
+	id    string
+	event string
+	data  string
+	retry string
+}
+
+// readSSE reads the next SSE message, skipping comments.
+func readSSE(t *testing.T, reader *bufio.Reader) sseMessage {
+	t.Helper()
+	
+	var msg sseMessage
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("ReadString() error = %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			if msg != (sseMessage{}) {
+				return msg
+			}
+			continue
+		}
+		field, value, _ := strings.Cut(line, ": ")
+		switch field {
+		case "id":
+			msg.id = value
+		case "event":
+			msg.event = value
+		case "data":
+			msg.data = value
+		case "retry":
+			msg.retry = value
+		}
+	}
+}
+
+func TestStreamHandlerResume(t *testing.T) {
+	sim := createTestSimulator(t)
+	server := httptest.NewServer(setupRouter(sim))
+	defer server.Close()
+	
+	connect := func(lastEventID string) (*bufio.Reader, func()) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/stream", nil)
+		if lastEventID != "" {
+			req.Header.Set("Last-Event-ID", lastEventID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("GET /stream error = %v", err)
+		}
+		return bufio.NewReader(resp.Body), func() {
+			resp.Body.Close()
+			cancel()
+		}
+	}
+	
+	reader, disconnect := connect("")
+	if msg := readSSE(t, reader); msg.retry != "3000" {
+		t.Errorf("first message = %+v, want retry: 3000", msg)
+	}
+	if msg := readSSE(t, reader); msg.event != "connected" || msg.id != "" {
+		t.Errorf("second message = %+v, want connected without an id", msg)
+	}
+	state := readSSE(t, reader)
+	if state.event != "state" || state.id == "" {
+		t.Fatalf("third message = %+v, want a state with an id", state)
+	}
+	disconnect()
+	
+	// Events published while disconnected are replayed on reconnect
+	resp, err := http.Post(server.URL+"/command/goto", "application/json", strings.NewReader(`{"lat": 32.1, "lon": 34.1, "alt": 1500}`))
+	if err != nil {
+		t.Fatalf("POST /command/goto error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("goto status = %d, want 200", resp.StatusCode)
+	}
+	time.Sleep(200 * time.Millisecond)
+	
+	reader, disconnect = connect(state.id)
+	defer disconnect()
+	readSSE(t, reader) // retry
+	var connected map[string]any
+	if msg := readSSE(t, reader); json.Unmarshal([]byte(msg.data), &connected) != nil || connected["resumed"] != true {
+		t.Errorf("connected = %+v, want resumed", msg)
+	}
+	
+	lastID, _ := strconv.ParseUint(state.id, 10, 64)
+	var events []string
+	for len(events) < 2 {
+		msg := readSSE(t, reader)
+		id, err := strconv.ParseUint(msg.id, 10, 64)
+		if err != nil || id <= lastID {
+			t.Fatalf("message %+v after id %d, want a greater id", msg, lastID)
+		}
+		lastID = id
+		if msg.event != "state" {
+			events = append(events, msg.event)
+		}
+	}
+	
+	want := []string{string(models.EventCommandAccepted), string(models.EventCommandStarted)}
+	if strings.Join(events, ",") != strings.Join(want, ",") {
+		t.Errorf("replayed events = %v, want %v", events, want)
+	}
+}
+
+func TestStreamHandlerMissedEvents(t *testing.T) {
+	sim := createTestSimulator(t)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	handler := NewStreamHandler(sim, logger, config.StreamingConfig{UpdateRateHz: 10}, 10.0)
+	opts := streamOptions{format: streamFormatJSON, topics: []string{simulator.EventsPattern(sim.AircraftID())}}
+	
+	// A client that falls more than the kept history behind misses events
+	sequence := sim.GetPublisher().Sequence()
+	cursor := sequence.Last()
+	topic := simulator.EventTopic(sim.AircraftID(), models.EventWaypointReached)
+	for i := 0; i <= 1000; i++ {
+		sim.GetEventBroker().Publish(topic, models.Event{Type: models.EventWaypointReached})
+	}
+	
+	items, missed := handler.readItems(cursor, sequence.Last(), opts)
+	if !missed || len(items) != 1000 {
+		t.Fatalf("readItems() = %d items, missed %v, want 1000 and missed", len(items), missed)
+	}
+	
+	cursor = items[0].seq
+	if _, missed := handler.readItems(cursor, sequence.Last(), opts); missed {
+		t.Errorf("readItems() after the oldest kept event missed = true, want false")
+	}
+}
+
+func TestAdminSubscribers(t *testing.T) {
+	sim := createTestSimulator(t)
+	router := setupRouter(sim)
+	
+	publisher := sim.GetPublisher()
+	_, err := publisher.Subscribe("slow-client", pubsub.SubscribeOptions{
+		Topics:     []string{simulator.StateTopic(sim.AircraftID())},
+		Kind:       "grpc",
+		BufferSize: 1,
+		Policy:     pubsub.PolicyDropOldest,
+	})
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	defer publisher.Unsubscribe("slow-client")
+	
+	// The subscriber reads nothing while the simulator publishes
+	time.Sleep(500 * time.Millisecond)
+	
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/subscribers", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /admin/subscribers status = %d, want %d", w.Code, http.StatusOK)
+	}
+	
+	var response struct {
+		Subscribers []models.SubscriberStats `json:"subscribers"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	var found *models.SubscriberStats
+	for i := range response.Subscribers {
+		if response.Subscribers[i].ID == "slow-client" {
+			found = &response.Subscribers[i]
+		}
+	}
+	if found == nil {
+		t.Fatalf("subscribers = %+v, want slow-client", response.Subscribers)
+	}
+	if found.Kind != "grpc" || found.Policy != "drop_oldest" || found.BufferSize != 1 || len(found.Topics) != 1 {
+		t.Errorf("slow-client = %+v, want its subscription options", found)
+	}
+	if found.Queued != 1 || found.Dropped == 0 || found.LastDropAt == nil {
+		t.Errorf("slow-client = %+v, want a full buffer and dropped states", found)
+	}
+}
+
+func TestWebhookHandler(t *testing.T) {
+	sim := createTestSimulator(t)
+	router := setupRouter(sim)
+	
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "valid", body: `{"url": "http://localhost:9000/hook", "event_types": ["command_completed"], "secret": "s3cret"}`, wantStatus: http.StatusCreated},
+		{name: "missing secret", body: `{"url": "http://localhost:9000/hook"}`, wantStatus: http.StatusBadRequest, wantCode: "INVALID_REQUEST"},
+		{name: "relative url", body: `{"url": "/hook", "secret": "s3cret"}`, wantStatus: http.StatusBadRequest, wantCode: "INVALID_WEBHOOK"},
+		{name: "unknown event type", body: `{"url": "http://localhost:9000/hook", "event_types": ["landed"], "secret": "s3cret"}`, wantStatus: http.StatusBadRequest, wantCode: "INVALID_WEBHOOK"},
+	}
+	
+	var created models.Webhook
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Create webhook status = %d, want %d. Body: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantCode != "" {
+				var errResp models.ErrorResponse
+				json.NewDecoder(w.Body).Decode(&errResp)
+				if errResp.Error.Code != tt.wantCode {
+					t.Errorf("Error code = %s, want %s", errResp.Error.Code, tt.wantCode)
+				}
+				return
+			}
+			if strings.Contains(w.Body.String(), "s3cret") {
+				t.Errorf("Created webhook exposes its secret: %s", w.Body.String())
+			}
+			if err := json.NewDecoder(w.Body).Decode(&created); err != nil {
+				t.Fatalf("Failed to decode webhook: %v", err)
+			}
+		})
+	}
+	
+	req := httptest.NewRequest(http.MethodGet, "/webhooks/"+created.ID, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	
+	if w.Code != http.StatusOK {
+		t.Errorf("Get webhook status = %d, want %d", w.Code, http.StatusOK)
+	}
+	
+	req = httptest.NewRequest(http.MethodGet, "/webhooks/deliveries?webhook_id="+created.ID, nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"deliveries":[]`) {
+		t.Errorf("Deliveries status = %d, body = %s, want no deliveries", w.Code, w.Body.String())
+	}
+	
+	req = httptest.NewRequest(http.MethodDelete, "/webhooks/"+created.ID, nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("Delete webhook status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	
+	req = httptest.NewRequest(http.MethodGet, "/webhooks/"+created.ID, nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Get deleted webhook status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestCommandCallbackURL(t *testing.T) {
+	sim := createTestSimulator(t)
+	router := setupRouter(sim)
+	
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		wantStatus int
+		wantCode   string
+		wantField  string
+	}{
+		{name: "goto", path: "/command/goto", body: `{"lat": 32.1, "lon": 34.1, "alt": 1500, "callback_url": "http://localhost:9000/done", "callback_secret": "s"}`, wantStatus: http.StatusOK},
+		{name: "goto invalid", path: "/command/goto", body: `{"lat": 32.1, "lon": 34.1, "alt": 1500, "callback_url": "ftp://localhost/done", "callback_secret": "s"}`, wantStatus: http.StatusBadRequest, wantCode: "INVALID_CALLBACK_URL", wantField: "callback_url"},
+		{name: "goto unsigned", path: "/command/goto", body: `{"lat": 32.1, "lon": 34.1, "alt": 1500, "callback_url": "http://localhost:9000/done"}`, wantStatus: http.StatusBadRequest, wantCode: "MISSING_CALLBACK_SECRET", wantField: "callback_secret"},
+		{name: "trajectory invalid", path: "/command/trajectory", body: `{"waypoints": [{"lat": 32.1, "lon": 34.1, "alt": 1500}], "callback_url": "done"}`, wantStatus: http.StatusBadRequest, wantCode: "INVALID_CALLBACK_URL", wantField: "callback_url"},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Status = %d, want %d. Body: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus == http.StatusBadRequest {
+				var errResp models.ErrorResponse
+				json.NewDecoder(w.Body).Decode(&errResp)
+				if errResp.Error.Code != tt.wantCode || errResp.Error.Field != tt.wantField {
+					t.Errorf("Error = %+v, want %s on %s", errResp.Error, tt.wantCode, tt.wantField)
+				}
+			}
+		})
+	}
 }
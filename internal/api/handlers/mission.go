@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/flightplan"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/simulator"
+)
+
+// MissionHandler handles mission import and export requests.
+type MissionHandler struct {
+	commands     *CommandHandler
+	simulator    *simulator.Simulator
+	logger       *slog.Logger
+	defaultSpeed float64
+}
+
+// NewMissionHandler creates a new mission handler. Imported missions are
+// submitted through the command handler so they get the same checks as
+// trajectory commands.
+func NewMissionHandler(commands *CommandHandler, sim *simulator.Simulator, logger *slog.Logger, defaultSpeed float64) *MissionHandler {
+	return &MissionHandler{
+		commands:     commands,
+		simulator:    sim,
+		logger:       logger,
+		defaultSpeed: defaultSpeed,
+	}
+}
+
+// Import handles POST /mission/import[?format=plan|wpl]
+// The file is sent either as the raw request body or as a multipart "file" field.
+func (h *MissionHandler) Import(c *gin.Context) {
+	filename, data, err := readUpload(c)
+	if err != nil {
+		h.writeBadRequest(c, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	format := flightplan.MissionFormat(c.Query("format"))
+	if format == "" {
+		format = flightplan.DetectMissionFormat(data)
+	}
+
+	result, err := flightplan.ParseMission(data, format, flightplan.MissionOptions{
+		Home:  h.simulator.GetHome(),
+		Speed: h.defaultSpeed,
+	})
+	if err != nil {
+		h.logger.Warn("Mission import failed", "error", err)
+		h.writeBadRequest(c, "INVALID_MISSION", err.Error())
+		return
+	}
+
+	cmd := models.NewCommand(models.CommandTypeTrajectory)
+	cmd.Trajectory = result.Trajectory
+	if !h.commands.submitTrajectory(c, cmd) {
+		return
+	}
+
+	h.logger.Info("Mission imported",
+		"source", filename,
+		"format", format,
+		"waypoints", len(result.Trajectory.Waypoints),
+		"warnings", len(result.Warnings),
+	)
+
+	c.JSON(http.StatusOK, models.CommandResponse{
+		Status:        "accepted",
+		CommandID:     cmd.ID,
+		Message:       "Mission accepted",
+		WaypointCount: len(result.Trajectory.Waypoints),
+		Waypoints:     result.Trajectory.Waypoints,
+		Warnings:      result.Warnings,
+	})
+}
+
+// Export handles GET /mission/export[?format=plan|wpl]
+// Returns the most recent trajectory as a mission file.
+func (h *MissionHandler) Export(c *gin.Context) {
+	mission, err := h.simulator.GetMission(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to get mission", "error", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "INTERNAL_ERROR",
+				Message: "Failed to retrieve mission",
+			},
+		})
+		return
+	}
+	if mission == nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "NO_MISSION",
+				Message: "No trajectory has been flown yet",
+			},
+		})
+		return
+	}
+
+	home := h.simulator.GetHome()
+	switch flightplan.MissionFormat(c.DefaultQuery("format", string(flightplan.FormatPlan))) {
+	case flightplan.FormatPlan:
+		data, err := flightplan.ExportPlan(mission, home, h.defaultSpeed)
+		if err != nil {
+			h.logger.Error("Failed to export mission", "error", err)
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error: models.ErrorDetail{
+					Code:    "INTERNAL_ERROR",
+					Message: "Failed to export mission",
+				},
+			})
+			return
+		}
+		c.Header("Content-Disposition", `attachment; filename="mission.plan"`)
+		c.Data(http.StatusOK, "application/json", data)
+	case flightplan.FormatWPL:
+		c.Header("Content-Disposition", `attachment; filename="mission.waypoints"`)
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", flightplan.ExportWPL(mission, home))
+	default:
+		h.writeBadRequest(c, "INVALID_FORMAT", "format must be 'plan' or 'wpl'")
+	}
+}
+
+// writeBadRequest writes a 400 error response.
+func (h *MissionHandler) writeBadRequest(c *gin.Context, code, message string) {
+	c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		Error: models.ErrorDetail{
+			Code:    code,
+			Message: message,
+		},
+	})
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/montecarlo"
+)
+
+// MonteCarloHandler handles Monte Carlo study requests.
+type MonteCarloHandler struct {
+	manager *montecarlo.Manager
+	logger  *slog.Logger
+}
+
+// NewMonteCarloHandler creates a new Monte Carlo handler.
+func NewMonteCarloHandler(manager *montecarlo.Manager, logger *slog.Logger) *MonteCarloHandler {
+	return &MonteCarloHandler{
+		manager: manager,
+		logger:  logger,
+	}
+}
+
+// Run handles POST /montecarlo/run
+// The study (YAML or JSON) is the raw request body or a multipart "file"
+// field. The study runs in the background; poll GET /montecarlo/:id for its
+// progress and report.
+func (h *MonteCarloHandler) Run(c *gin.Context) {
+	_, data, err := readUpload(c)
+	if err != nil {
+		h.logger.Warn("Failed to read study", "error", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "INVALID_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	study, err := montecarlo.Parse(data)
+	if err != nil {
+		h.writeError(c, err, "Failed to parse study")
+		return
+	}
+
+	status, err := h.manager.Start(study)
+	if err != nil {
+		h.writeError(c, err, "Failed to start study")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, status)
+}
+
+// List handles GET /montecarlo
+func (h *MonteCarloHandler) List(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"studies": h.manager.List(),
+	})
+}
+
+// Get handles GET /montecarlo/:id
+func (h *MonteCarloHandler) Get(c *gin.Context) {
+	status, err := h.manager.Get(c.Param("id"))
+	if err != nil {
+		h.writeError(c, err, "Failed to get study")
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// Abort handles POST /montecarlo/:id/abort
+func (h *MonteCarloHandler) Abort(c *gin.Context) {
+	status, err := h.manager.Abort(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.writeError(c, err, "Failed to abort study")
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// writeError writes the response for a failed study operation.
+func (h *MonteCarloHandler) writeError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, models.ErrInvalidStudy):
+		h.logger.Warn("Invalid study", "error", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "INVALID_STUDY",
+				Message: err.Error(),
+			},
+		})
+	case errors.Is(err, models.ErrStudyNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "STUDY_NOT_FOUND",
+				Message: err.Error(),
+			},
+		})
+	case errors.Is(err, models.ErrStudyRunning):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "STUDY_RUNNING",
+				Message: err.Error(),
+			},
+		})
+	default:
+		h.logger.Error(message, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "INTERNAL_ERROR",
+				Message: message,
+			},
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/navdata"
+)
+
+const (
+	// defaultNavdataSearchLimit is the number of results returned when no limit is given.
+	defaultNavdataSearchLimit = 20
+
+	// maxNavdataSearchLimit bounds the number of results of a single search.
+	maxNavdataSearchLimit = 100
+)
+
+// NavdataHandler handles navigation database lookups.
+type NavdataHandler struct {
+	navdata *navdata.Database
+	logger  *slog.Logger
+}
+
+// NewNavdataHandler creates a new navdata handler.
+func NewNavdataHandler(nav *navdata.Database, logger *slog.Logger) *NavdataHandler {
+	return &NavdataHandler{
+		navdata: nav,
+		logger:  logger,
+	}
+}
+
+// Search handles GET /navdata/search?q=[&limit=]
+// Matches identifiers (exact, then prefix) and names.
+func (h *NavdataHandler) Search(c *gin.Context) {
+	query := c.Query("q")
+	if query == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "INVALID_REQUEST",
+				Message: "q query parameter is required",
+				Field:   "q",
+			},
+		})
+		return
+	}
+
+	limit := defaultNavdataSearchLimit
+	if limitText := c.Query("limit"); limitText != "" {
+		value, err := strconv.Atoi(limitText)
+		if err != nil || value <= 0 {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: models.ErrorDetail{
+					Code:    "INVALID_REQUEST",
+					Message: "limit query parameter must be a positive integer",
+					Field:   "limit",
+				},
+			})
+			return
+		}
+		limit = min(value, maxNavdataSearchLimit)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"results": h.navdata.Search(query, limit),
+	})
+}
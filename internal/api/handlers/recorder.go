@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/simulator"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/track"
+)
+
+// TrackHandler handles flight track export requests.
+type TrackHandler struct {
+	recorder *track.Recorder
+	logger   *slog.Logger
+}
+
+// NewTrackHandler creates a new track handler.
+func NewTrackHandler(sim *simulator.Simulator, logger *slog.Logger) *TrackHandler {
+	return &TrackHandler{
+		recorder: sim.GetRecorder(),
+		logger:   logger,
+	}
+}
+
+// Export handles GET /track[?format=kml|gpx|geojson|czml|csv&from=&to=]
+// from and to are RFC 3339 timestamps or seconds of simulation time.
+func (h *TrackHandler) Export(c *gin.Context) {
+	if h.recorder == nil {
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "RECORDER_DISABLED",
+				Message: "Track recording is disabled in the simulator configuration",
+			},
+		})
+		return
+	}
+
+	format := track.Format(c.DefaultQuery("format", string(track.FormatGeoJSON)))
+	switch format {
+	case track.FormatKML, track.FormatGPX, track.FormatGeoJSON, track.FormatCZML, track.FormatCSV:
+	default:
+		h.writeBadRequest(c, "INVALID_FORMAT", "format must be one of kml, gpx, geojson, czml, csv")
+		return
+	}
+
+	from, err := h.parseTime(c.Query("from"))
+	if err != nil {
+		h.writeBadRequest(c, "INVALID_REQUEST", "from: "+err.Error())
+		return
+	}
+	to, err := h.parseTime(c.Query("to"))
+	if err != nil {
+		h.writeBadRequest(c, "INVALID_REQUEST", "to: "+err.Error())
+		return
+	}
+
+	samples := h.recorder.Samples(from, to)
+	if len(samples) == 0 {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "NO_TRACK_DATA",
+				Message: "No track samples recorded in the requested time range",
+			},
+		})
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := track.Export(&buf, format, "Flight track", h.recorder.Epoch(), samples); err != nil {
+		h.logger.Error("Failed to export track", "error", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "INTERNAL_ERROR",
+				Message: "Failed to export track",
+			},
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="track.%s"`, format))
+	c.Data(http.StatusOK, format.ContentType(), buf.Bytes())
+}
+
+// parseTime parses an RFC 3339 timestamp or a number of seconds of
+// simulation time. An empty value returns the zero time.
+func (h *TrackHandler) parseTime(text string) (time.Time, error) {
+	if text == "" {
+		return time.Time{}, nil
+	}
+	if seconds, err := strconv.ParseFloat(text, 64); err == nil {
+		return h.recorder.Epoch().Add(time.Duration(seconds * float64(time.Second))), nil
+	}
+	t, err := time.Parse(time.RFC3339, text)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("must be an RFC 3339 timestamp or seconds of simulation time")
+	}
+	return t, nil
+}
+
+// writeBadRequest writes a 400 error response.
+func (h *TrackHandler) writeBadRequest(c *gin.Context, code, message string) {
+	c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		Error: models.ErrorDetail{
+			Code:    code,
+			Message: message,
+		},
+	})
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/simulator"
+)
+
+// ReplayHandler handles session replay control requests.
+type ReplayHandler struct {
+	simulator *simulator.Simulator
+	logger    *slog.Logger
+}
+
+// NewReplayHandler creates a new replay handler.
+func NewReplayHandler(sim *simulator.Simulator, logger *slog.Logger) *ReplayHandler {
+	return &ReplayHandler{
+		simulator: sim,
+		logger:    logger,
+	}
+}
+
+// SeekRequest represents the request body for POST /replay/seek.
+type SeekRequest struct {
+	SimTime *float64 `json:"sim_time" binding:"required,min=0"` // seconds since the session start
+}
+
+// SpeedRequest represents the request body for POST /replay/speed.
+type SpeedRequest struct {
+	Speed *float64 `json:"speed" binding:"required,min=0,max=100"` // 1 = real time, 0 = paused
+}
+
+// Status handles GET /replay
+func (h *ReplayHandler) Status(c *gin.Context) {
+	h.respond(c, h.simulator.ReplayStatus)
+}
+
+// Seek handles POST /replay/seek
+func (h *ReplayHandler) Seek(c *gin.Context) {
+	var req SeekRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeBadRequest(c, err)
+		return
+	}
+
+	h.respond(c, func(ctx context.Context) (models.ReplayStatus, error) {
+		return h.simulator.SeekReplay(ctx, *req.SimTime)
+	})
+}
+
+// Speed handles POST /replay/speed
+func (h *ReplayHandler) Speed(c *gin.Context) {
+	var req SpeedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeBadRequest(c, err)
+		return
+	}
+
+	h.respond(c, func(ctx context.Context) (models.ReplayStatus, error) {
+		return h.simulator.SetReplaySpeed(ctx, *req.Speed)
+	})
+}
+
+// respond runs a replay request and writes the resulting playback status.
+func (h *ReplayHandler) respond(c *gin.Context, request func(context.Context) (models.ReplayStatus, error)) {
+	status, err := request(c.Request.Context())
+	if errors.Is(err, models.ErrNotReplaying) {
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "REPLAY_DISABLED",
+				Message: "The simulator is not replaying a session; start it with -replay",
+			},
+		})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Replay request failed", "error", err)
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "SIMULATOR_NOT_RUNNING",
+				Message: "Failed to reach the replay",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// writeBadRequest writes a 400 response for an invalid request body.
+func (h *ReplayHandler) writeBadRequest(c *gin.Context, err error) {
+	h.logger.Warn("Invalid request", "error", err)
+	c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		Error: models.ErrorDetail{
+			Code:    "INVALID_REQUEST",
+			Message: err.Error(),
+		},
+	})
+}
+
+// writeReplayActive writes the response for a command, heartbeat or state
+// change sent while a recorded session is being replayed.
+func writeReplayActive(c *gin.Context) {
+	c.JSON(http.StatusConflict, replayActiveResponse())
+}
+
+// replayActiveResponse builds the 409 response body for a request rejected
+// while replaying.
+func replayActiveResponse() models.ErrorResponse {
+	return models.ErrorResponse{
+		Error: models.ErrorDetail{
+			Code:    "REPLAY_ACTIVE",
+			Message: "The simulator is replaying a recorded session and does not accept commands or state changes",
+		},
+	}
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/scenario"
+)
+
+// ScenarioHandler handles scenario run requests.
+type ScenarioHandler struct {
+	manager *scenario.Manager
+	logger  *slog.Logger
+}
+
+// NewScenarioHandler creates a new scenario handler.
+func NewScenarioHandler(manager *scenario.Manager, logger *slog.Logger) *ScenarioHandler {
+	return &ScenarioHandler{
+		manager: manager,
+		logger:  logger,
+	}
+}
+
+// Run handles POST /scenarios/run
+// The scenario (YAML or JSON) is the raw request body or a multipart "file"
+// field. The run starts in the background; poll GET /scenarios/:id for its
+// outcome.
+func (h *ScenarioHandler) Run(c *gin.Context) {
+	_, data, err := readUpload(c)
+	if err != nil {
+		h.logger.Warn("Failed to read scenario", "error", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "INVALID_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	sc, err := scenario.Parse(data)
+	if err != nil {
+		h.writeError(c, err, "Failed to parse scenario")
+		return
+	}
+
+	status, err := h.manager.Start(sc)
+	if err != nil {
+		h.writeError(c, err, "Failed to start scenario")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, status)
+}
+
+// List handles GET /scenarios
+func (h *ScenarioHandler) List(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"scenarios": h.manager.List(),
+	})
+}
+
+// Get handles GET /scenarios/:id
+func (h *ScenarioHandler) Get(c *gin.Context) {
+	status, err := h.manager.Get(c.Param("id"))
+	if err != nil {
+		h.writeError(c, err, "Failed to get scenario")
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// Abort handles POST /scenarios/:id/abort
+func (h *ScenarioHandler) Abort(c *gin.Context) {
+	status, err := h.manager.Abort(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.writeError(c, err, "Failed to abort scenario")
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// writeError writes the response for a failed scenario operation.
+func (h *ScenarioHandler) writeError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, models.ErrInvalidScenario):
+		h.logger.Warn("Invalid scenario", "error", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "INVALID_SCENARIO",
+				Message: err.Error(),
+			},
+		})
+	case errors.Is(err, models.ErrScenarioNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "SCENARIO_NOT_FOUND",
+				Message: err.Error(),
+			},
+		})
+	case errors.Is(err, models.ErrScenarioRunning):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "SCENARIO_RUNNING",
+				Message: err.Error(),
+			},
+		})
+	case errors.Is(err, models.ErrReplayActive):
+		writeReplayActive(c)
+	default:
+		h.logger.Error(message, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "INTERNAL_ERROR",
+				Message: message,
+			},
+		})
+	}
+}
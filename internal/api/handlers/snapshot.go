@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/simulator"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/snapshot"
+)
+
+// SnapshotHandler handles state snapshot requests.
+type SnapshotHandler struct {
+	simulator *simulator.Simulator
+	store     *snapshot.Store
+	logger    *slog.Logger
+}
+
+// NewSnapshotHandler creates a new snapshot handler.
+func NewSnapshotHandler(sim *simulator.Simulator, store *snapshot.Store, logger *slog.Logger) *SnapshotHandler {
+	return &SnapshotHandler{
+		simulator: sim,
+		store:     store,
+		logger:    logger,
+	}
+}
+
+// SnapshotRequest represents the optional request body for POST /snapshots.
+type SnapshotRequest struct {
+	Name string `json:"name,omitempty"` // e.g. "final approach at 1000 m"
+}
+
+// RestoreResponse represents the response to a snapshot restore.
+type RestoreResponse struct {
+	Status     string               `json:"status"`
+	SnapshotID string               `json:"snapshot_id"`
+	State      models.AircraftState `json:"state"`
+}
+
+// Create handles POST /snapshots
+func (h *SnapshotHandler) Create(c *gin.Context) {
+	var req SnapshotRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			h.logger.Warn("Invalid request", "error", err)
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: models.ErrorDetail{
+					Code:    "INVALID_REQUEST",
+					Message: err.Error(),
+				},
+			})
+			return
+		}
+	}
+
+	snap, err := h.simulator.Snapshot(c.Request.Context())
+	if err != nil {
+		h.writeError(c, err, "Failed to capture snapshot")
+		return
+	}
+	snap.Name = req.Name
+
+	snap, err = h.store.Save(snap)
+	if err != nil {
+		h.writeError(c, err, "Failed to save snapshot")
+		return
+	}
+	h.logger.Info("Snapshot created", "snapshot_id", snap.ID, "name", snap.Name)
+
+	c.JSON(http.StatusCreated, snap)
+}
+
+// List handles GET /snapshots
+func (h *SnapshotHandler) List(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"snapshots": h.store.List(),
+	})
+}
+
+// Get handles GET /snapshots/:id
+func (h *SnapshotHandler) Get(c *gin.Context) {
+	snap, err := h.store.Get(c.Param("id"))
+	if err != nil {
+		h.writeError(c, err, "Failed to get snapshot")
+		return
+	}
+
+	c.JSON(http.StatusOK, snap)
+}
+
+// Delete handles DELETE /snapshots/:id
+func (h *SnapshotHandler) Delete(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.store.Delete(id); err != nil {
+		h.writeError(c, err, "Failed to delete snapshot")
+		return
+	}
+	h.logger.Info("Snapshot deleted", "snapshot_id", id)
+
+	c.Status(http.StatusNoContent)
+}
+
+// Restore handles POST /snapshots/:id/restore
+func (h *SnapshotHandler) Restore(c *gin.Context) {
+	snap, err := h.store.Get(c.Param("id"))
+	if err != nil {
+		h.writeError(c, err, "Failed to get snapshot")
+		return
+	}
+
+	state, err := h.simulator.RestoreSnapshot(c.Request.Context(), snap)
+	if err != nil {
+		h.writeError(c, err, "Failed to restore snapshot")
+		return
+	}
+
+	c.JSON(http.StatusOK, RestoreResponse{
+		Status:     "restored",
+		SnapshotID: snap.ID,
+		State:      state,
+	})
+}
+
+// writeError writes the response for a failed snapshot operation.
+func (h *SnapshotHandler) writeError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, models.ErrSnapshotNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "SNAPSHOT_NOT_FOUND",
+				Message: err.Error(),
+			},
+		})
+	case errors.Is(err, models.ErrReplayActive):
+		writeReplayActive(c)
+	case errors.Is(err, models.ErrTimeout):
+		h.logger.Error(message, "error", err)
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "SIMULATOR_NOT_RUNNING",
+				Message: message,
+			},
+		})
+	default:
+		h.logger.Error(message, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "INTERNAL_ERROR",
+				Message: message,
+			},
+		})
+	}
+}
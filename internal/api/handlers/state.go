@@ -1,10 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"log/slog"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/api/validation"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/config"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
 	"github.com/meiron-tzhori/Flight-Simulator/internal/simulator"
 )
 
@@ -12,16 +16,30 @@ import (
 type StateHandler struct {
 	simulator *simulator.Simulator
 	logger    *slog.Logger
+	simCfg    config.SimulationConfig
 }
 
-// NewStateHandler creates a new state handler.
-func NewStateHandler(sim *simulator.Simulator, logger *slog.Logger) *StateHandler {
+// NewStateHandler creates a new state handler. simCfg supplies the speed and
+// climb limits state updates are validated against.
+func NewStateHandler(sim *simulator.Simulator, logger *slog.Logger, simCfg config.SimulationConfig) *StateHandler {
 	return &StateHandler{
 		simulator: sim,
 		logger:    logger,
+		simCfg:    simCfg,
 	}
 }
 
+// SetStateRequest represents the request body for PUT /state.
+type SetStateRequest struct {
+	Lat           *float64 `json:"lat" binding:"required"`
+	Lon           *float64 `json:"lon" binding:"required"`
+	Alt           *float64 `json:"alt" binding:"required"` // meters MSL
+	Heading       *float64 `json:"heading,omitempty"`
+	GroundSpeed   *float64 `json:"ground_speed,omitempty"`
+	VerticalSpeed *float64 `json:"vertical_speed,omitempty"`
+	ClearCommand  bool     `json:"clear_command,omitempty"`
+}
+
 // GetState handles GET /state
 func (h *StateHandler) GetState(c *gin.Context) {
 	state, err := h.simulator.GetState(c.Request.Context())
@@ -35,3 +53,71 @@ func (h *StateHandler) GetState(c *gin.Context) {
 
 	c.JSON(http.StatusOK, state)
 }
+
+// SetState handles PUT /state
+func (h *StateHandler) SetState(c *gin.Context) {
+	var req SetStateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Invalid request", "error", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "INVALID_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	update := models.StateUpdate{
+		Position: models.Position{
+			Latitude:  *req.Lat,
+			Longitude: *req.Lon,
+			Altitude:  *req.Alt,
+		},
+		Heading:       req.Heading,
+		GroundSpeed:   req.GroundSpeed,
+		VerticalSpeed: req.VerticalSpeed,
+		ClearCommand:  req.ClearCommand,
+	}
+	if err := validation.ValidateStateUpdate(&update, h.simCfg.MaxSpeed, h.simCfg.MaxClimbRate, h.simCfg.MaxDescentRate); err != nil {
+		h.logger.Warn("Validation failed", "error", err)
+		detail := models.ErrorDetail{
+			Code:    getErrorCode(err),
+			Message: err.Error(),
+		}
+		if errors.Is(err, models.ErrInvalidVerticalSpeed) {
+			detail.Field = "vertical_speed"
+			detail.Value = *update.VerticalSpeed
+		}
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: detail})
+		return
+	}
+
+	// The aircraft may be placed on the ground, but not below it
+	terrain := h.simulator.GetEnvironment().GetTerrain()
+	if err := validation.ValidateAboveTerrain(update.Position, terrain); err != nil {
+		h.logger.Warn("Terrain conflict", "error", err)
+		response := terrainConflictResponse(err)
+		response.Error.Message = "Position below terrain"
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	state, err := h.simulator.SetState(c.Request.Context(), update)
+	if errors.Is(err, models.ErrReplayActive) {
+		writeReplayActive(c)
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to set state", "error", err)
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "SIMULATOR_NOT_RUNNING",
+				Message: "Failed to set aircraft state",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, state)
+}
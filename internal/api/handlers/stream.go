@@ -1,35 +1,122 @@
 package handlers
 
 import (
+	"cmp"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/config"
 	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/pubsub"
 	"github.com/meiron-tzhori/Flight-Simulator/internal/simulator"
+	"github.com/ugorji/go/codec"
+)
+
+const (
+	// defaultStreamRateHz is the state update rate when none is configured.
+	defaultStreamRateHz = 10
+
+	// streamRetry is the reconnection delay sent to clients.
+	streamRetry = 3 * time.Second
+)
+
+// Stream formats. Binary formats are base64-encoded in the SSE data field.
+const (
+	streamFormatJSON    = "json"
+	streamFormatMsgpack = "msgpack"
+	streamFormatCBOR    = "cbor"
+)
+
+var (
+	msgpackHandle = &codec.MsgpackHandle{}
+	cborHandle    = &codec.CborHandle{}
+
+	// stateFields are the top-level JSON fields of a state, which clients
+	// select with ?fields=
+	stateFields = jsonFieldNames(reflect.TypeOf(models.AircraftState{}))
 )
 
 // StreamHandler handles SSE streaming requests.
 type StreamHandler struct {
-	simulator *simulator.Simulator
-	logger    *slog.Logger
+	simulator  *simulator.Simulator
+	logger     *slog.Logger
+	cfg        config.StreamingConfig
+	tickRateHz float64
+	clients    atomic.Int64
 }
 
-// NewStreamHandler creates a new stream handler.
-func NewStreamHandler(sim *simulator.Simulator, logger *slog.Logger) *StreamHandler {
+// NewStreamHandler creates a new stream handler. The configured update rate
+// is the default state rate of a client, and clients may ask for any rate
+// up to the tick rate.
+func NewStreamHandler(sim *simulator.Simulator, logger *slog.Logger, cfg config.StreamingConfig, tickRateHz float64) *StreamHandler {
 	return &StreamHandler{
-		simulator: sim,
-		logger:    logger,
+		simulator:  sim,
+		logger:     logger,
+		cfg:        cfg,
+		tickRateHz: tickRateHz,
 	}
 }
 
-// Stream handles GET /stream
-// Streams aircraft state updates via Server-Sent Events (SSE).
+// streamOptions are the per-client options of a stream.
+type streamOptions struct {
+	rateHz float64
+	fields []string // nil = all fields
+	format string
+	topics []string // topic patterns
+}
+
+// streamItem is a message read from a broker, to be sent to a client.
+type streamItem struct {
+	seq    uint64
+	topic  string
+	event  string // SSE event name
+	latest bool   // only the latest of the topic is sent, at the client's rate
+	encode func() ([]byte, error)
+}
+
+// Stream handles GET /stream?rate_hz=&fields=&format=&topics=
+// Streams the messages of the topics a client asks for via Server-Sent
+// Events (SSE), by default the aircraft's states and events. States and
+// environment updates are sent at the client's rate; simulation events are
+// sent as they happen, with their type as the SSE event name.
 func (h *StreamHandler) Stream(c *gin.Context) {
+	opts, err := h.parseOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "INVALID_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	// Count the client before writing anything, so a rejected client gets a
+	// plain error response
+	clients := h.clients.Add(1)
+	defer h.clients.Add(-1)
+	if h.cfg.MaxClients > 0 && clients > int64(h.cfg.MaxClients) {
+		h.logger.Warn("SSE client rejected, too many clients", "max_clients", h.cfg.MaxClients, "remote_addr", c.ClientIP())
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "TOO_MANY_CLIENTS",
+				Message: fmt.Sprintf("Stream is limited to %d clients, please retry later", h.cfg.MaxClients),
+			},
+		})
+		return
+	}
+
 	// Set SSE headers
 	c.Header("Content-Type", "text/event-stream")
 	c.Header("Cache-Control", "no-cache")
@@ -48,25 +135,130 @@ func (h *StreamHandler) Stream(c *gin.Context) {
 	// Generate unique subscriber ID
 	subID := uuid.New().String()
 
-	// Subscribe to state updates
-	publisher := h.simulator.GetPublisher()
-	stateChan := publisher.Subscribe(subID)
-	defer publisher.Unsubscribe(subID)
+	// Messages are read from the brokers' history when their sequence
+	// changes, so they are sent in order and numbered, and each message is
+	// encoded once for all clients with the same options
+	sequence := h.simulator.GetPublisher().Sequence()
+	changed := sequence.Changed()
+
+	// Resume after the last message the client received, or start with new
+	// messages. An ID from before a server restart is newer than any
+	// message, so the client starts over.
+	cursor := sequence.Last()
+	resumed := false
+	if seq, ok := lastEventID(c); ok && seq <= cursor {
+		cursor = seq
+		resumed = true
+	}
 
-	h.logger.Info("SSE client connected", "subscriber_id", subID, "remote_addr", c.ClientIP())
+	h.logger.Info("SSE client connected", "subscriber_id", subID, "remote_addr", c.ClientIP(),
+		"rate_hz", opts.rateHz, "format", opts.format, "topics", opts.topics, "resumed", resumed)
 	defer h.logger.Info("SSE client disconnected", "subscriber_id", subID)
 
-	// Send initial connection event
-	fmt.Fprintf(c.Writer, "event: connected\n")
-	fmt.Fprintf(c.Writer, "data: {\"subscriber_id\":\"%s\"}\n\n", subID)
-	flusher.Flush()
+	// send writes an SSE message; binary formats are base64-encoded. Written
+	// messages are flushed together.
+	unflushed := false
+	flush := func() {
+		if unflushed {
+			flusher.Flush()
+			unflushed = false
+		}
+	}
+	send := func(id uint64, event string, data []byte) {
+		unflushed = true
+		if id > 0 {
+			fmt.Fprintf(c.Writer, "id: %d\n", id)
+		}
+		fmt.Fprintf(c.Writer, "event: %s\n", event)
+		if opts.format == streamFormatJSON {
+			fmt.Fprintf(c.Writer, "data: %s\n\n", data)
+		} else {
+			fmt.Fprintf(c.Writer, "data: %s\n\n", base64.StdEncoding.EncodeToString(data))
+		}
+	}
+
+	// Send the reconnection delay and initial connection event
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", streamRetry.Milliseconds())
+	data, err := encodeStream(gin.H{"subscriber_id": subID, "resumed": resumed}, opts.format)
+	if err != nil {
+		h.logger.Error("Failed to encode connected event", "error", err, "subscriber_id", subID)
+		return
+	}
+	send(0, "connected", data)
+	flush()
 
-	// Throttle updates to 10 Hz (every 100ms) to avoid overwhelming clients
-	throttle := time.NewTicker(100 * time.Millisecond)
+	// Throttle updates to the client's rate to avoid overwhelming it
+	throttle := time.NewTicker(time.Duration(float64(time.Second) / opts.rateHz))
 	defer throttle.Stop()
 
-	// Keep track of latest state
-	var latestState *models.AircraftState
+	// Keep track of the latest message of each throttled topic
+	pending := make(map[string]streamItem)
+
+	sendPending := func() {
+		items := make([]streamItem, 0, len(pending))
+		for _, item := range pending {
+			items = append(items, item)
+		}
+		slices.SortFunc(items, func(a, b streamItem) int { return cmp.Compare(a.seq, b.seq) })
+		for _, item := range items {
+			data, err := item.encode()
+			if err != nil {
+				h.logger.Error("Failed to encode message", "error", err, "topic", item.topic, "subscriber_id", subID)
+				continue
+			}
+			send(item.seq, item.event, data)
+		}
+		clear(pending)
+	}
+
+	// A client that falls behind the kept history misses events. Each time
+	// it does counts as a drop, and with the disconnect policy the client
+	// is evicted after more than max_drops of them. It then resumes from
+	// the last message it got, so missing events on resume is not counted.
+	evict := pubsub.SlowPolicy(h.cfg.SlowPolicy) == pubsub.PolicyDisconnect
+	dropped := 0
+
+	// readMessages sends the events published since the cursor and keeps the
+	// latest states for the next throttle tick. States waiting for the tick
+	// are sent before an event, so the IDs keep increasing. It returns false
+	// when the client is evicted.
+	readMessages := func(resuming bool) bool {
+		last := sequence.Last()
+		items, missed := h.readItems(cursor, last, opts)
+		if missed && resuming {
+			h.logger.Warn("SSE client resumed after events no longer kept", "subscriber_id", subID, "last_event_id", cursor)
+		} else if missed {
+			dropped++
+			h.logger.Warn("SSE client missed events, it fell behind the kept history",
+				"subscriber_id", subID, "after", cursor, "dropped", dropped)
+			if evict && dropped > h.cfg.MaxDrops {
+				h.logger.Warn("SSE client evicted, too many messages dropped",
+					"subscriber_id", subID, "dropped", dropped, "max_drops", h.cfg.MaxDrops)
+				return false
+			}
+		}
+
+		for _, item := range items {
+			if item.latest {
+				pending[item.topic] = item
+				continue
+			}
+
+			data, err := item.encode()
+			if err != nil {
+				h.logger.Error("Failed to encode message", "error", err, "topic", item.topic, "subscriber_id", subID)
+				continue
+			}
+			sendPending()
+			send(item.seq, item.event, data)
+		}
+		cursor = last
+		flush()
+		return true
+	}
+
+	// Replay what the client missed
+	readMessages(true)
 
 	// Heartbeat to detect client disconnections
 	heartbeat := time.NewTicker(30 * time.Second)
@@ -74,34 +266,19 @@ func (h *StreamHandler) Stream(c *gin.Context) {
 
 	for {
 		select {
-		case state, ok := <-stateChan:
-			if !ok {
-				// Channel closed (simulator shutdown)
-				h.logger.Info("State channel closed", "subscriber_id", subID)
+		case <-changed:
+			changed = sequence.Changed()
+			if !readMessages(false) {
 				return
 			}
-			// Cache latest state (will be sent on next throttle tick)
-			latestState = &state
 
 		case <-throttle.C:
-			if latestState != nil {
-				// Marshal state to JSON
-				data, err := json.Marshal(latestState)
-				if err != nil {
-					h.logger.Error("Failed to marshal state", "error", err, "subscriber_id", subID)
-					continue
-				}
-
-				// Send SSE event in standard format:
-				// event: state
-				// data: {json}
-				// (blank line)
-				fmt.Fprintf(c.Writer, "event: state\n")
-				fmt.Fprintf(c.Writer, "data: %s\n\n", data)
-
-				// Flush to send immediately
-				flusher.Flush()
+			// Events are not throttled, states are sent at the client's rate
+			if !readMessages(false) {
+				return
 			}
+			sendPending()
+			flush()
 
 		case <-heartbeat.C:
 			// Send heartbeat to keep connection alive
@@ -115,3 +292,199 @@ func (h *StreamHandler) Stream(c *gin.Context) {
 		}
 	}
 }
+
+// readItems returns the messages of the client's topics with a sequence
+// number after after and up to upTo, in order. All of them can be read, as
+// upTo is at most the sequence's last number. missed reports that events
+// after after are no longer kept; states and environment updates in between
+// are skipped anyway, as only the latest is sent.
+func (h *StreamHandler) readItems(after, upTo uint64, opts streamOptions) (items []streamItem, missed bool) {
+	encodingKey := opts.format + ":" + strings.Join(opts.fields, ",")
+	encodeFields := func(state *models.AircraftState) ([]byte, error) {
+		return encodeState(state, opts)
+	}
+	encodeFormat := func(v any) ([]byte, error) {
+		return encodeStream(v, opts.format)
+	}
+
+	items, _ = brokerItems(h.simulator.GetPublisher(), after, upTo, opts.topics,
+		func(msg *pubsub.Message[models.AircraftState]) streamItem {
+			return streamItem{event: "state", latest: true, encode: func() ([]byte, error) {
+				return msg.Encoded(encodingKey, encodeFields)
+			}}
+		})
+	environment, _ := brokerItems(h.simulator.GetEnvironmentBroker(), after, upTo, opts.topics,
+		func(msg *pubsub.Message[models.EnvironmentState]) streamItem {
+			return streamItem{event: "environment", latest: true, encode: func() ([]byte, error) {
+				return msg.Encoded(opts.format, func(env *models.EnvironmentState) ([]byte, error) { return encodeFormat(env) })
+			}}
+		})
+	events, missed := brokerItems(h.simulator.GetEventBroker(), after, upTo, opts.topics,
+		func(msg *pubsub.Message[models.Event]) streamItem {
+			return streamItem{event: string(msg.Payload.Type), encode: func() ([]byte, error) {
+				return msg.Encoded(opts.format, func(event *models.Event) ([]byte, error) { return encodeFormat(event) })
+			}}
+		})
+	items = append(append(items, environment...), events...)
+
+	slices.SortFunc(items, func(a, b streamItem) int { return cmp.Compare(a.seq, b.seq) })
+	return items, missed
+}
+
+// brokerItems returns the messages of a broker on the topics matching the
+// patterns, with a sequence number after after and up to upTo. missed
+// reports that messages after after are no longer kept.
+func brokerItems[T any](broker *pubsub.Broker[T], after, upTo uint64, topics []string, item func(*pubsub.Message[T]) streamItem) (items []streamItem, missed bool) {
+	messages, missed := broker.Since(after)
+	for _, msg := range messages {
+		if msg.Seq > upTo {
+			break
+		}
+		if !pubsub.MatchAny(topics, msg.Topic) {
+			continue
+		}
+		it := item(msg)
+		it.seq = msg.Seq
+		it.topic = msg.Topic
+		items = append(items, it)
+	}
+	return items, missed
+}
+
+// lastEventID returns the sequence number in the Last-Event-ID header a
+// reconnecting client sends.
+func lastEventID(c *gin.Context) (uint64, bool) {
+	text := c.GetHeader("Last-Event-ID")
+	if text == "" {
+		return 0, false
+	}
+	seq, err := strconv.ParseUint(text, 10, 64)
+	return seq, err == nil
+}
+
+// parseOptions reads the stream options from the query, defaulting to the
+// configured rate, all fields, JSON and the aircraft's states and events.
+func (h *StreamHandler) parseOptions(c *gin.Context) (streamOptions, error) {
+	opts := streamOptions{format: streamFormatJSON}
+	rate, err := parseRate(c, h.cfg.UpdateRateHz, h.tickRateHz)
+	if err != nil {
+		return opts, err
+	}
+	opts.rateHz = rate
+
+	if text := c.Query("fields"); text != "" {
+		for _, field := range strings.Split(text, ",") {
+			field = strings.TrimSpace(field)
+			if !slices.Contains(stateFields, field) {
+				return opts, fmt.Errorf("unknown state field %q in fields, want %s", field, strings.Join(stateFields, ", "))
+			}
+			opts.fields = append(opts.fields, field)
+		}
+	}
+
+	opts.topics = []string{
+		simulator.StateTopic(h.simulator.AircraftID()),
+		simulator.EventsPattern(h.simulator.AircraftID()),
+	}
+	if text := c.Query("topics"); text != "" {
+		opts.topics = nil
+		for _, pattern := range strings.Split(text, ",") {
+			pattern = strings.TrimSpace(pattern)
+			if err := pubsub.ValidatePattern(pattern); err != nil {
+				return opts, fmt.Errorf("invalid topics: %w", err)
+			}
+			opts.topics = append(opts.topics, pattern)
+		}
+	}
+
+	if format := c.Query("format"); format != "" {
+		switch format {
+		case streamFormatJSON, streamFormatMsgpack, streamFormatCBOR:
+			opts.format = format
+		default:
+			return opts, fmt.Errorf("unknown format %q, want json, msgpack or cbor", format)
+		}
+	}
+
+	return opts, nil
+}
+
+// parseRate reads a client's state rate from ?rate_hz=, defaulting to the
+// configured update rate. Clients may ask for any rate up to the tick rate.
+func parseRate(c *gin.Context, updateRateHz int, tickRateHz float64) (float64, error) {
+	rate := float64(updateRateHz)
+	if rate <= 0 {
+		rate = defaultStreamRateHz
+	}
+	maxRate := max(tickRateHz, rate)
+
+	if text := c.Query("rate_hz"); text != "" {
+		requested, err := strconv.ParseFloat(text, 64)
+		if err != nil || requested <= 0 || requested > maxRate {
+			return rate, fmt.Errorf("rate_hz must be greater than 0 and at most %g", maxRate)
+		}
+		rate = requested
+	}
+	return rate, nil
+}
+
+// encodeState encodes the selected fields of a state.
+func encodeState(state *models.AircraftState, opts streamOptions) ([]byte, error) {
+	if opts.fields == nil {
+		return encodeStream(state, opts.format)
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	// Omitted fields (e.g. no active command) stay omitted
+	selected := make(map[string]json.RawMessage, len(opts.fields))
+	for _, field := range opts.fields {
+		if value, ok := doc[field]; ok {
+			selected[field] = value
+		}
+	}
+	return encodeStream(selected, opts.format)
+}
+
+// encodeStream encodes v in a stream format. Binary formats encode the JSON
+// document, so they have the same field names and values as JSON.
+func encodeStream(v any, format string) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || format == streamFormatJSON {
+		return data, err
+	}
+
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	var handle codec.Handle = msgpackHandle
+	if format == streamFormatCBOR {
+		handle = cborHandle
+	}
+	var out []byte
+	if err := codec.NewEncoderBytes(&out, handle).Encode(doc); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// jsonFieldNames returns the JSON names of the fields of a struct type.
+func jsonFieldNames(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/flightplan"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+)
+
+// ImportTrajectory handles POST /command/trajectory/import
+// The GPX, KML or GeoJSON file is sent either as the raw request body or as a
+// multipart "file" field. Query parameters:
+//
+//	format         gpx, kml or geojson (default: detected)
+//	alt, speed     defaults for points without an altitude or speed
+//	use_altitude   take altitudes from the file (default true)
+//	use_speed      take speeds or timestamps from the file (default false)
+//	tolerance      Douglas-Peucker tolerance in meters (default 0)
+//	max_waypoints  keep at most this many waypoints
+//	loop           loop the trajectory
+func (h *CommandHandler) ImportTrajectory(c *gin.Context) {
+	opts, loop, err := trackOptions(c)
+	if err != nil {
+		h.logger.Warn("Invalid request", "error", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "INVALID_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	filename, data, err := readUpload(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "INVALID_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	format := flightplan.TrackFormat(c.Query("format"))
+	if format == "" {
+		format = flightplan.DetectTrackFormat(filename, data)
+	}
+
+	result, err := flightplan.ImportTrack(data, format, opts)
+	if err != nil {
+		h.logger.Warn("Trajectory import failed", "error", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "INVALID_TRACK",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+	result.Trajectory.Loop = loop
+
+	cmd := models.NewCommand(models.CommandTypeTrajectory)
+	cmd.Trajectory = result.Trajectory
+	if !h.submitTrajectory(c, cmd) {
+		return
+	}
+
+	h.logger.Info("Trajectory imported",
+		"source", filename,
+		"format", format,
+		"source_points", result.SourcePoints,
+		"waypoints", len(result.Trajectory.Waypoints),
+		"warnings", len(result.Warnings),
+	)
+
+	c.JSON(http.StatusOK, models.CommandResponse{
+		Status:        "accepted",
+		CommandID:     cmd.ID,
+		Message:       "Trajectory import accepted",
+		WaypointCount: len(result.Trajectory.Waypoints),
+		Waypoints:     result.Trajectory.Waypoints,
+		Warnings:      result.Warnings,
+	})
+}
+
+// trackOptions reads the import options from the query string.
+func trackOptions(c *gin.Context) (flightplan.TrackOptions, bool, error) {
+	opts := flightplan.TrackOptions{ExtractAltitude: true}
+	var loop bool
+	var err error
+
+	floatParam := func(name string) (*float64, error) {
+		text := c.Query(name)
+		if text == "" {
+			return nil, nil
+		}
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s query parameter must be a number", name)
+		}
+		return &value, nil
+	}
+	boolParam := func(name string, value *bool) error {
+		text := c.Query(name)
+		if text == "" {
+			return nil
+		}
+		parsed, err := strconv.ParseBool(text)
+		if err != nil {
+			return fmt.Errorf("%s query parameter must be true or false", name)
+		}
+		*value = parsed
+		return nil
+	}
+
+	if opts.Altitude, err = floatParam("alt"); err != nil {
+		return opts, false, err
+	}
+	if opts.Speed, err = floatParam("speed"); err != nil {
+		return opts, false, err
+	}
+	tolerance, err := floatParam("tolerance")
+	if err != nil {
+		return opts, false, err
+	}
+	if tolerance != nil {
+		if *tolerance < 0 {
+			return opts, false, fmt.Errorf("tolerance must be non-negative")
+		}
+		opts.Tolerance = *tolerance
+	}
+	if text := c.Query("max_waypoints"); text != "" {
+		opts.MaxWaypoints, err = strconv.Atoi(text)
+		if err != nil || opts.MaxWaypoints < 2 {
+			return opts, false, fmt.Errorf("max_waypoints must be an integer of at least 2")
+		}
+	}
+
+	if err := boolParam("use_altitude", &opts.ExtractAltitude); err != nil {
+		return opts, false, err
+	}
+	if err := boolParam("use_speed", &opts.ExtractSpeed); err != nil {
+		return opts, false, err
+	}
+	if err := boolParam("loop", &loop); err != nil {
+		return opts, false, err
+	}
+
+	return opts, loop, nil
+}
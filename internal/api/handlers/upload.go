@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxUploadBytes limits the size of uploaded files.
+const maxUploadBytes = 10 << 20
+
+// readUpload reads a file sent either as the raw request body or as a
+// multipart "file" field. The returned filename is "upload" for raw bodies.
+func readUpload(c *gin.Context) (string, []byte, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxUploadBytes)
+
+	if file, header, err := c.Request.FormFile("file"); err == nil {
+		defer file.Close()
+		data, err := io.ReadAll(file)
+		return header.Filename, data, err
+	}
+
+	data, err := io.ReadAll(c.Request.Body)
+	return "upload", data, err
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/webhook"
+)
+
+// WebhookRequest represents the request body for registering a webhook.
+type WebhookRequest struct {
+	URL        string             `json:"url" binding:"required"`
+	EventTypes []models.EventType `json:"event_types,omitempty"` // none = all event types
+	Secret     string             `json:"secret" binding:"required"`
+}
+
+// WebhookHandler handles webhook requests.
+type WebhookHandler struct {
+	manager *webhook.Manager
+	logger  *slog.Logger
+}
+
+// NewWebhookHandler creates a new webhook handler.
+func NewWebhookHandler(manager *webhook.Manager, logger *slog.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		manager: manager,
+		logger:  logger,
+	}
+}
+
+// Create handles POST /webhooks
+func (h *WebhookHandler) Create(c *gin.Context) {
+	var req WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Invalid request", "error", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "INVALID_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	hook, err := h.manager.Register(models.Webhook{
+		URL:        req.URL,
+		EventTypes: req.EventTypes,
+		Secret:     req.Secret,
+	})
+	if err != nil {
+		h.writeError(c, err, "Failed to register webhook")
+		return
+	}
+
+	c.JSON(http.StatusCreated, hook)
+}
+
+// List handles GET /webhooks
+func (h *WebhookHandler) List(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"webhooks": h.manager.List(),
+	})
+}
+
+// Get handles GET /webhooks/:id
+func (h *WebhookHandler) Get(c *gin.Context) {
+	hook, err := h.manager.Get(c.Param("id"))
+	if err != nil {
+		h.writeError(c, err, "Failed to get webhook")
+		return
+	}
+
+	c.JSON(http.StatusOK, hook)
+}
+
+// Delete handles DELETE /webhooks/:id
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.manager.Delete(id); err != nil {
+		h.writeError(c, err, "Failed to delete webhook")
+		return
+	}
+	h.logger.Info("Webhook deleted", "webhook_id", id)
+
+	c.Status(http.StatusNoContent)
+}
+
+// Deliveries handles GET /webhooks/deliveries?webhook_id=&command_id=
+// Returns the delivery log, oldest first, optionally of one webhook or one
+// command's callback.
+func (h *WebhookHandler) Deliveries(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"deliveries": h.manager.Deliveries(c.Query("webhook_id"), c.Query("command_id")),
+	})
+}
+
+// writeError writes the response for a failed webhook operation.
+func (h *WebhookHandler) writeError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, models.ErrInvalidWebhook):
+		h.logger.Warn("Invalid webhook", "error", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "INVALID_WEBHOOK",
+				Message: err.Error(),
+			},
+		})
+	case errors.Is(err, models.ErrWebhookNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "WEBHOOK_NOT_FOUND",
+				Message: err.Error(),
+			},
+		})
+	default:
+		h.logger.Error(message, "error", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "INTERNAL_ERROR",
+				Message: message,
+			},
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/config"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/pubsub"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/simulator"
+)
+
+const (
+	// wsWriteTimeout bounds every write to a WebSocket client.
+	wsWriteTimeout = 10 * time.Second
+
+	// wsPingInterval is the time between pings; a client that sends neither
+	// a message nor a pong within wsPongTimeout is disconnected.
+	wsPingInterval = 30 * time.Second
+	wsPongTimeout  = 60 * time.Second
+
+	// wsMaxMessageSize limits the size of client messages.
+	wsMaxMessageSize = 1 << 20
+)
+
+// WSRequest is a command message from a WebSocket client.
+type WSRequest struct {
+	ID string `json:"id,omitempty"` // echoed in the reply
+	CommandRequest
+}
+
+// WSMessage is a message to a WebSocket client.
+type WSMessage struct {
+	Type         string                  `json:"type"`                    // connected, state, event, ack or error
+	ID           string                  `json:"id,omitempty"`            // request ID of an ack or error
+	SubscriberID string                  `json:"subscriber_id,omitempty"` // connected
+	State        *models.AircraftState   `json:"state,omitempty"`
+	Event        *models.Event           `json:"event,omitempty"`
+	Response     *models.CommandResponse `json:"response,omitempty"` // ack: the command endpoint's response
+	Status       int                     `json:"status,omitempty"`   // error: the command endpoint's HTTP status
+	Error        *models.ErrorDetail     `json:"error,omitempty"`
+}
+
+// WebSocketHandler handles WebSocket connections that stream the aircraft
+// state and events and accept commands.
+type WebSocketHandler struct {
+	commands   *CommandHandler
+	simulator  *simulator.Simulator
+	logger     *slog.Logger
+	cfg        config.StreamingConfig
+	tickRateHz float64
+	upgrader   websocket.Upgrader
+}
+
+// NewWebSocketHandler creates a new WebSocket handler. Commands are
+// submitted through the command handler so they get the same checks as
+// the command endpoints. States are sent at the rates of /stream.
+func NewWebSocketHandler(commands *CommandHandler, sim *simulator.Simulator, logger *slog.Logger, cfg config.StreamingConfig, tickRateHz float64) *WebSocketHandler {
+	return &WebSocketHandler{
+		commands:   commands,
+		simulator:  sim,
+		logger:     logger,
+		cfg:        cfg,
+		tickRateHz: tickRateHz,
+		upgrader: websocket.Upgrader{
+			// Like the REST API, any origin may connect
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+}
+
+// Connect handles GET /ws?rate_hz=
+// Streams state updates at the client's rate and simulation events, and
+// answers every command message with an ack or an error carrying its ID.
+func (h *WebSocketHandler) Connect(c *gin.Context) {
+	rateHz, err := parseRate(c, h.cfg.UpdateRateHz, h.tickRateHz)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "INVALID_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// The upgrader has written the error response
+		h.logger.Warn("WebSocket upgrade failed", "error", err)
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	// States are read from the latest state of the aircraft, encoded once
+	// for all connections; events are subscribed to
+	subID := uuid.New().String()
+	publisher := h.simulator.GetPublisher()
+	stateTopic := simulator.StateTopic(h.simulator.AircraftID())
+	broker := h.simulator.GetEventBroker()
+	eventChan, err := broker.Subscribe(subID, pubsub.SubscribeOptions{
+		Topics: []string{simulator.EventsPattern(h.simulator.AircraftID())},
+		Kind:   "websocket",
+	})
+	if err != nil {
+		h.logger.Error("Failed to subscribe to events", "error", err)
+		return
+	}
+	defer broker.Unsubscribe(subID)
+
+	h.logger.Info("WebSocket client connected", "subscriber_id", subID, "remote_addr", c.ClientIP(), "rate_hz", rateHz)
+	defer h.logger.Info("WebSocket client disconnected", "subscriber_id", subID)
+
+	// Commands are read and handled in order on their own goroutine; this
+	// goroutine is the connection's only writer
+	replies := make(chan WSMessage, 16)
+	done := make(chan struct{})
+	go h.readRequests(ctx, conn, replies, done)
+
+	send := func(msg WSMessage) bool {
+		conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+		if err := conn.WriteJSON(msg); err != nil {
+			h.logger.Info("WebSocket write failed", "error", err, "subscriber_id", subID)
+			return false
+		}
+		return true
+	}
+
+	if !send(WSMessage{Type: "connected", SubscriberID: subID}) {
+		return
+	}
+
+	throttle := time.NewTicker(time.Duration(float64(time.Second) / rateHz))
+	defer throttle.Stop()
+	ping := time.NewTicker(wsPingInterval)
+	defer ping.Stop()
+
+	var lastSeq uint64
+	for {
+		select {
+		case <-throttle.C:
+			msg := publisher.Latest(stateTopic)
+			if msg == nil || msg.Seq == lastSeq {
+				continue
+			}
+			data, err := msg.Encoded("websocket", encodeWSState)
+			if err != nil {
+				h.logger.Error("Failed to encode state", "error", err, "subscriber_id", subID)
+				continue
+			}
+			conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				h.logger.Info("WebSocket write failed", "error", err, "subscriber_id", subID)
+				return
+			}
+			lastSeq = msg.Seq
+
+		case msg, ok := <-eventChan:
+			if !ok {
+				return
+			}
+			if !send(WSMessage{Type: "event", Event: &msg.Payload}) {
+				return
+			}
+
+		case reply := <-replies:
+			if !send(reply) {
+				return
+			}
+
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout)); err != nil {
+				return
+			}
+
+		case <-done:
+			// Client closed the connection or stopped answering pings
+			return
+		}
+	}
+}
+
+// encodeWSState encodes the state message of a state.
+func encodeWSState(state *models.AircraftState) ([]byte, error) {
+	return json.Marshal(WSMessage{Type: "state", State: state})
+}
+
+// readRequests reads command messages until the connection fails and
+// queues a reply to each.
+func (h *WebSocketHandler) readRequests(ctx context.Context, conn *websocket.Conn, replies chan<- WSMessage, done chan<- struct{}) {
+	defer close(done)
+
+	conn.SetReadLimit(wsMaxMessageSize)
+	conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+	})
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				h.logger.Warn("WebSocket read failed", "error", err)
+			}
+			return
+		}
+		conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+
+		select {
+		case replies <- h.handleRequest(ctx, data):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// handleRequest validates and submits a command message and returns the
+// reply.
+func (h *WebSocketHandler) handleRequest(ctx context.Context, data []byte) WSMessage {
+	var req WSRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		h.logger.Warn("Invalid WebSocket message", "error", err)
+		return errorMessage("", newCommandError(http.StatusBadRequest, "MALFORMED_JSON", err.Error()))
+	}
+
+	response, cmdErr := h.commands.Submit(ctx, req.CommandRequest)
+	if cmdErr != nil {
+		return errorMessage(req.ID, cmdErr)
+	}
+	return WSMessage{Type: "ack", ID: req.ID, Response: &response}
+}
+
+// errorMessage builds the reply for a rejected command message.
+func errorMessage(id string, cmdErr *CommandError) WSMessage {
+	detail := cmdErr.Response.Error
+	return WSMessage{Type: "error", ID: id, Status: cmdErr.Status, Error: &detail}
+}
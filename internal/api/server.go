@@ -11,7 +11,12 @@ import (
 	"github.com/meiron-tzhori/Flight-Simulator/internal/api/handlers"
 	"github.com/meiron-tzhori/Flight-Simulator/internal/api/middleware"
 	"github.com/meiron-tzhori/Flight-Simulator/internal/config"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/montecarlo"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/navdata"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/scenario"
 	"github.com/meiron-tzhori/Flight-Simulator/internal/simulator"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/snapshot"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/webhook"
 )
 
 // Server represents the HTTP API server.
@@ -22,7 +27,7 @@ type Server struct {
 }
 
 // NewServer creates a new API server.
-func NewServer(cfg config.ServerConfig, simCfg config.SimulationConfig, sim *simulator.Simulator, logger *slog.Logger) *Server {
+func NewServer(cfg config.ServerConfig, simCfg config.SimulationConfig, streamCfg config.StreamingConfig, sim *simulator.Simulator, nav *navdata.Database, snapshots *snapshot.Store, scenarios *scenario.Manager, studies *montecarlo.Manager, webhooks *webhook.Manager, logger *slog.Logger) *Server {
 	// Set Gin mode
 	gin.SetMode(gin.ReleaseMode)
 
@@ -36,18 +41,73 @@ func NewServer(cfg config.ServerConfig, simCfg config.SimulationConfig, sim *sim
 
 	// Create handlers
 	healthHandler := handlers.NewHealthHandler(sim, logger, simCfg.TickRateHz)
-	commandHandler := handlers.NewCommandHandler(sim, logger, simCfg.MaxSpeed)
-	stateHandler := handlers.NewStateHandler(sim, logger)
-	streamHandler := handlers.NewStreamHandler(sim, logger)
+	commandHandler := handlers.NewCommandHandler(sim, nav, webhooks, logger, simCfg.MaxSpeed)
+	stateHandler := handlers.NewStateHandler(sim, logger, simCfg)
+	streamHandler := handlers.NewStreamHandler(sim, logger, streamCfg, simCfg.TickRateHz)
+	eventHandler := handlers.NewEventHandler(sim, logger)
+	webSocketHandler := handlers.NewWebSocketHandler(commandHandler, sim, logger, streamCfg, simCfg.TickRateHz)
+	geofenceHandler := handlers.NewGeofenceHandler(sim, logger)
+	airspaceHandler := handlers.NewAirspaceHandler(sim, logger)
+	navdataHandler := handlers.NewNavdataHandler(nav, logger)
+	missionHandler := handlers.NewMissionHandler(commandHandler, sim, logger, simCfg.DefaultSpeed)
+	trackHandler := handlers.NewTrackHandler(sim, logger)
+	replayHandler := handlers.NewReplayHandler(sim, logger)
+	snapshotHandler := handlers.NewSnapshotHandler(sim, snapshots, logger)
+	scenarioHandler := handlers.NewScenarioHandler(scenarios, logger)
+	monteCarloHandler := handlers.NewMonteCarloHandler(studies, logger)
+	webhookHandler := handlers.NewWebhookHandler(webhooks, logger)
+	adminHandler := handlers.NewAdminHandler(sim, logger)
 
 	// Register routes
 	router.GET("/health", healthHandler.Health)
 	router.GET("/state", stateHandler.GetState)
+	router.PUT("/state", stateHandler.SetState)
 	router.GET("/stream", streamHandler.Stream)
+	router.GET("/events", eventHandler.List)
+	router.GET("/ws", webSocketHandler.Connect)
 	router.POST("/command/goto", commandHandler.GoTo)
 	router.POST("/command/trajectory", commandHandler.Trajectory)
+	router.POST("/command/trajectory/import", commandHandler.ImportTrajectory)
+	router.POST("/command/route", commandHandler.Route)
 	router.POST("/command/stop", commandHandler.Stop)
 	router.POST("/command/hold", commandHandler.Hold)
+	router.POST("/command/rth", commandHandler.RTH)
+	router.POST("/command/takeoff", commandHandler.Takeoff)
+	router.POST("/command/land", commandHandler.Land)
+	router.POST("/heartbeat", commandHandler.Heartbeat)
+	router.POST("/geofences", geofenceHandler.Create)
+	router.GET("/geofences", geofenceHandler.List)
+	router.GET("/geofences/:id", geofenceHandler.Get)
+	router.PUT("/geofences/:id", geofenceHandler.Update)
+	router.DELETE("/geofences/:id", geofenceHandler.Delete)
+	router.GET("/airspace", airspaceHandler.Query)
+	router.POST("/airspace/import", airspaceHandler.Import)
+	router.GET("/navdata/search", navdataHandler.Search)
+	router.POST("/mission/import", missionHandler.Import)
+	router.GET("/mission/export", missionHandler.Export)
+	router.GET("/track", trackHandler.Export)
+	router.GET("/replay", replayHandler.Status)
+	router.POST("/replay/seek", replayHandler.Seek)
+	router.POST("/replay/speed", replayHandler.Speed)
+	router.POST("/snapshots", snapshotHandler.Create)
+	router.GET("/snapshots", snapshotHandler.List)
+	router.GET("/snapshots/:id", snapshotHandler.Get)
+	router.DELETE("/snapshots/:id", snapshotHandler.Delete)
+	router.POST("/snapshots/:id/restore", snapshotHandler.Restore)
+	router.POST("/scenarios/run", scenarioHandler.Run)
+	router.GET("/scenarios", scenarioHandler.List)
+	router.GET("/scenarios/:id", scenarioHandler.Get)
+	router.POST("/scenarios/:id/abort", scenarioHandler.Abort)
+	router.POST("/montecarlo/run", monteCarloHandler.Run)
+	router.GET("/montecarlo", monteCarloHandler.List)
+	router.GET("/montecarlo/:id", monteCarloHandler.Get)
+	router.POST("/montecarlo/:id/abort", monteCarloHandler.Abort)
+	router.POST("/webhooks", webhookHandler.Create)
+	router.GET("/webhooks", webhookHandler.List)
+	router.GET("/webhooks/deliveries", webhookHandler.Deliveries)
+	router.GET("/webhooks/:id", webhookHandler.Get)
+	router.DELETE("/webhooks/:id", webhookHandler.Delete)
+	router.GET("/admin/subscribers", adminHandler.Subscribers)
 
 	// Create HTTP server
 	addr := fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
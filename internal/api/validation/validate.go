@@ -3,9 +3,29 @@ package validation
 import (
 	"fmt"
 
+	"github.com/meiron-tzhori/Flight-Simulator/internal/environment"
 	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/navdata"
 )
 
+// TerrainConflictError reports a target altitude below the terrain safety margin.
+// It wraps models.ErrTerrainConflict.
+type TerrainConflictError struct {
+	TerrainAltitude   float64 // meters MSL
+	SafetyMargin      float64 // meters
+	MinimumAltitude   float64 // meters MSL (or AGL for AGL targets)
+	RequestedAltitude float64 // meters, in the requested reference
+}
+
+func (e *TerrainConflictError) Error() string {
+	return fmt.Sprintf("%s: requested altitude %.1f below minimum %.1f",
+		models.ErrTerrainConflict, e.RequestedAltitude, e.MinimumAltitude)
+}
+
+func (e *TerrainConflictError) Unwrap() error {
+	return models.ErrTerrainConflict
+}
+
 // ValidatePosition validates geographic coordinates.
 func ValidatePosition(pos models.Position) error {
 	if pos.Latitude < -90 || pos.Latitude > 90 {
@@ -20,6 +40,19 @@ func ValidatePosition(pos models.Position) error {
 	return nil
 }
 
+// ResolvePosition resolves a fix identifier to its coordinates, keeping the
+// given altitude. Without a fix the position is returned unchanged.
+func ResolvePosition(fix string, pos models.Position, db *navdata.Database) (models.Position, error) {
+	if fix == "" {
+		return pos, nil
+	}
+	resolved, err := db.Lookup(fix)
+	if err != nil {
+		return pos, err
+	}
+	return resolved.Position(pos.Altitude), nil
+}
+
 // ValidateSpeed validates speed value.
 func ValidateSpeed(speed float64, maxSpeed float64) error {
 	if speed < 0 {
@@ -31,11 +64,99 @@ func ValidateSpeed(speed float64, maxSpeed float64) error {
 	return nil
 }
 
+// ValidateVerticalSpeed validates a vertical speed (m/s, positive climbing)
+// against the climb and descent rates.
+func ValidateVerticalSpeed(speed, maxClimbRate, maxDescentRate float64) error {
+	if speed > maxClimbRate || speed < -maxDescentRate {
+		return fmt.Errorf("%w: %f outside [%f, %f]", models.ErrInvalidVerticalSpeed, speed, -maxDescentRate, maxClimbRate)
+	}
+	return nil
+}
+
+// ValidateAltitudeReference validates an altitude reference.
+// An empty reference defaults to MSL.
+func ValidateAltitudeReference(ref models.AltitudeReference) error {
+	switch ref {
+	case "", models.AltitudeReferenceMSL, models.AltitudeReferenceAGL:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", models.ErrInvalidAltitudeReference, ref)
+	}
+}
+
+// ValidateTerrainClearance checks that a target keeps the safety margin above terrain.
+// A nil terrain map disables the check.
+func ValidateTerrainClearance(pos models.Position, ref models.AltitudeReference, terrain *environment.TerrainMap) error {
+	if terrain == nil {
+		return nil
+	}
+
+	elevation := terrain.GetAltitude(pos.Latitude, pos.Longitude)
+	margin := terrain.SafetyMargin()
+
+	minimum := elevation + margin
+	if ref == models.AltitudeReferenceAGL {
+		minimum = margin
+	}
+
+	if pos.Altitude < minimum {
+		return &TerrainConflictError{
+			TerrainAltitude:   elevation,
+			SafetyMargin:      margin,
+			MinimumAltitude:   minimum,
+			RequestedAltitude: pos.Altitude,
+		}
+	}
+	return nil
+}
+
+// ValidateAboveTerrain checks that a position is not below the terrain. Unlike
+// ValidateTerrainClearance it allows positions on the ground.
+func ValidateAboveTerrain(pos models.Position, terrain *environment.TerrainMap) error {
+	if terrain == nil {
+		return nil
+	}
+
+	elevation := terrain.GetAltitude(pos.Latitude, pos.Longitude)
+	if pos.Altitude < elevation {
+		return &TerrainConflictError{
+			TerrainAltitude:   elevation,
+			MinimumAltitude:   elevation,
+			RequestedAltitude: pos.Altitude,
+		}
+	}
+	return nil
+}
+
+// ValidateStateUpdate validates a request to reposition the aircraft.
+func ValidateStateUpdate(update *models.StateUpdate, maxSpeed, maxClimbRate, maxDescentRate float64) error {
+	if err := ValidatePosition(update.Position); err != nil {
+		return err
+	}
+	if update.Heading != nil && (*update.Heading < 0 || *update.Heading >= 360) {
+		return fmt.Errorf("%w: %f", models.ErrInvalidHeading, *update.Heading)
+	}
+	if update.GroundSpeed != nil {
+		if err := ValidateSpeed(*update.GroundSpeed, maxSpeed); err != nil {
+			return err
+		}
+	}
+	if update.VerticalSpeed != nil {
+		if err := ValidateVerticalSpeed(*update.VerticalSpeed, maxClimbRate, maxDescentRate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ValidateGoToCommand validates a go-to command.
 func ValidateGoToCommand(cmd *models.GoToCommand, maxSpeed float64) error {
 	if err := ValidatePosition(cmd.Target); err != nil {
 		return err
 	}
+	if err := ValidateAltitudeReference(cmd.AltitudeRef); err != nil {
+		return err
+	}
 	if cmd.Speed != nil {
 		if err := ValidateSpeed(*cmd.Speed, maxSpeed); err != nil {
 			return err
@@ -54,12 +175,111 @@ func ValidateTrajectoryCommand(cmd *models.TrajectoryCommand, maxSpeed float64)
 		if err := ValidatePosition(wp.Position); err != nil {
 			return fmt.Errorf("waypoint %d: %w", i, err)
 		}
+		if err := ValidateAltitudeReference(wp.AltitudeRef); err != nil {
+			return fmt.Errorf("waypoint %d: %w", i, err)
+		}
 		if wp.Speed != nil {
 			if err := ValidateSpeed(*wp.Speed, maxSpeed); err != nil {
 				return fmt.Errorf("waypoint %d: %w", i, err)
 			}
 		}
+		if wp.HoldSeconds < 0 {
+			return fmt.Errorf("waypoint %d: %w: hold_seconds must be non-negative", i, models.ErrInvalidWaypoint)
+		}
+	}
+
+	return nil
+}
+
+// ValidateRunway validates a runway threshold and heading.
+func ValidateRunway(runway *models.Runway) error {
+	if err := ValidatePosition(runway.Threshold); err != nil {
+		return fmt.Errorf("runway: %w", err)
 	}
+	if runway.Heading < 0 || runway.Heading >= 360 {
+		return fmt.Errorf("runway: %w: %f", models.ErrInvalidHeading, runway.Heading)
+	}
+	return nil
+}
 
+// ValidateTakeoffCommand validates a takeoff command.
+func ValidateTakeoffCommand(cmd *models.TakeoffCommand, maxSpeed float64) error {
+	if cmd.Altitude < 0 {
+		return fmt.Errorf("%w: %f", models.ErrInvalidAltitude, cmd.Altitude)
+	}
+	if cmd.Runway != nil {
+		if err := ValidateRunway(cmd.Runway); err != nil {
+			return err
+		}
+	}
+	if cmd.Speed != nil {
+		if err := ValidateSpeed(*cmd.Speed, maxSpeed); err != nil {
+			return err
+		}
+	}
 	return nil
 }
+
+// ValidateLandCommand validates a land command. A zero glide slope selects
+// the configured default.
+func ValidateLandCommand(cmd *models.LandCommand) error {
+	if cmd.Runway != nil {
+		if err := ValidateRunway(cmd.Runway); err != nil {
+			return err
+		}
+	}
+	if cmd.GlideSlope < 0 || cmd.GlideSlope > 10 {
+		return fmt.Errorf("%w: %f", models.ErrInvalidGlideSlope, cmd.GlideSlope)
+	}
+	return nil
+}
+
+// ValidateGeofence validates a geofence definition.
+func ValidateGeofence(zone *models.Geofence) error {
+	switch zone.Mode {
+	case models.GeofenceModeInclusion, models.GeofenceModeExclusion, models.GeofenceModeAdvisory:
+	default:
+		return fmt.Errorf("%w: mode must be 'inclusion', 'exclusion' or 'advisory'", models.ErrInvalidGeofence)
+	}
+
+	switch zone.BreachAction {
+	case "", models.BreachActionEvent, models.BreachActionHold, models.BreachActionReturn:
+	default:
+		return fmt.Errorf("%w: breach_action must be 'event', 'hold' or 'return'", models.ErrInvalidGeofence)
+	}
+
+	switch zone.Shape {
+	case models.GeofenceShapePolygon:
+		if len(zone.Vertices) < 3 {
+			return fmt.Errorf("%w: polygon requires at least 3 vertices", models.ErrInvalidGeofence)
+		}
+		for i, v := range zone.Vertices {
+			if err := validateCoordinate(v); err != nil {
+				return fmt.Errorf("vertex %d: %w", i, err)
+			}
+		}
+	case models.GeofenceShapeCircle:
+		if zone.Center == nil {
+			return fmt.Errorf("%w: circle requires a center", models.ErrInvalidGeofence)
+		}
+		if err := validateCoordinate(*zone.Center); err != nil {
+			return fmt.Errorf("center: %w", err)
+		}
+		if zone.RadiusM <= 0 {
+			return fmt.Errorf("%w: circle radius must be positive", models.ErrInvalidGeofence)
+		}
+	default:
+		return fmt.Errorf("%w: shape must be 'polygon' or 'circle'", models.ErrInvalidGeofence)
+	}
+
+	if zone.FloorM != nil && zone.CeilingM != nil && *zone.FloorM >= *zone.CeilingM {
+		return fmt.Errorf("%w: floor must be below ceiling", models.ErrInvalidGeofence)
+	}
+
+	return nil
+}
+
+// validateCoordinate validates a horizontal coordinate.
+func validateCoordinate(c models.Coordinate) error {
+	return ValidatePosition(models.Position{Latitude: c.Latitude, Longitude: c.Longitude})
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/meiron-tzhori/Flight-Simulator/internal/environment"
 	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
 )
 
@@ -255,6 +256,32 @@ func TestValidateGoToCommand(t *testing.T) {
 			maxSpeed:  250.0,
 			wantError: true,
 		},
+		{
+			name: "Valid AGL altitude reference",
+			cmd: &models.GoToCommand{
+				Target: models.Position{
+					Latitude:  32.0853,
+					Longitude: 34.7818,
+					Altitude:  150.0,
+				},
+				AltitudeRef: models.AltitudeReferenceAGL,
+			},
+			maxSpeed:  250.0,
+			wantError: false,
+		},
+		{
+			name: "Invalid altitude reference",
+			cmd: &models.GoToCommand{
+				Target: models.Position{
+					Latitude:  32.0853,
+					Longitude: 34.7818,
+					Altitude:  150.0,
+				},
+				AltitudeRef: "qfe",
+			},
+			maxSpeed:  250.0,
+			wantError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -361,6 +388,244 @@ func TestValidateTrajectoryCommand(t *testing.T) {
 	}
 }
 
+func TestValidateTerrainClearance(t *testing.T) {
+	terrain := environment.NewTerrainMap(800.0, 100.0, nil)
+
+	tests := []struct {
+		name      string
+		position  models.Position
+		ref       models.AltitudeReference
+		terrain   *environment.TerrainMap
+		wantError bool
+	}{
+		{
+			name:      "MSL above safety margin",
+			position:  models.Position{Latitude: 32.0, Longitude: 34.7, Altitude: 1000},
+			terrain:   terrain,
+			wantError: false,
+		},
+		{
+			name:      "MSL below safety margin",
+			position:  models.Position{Latitude: 32.0, Longitude: 34.7, Altitude: 850},
+			terrain:   terrain,
+			wantError: true,
+		},
+		{
+			name:      "AGL above safety margin",
+			position:  models.Position{Latitude: 32.0, Longitude: 34.7, Altitude: 150},
+			ref:       models.AltitudeReferenceAGL,
+			terrain:   terrain,
+			wantError: false,
+		},
+		{
+			name:      "AGL below safety margin",
+			position:  models.Position{Latitude: 32.0, Longitude: 34.7, Altitude: 50},
+			ref:       models.AltitudeReferenceAGL,
+			terrain:   terrain,
+			wantError: true,
+		},
+		{
+			name:      "Terrain disabled",
+			position:  models.Position{Latitude: 32.0, Longitude: 34.7, Altitude: 0},
+			terrain:   nil,
+			wantError: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTerrainClearance(tt.position, tt.ref, tt.terrain)
+
+			if tt.wantError {
+				if !errors.Is(err, models.ErrTerrainConflict) {
+					t.Errorf("ValidateTerrainClearance() error = %v, want %v", err, models.ErrTerrainConflict)
+				}
+				var conflict *TerrainConflictError
+				if !errors.As(err, &conflict) {
+					t.Errorf("ValidateTerrainClearance() error is not a *TerrainConflictError")
+				}
+			} else if err != nil {
+				t.Errorf("ValidateTerrainClearance() unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateGeofence(t *testing.T) {
+	square := []models.Coordinate{
+		{Latitude: 32.0, Longitude: 34.0},
+		{Latitude: 32.0, Longitude: 34.1},
+		{Latitude: 32.1, Longitude: 34.1},
+	}
+
+	tests := []struct {
+		name      string
+		zone      models.Geofence
+		wantError bool
+	}{
+		{
+			name:      "Valid polygon",
+			zone:      models.Geofence{Shape: models.GeofenceShapePolygon, Mode: models.GeofenceModeExclusion, Vertices: square},
+			wantError: false,
+		},
+		{
+			name: "Valid circle with limits",
+			zone: models.Geofence{
+				Shape:        models.GeofenceShapeCircle,
+				Mode:         models.GeofenceModeInclusion,
+				Center:       &models.Coordinate{Latitude: 32.0, Longitude: 34.0},
+				RadiusM:      500,
+				FloorM:       ptr(100),
+				CeilingM:     ptr(1000),
+				BreachAction: models.BreachActionReturn,
+			},
+			wantError: false,
+		},
+		{
+			name:      "Polygon with two vertices",
+			zone:      models.Geofence{Shape: models.GeofenceShapePolygon, Mode: models.GeofenceModeExclusion, Vertices: square[:2]},
+			wantError: true,
+		},
+		{
+			name:      "Circle without radius",
+			zone:      models.Geofence{Shape: models.GeofenceShapeCircle, Mode: models.GeofenceModeExclusion, Center: &models.Coordinate{}},
+			wantError: true,
+		},
+		{
+			name:      "Floor above ceiling",
+			zone:      models.Geofence{Shape: models.GeofenceShapePolygon, Mode: models.GeofenceModeExclusion, Vertices: square, FloorM: ptr(1000), CeilingM: ptr(100)},
+			wantError: true,
+		},
+		{
+			name:      "Unknown mode",
+			zone:      models.Geofence{Shape: models.GeofenceShapePolygon, Mode: "avoid", Vertices: square},
+			wantError: true,
+		},
+		{
+			name:      "Unknown breach action",
+			zone:      models.Geofence{Shape: models.GeofenceShapePolygon, Mode: models.GeofenceModeExclusion, Vertices: square, BreachAction: "land"},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateGeofence(&tt.zone)
+
+			if tt.wantError && !errors.Is(err, models.ErrInvalidGeofence) {
+				t.Errorf("ValidateGeofence() error = %v, want %v", err, models.ErrInvalidGeofence)
+			}
+			if !tt.wantError && err != nil {
+				t.Errorf("ValidateGeofence() unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateLandCommand(t *testing.T) {
+	runway := &models.Runway{Threshold: models.Position{Latitude: 32.0, Longitude: 34.0, Altitude: 20}, Heading: 270}
+
+	tests := []struct {
+		name      string
+		cmd       models.LandCommand
+		errorType error
+	}{
+		{
+			name: "Vertical landing",
+			cmd:  models.LandCommand{},
+		},
+		{
+			name: "Runway with glide slope",
+			cmd:  models.LandCommand{Runway: runway, GlideSlope: 3},
+		},
+		{
+			name:      "Runway heading out of range",
+			cmd:       models.LandCommand{Runway: &models.Runway{Threshold: runway.Threshold, Heading: 360}},
+			errorType: models.ErrInvalidHeading,
+		},
+		{
+			name:      "Runway latitude out of range",
+			cmd:       models.LandCommand{Runway: &models.Runway{Threshold: models.Position{Latitude: 95}}},
+			errorType: models.ErrInvalidLatitude,
+		},
+		{
+			name:      "Glide slope too steep",
+			cmd:       models.LandCommand{Runway: runway, GlideSlope: 15},
+			errorType: models.ErrInvalidGlideSlope,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateLandCommand(&tt.cmd)
+
+			if tt.errorType != nil && !errors.Is(err, tt.errorType) {
+				t.Errorf("ValidateLandCommand() error = %v, want %v", err, tt.errorType)
+			}
+			if tt.errorType == nil && err != nil {
+				t.Errorf("ValidateLandCommand() unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateStateUpdate(t *testing.T) {
+	position := models.Position{Latitude: 32.0, Longitude: 34.0, Altitude: 500}
+
+	tests := []struct {
+		name      string
+		update    models.StateUpdate
+		errorType error
+	}{
+		{
+			name:   "Position only",
+			update: models.StateUpdate{Position: position},
+		},
+		{
+			name:   "Position, heading and velocity",
+			update: models.StateUpdate{Position: position, Heading: ptr(90), GroundSpeed: ptr(100), VerticalSpeed: ptr(-5)},
+		},
+		{
+			name:      "Invalid position",
+			update:    models.StateUpdate{Position: models.Position{Latitude: 32.0, Longitude: 190}},
+			errorType: models.ErrInvalidLongitude,
+		},
+		{
+			name:      "Heading out of range",
+			update:    models.StateUpdate{Position: position, Heading: ptr(360)},
+			errorType: models.ErrInvalidHeading,
+		},
+		{
+			name:      "Ground speed exceeds max",
+			update:    models.StateUpdate{Position: position, GroundSpeed: ptr(300)},
+			errorType: models.ErrSpeedExceedsMax,
+		},
+		{
+			name:      "Climb rate exceeds max",
+			update:    models.StateUpdate{Position: position, VerticalSpeed: ptr(20)},
+			errorType: models.ErrInvalidVerticalSpeed,
+		},
+		{
+			name:      "Descent rate exceeds max",
+			update:    models.StateUpdate{Position: position, VerticalSpeed: ptr(-12)},
+			errorType: models.ErrInvalidVerticalSpeed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateStateUpdate(&tt.update, 250.0, 15.0, 10.0)
+
+			if tt.errorType != nil && !errors.Is(err, tt.errorType) {
+				t.Errorf("ValidateStateUpdate() error = %v, want %v", err, tt.errorType)
+			}
+			if tt.errorType == nil && err != nil {
+				t.Errorf("ValidateStateUpdate() unexpected error: %v", err)
+			}
+		})
+	}
+}
+
 // Helper function to create pointer to float64
 func ptr(f float64) *float64 {
 	return &f
@@ -11,11 +11,17 @@ import (
 // Config represents the complete application configuration.
 type Config struct {
 	Server      ServerConfig      `yaml:"server"`
+	GRPC        GRPCConfig        `yaml:"grpc"`
+	MQTT        MQTTConfig        `yaml:"mqtt"`
 	Simulation  SimulationConfig  `yaml:"simulation"`
 	Environment EnvironmentConfig `yaml:"environment"`
 	Logging     LoggingConfig     `yaml:"logging"`
 	Metrics     MetricsConfig     `yaml:"metrics"`
 	Streaming   StreamingConfig   `yaml:"streaming"`
+	Navdata     NavdataConfig     `yaml:"navdata"`
+	Session     SessionConfig     `yaml:"session"`
+	Snapshots   SnapshotConfig    `yaml:"snapshots"`
+	Webhooks    WebhookConfig     `yaml:"webhooks"`
 }
 
 // ServerConfig contains HTTP server settings.
@@ -27,8 +33,23 @@ type ServerConfig struct {
 	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
 }
 
+// GRPCConfig contains gRPC server settings.
+type GRPCConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Host    string `yaml:"host"`
+	Port    int    `yaml:"port"`
+}
+
+// MQTTConfig contains embedded MQTT broker settings.
+type MQTTConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Host    string `yaml:"host"`
+	Port    int    `yaml:"port"`
+}
+
 // SimulationConfig contains simulation engine settings.
 type SimulationConfig struct {
+	AircraftID        string         `yaml:"aircraft_id"` // names the aircraft's topics, defaults to "aircraft-1"
 	TickRateHz        float64        `yaml:"tick_rate_hz"`
 	CommandQueueSize  int            `yaml:"command_queue_size"`
 	InitialPosition   PositionConfig `yaml:"initial_position"`
@@ -41,6 +62,47 @@ type SimulationConfig struct {
 	PositionTolerance float64        `yaml:"position_tolerance"`
 	HeadingChangeRate float64        `yaml:"heading_change_rate"`
 	SpeedChangeRate   float64        `yaml:"speed_change_rate"`
+	Geofence          GeofenceConfig `yaml:"geofence"`
+	Home              PositionConfig `yaml:"home"`         // defaults to the initial position
+	RTHAltitude       float64        `yaml:"rth_altitude"` // meters MSL
+	LostLink          LostLinkConfig `yaml:"lost_link"`
+	TaxiSpeed         float64        `yaml:"taxi_speed"`     // m/s
+	RotationSpeed     float64        `yaml:"rotation_speed"` // m/s, takeoff roll lift-off speed
+	ApproachSpeed     float64        `yaml:"approach_speed"` // m/s, final approach speed
+	GlideSlope        float64        `yaml:"glide_slope"`    // degrees
+	Recorder          RecorderConfig `yaml:"recorder"`
+	Fuel              FuelConfig     `yaml:"fuel"`
+}
+
+// FuelConfig contains fuel model settings. Fuel burns while the engine runs
+// outside the parked and landed phases.
+type FuelConfig struct {
+	Enabled    bool    `yaml:"enabled"`
+	Capacity   float64 `yaml:"capacity"`    // kg on board at start
+	IdleFlow   float64 `yaml:"idle_flow"`   // kg/h at zero ground speed
+	CruiseFlow float64 `yaml:"cruise_flow"` // kg/h in level flight at default_speed
+	ClimbFlow  float64 `yaml:"climb_flow"`  // kg/h added per m/s of climb
+}
+
+// RecorderConfig contains flight track recorder settings.
+type RecorderConfig struct {
+	Enabled    bool          `yaml:"enabled"`
+	MaxSamples int           `yaml:"max_samples"` // oldest samples are dropped first
+	Interval   time.Duration `yaml:"interval"`    // simulation time between samples, 0 = every tick
+}
+
+// LostLinkConfig contains lost-link watchdog settings.
+type LostLinkConfig struct {
+	Enabled bool          `yaml:"enabled"`
+	Timeout time.Duration `yaml:"timeout"`
+	Action  string        `yaml:"action"` // continue, hold, rth, land
+}
+
+// GeofenceConfig contains geofence settings.
+type GeofenceConfig struct {
+	Enabled       bool   `yaml:"enabled"`
+	DefaultAction string `yaml:"default_action"` // event, hold, return
+	AirspaceDir   string `yaml:"airspace_dir"`   // OpenAir/GeoJSON files loaded at startup
 }
 
 // PositionConfig represents a configured position.
@@ -58,10 +120,11 @@ type VelocityConfig struct {
 
 // EnvironmentConfig contains environment settings.
 type EnvironmentConfig struct {
-	Enabled  bool           `yaml:"enabled"`
-	Wind     WindConfig     `yaml:"wind"`
-	Humidity HumidityConfig `yaml:"humidity"`
-	Terrain  TerrainConfig  `yaml:"terrain"`
+	Enabled    bool             `yaml:"enabled"`
+	Wind       WindConfig       `yaml:"wind"`
+	Turbulence TurbulenceConfig `yaml:"turbulence"`
+	Humidity   HumidityConfig   `yaml:"humidity"`
+	Terrain    TerrainConfig    `yaml:"terrain"`
 }
 
 // WindConfig contains wind settings.
@@ -71,6 +134,13 @@ type WindConfig struct {
 	Speed     float64 `yaml:"speed"`
 }
 
+// TurbulenceConfig contains turbulence settings.
+type TurbulenceConfig struct {
+	Enabled   bool    `yaml:"enabled"`
+	Intensity float64 `yaml:"intensity"` // RMS gust speed, m/s
+	Seed      int64   `yaml:"seed"`
+}
+
 // HumidityConfig contains humidity settings.
 type HumidityConfig struct {
 	Enabled bool    `yaml:"enabled"`
@@ -79,8 +149,27 @@ type HumidityConfig struct {
 
 // TerrainConfig contains terrain settings.
 type TerrainConfig struct {
-	Enabled      bool    `yaml:"enabled"`
-	SafetyMargin float64 `yaml:"safety_margin"`
+	Enabled          bool    `yaml:"enabled"`
+	SafetyMargin     float64 `yaml:"safety_margin"`
+	BaseElevation    float64 `yaml:"base_elevation"`
+	ElevationFile    string  `yaml:"elevation_file"`
+	LookAheadSeconds float64 `yaml:"look_ahead_seconds"`
+}
+
+// NavdataConfig contains navigation database settings.
+type NavdataConfig struct {
+	Files []string `yaml:"files"` // CSV (OurAirports-style) or JSON fix files
+}
+
+// SessionConfig contains session log settings.
+type SessionConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Dir     string `yaml:"dir"` // a new session log is created here for every run
+}
+
+// SnapshotConfig contains state snapshot settings.
+type SnapshotConfig struct {
+	Dir string `yaml:"dir"` // snapshots are saved here as <id>.json, empty = memory only
 }
 
 // LoggingConfig contains logging settings.
@@ -99,10 +188,22 @@ type MetricsConfig struct {
 
 // StreamingConfig contains SSE streaming settings.
 type StreamingConfig struct {
-	Enabled      bool `yaml:"enabled"`
-	UpdateRateHz int  `yaml:"update_rate_hz"`
-	BufferSize   int  `yaml:"buffer_size"`
-	MaxClients   int  `yaml:"max_clients"`
+	Enabled      bool   `yaml:"enabled"`
+	UpdateRateHz int    `yaml:"update_rate_hz"`
+	BufferSize   int    `yaml:"buffer_size"`
+	MaxClients   int    `yaml:"max_clients"`
+	SlowPolicy   string `yaml:"slow_policy"` // drop_newest, drop_oldest, coalesce or disconnect
+	MaxDrops     int    `yaml:"max_drops"`   // disconnect: states dropped (SSE: events missed) before eviction
+}
+
+// WebhookConfig contains webhook delivery settings. Zero values use the
+// defaults.
+type WebhookConfig struct {
+	Timeout        time.Duration `yaml:"timeout"`         // per attempt
+	MaxAttempts    int           `yaml:"max_attempts"`    // including the first
+	InitialBackoff time.Duration `yaml:"initial_backoff"` // doubled after every failed attempt
+	MaxBackoff     time.Duration `yaml:"max_backoff"`
+	CallbackSecret string        `yaml:"callback_secret"` // signs the callbacks of commands that set no secret
 }
 
 // Load loads configuration from a YAML file.
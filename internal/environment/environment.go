@@ -1,21 +1,26 @@
 package environment
 
 import (
+	"fmt"
+
 	"github.com/meiron-tzhori/Flight-Simulator/internal/config"
 	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
 )
 
 // Environment manages environmental effects on the aircraft.
 type Environment struct {
-	wind     *WindEffect
-	humidity *float64
-	enabled  bool
+	wind       *WindEffect
+	turbulence *Turbulence
+	humidity   *float64
+	terrain    *TerrainMap
+	enabled    bool
 }
 
 // New creates a new environment from configuration.
-func New(cfg config.EnvironmentConfig) *Environment {
+// Returns nil if the environment is disabled.
+func New(cfg config.EnvironmentConfig) (*Environment, error) {
 	if !cfg.Enabled {
-		return nil
+		return nil, nil
 	}
 
 	env := &Environment{
@@ -27,12 +32,30 @@ func New(cfg config.EnvironmentConfig) *Environment {
 		env.wind = NewWindEffect(cfg.Wind.Direction, cfg.Wind.Speed)
 	}
 
+	// Initialize turbulence if enabled
+	if cfg.Turbulence.Enabled {
+		env.turbulence = NewTurbulence(cfg.Turbulence.Intensity, cfg.Turbulence.Seed)
+	}
+
 	// Initialize humidity if enabled
 	if cfg.Humidity.Enabled {
 		env.humidity = &cfg.Humidity.Value
 	}
 
-	return env
+	// Initialize terrain if enabled
+	if cfg.Terrain.Enabled {
+		var grid *ElevationGrid
+		if cfg.Terrain.ElevationFile != "" {
+			var err error
+			grid, err = LoadElevationGrid(cfg.Terrain.ElevationFile)
+			if err != nil {
+				return nil, fmt.Errorf("terrain: %w", err)
+			}
+		}
+		env.terrain = NewTerrainMap(cfg.Terrain.BaseElevation, cfg.Terrain.SafetyMargin, grid)
+	}
+
+	return env, nil
 }
 
 // ApplyEffects applies all enabled environmental effects to the velocity.
@@ -49,6 +72,11 @@ func (e *Environment) ApplyEffects(heading float64, velocity models.Velocity) mo
 		result = e.wind.Apply(heading, result)
 	}
 
+	// Apply turbulence gusts
+	if e.turbulence != nil {
+		result = e.turbulence.Apply(result)
+	}
+
 	// Future: Apply other effects like humidity, air density, etc.
 	// if e.humidity != nil {
 	//     result = applyHumidityEffect(result, *e.humidity)
@@ -69,6 +97,11 @@ func (e *Environment) GetState() *models.EnvironmentState {
 		state.Wind = e.wind.GetVector()
 	}
 
+	if e.turbulence != nil {
+		intensity := e.turbulence.Intensity()
+		state.Turbulence = &intensity
+	}
+
 	if e.humidity != nil {
 		state.Humidity = e.humidity
 	}
@@ -76,6 +109,23 @@ func (e *Environment) GetState() *models.EnvironmentState {
 	return state
 }
 
+// Advance moves time-dependent effects forward by dt seconds. Like SetWind,
+// it must only be called by the simulation loop.
+func (e *Environment) Advance(dt float64) {
+	if e == nil || e.turbulence == nil {
+		return
+	}
+	e.turbulence.Advance(dt)
+}
+
+// ResetTurbulence restarts the turbulence gust sequence from its seed.
+func (e *Environment) ResetTurbulence() {
+	if e == nil || e.turbulence == nil {
+		return
+	}
+	e.turbulence.Reset()
+}
+
 // GetWind returns the wind effect if enabled.
 func (e *Environment) GetWind() *WindEffect {
 	if e == nil {
@@ -84,6 +134,26 @@ func (e *Environment) GetWind() *WindEffect {
 	return e.wind
 }
 
+// SetWind replaces the wind, or removes it if wind is nil. The wind is only
+// read by the simulation loop, which must be the caller.
+func (e *Environment) SetWind(wind *models.WindVector) {
+	if e == nil {
+		return
+	}
+	e.wind = nil
+	if wind != nil {
+		e.wind = NewWindEffect(wind.Direction, wind.Speed)
+	}
+}
+
+// GetTerrain returns the terrain map if enabled.
+func (e *Environment) GetTerrain() *TerrainMap {
+	if e == nil {
+		return nil
+	}
+	return e.terrain
+}
+
 // IsEnabled returns whether environment effects are enabled.
 func (e *Environment) IsEnabled() bool {
 	return e != nil && e.enabled
@@ -0,0 +1,120 @@
+package environment
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+)
+
+// TerrainMap provides terrain elevation lookups.
+// Elevations come from an optional regular lat/lon grid; points outside the
+// grid fall back to a constant base elevation.
+type TerrainMap struct {
+	baseElevation float64 // meters MSL
+	safetyMargin  float64 // meters above terrain
+	grid          *ElevationGrid
+}
+
+// ElevationGrid is a regular grid of elevation samples.
+// Row 0 is the southern edge and column 0 is the western edge.
+type ElevationGrid struct {
+	OriginLat  float64     `json:"origin_lat"`  // degrees, south-west corner
+	OriginLon  float64     `json:"origin_lon"`  // degrees, south-west corner
+	Spacing    float64     `json:"spacing_deg"` // degrees between samples
+	Elevations [][]float64 `json:"elevations"`  // meters MSL, [row][col]
+}
+
+// NewTerrainMap creates a terrain map with an optional elevation grid.
+func NewTerrainMap(baseElevation, safetyMargin float64, grid *ElevationGrid) *TerrainMap {
+	return &TerrainMap{
+		baseElevation: baseElevation,
+		safetyMargin:  safetyMargin,
+		grid:          grid,
+	}
+}
+
+// LoadElevationGrid loads an elevation grid from a JSON file.
+func LoadElevationGrid(path string) (*ElevationGrid, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read elevation file: %w", err)
+	}
+
+	var grid ElevationGrid
+	if err := json.Unmarshal(data, &grid); err != nil {
+		return nil, fmt.Errorf("failed to parse elevation file: %w", err)
+	}
+
+	if grid.Spacing <= 0 {
+		return nil, fmt.Errorf("elevation grid spacing must be positive")
+	}
+	if len(grid.Elevations) < 2 || len(grid.Elevations[0]) < 2 {
+		return nil, fmt.Errorf("elevation grid must be at least 2x2")
+	}
+	for i, row := range grid.Elevations {
+		if len(row) != len(grid.Elevations[0]) {
+			return nil, fmt.Errorf("elevation grid row %d has %d columns, want %d", i, len(row), len(grid.Elevations[0]))
+		}
+	}
+
+	return &grid, nil
+}
+
+// GetAltitude returns the terrain elevation (meters MSL) at a position.
+// Inside the grid the elevation is bilinearly interpolated.
+func (t *TerrainMap) GetAltitude(lat, lon float64) float64 {
+	if t == nil {
+		return 0
+	}
+	if t.grid == nil {
+		return t.baseElevation
+	}
+
+	// Fractional grid coordinates
+	row := (lat - t.grid.OriginLat) / t.grid.Spacing
+	col := (lon - t.grid.OriginLon) / t.grid.Spacing
+
+	// Tolerance for floating-point error on the grid edges
+	const edgeEpsilon = 1e-9
+
+	rows := len(t.grid.Elevations)
+	cols := len(t.grid.Elevations[0])
+	if row < -edgeEpsilon || col < -edgeEpsilon ||
+		row > float64(rows-1)+edgeEpsilon || col > float64(cols-1)+edgeEpsilon {
+		return t.baseElevation
+	}
+	row = clampFloat(row, 0, float64(rows-1))
+	col = clampFloat(col, 0, float64(cols-1))
+
+	// Surrounding cell (clamped so the far edges are still inside)
+	r0 := int(math.Min(math.Floor(row), float64(rows-2)))
+	c0 := int(math.Min(math.Floor(col), float64(cols-2)))
+	fr := row - float64(r0)
+	fc := col - float64(c0)
+
+	// Bilinear interpolation
+	south := t.grid.Elevations[r0][c0]*(1-fc) + t.grid.Elevations[r0][c0+1]*fc
+	north := t.grid.Elevations[r0+1][c0]*(1-fc) + t.grid.Elevations[r0+1][c0+1]*fc
+
+	return south*(1-fr) + north*fr
+}
+
+// SafetyMargin returns the required clearance above terrain in meters.
+func (t *TerrainMap) SafetyMargin() float64 {
+	if t == nil {
+		return 0
+	}
+	return t.safetyMargin
+}
+
+// MinimumSafeAltitude returns the lowest altitude (meters MSL) that keeps
+// the safety margin above terrain at a position.
+func (t *TerrainMap) MinimumSafeAltitude(lat, lon float64) float64 {
+	return t.GetAltitude(lat, lon) + t.SafetyMargin()
+}
+
+// clampFloat clamps a value between min and max.
+func clampFloat(value, min, max float64) float64 {
+	return math.Max(min, math.Min(max, value))
+}
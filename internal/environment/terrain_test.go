@@ -0,0 +1,98 @@
+package environment
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testGrid() *ElevationGrid {
+	return &ElevationGrid{
+		OriginLat: 32.0,
+		OriginLon: 34.0,
+		Spacing:   0.1,
+		Elevations: [][]float64{
+			{0, 100},
+			{200, 300},
+		},
+	}
+}
+
+func TestTerrainMap_GetAltitude(t *testing.T) {
+	terrain := NewTerrainMap(50.0, 100.0, testGrid())
+
+	tests := []struct {
+		name     string
+		lat      float64
+		lon      float64
+		expected float64
+	}{
+		{name: "South-west corner", lat: 32.0, lon: 34.0, expected: 0},
+		{name: "South-east corner", lat: 32.0, lon: 34.1, expected: 100},
+		{name: "North-west corner", lat: 32.1, lon: 34.0, expected: 200},
+		{name: "North-east corner", lat: 32.1, lon: 34.1, expected: 300},
+		{name: "Cell center", lat: 32.05, lon: 34.05, expected: 150},
+		{name: "Outside grid uses base elevation", lat: 31.0, lon: 34.0, expected: 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := terrain.GetAltitude(tt.lat, tt.lon)
+			if math.Abs(result-tt.expected) > 0.01 {
+				t.Errorf("GetAltitude(%.2f, %.2f) = %.2f, want %.2f", tt.lat, tt.lon, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTerrainMap_NilSafe(t *testing.T) {
+	var terrain *TerrainMap
+
+	if alt := terrain.GetAltitude(32.0, 34.0); alt != 0 {
+		t.Errorf("nil GetAltitude() = %.2f, want 0", alt)
+	}
+	if margin := terrain.SafetyMargin(); margin != 0 {
+		t.Errorf("nil SafetyMargin() = %.2f, want 0", margin)
+	}
+}
+
+func TestTerrainMap_MinimumSafeAltitude(t *testing.T) {
+	terrain := NewTerrainMap(800.0, 100.0, nil)
+
+	if alt := terrain.MinimumSafeAltitude(32.0, 34.0); alt != 900.0 {
+		t.Errorf("MinimumSafeAltitude() = %.2f, want 900.0", alt)
+	}
+}
+
+func TestLoadElevationGrid(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "valid.json")
+	if err := os.WriteFile(valid, []byte(`{
+		"origin_lat": 32.0, "origin_lon": 34.0, "spacing_deg": 0.01,
+		"elevations": [[10, 20], [30, 40]]
+	}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	grid, err := LoadElevationGrid(valid)
+	if err != nil {
+		t.Fatalf("LoadElevationGrid() error = %v", err)
+	}
+	if grid.Elevations[1][1] != 40 {
+		t.Errorf("Elevations[1][1] = %.2f, want 40", grid.Elevations[1][1])
+	}
+
+	ragged := filepath.Join(dir, "ragged.json")
+	if err := os.WriteFile(ragged, []byte(`{
+		"origin_lat": 32.0, "origin_lon": 34.0, "spacing_deg": 0.01,
+		"elevations": [[10, 20], [30]]
+	}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadElevationGrid(ragged); err == nil {
+		t.Error("LoadElevationGrid() expected error for ragged grid, got nil")
+	}
+}
@@ -0,0 +1,60 @@
+package environment
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+)
+
+// gustTimeConstant is the correlation time of turbulence gusts (seconds).
+const gustTimeConstant = 5.0
+
+// Turbulence adds random gusts to aircraft velocity. The gusts along the
+// heading and in the vertical are first-order Gauss-Markov processes driven
+// by a seeded generator, so a given seed always produces the same gusts.
+type Turbulence struct {
+	intensity float64 // RMS gust speed, m/s
+	seed      int64
+	rng       *rand.Rand
+	along     float64 // gust along the heading, m/s
+	vertical  float64 // vertical gust, m/s
+}
+
+// NewTurbulence creates turbulence with the given RMS gust speed (m/s).
+func NewTurbulence(intensity float64, seed int64) *Turbulence {
+	t := &Turbulence{
+		intensity: intensity,
+		seed:      seed,
+	}
+	t.Reset()
+	return t
+}
+
+// Reset restarts the gust sequence from the seed.
+func (t *Turbulence) Reset() {
+	t.rng = rand.New(rand.NewSource(t.seed))
+	t.along = 0
+	t.vertical = 0
+}
+
+// Advance moves the gusts forward by dt seconds.
+func (t *Turbulence) Advance(dt float64) {
+	decay := math.Exp(-dt / gustTimeConstant)
+	spread := t.intensity * math.Sqrt(1-decay*decay)
+	t.along = t.along*decay + spread*t.rng.NormFloat64()
+	t.vertical = t.vertical*decay + spread*t.rng.NormFloat64()
+}
+
+// Apply adds the current gusts to velocity.
+func (t *Turbulence) Apply(velocity models.Velocity) models.Velocity {
+	return models.Velocity{
+		GroundSpeed:   math.Max(velocity.GroundSpeed+t.along, 0),
+		VerticalSpeed: velocity.VerticalSpeed + t.vertical,
+	}
+}
+
+// Intensity returns the RMS gust speed (m/s).
+func (t *Turbulence) Intensity() float64 {
+	return t.intensity
+}
@@ -0,0 +1,58 @@
+package environment
+
+import (
+	"math"
+	"testing"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+)
+
+func TestTurbulence_Deterministic(t *testing.T) {
+	velocity := models.Velocity{GroundSpeed: 100}
+	a, b, c := NewTurbulence(2, 7), NewTurbulence(2, 7), NewTurbulence(2, 8)
+
+	differs := false
+	for i := 0; i < 100; i++ {
+		a.Advance(0.1)
+		b.Advance(0.1)
+		c.Advance(0.1)
+		if a.Apply(velocity) != b.Apply(velocity) {
+			t.Fatalf("step %d: same seed produced %+v and %+v", i, a.Apply(velocity), b.Apply(velocity))
+		}
+		if a.Apply(velocity) != c.Apply(velocity) {
+			differs = true
+		}
+	}
+	if !differs {
+		t.Error("different seeds produced the same gusts")
+	}
+
+	// Reset replays the sequence from the start
+	first := NewTurbulence(2, 7)
+	first.Advance(0.1)
+	a.Reset()
+	a.Advance(0.1)
+	if a.Apply(velocity) != first.Apply(velocity) {
+		t.Errorf("after Reset() = %+v, want %+v", a.Apply(velocity), first.Apply(velocity))
+	}
+}
+
+func TestTurbulence_Intensity(t *testing.T) {
+	turbulence := NewTurbulence(3, 1)
+	velocity := models.Velocity{GroundSpeed: 100}
+
+	var sumSquares float64
+	const steps = 200000
+	for i := 0; i < steps; i++ {
+		turbulence.Advance(0.1)
+		gust := turbulence.Apply(velocity).GroundSpeed - velocity.GroundSpeed
+		sumSquares += gust * gust
+	}
+
+	if rms := math.Sqrt(sumSquares / steps); math.Abs(rms-3) > 0.3 {
+		t.Errorf("RMS gust = %.2f m/s, want about 3", rms)
+	}
+	if got := turbulence.Apply(models.Velocity{}).GroundSpeed; got < 0 {
+		t.Errorf("ground speed = %f, want it clamped at 0", got)
+	}
+}
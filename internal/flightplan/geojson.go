@@ -0,0 +1,135 @@
+package flightplan
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+)
+
+// geoJSONObject covers FeatureCollection, Feature and bare geometry objects.
+type geoJSONObject struct {
+	Type        string          `json:"type"`
+	Features    []geoJSONObject `json:"features,omitempty"`
+	Geometry    *geoJSONObject  `json:"geometry,omitempty"`
+	Properties  geoJSONProps    `json:"properties,omitempty"`
+	Coordinates json.RawMessage `json:"coordinates,omitempty"`
+}
+
+// geoJSONProps holds per-coordinate timestamps in the two common conventions:
+// "coordTimes" (togeojson) and "coordinateProperties.times".
+type geoJSONProps struct {
+	Name                 string          `json:"name"`
+	CoordTimes           json.RawMessage `json:"coordTimes"`
+	CoordinateProperties struct {
+		Times json.RawMessage `json:"times"`
+	} `json:"coordinateProperties"`
+}
+
+// parseTrackGeoJSON reads the first LineString or MultiLineString feature of
+// a GeoJSON file. If the file has no lines, its Point features are used as
+// waypoints in order. Altitudes are meters MSL.
+func parseTrackGeoJSON(data []byte, result *TrackResult) ([]trackPoint, error) {
+	var root geoJSONObject
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("%w: invalid GeoJSON: %v", models.ErrInvalidTrack, err)
+	}
+
+	var features []geoJSONObject
+	switch root.Type {
+	case "FeatureCollection":
+		features = root.Features
+	case "Feature":
+		features = []geoJSONObject{root}
+	default:
+		features = []geoJSONObject{{Type: "Feature", Geometry: &root}}
+	}
+
+	var lines []geoJSONObject
+	for _, f := range features {
+		if f.Geometry != nil && (f.Geometry.Type == "LineString" || f.Geometry.Type == "MultiLineString") {
+			lines = append(lines, f)
+		}
+	}
+
+	if len(lines) > 0 {
+		if len(lines) > 1 {
+			result.warnf("using line %q, %d other lines ignored", lines[0].Properties.Name, len(lines)-1)
+		}
+		return geoJSONLinePoints(lines[0])
+	}
+
+	var points []trackPoint
+	for i, f := range features {
+		if f.Geometry == nil || f.Geometry.Type != "Point" {
+			continue
+		}
+		var position []float64
+		if err := json.Unmarshal(f.Geometry.Coordinates, &position); err != nil {
+			return nil, fmt.Errorf("%w: feature %d: invalid coordinates: %v", models.ErrInvalidTrack, i, err)
+		}
+		point, err := geoJSONPoint(position)
+		if err != nil {
+			return nil, fmt.Errorf("%w: feature %d: %v", models.ErrInvalidTrack, i, err)
+		}
+		points = append(points, point)
+	}
+	return points, nil
+}
+
+// geoJSONLinePoints returns the points of a LineString or MultiLineString
+// feature with their timestamps.
+func geoJSONLinePoints(feature geoJSONObject) ([]trackPoint, error) {
+	times := feature.Properties.CoordTimes
+	if len(times) == 0 {
+		times = feature.Properties.CoordinateProperties.Times
+	}
+
+	var lines [][][]float64
+	var lineTimes [][]string
+	if feature.Geometry.Type == "LineString" {
+		var line [][]float64
+		if err := json.Unmarshal(feature.Geometry.Coordinates, &line); err != nil {
+			return nil, fmt.Errorf("%w: invalid LineString coordinates: %v", models.ErrInvalidTrack, err)
+		}
+		lines = [][][]float64{line}
+		var t []string
+		if json.Unmarshal(times, &t) == nil {
+			lineTimes = [][]string{t}
+		}
+	} else {
+		if err := json.Unmarshal(feature.Geometry.Coordinates, &lines); err != nil {
+			return nil, fmt.Errorf("%w: invalid MultiLineString coordinates: %v", models.ErrInvalidTrack, err)
+		}
+		_ = json.Unmarshal(times, &lineTimes) // timestamps are optional
+	}
+
+	var points []trackPoint
+	for l, line := range lines {
+		for i, position := range line {
+			point, err := geoJSONPoint(position)
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d point %d: %v", models.ErrInvalidTrack, l, i, err)
+			}
+			if l < len(lineTimes) && i < len(lineTimes[l]) {
+				point.time = parseTime(lineTimes[l][i])
+			}
+			points = append(points, point)
+		}
+	}
+	return points, nil
+}
+
+// geoJSONPoint converts a [lon, lat(, alt)] position.
+func geoJSONPoint(position []float64) (trackPoint, error) {
+	if len(position) < 2 {
+		return trackPoint{}, fmt.Errorf("position needs longitude and latitude")
+	}
+	point := trackPoint{lat: position[1], lon: position[0]}
+	if len(position) > 2 {
+		altitude := position[2]
+		point.altitude = &altitude
+		point.altitudeRef = models.AltitudeReferenceMSL
+	}
+	return point, nil
+}
@@ -0,0 +1,97 @@
+package flightplan
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+)
+
+// gpxFile is the subset of GPX 1.0/1.1 used here. Element names are matched
+// without namespaces so both versions parse.
+type gpxFile struct {
+	Routes []struct {
+		Name   string     `xml:"name"`
+		Points []gpxPoint `xml:"rtept"`
+	} `xml:"rte"`
+	Tracks []struct {
+		Name     string `xml:"name"`
+		Segments []struct {
+			Points []gpxPoint `xml:"trkpt"`
+		} `xml:"trkseg"`
+	} `xml:"trk"`
+}
+
+type gpxPoint struct {
+	Lat       float64 `xml:"lat,attr"`
+	Lon       float64 `xml:"lon,attr"`
+	Elevation string  `xml:"ele"`
+	Time      string  `xml:"time"`
+	Speed     string  `xml:"speed"` // GPX 1.0, m/s
+	// GPX 1.1 speed extensions, either directly or nested as in the Garmin
+	// TrackPointExtension
+	Extensions struct {
+		Speed  string `xml:"speed"`
+		Nested []struct {
+			Speed string `xml:"speed"`
+		} `xml:",any"`
+	} `xml:"extensions"`
+}
+
+// parseGPX reads the first route of a GPX file, or the first track (all
+// segments joined) if it has no routes.
+func parseGPX(data []byte, result *TrackResult) ([]trackPoint, error) {
+	var file gpxFile
+	if err := xml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%w: invalid GPX: %v", models.ErrInvalidTrack, err)
+	}
+
+	var source []gpxPoint
+	switch {
+	case len(file.Routes) > 0:
+		source = file.Routes[0].Points
+		if len(file.Routes) > 1 || len(file.Tracks) > 0 {
+			result.warnf("using route %q, %d other routes and tracks ignored", file.Routes[0].Name, len(file.Routes)-1+len(file.Tracks))
+		}
+	case len(file.Tracks) > 0:
+		for _, segment := range file.Tracks[0].Segments {
+			source = append(source, segment.Points...)
+		}
+		if len(file.Tracks) > 1 {
+			result.warnf("using track %q, %d other tracks ignored", file.Tracks[0].Name, len(file.Tracks)-1)
+		}
+	}
+
+	points := make([]trackPoint, 0, len(source))
+	for _, p := range source {
+		point := trackPoint{
+			lat:         p.Lat,
+			lon:         p.Lon,
+			altitude:    parseOptionalFloat(p.Elevation),
+			altitudeRef: models.AltitudeReferenceMSL,
+			time:        parseTime(p.Time),
+			speed:       parseOptionalFloat(p.Speed),
+		}
+		if point.speed == nil {
+			point.speed = parseOptionalFloat(p.Extensions.Speed)
+		}
+		for _, ext := range p.Extensions.Nested {
+			if point.speed == nil {
+				point.speed = parseOptionalFloat(ext.Speed)
+			}
+		}
+		points = append(points, point)
+	}
+	return points, nil
+}
+
+// parseOptionalFloat parses a number, returning nil if it is empty or invalid.
+func parseOptionalFloat(text string) *float64 {
+	value, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+	if err != nil {
+		return nil
+	}
+	return &value
+}
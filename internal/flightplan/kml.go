@@ -0,0 +1,178 @@
+package flightplan
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+)
+
+// kmlPlacemark is the subset of a KML Placemark used here.
+type kmlPlacemark struct {
+	Name          string            `xml:"name"`
+	Point         *kmlGeometry      `xml:"Point"`
+	LineString    *kmlGeometry      `xml:"LineString"`
+	MultiGeometry *kmlMultiGeometry `xml:"MultiGeometry"`
+	Track         *kmlTrack         `xml:"Track"` // gx:Track
+}
+
+type kmlGeometry struct {
+	AltitudeMode string `xml:"altitudeMode"`
+	Coordinates  string `xml:"coordinates"`
+}
+
+type kmlMultiGeometry struct {
+	LineStrings []kmlGeometry `xml:"LineString"`
+}
+
+type kmlTrack struct {
+	AltitudeMode string   `xml:"altitudeMode"`
+	When         []string `xml:"when"`
+	Coords       []string `xml:"coord"` // gx:coord, "lon lat alt"
+}
+
+// parseKML reads the first LineString or gx:Track in a KML file. If the file
+// has no lines, its Point placemarks are used as waypoints in document order.
+func parseKML(data []byte, result *TrackResult) ([]trackPoint, error) {
+	placemarks, err := decodePlacemarks(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid KML: %v", models.ErrInvalidTrack, err)
+	}
+
+	var lines []*kmlPlacemark
+	for i := range placemarks {
+		pm := &placemarks[i]
+		if pm.LineString != nil || pm.Track != nil || (pm.MultiGeometry != nil && len(pm.MultiGeometry.LineStrings) > 0) {
+			lines = append(lines, pm)
+		}
+	}
+
+	if len(lines) > 0 {
+		if len(lines) > 1 {
+			result.warnf("using line %q, %d other lines ignored", lines[0].Name, len(lines)-1)
+		}
+		return kmlLinePoints(lines[0])
+	}
+
+	var points []trackPoint
+	for _, pm := range placemarks {
+		if pm.Point == nil {
+			continue
+		}
+		coordinates, err := parseKMLCoordinates(pm.Point.Coordinates, pm.Point.AltitudeMode)
+		if err != nil {
+			return nil, fmt.Errorf("%w: placemark %q: %v", models.ErrInvalidTrack, pm.Name, err)
+		}
+		points = append(points, coordinates...)
+	}
+	return points, nil
+}
+
+// decodePlacemarks returns every Placemark in the document, however deeply
+// it is nested in Documents and Folders.
+func decodePlacemarks(data []byte) ([]kmlPlacemark, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var placemarks []kmlPlacemark
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return placemarks, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local == "Placemark" {
+			var pm kmlPlacemark
+			if err := decoder.DecodeElement(&pm, &start); err != nil {
+				return nil, err
+			}
+			placemarks = append(placemarks, pm)
+		}
+	}
+}
+
+// kmlLinePoints returns the points of a line placemark.
+func kmlLinePoints(pm *kmlPlacemark) ([]trackPoint, error) {
+	if pm.Track != nil {
+		return kmlTrackPoints(pm.Track)
+	}
+
+	geometries := []kmlGeometry{}
+	if pm.LineString != nil {
+		geometries = append(geometries, *pm.LineString)
+	} else {
+		geometries = pm.MultiGeometry.LineStrings
+	}
+
+	var points []trackPoint
+	for _, g := range geometries {
+		coordinates, err := parseKMLCoordinates(g.Coordinates, g.AltitudeMode)
+		if err != nil {
+			return nil, fmt.Errorf("%w: placemark %q: %v", models.ErrInvalidTrack, pm.Name, err)
+		}
+		points = append(points, coordinates...)
+	}
+	return points, nil
+}
+
+// kmlTrackPoints returns the timestamped points of a gx:Track.
+func kmlTrackPoints(track *kmlTrack) ([]trackPoint, error) {
+	points := make([]trackPoint, 0, len(track.Coords))
+	for i, coord := range track.Coords {
+		point, err := parseKMLTuple(strings.Fields(coord), track.AltitudeMode)
+		if err != nil {
+			return nil, fmt.Errorf("%w: gx:coord %d: %v", models.ErrInvalidTrack, i, err)
+		}
+		if i < len(track.When) {
+			point.time = parseTime(track.When[i])
+		}
+		points = append(points, point)
+	}
+	return points, nil
+}
+
+// parseKMLCoordinates parses a KML coordinates element: whitespace-separated
+// "lon,lat[,alt]" tuples.
+func parseKMLCoordinates(text, altitudeMode string) ([]trackPoint, error) {
+	var points []trackPoint
+	for _, tuple := range strings.Fields(text) {
+		point, err := parseKMLTuple(strings.Split(tuple, ","), altitudeMode)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, point)
+	}
+	return points, nil
+}
+
+// parseKMLTuple parses a lon, lat[, alt] tuple. Altitudes are kept for the
+// absolute and relativeToGround altitude modes; clampToGround (the KML
+// default) ignores them.
+func parseKMLTuple(fields []string, altitudeMode string) (trackPoint, error) {
+	if len(fields) < 2 {
+		return trackPoint{}, fmt.Errorf("invalid coordinate %q", strings.Join(fields, ","))
+	}
+	lon, lonErr := strconv.ParseFloat(fields[0], 64)
+	lat, latErr := strconv.ParseFloat(fields[1], 64)
+	if lonErr != nil || latErr != nil {
+		return trackPoint{}, fmt.Errorf("invalid coordinate %q", strings.Join(fields, ","))
+	}
+
+	point := trackPoint{lat: lat, lon: lon}
+	if len(fields) < 3 {
+		return point, nil
+	}
+	switch strings.TrimSpace(altitudeMode) {
+	case "absolute":
+		point.altitude = parseOptionalFloat(fields[2])
+		point.altitudeRef = models.AltitudeReferenceMSL
+	case "relativeToGround":
+		point.altitude = parseOptionalFloat(fields[2])
+		point.altitudeRef = models.AltitudeReferenceAGL
+	}
+	return point, nil
+}
@@ -0,0 +1,449 @@
+package flightplan
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+)
+
+// MissionFormat identifies a mission file format.
+type MissionFormat string
+
+const (
+	FormatPlan MissionFormat = "plan" // QGroundControl .plan JSON
+	FormatWPL  MissionFormat = "wpl"  // QGC WPL 110 text (MAVLink waypoint file)
+)
+
+// wplHeader is the first line of a QGC WPL 110 file.
+const wplHeader = "QGC WPL 110"
+
+// MAVLink mission commands (MAV_CMD).
+const (
+	mavCmdNavWaypoint       = 16
+	mavCmdNavLoiterUnlim    = 17
+	mavCmdNavLoiterTurns    = 18
+	mavCmdNavLoiterTime     = 19
+	mavCmdNavReturnToLaunch = 20
+	mavCmdNavLand           = 21
+	mavCmdNavTakeoff        = 22
+	mavCmdDoChangeSpeed     = 178
+)
+
+// MAVLink coordinate frames (MAV_FRAME).
+const (
+	mavFrameGlobal            = 0  // altitude MSL
+	mavFrameMission           = 2  // not a position (DO_ commands)
+	mavFrameGlobalRelativeAlt = 3  // altitude relative to home
+	mavFrameGlobalInt         = 5  // as mavFrameGlobal
+	mavFrameGlobalRelativeInt = 6  // as mavFrameGlobalRelativeAlt
+	mavFrameGlobalTerrainAlt  = 10 // altitude above terrain
+	mavFrameGlobalTerrainInt  = 11 // as mavFrameGlobalTerrainAlt
+)
+
+// QGroundControl .plan file values.
+const (
+	planFileType               = "Plan"
+	planGroundStation          = "QGroundControl"
+	planFileVersion            = 1
+	planMissionVersion         = 2
+	planSimpleItem             = "SimpleItem"
+	planFirmwareTypeGeneric    = 0
+	planVehicleTypeFixedWing   = 1
+	planAltitudeModeAbsolute   = 2
+	planAltitudeModeAboveTerra = 3
+)
+
+// defaultLoiterRadius is used for loiter items without a radius (meters).
+const defaultLoiterRadius = 50.0
+
+// MissionOptions provides defaults for mission conversion.
+type MissionOptions struct {
+	Home  models.Position // used for relative altitudes when the file has no home
+	Speed float64         // m/s, used to convert loiter turns into a hold time
+}
+
+// MissionResult is a parsed mission and any non-fatal warnings about items
+// that could not be converted.
+type MissionResult struct {
+	Trajectory *models.TrajectoryCommand `json:"trajectory"`
+	Home       models.Position           `json:"home"`
+	Warnings   []string                  `json:"warnings,omitempty"`
+}
+
+// missionItem is a MAVLink mission item in either file format.
+type missionItem struct {
+	command int
+	frame   int
+	params  [7]float64 // param1-4, latitude, longitude, altitude
+}
+
+// DetectMissionFormat guesses the format of a mission file from its content.
+func DetectMissionFormat(data []byte) MissionFormat {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("QGC WPL")) {
+		return FormatWPL
+	}
+	return FormatPlan
+}
+
+// ParseMission parses a mission file into a trajectory command.
+func ParseMission(data []byte, format MissionFormat, opts MissionOptions) (*MissionResult, error) {
+	var items []missionItem
+	var home *models.Position
+	var warnings []string
+	var err error
+
+	switch format {
+	case FormatPlan:
+		items, home, warnings, err = parsePlan(data)
+	case FormatWPL:
+		items, home, err = parseWPL(data)
+	default:
+		return nil, fmt.Errorf("%w: unsupported mission format %q", models.ErrInvalidMission, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MissionResult{Home: opts.Home, Warnings: warnings}
+	if home != nil {
+		result.Home = *home
+	}
+	result.convert(items, opts)
+
+	if len(result.Trajectory.Waypoints) == 0 {
+		return nil, fmt.Errorf("%w: mission contains no waypoints", models.ErrInvalidMission)
+	}
+	return result, nil
+}
+
+// convert maps mission items to trajectory waypoints.
+func (r *MissionResult) convert(items []missionItem, opts MissionOptions) {
+	r.Trajectory = &models.TrajectoryCommand{}
+	var speed *float64
+
+	for i, item := range items {
+		switch item.command {
+		case mavCmdNavWaypoint, mavCmdNavTakeoff, mavCmdNavLoiterTime, mavCmdNavLoiterTurns, mavCmdNavLoiterUnlim:
+			waypoint, ok := r.waypoint(i, item)
+			if !ok {
+				continue
+			}
+			waypoint.Speed = speed
+
+			switch item.command {
+			case mavCmdNavLoiterTime:
+				waypoint.HoldSeconds = item.params[0]
+			case mavCmdNavLoiterTurns:
+				waypoint.HoldSeconds = loiterTurnsSeconds(item, speed, opts.Speed)
+			}
+			r.Trajectory.Waypoints = append(r.Trajectory.Waypoints, waypoint)
+
+			if item.command == mavCmdNavLoiterUnlim {
+				// The aircraft stays at the end of the trajectory
+				if i < len(items)-1 {
+					r.warnf("item %d: unlimited loiter ends the mission, %d later items ignored", i, len(items)-1-i)
+				}
+				return
+			}
+
+		case mavCmdDoChangeSpeed:
+			if item.params[1] > 0 {
+				value := item.params[1]
+				speed = &value
+			}
+
+		case mavCmdNavReturnToLaunch:
+			r.Trajectory.ReturnToHome = true
+			if i < len(items)-1 {
+				r.warnf("item %d: return to launch ends the mission, %d later items ignored", i, len(items)-1-i)
+			}
+			return
+
+		case mavCmdNavLand:
+			r.warnf("item %d: NAV_LAND is not supported, ignored", i)
+
+		default:
+			r.warnf("item %d: unsupported command %d, ignored", i, item.command)
+		}
+	}
+}
+
+// waypoint converts a navigation item to a waypoint, applying its altitude frame.
+func (r *MissionResult) waypoint(i int, item missionItem) (models.Waypoint, bool) {
+	lat, lon, alt := item.params[4], item.params[5], item.params[6]
+	if lat == 0 && lon == 0 {
+		r.warnf("item %d: command %d has no position, ignored", i, item.command)
+		return models.Waypoint{}, false
+	}
+
+	waypoint := models.Waypoint{
+		Position: models.Position{Latitude: lat, Longitude: lon, Altitude: alt},
+	}
+	switch item.frame {
+	case mavFrameGlobal, mavFrameGlobalInt:
+	case mavFrameGlobalRelativeAlt, mavFrameGlobalRelativeInt:
+		waypoint.Position.Altitude += r.Home.Altitude
+	case mavFrameGlobalTerrainAlt, mavFrameGlobalTerrainInt:
+		waypoint.AltitudeRef = models.AltitudeReferenceAGL
+	default:
+		r.warnf("item %d: unsupported frame %d, ignored", i, item.frame)
+		return models.Waypoint{}, false
+	}
+	return waypoint, true
+}
+
+// warnf records a conversion warning.
+func (r *MissionResult) warnf(format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// loiterTurnsSeconds estimates how long a loiter of a number of turns lasts.
+func loiterTurnsSeconds(item missionItem, speed *float64, defaultSpeed float64) float64 {
+	radius := math.Abs(item.params[2])
+	if radius == 0 {
+		radius = defaultLoiterRadius
+	}
+	v := defaultSpeed
+	if speed != nil {
+		v = *speed
+	}
+	if v <= 0 {
+		return 0
+	}
+	return item.params[0] * 2 * math.Pi * radius / v
+}
+
+// planFile is the subset of the QGroundControl .plan format used here.
+type planFile struct {
+	FileType      string      `json:"fileType"`
+	Version       int         `json:"version"`
+	GroundStation string      `json:"groundStation"`
+	Mission       planMission `json:"mission"`
+	GeoFence      *planEmpty  `json:"geoFence,omitempty"`
+	RallyPoints   *planEmpty  `json:"rallyPoints,omitempty"`
+}
+
+// planEmpty is an empty geofence or rally point section.
+type planEmpty struct {
+	Version  int           `json:"version"`
+	Polygons []interface{} `json:"polygons,omitempty"`
+	Circles  []interface{} `json:"circles,omitempty"`
+	Points   []interface{} `json:"points,omitempty"`
+}
+
+type planMission struct {
+	Version             int        `json:"version"`
+	FirmwareType        int        `json:"firmwareType"`
+	VehicleType         int        `json:"vehicleType"`
+	CruiseSpeed         float64    `json:"cruiseSpeed"`
+	HoverSpeed          float64    `json:"hoverSpeed"`
+	PlannedHomePosition []float64  `json:"plannedHomePosition"`
+	Items               []planItem `json:"items"`
+}
+
+type planItem struct {
+	Type         string     `json:"type"`
+	ComplexType  string     `json:"complexItemType,omitempty"`
+	Command      int        `json:"command,omitempty"`
+	Frame        int        `json:"frame,omitempty"`
+	Params       []*float64 `json:"params,omitempty"` // QGC writes null for unused params
+	AutoContinue bool       `json:"autoContinue"`
+	DoJumpID     int        `json:"doJumpId,omitempty"`
+	AltitudeMode int        `json:"AltitudeMode,omitempty"`
+	Altitude     *float64   `json:"Altitude,omitempty"`
+}
+
+// parsePlan parses a QGroundControl .plan file.
+func parsePlan(data []byte) ([]missionItem, *models.Position, []string, error) {
+	var plan planFile
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, nil, nil, fmt.Errorf("%w: %v", models.ErrInvalidMission, err)
+	}
+	if plan.FileType != planFileType {
+		return nil, nil, nil, fmt.Errorf("%w: fileType must be %q", models.ErrInvalidMission, planFileType)
+	}
+
+	var home *models.Position
+	if p := plan.Mission.PlannedHomePosition; len(p) == 3 {
+		home = &models.Position{Latitude: p[0], Longitude: p[1], Altitude: p[2]}
+	}
+
+	var warnings []string
+	items := make([]missionItem, 0, len(plan.Mission.Items))
+	for i, pi := range plan.Mission.Items {
+		if pi.Type != planSimpleItem {
+			warnings = append(warnings, fmt.Sprintf("item %d: %s %s is not supported, ignored", i, pi.Type, pi.ComplexType))
+			continue
+		}
+		item := missionItem{command: pi.Command, frame: pi.Frame}
+		for j := 0; j < len(pi.Params) && j < len(item.params); j++ {
+			if pi.Params[j] != nil {
+				item.params[j] = *pi.Params[j]
+			}
+		}
+		items = append(items, item)
+	}
+
+	return items, home, warnings, nil
+}
+
+// parseWPL parses a QGC WPL 110 file. Item 0 is the home position.
+func parseWPL(data []byte) ([]missionItem, *models.Position, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	if !scanner.Scan() || strings.TrimSpace(scanner.Text()) != wplHeader {
+		return nil, nil, fmt.Errorf("%w: missing %q header", models.ErrInvalidMission, wplHeader)
+	}
+
+	var home *models.Position
+	var items []missionItem
+	line := 1
+	for scanner.Scan() {
+		line++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 12 {
+			return nil, nil, fmt.Errorf("%w: line %d: expected 12 fields, got %d", models.ErrInvalidMission, line, len(fields))
+		}
+
+		values := make([]float64, 12)
+		for i, field := range fields {
+			value, err := strconv.ParseFloat(field, 64)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%w: line %d: invalid number %q", models.ErrInvalidMission, line, field)
+			}
+			values[i] = value
+		}
+
+		item := missionItem{command: int(values[3]), frame: int(values[2])}
+		copy(item.params[:], values[4:11])
+
+		if int(values[0]) == 0 && home == nil {
+			home = &models.Position{Latitude: item.params[4], Longitude: item.params[5], Altitude: item.params[6]}
+			continue
+		}
+		items = append(items, item)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", models.ErrInvalidMission, err)
+	}
+
+	return items, home, nil
+}
+
+// exportItems converts a trajectory into mission items. Altitudes are
+// absolute (MSL) or terrain-relative for AGL waypoints.
+func exportItems(traj *models.TrajectoryCommand) []missionItem {
+	var items []missionItem
+	var speed *float64
+
+	for _, wp := range traj.Waypoints {
+		if wp.Speed != nil && (speed == nil || *speed != *wp.Speed) {
+			items = append(items, missionItem{
+				command: mavCmdDoChangeSpeed,
+				frame:   mavFrameMission,
+				params:  [7]float64{1, *wp.Speed, -1, 0, 0, 0, 0},
+			})
+			speed = wp.Speed
+		}
+
+		item := missionItem{
+			command: mavCmdNavWaypoint,
+			frame:   mavFrameGlobal,
+			params:  [7]float64{0, 0, 0, 0, wp.Position.Latitude, wp.Position.Longitude, wp.Position.Altitude},
+		}
+		if wp.AltitudeRef == models.AltitudeReferenceAGL {
+			item.frame = mavFrameGlobalTerrainAlt
+		}
+		if wp.HoldSeconds > 0 {
+			item.command = mavCmdNavLoiterTime
+			item.params[0] = wp.HoldSeconds
+		}
+		items = append(items, item)
+	}
+
+	if traj.ReturnToHome {
+		items = append(items, missionItem{command: mavCmdNavReturnToLaunch, frame: mavFrameGlobalRelativeAlt})
+	}
+	return items
+}
+
+// ExportPlan writes a trajectory as a QGroundControl .plan file.
+func ExportPlan(traj *models.TrajectoryCommand, home models.Position, cruiseSpeed float64) ([]byte, error) {
+	plan := planFile{
+		FileType:      planFileType,
+		Version:       planFileVersion,
+		GroundStation: planGroundStation,
+		Mission: planMission{
+			Version:             planMissionVersion,
+			FirmwareType:        planFirmwareTypeGeneric,
+			VehicleType:         planVehicleTypeFixedWing,
+			CruiseSpeed:         cruiseSpeed,
+			HoverSpeed:          cruiseSpeed,
+			PlannedHomePosition: []float64{home.Latitude, home.Longitude, home.Altitude},
+			Items:               []planItem{},
+		},
+		GeoFence:    &planEmpty{Version: 2, Polygons: []interface{}{}, Circles: []interface{}{}},
+		RallyPoints: &planEmpty{Version: 2, Points: []interface{}{}},
+	}
+
+	for i, item := range exportItems(traj) {
+		params := make([]*float64, len(item.params))
+		for j := range item.params {
+			value := item.params[j]
+			params[j] = &value
+		}
+		pi := planItem{
+			Type:         planSimpleItem,
+			Command:      item.command,
+			Frame:        item.frame,
+			Params:       params,
+			AutoContinue: true,
+			DoJumpID:     i + 1,
+		}
+		if item.command != mavCmdDoChangeSpeed && item.command != mavCmdNavReturnToLaunch {
+			altitude := item.params[6]
+			pi.Altitude = &altitude
+			pi.AltitudeMode = planAltitudeModeAbsolute
+			if item.frame == mavFrameGlobalTerrainAlt {
+				pi.AltitudeMode = planAltitudeModeAboveTerra
+			}
+		}
+		plan.Mission.Items = append(plan.Mission.Items, pi)
+	}
+
+	return json.MarshalIndent(plan, "", "    ")
+}
+
+// ExportWPL writes a trajectory as a QGC WPL 110 file with the home position
+// as item 0.
+func ExportWPL(traj *models.TrajectoryCommand, home models.Position) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(wplHeader + "\n")
+
+	writeItem := func(index, current int, item missionItem) {
+		fmt.Fprintf(&buf, "%d\t%d\t%d\t%d", index, current, item.frame, item.command)
+		for _, p := range item.params {
+			buf.WriteString("\t" + strconv.FormatFloat(p, 'f', -1, 64))
+		}
+		buf.WriteString("\t1\n")
+	}
+
+	writeItem(0, 1, missionItem{
+		command: mavCmdNavWaypoint,
+		frame:   mavFrameGlobal,
+		params:  [7]float64{0, 0, 0, 0, home.Latitude, home.Longitude, home.Altitude},
+	})
+	for i, item := range exportItems(traj) {
+		writeItem(i+1, 0, item)
+	}
+
+	return buf.Bytes()
+}
@@ -0,0 +1,174 @@
+package flightplan
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+)
+
+const testPlan = `{
+    "fileType": "Plan",
+    "version": 1,
+    "groundStation": "QGroundControl",
+    "mission": {
+        "version": 2,
+        "firmwareType": 12,
+        "vehicleType": 1,
+        "cruiseSpeed": 20,
+        "hoverSpeed": 5,
+        "plannedHomePosition": [32.0, 34.0, 100],
+        "items": [
+            {"type": "SimpleItem", "command": 22, "frame": 3, "params": [15, 0, 0, null, 0, 0, 50], "autoContinue": true, "doJumpId": 1},
+            {"type": "SimpleItem", "command": 178, "frame": 2, "params": [1, 30, -1, 0, 0, 0, 0], "autoContinue": true, "doJumpId": 2},
+            {"type": "SimpleItem", "command": 16, "frame": 3, "params": [0, 0, 0, null, 32.01, 34.0, 200], "autoContinue": true, "doJumpId": 3},
+            {"type": "SimpleItem", "command": 19, "frame": 0, "params": [60, 0, 0, null, 32.02, 34.01, 500], "autoContinue": true, "doJumpId": 4},
+            {"type": "SimpleItem", "command": 18, "frame": 10, "params": [2, 0, 100, null, 32.03, 34.02, 150], "autoContinue": true, "doJumpId": 5},
+            {"type": "ComplexItem", "complexItemType": "survey"},
+            {"type": "SimpleItem", "command": 20, "frame": 2, "params": [0, 0, 0, 0, 0, 0, 0], "autoContinue": true, "doJumpId": 6}
+        ]
+    }
+}`
+
+const testWPL = `QGC WPL 110
+0	1	0	16	0	0	0	0	32.0	34.0	100	1
+1	0	3	16	0	0	0	0	32.01	34.0	200	1
+2	0	0	178	1	25	-1	0	0	0	0	1
+3	0	0	17	0	0	50	0	32.02	34.01	500	1
+4	0	0	16	0	0	0	0	32.03	34.02	500	1
+`
+
+func TestParseMission_Plan(t *testing.T) {
+	result, err := ParseMission([]byte(testPlan), FormatPlan, MissionOptions{Speed: 50})
+	if err != nil {
+		t.Fatalf("ParseMission() error = %v", err)
+	}
+
+	if result.Home.Altitude != 100 {
+		t.Errorf("Home = %+v, want plannedHomePosition", result.Home)
+	}
+
+	traj := result.Trajectory
+	if len(traj.Waypoints) != 3 {
+		t.Fatalf("ParseMission() returned %d waypoints, want 3", len(traj.Waypoints))
+	}
+	if !traj.ReturnToHome {
+		t.Error("ReturnToHome = false, want true for NAV_RETURN_TO_LAUNCH")
+	}
+
+	// Relative altitude is converted to MSL using the planned home
+	first := traj.Waypoints[0]
+	if first.Position.Altitude != 300 || first.Speed == nil || *first.Speed != 30 {
+		t.Errorf("waypoint 0 = %+v, want altitude 300 at 30 m/s", first)
+	}
+
+	if traj.Waypoints[1].HoldSeconds != 60 {
+		t.Errorf("LOITER_TIME hold = %f, want 60", traj.Waypoints[1].HoldSeconds)
+	}
+
+	// Two turns of 100 m radius at 30 m/s
+	turns := traj.Waypoints[2]
+	if want := 2 * 2 * math.Pi * 100 / 30; math.Abs(turns.HoldSeconds-want) > 1e-9 {
+		t.Errorf("LOITER_TURNS hold = %f, want %f", turns.HoldSeconds, want)
+	}
+	if turns.AltitudeRef != models.AltitudeReferenceAGL {
+		t.Errorf("terrain frame altitude_ref = %q, want agl", turns.AltitudeRef)
+	}
+
+	// The takeoff item has no position and the survey is not supported
+	if len(result.Warnings) != 2 {
+		t.Errorf("Warnings = %v, want 2", result.Warnings)
+	}
+}
+
+func TestParseMission_WPL(t *testing.T) {
+	result, err := ParseMission([]byte(testWPL), DetectMissionFormat([]byte(testWPL)), MissionOptions{})
+	if err != nil {
+		t.Fatalf("ParseMission() error = %v", err)
+	}
+
+	traj := result.Trajectory
+	if len(traj.Waypoints) != 2 {
+		t.Fatalf("ParseMission() returned %d waypoints, want 2", len(traj.Waypoints))
+	}
+	if traj.Waypoints[0].Position.Altitude != 300 {
+		t.Errorf("relative altitude = %f, want 300 above the item 0 home", traj.Waypoints[0].Position.Altitude)
+	}
+	if traj.Waypoints[1].Speed == nil || *traj.Waypoints[1].Speed != 25 {
+		t.Errorf("waypoint 1 speed = %v, want 25", traj.Waypoints[1].Speed)
+	}
+
+	// Unlimited loiter ends the mission
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "unlimited loiter") {
+		t.Errorf("Warnings = %v, want one about the unlimited loiter", result.Warnings)
+	}
+}
+
+func TestParseMission_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		format MissionFormat
+	}{
+		{"invalid json", `{"fileType": `, FormatPlan},
+		{"wrong file type", `{"fileType": "Fence"}`, FormatPlan},
+		{"no waypoints", `{"fileType": "Plan", "mission": {"items": []}}`, FormatPlan},
+		{"missing header", "0\t1\t0\t16\t0\t0\t0\t0\t32\t34\t100\t1\n", FormatWPL},
+		{"short line", "QGC WPL 110\n0\t1\t0\t16\n", FormatWPL},
+		{"unknown format", testWPL, MissionFormat("kml")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseMission([]byte(tt.data), tt.format, MissionOptions{})
+			if !errors.Is(err, models.ErrInvalidMission) {
+				t.Errorf("ParseMission() error = %v, want ErrInvalidMission", err)
+			}
+		})
+	}
+}
+
+func TestExportMission_RoundTrip(t *testing.T) {
+	speed := 40.0
+	traj := &models.TrajectoryCommand{
+		Waypoints: []models.Waypoint{
+			{Position: models.Position{Latitude: 32.01, Longitude: 34.0, Altitude: 500}, Speed: &speed},
+			{Position: models.Position{Latitude: 32.02, Longitude: 34.01, Altitude: 150}, Speed: &speed, AltitudeRef: models.AltitudeReferenceAGL, HoldSeconds: 30},
+		},
+		ReturnToHome: true,
+	}
+	home := models.Position{Latitude: 32.0, Longitude: 34.0, Altitude: 100}
+
+	plan, err := ExportPlan(traj, home, 40)
+	if err != nil {
+		t.Fatalf("ExportPlan() error = %v", err)
+	}
+
+	exports := map[MissionFormat][]byte{
+		FormatPlan: plan,
+		FormatWPL:  ExportWPL(traj, home),
+	}
+	for format, data := range exports {
+		result, err := ParseMission(data, format, MissionOptions{})
+		if err != nil {
+			t.Fatalf("ParseMission(%s) error = %v", format, err)
+		}
+		if len(result.Warnings) != 0 {
+			t.Errorf("%s warnings = %v, want none", format, result.Warnings)
+		}
+		if result.Home != home {
+			t.Errorf("%s home = %+v, want %+v", format, result.Home, home)
+		}
+		if len(result.Trajectory.Waypoints) != 2 || !result.Trajectory.ReturnToHome {
+			t.Fatalf("%s trajectory = %+v, want 2 waypoints and return to home", format, result.Trajectory)
+		}
+		for i, wp := range result.Trajectory.Waypoints {
+			want := traj.Waypoints[i]
+			if wp.Position != want.Position || wp.AltitudeRef != want.AltitudeRef || wp.HoldSeconds != want.HoldSeconds || *wp.Speed != speed {
+				t.Errorf("%s waypoint %d = %+v, want %+v", format, i, wp, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,258 @@
+// Package flightplan converts flight plan formats into trajectory commands.
+package flightplan
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/navdata"
+)
+
+// Unit conversions to SI.
+const (
+	knotsToMPS   = 0.514444
+	kmhToMPS     = 1.0 / 3.6
+	machToMPS    = 340.29 // speed of sound at ISA sea level
+	feetToMeters = 0.3048
+)
+
+var (
+	// speedLevelPattern matches an Item 15 speed/level group, e.g. N0250F100,
+	// K0450S1130 or M082F350. A level of VFR keeps the current altitude.
+	speedLevelPattern = regexp.MustCompile(`^(N\d{4}|K\d{4}|M\d{3})(F\d{3}|A\d{3}|S\d{4}|M\d{4}|VFR)$`)
+
+	// coordinatePattern matches lat/lon tokens in degrees (32N034E), degrees
+	// and minutes (3205N03447E) or degrees, minutes and seconds (320530N0344700E).
+	coordinatePattern = regexp.MustCompile(`^(\d{2}|\d{4}|\d{6})([NS])(\d{3}|\d{5}|\d{7})([EW])$`)
+)
+
+// RouteOptions provides defaults for route conversion.
+type RouteOptions struct {
+	Altitude *float64 // meters MSL, used until the route sets a level
+	Speed    *float64 // m/s, used until the route sets a speed
+}
+
+// TokenError reports a route token that could not be parsed.
+type TokenError struct {
+	Index   int    `json:"index"` // position of the token in the route
+	Token   string `json:"token"`
+	Message string `json:"message"`
+}
+
+func (e TokenError) Error() string {
+	return fmt.Sprintf("token %d %q: %s", e.Index, e.Token, e.Message)
+}
+
+// RouteError reports every invalid token of a route.
+// It wraps models.ErrInvalidRoute.
+type RouteError struct {
+	Tokens []TokenError
+}
+
+func (e *RouteError) Error() string {
+	messages := make([]string, len(e.Tokens))
+	for i, t := range e.Tokens {
+		messages[i] = t.Error()
+	}
+	return fmt.Sprintf("%s: %s", models.ErrInvalidRoute, strings.Join(messages, "; "))
+}
+
+func (e *RouteError) Unwrap() error {
+	return models.ErrInvalidRoute
+}
+
+// ParseRoute converts an ICAO Item 15 style route, e.g.
+// "N0250F100 LLBG DCT ZAMIR DCT VESAR LLHA", into a trajectory command.
+//
+// Points are navdata identifiers or lat/lon tokens, optionally followed by a
+// speed/level group ("ZAMIR/N0250F100") that applies from that point on.
+// DCT and the flight rule changes IFR/VFR are accepted between points.
+// Airways, SIDs and STARs are not supported.
+func ParseRoute(route string, db *navdata.Database, opts RouteOptions) (*models.TrajectoryCommand, error) {
+	tokens := strings.Fields(strings.ToUpper(route))
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("%w: route is empty", models.ErrInvalidRoute)
+	}
+
+	altitude := opts.Altitude
+	speed := opts.Speed
+
+	var waypoints []models.Waypoint
+	var errs []TokenError
+	fail := func(i int, token, format string, args ...interface{}) {
+		errs = append(errs, TokenError{Index: i, Token: token, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// Track point tokens rather than waypoints so that an invalid point
+	// does not also report the following DCT
+	seenPoint, previousDirect := false, false
+	for i, token := range tokens {
+		switch token {
+		case "DCT":
+			if !seenPoint || previousDirect {
+				fail(i, token, "DCT must follow a point")
+			}
+			previousDirect = true
+			continue
+		case "IFR", "VFR":
+			continue
+		}
+		previousDirect = false
+
+		// A leading speed/level group sets the initial cruise
+		if speedLevelPattern.MatchString(token) {
+			if err := applySpeedLevel(token, &speed, &altitude); err != nil {
+				fail(i, token, "%s", err)
+			}
+			continue
+		}
+
+		seenPoint = true
+		point, group, _ := strings.Cut(token, "/")
+		if group != "" {
+			if err := applySpeedLevel(group, &speed, &altitude); err != nil {
+				fail(i, token, "%s", err)
+				continue
+			}
+		}
+
+		position, fix, err := resolvePoint(point, db)
+		if err != nil {
+			fail(i, token, "%s", err)
+			continue
+		}
+		if altitude == nil {
+			fail(i, token, "no cruising level: start the route with a speed/level group such as N0250F100 or give a default altitude")
+			continue
+		}
+
+		position.Altitude = *altitude
+		waypoints = append(waypoints, models.Waypoint{
+			Position: position,
+			Speed:    speed,
+			Fix:      fix,
+		})
+	}
+
+	if previousDirect {
+		fail(len(tokens)-1, tokens[len(tokens)-1], "route must not end with DCT")
+	}
+	if len(errs) > 0 {
+		return nil, &RouteError{Tokens: errs}
+	}
+	if len(waypoints) == 0 {
+		return nil, fmt.Errorf("%w: route contains no points", models.ErrInvalidRoute)
+	}
+
+	return &models.TrajectoryCommand{Waypoints: waypoints}, nil
+}
+
+// resolvePoint resolves a lat/lon token or navdata identifier. The returned
+// fix is the identifier for named points.
+func resolvePoint(token string, db *navdata.Database) (models.Position, string, error) {
+	if coordinatePattern.MatchString(token) {
+		position, err := parseCoordinate(token)
+		return position, "", err
+	}
+
+	fix, err := db.Lookup(token)
+	if err != nil {
+		return models.Position{}, "", fmt.Errorf("unknown fix or unsupported route element")
+	}
+	return fix.Position(0), fix.Ident, nil
+}
+
+// parseCoordinate parses a lat/lon token such as 3205N03447E.
+func parseCoordinate(token string) (models.Position, error) {
+	m := coordinatePattern.FindStringSubmatch(token)
+	if m == nil {
+		return models.Position{}, fmt.Errorf("invalid coordinate")
+	}
+	if len(m[1])+1 != len(m[3]) {
+		return models.Position{}, fmt.Errorf("latitude and longitude precision differ")
+	}
+
+	lat, err := parseDegrees(m[1], 2)
+	if err != nil {
+		return models.Position{}, err
+	}
+	lon, err := parseDegrees(m[3], 3)
+	if err != nil {
+		return models.Position{}, err
+	}
+	if m[2] == "S" {
+		lat = -lat
+	}
+	if m[4] == "W" {
+		lon = -lon
+	}
+	if lat > 90 || lon > 180 {
+		return models.Position{}, fmt.Errorf("coordinate out of range")
+	}
+
+	return models.Position{Latitude: lat, Longitude: lon}, nil
+}
+
+// parseDegrees parses DD[MM[SS]] digits with the given number of degree digits.
+func parseDegrees(digits string, degreeDigits int) (float64, error) {
+	degrees, _ := strconv.Atoi(digits[:degreeDigits])
+	value := float64(degrees)
+
+	rest := digits[degreeDigits:]
+	if len(rest) >= 2 {
+		minutes, _ := strconv.Atoi(rest[:2])
+		if minutes >= 60 {
+			return 0, fmt.Errorf("minutes out of range")
+		}
+		value += float64(minutes) / 60
+	}
+	if len(rest) == 4 {
+		seconds, _ := strconv.Atoi(rest[2:])
+		if seconds >= 60 {
+			return 0, fmt.Errorf("seconds out of range")
+		}
+		value += float64(seconds) / 3600
+	}
+
+	return value, nil
+}
+
+// applySpeedLevel parses a speed/level group and updates the current speed
+// and altitude. A VFR level leaves the altitude unchanged.
+func applySpeedLevel(group string, speed, altitude **float64) error {
+	m := speedLevelPattern.FindStringSubmatch(group)
+	if m == nil {
+		return fmt.Errorf("invalid speed/level group")
+	}
+
+	speedValue, _ := strconv.Atoi(m[1][1:])
+	var mps float64
+	switch m[1][0] {
+	case 'N':
+		mps = float64(speedValue) * knotsToMPS
+	case 'K':
+		mps = float64(speedValue) * kmhToMPS
+	case 'M':
+		mps = float64(speedValue) / 100 * machToMPS
+	}
+	*speed = &mps
+
+	if m[2] == "VFR" {
+		return nil
+	}
+
+	levelValue, _ := strconv.Atoi(m[2][1:])
+	var meters float64
+	switch m[2][0] {
+	case 'F', 'A':
+		meters = float64(levelValue) * 100 * feetToMeters // flight level / altitude in hundreds of feet
+	case 'S', 'M':
+		meters = float64(levelValue) * 10 // tens of meters
+	}
+	*altitude = &meters
+
+	return nil
+}
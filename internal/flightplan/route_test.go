@@ -0,0 +1,119 @@
+package flightplan
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/navdata"
+)
+
+func testNavdata() *navdata.Database {
+	db := navdata.NewDatabase()
+	db.Add(navdata.Fix{Ident: "LLBG", Type: "large_airport", Latitude: 32.0114, Longitude: 34.8867})
+	db.Add(navdata.Fix{Ident: "ZAMIR", Type: navdata.TypeFix, Latitude: 32.3, Longitude: 34.8})
+	db.Add(navdata.Fix{Ident: "VESAR", Type: navdata.TypeFix, Latitude: 32.6, Longitude: 34.9})
+	db.Add(navdata.Fix{Ident: "LLHA", Type: "medium_airport", Latitude: 32.8094, Longitude: 35.0431})
+	return db
+}
+
+func TestParseRoute(t *testing.T) {
+	db := testNavdata()
+	route := "N0250F100 LLBG DCT ZAMIR DCT VESAR/K0360A045 DCT 3245N03500E LLHA"
+
+	cmd, err := ParseRoute(route, db, RouteOptions{})
+	if err != nil {
+		t.Fatalf("ParseRoute() error = %v", err)
+	}
+
+	if len(cmd.Waypoints) != 5 {
+		t.Fatalf("ParseRoute() returned %d waypoints, want 5", len(cmd.Waypoints))
+	}
+
+	wantFixes := []string{"LLBG", "ZAMIR", "VESAR", "", "LLHA"}
+	for i, want := range wantFixes {
+		if cmd.Waypoints[i].Fix != want {
+			t.Errorf("waypoint %d fix = %q, want %q", i, cmd.Waypoints[i].Fix, want)
+		}
+	}
+
+	// N0250F100 applies to the first two points
+	first := cmd.Waypoints[0]
+	if math.Abs(first.Position.Altitude-3048.0) > 0.01 {
+		t.Errorf("FL100 altitude = %f, want 3048", first.Position.Altitude)
+	}
+	if math.Abs(*first.Speed-250*knotsToMPS) > 0.01 {
+		t.Errorf("N0250 speed = %f, want %f", *first.Speed, 250*knotsToMPS)
+	}
+
+	// K0360A045 applies from VESAR on
+	vesar := cmd.Waypoints[2]
+	if math.Abs(vesar.Position.Altitude-4500*feetToMeters) > 0.01 {
+		t.Errorf("A045 altitude = %f, want %f", vesar.Position.Altitude, 4500*feetToMeters)
+	}
+	if math.Abs(*vesar.Speed-100.0) > 0.01 {
+		t.Errorf("K0360 speed = %f, want 100", *vesar.Speed)
+	}
+
+	coordinate := cmd.Waypoints[3].Position
+	if math.Abs(coordinate.Latitude-32.75) > 1e-9 || math.Abs(coordinate.Longitude-35.0) > 1e-9 {
+		t.Errorf("3245N03500E = %+v, want 32.75, 35.0", coordinate)
+	}
+}
+
+func TestParseRoute_DefaultAltitude(t *testing.T) {
+	altitude := 1500.0
+	cmd, err := ParseRoute("ZAMIR DCT VESAR", testNavdata(), RouteOptions{Altitude: &altitude})
+	if err != nil {
+		t.Fatalf("ParseRoute() error = %v", err)
+	}
+	for i, wp := range cmd.Waypoints {
+		if wp.Position.Altitude != altitude || wp.Speed != nil {
+			t.Errorf("waypoint %d = %+v, want altitude %f and no speed", i, wp, altitude)
+		}
+	}
+}
+
+func TestParseRoute_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		route       string
+		wantIndexes []int
+	}{
+		{name: "unknown fix", route: "N0250F100 ZAMIR DCT NOWHERE DCT VESAR", wantIndexes: []int{3}},
+		{name: "airway", route: "N0250F100 ZAMIR UL610 VESAR", wantIndexes: []int{2}},
+		{name: "several bad tokens", route: "N0250F100 BOGUS DCT VESAR/X999", wantIndexes: []int{1, 3}},
+		{name: "leading DCT", route: "N0250F100 DCT ZAMIR", wantIndexes: []int{1}},
+		{name: "trailing DCT", route: "N0250F100 ZAMIR DCT", wantIndexes: []int{2}},
+		{name: "no level", route: "ZAMIR DCT VESAR", wantIndexes: []int{0, 2}},
+		{name: "minutes out of range", route: "N0250F100 3275N03500E", wantIndexes: []int{1}},
+		{name: "mixed precision", route: "N0250F100 32N03500E", wantIndexes: []int{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRoute(tt.route, testNavdata(), RouteOptions{})
+			if !errors.Is(err, models.ErrInvalidRoute) {
+				t.Fatalf("ParseRoute() error = %v, want %v", err, models.ErrInvalidRoute)
+			}
+
+			var routeErr *RouteError
+			if !errors.As(err, &routeErr) {
+				t.Fatalf("ParseRoute() error = %T, want *RouteError", err)
+			}
+			if len(routeErr.Tokens) != len(tt.wantIndexes) {
+				t.Fatalf("ParseRoute() token errors = %v, want indexes %v", routeErr.Tokens, tt.wantIndexes)
+			}
+			for i, index := range tt.wantIndexes {
+				if routeErr.Tokens[i].Index != index {
+					t.Errorf("token error %d index = %d, want %d", i, routeErr.Tokens[i].Index, index)
+				}
+			}
+		})
+	}
+
+	if _, err := ParseRoute("   ", testNavdata(), RouteOptions{}); !errors.Is(err, models.ErrInvalidRoute) {
+		t.Errorf("ParseRoute(empty) error = %v, want %v", err, models.ErrInvalidRoute)
+	}
+}
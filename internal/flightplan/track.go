@@ -0,0 +1,206 @@
+package flightplan
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/pkg/geo"
+)
+
+// TrackFormat identifies a route or track file format.
+type TrackFormat string
+
+const (
+	FormatGPX     TrackFormat = "gpx"
+	FormatKML     TrackFormat = "kml"
+	FormatGeoJSON TrackFormat = "geojson"
+)
+
+// TrackOptions controls how a route or track file becomes a trajectory.
+type TrackOptions struct {
+	Altitude        *float64 // meters MSL, for points without a usable altitude
+	Speed           *float64 // m/s, for points without a usable speed
+	ExtractAltitude bool     // use altitudes from the file
+	ExtractSpeed    bool     // use speeds from the file, or derive them from timestamps
+	Tolerance       float64  // meters; points closer than this to the simplified line are dropped
+	MaxWaypoints    int      // keep at most this many waypoints (0 = no limit)
+}
+
+// TrackResult is an imported trajectory and any non-fatal warnings.
+type TrackResult struct {
+	Trajectory   *models.TrajectoryCommand `json:"trajectory"`
+	SourcePoints int                       `json:"source_points"` // points in the file before thinning
+	Warnings     []string                  `json:"warnings,omitempty"`
+}
+
+// trackPoint is a point read from a route or track file.
+type trackPoint struct {
+	lat, lon    float64
+	altitude    *float64 // meters
+	altitudeRef models.AltitudeReference
+	time        *time.Time
+	speed       *float64 // m/s, when the file records it
+}
+
+// DetectTrackFormat guesses the format of a route or track file from its
+// name and content.
+func DetectTrackFormat(filename string, data []byte) TrackFormat {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".gpx":
+		return FormatGPX
+	case ".kml":
+		return FormatKML
+	case ".json", ".geojson":
+		return FormatGeoJSON
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case len(trimmed) > 0 && trimmed[0] == '{':
+		return FormatGeoJSON
+	case bytes.Contains(trimmed, []byte("<kml")):
+		return FormatKML
+	default:
+		return FormatGPX
+	}
+}
+
+// ImportTrack converts a GPX, KML or GeoJSON route or track into a trajectory.
+func ImportTrack(data []byte, format TrackFormat, opts TrackOptions) (*TrackResult, error) {
+	result := &TrackResult{}
+
+	var points []trackPoint
+	var err error
+	switch format {
+	case FormatGPX:
+		points, err = parseGPX(data, result)
+	case FormatKML:
+		points, err = parseKML(data, result)
+	case FormatGeoJSON:
+		points, err = parseTrackGeoJSON(data, result)
+	default:
+		return nil, fmt.Errorf("%w: unsupported track format %q", models.ErrInvalidTrack, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(points) == 0 {
+		return nil, fmt.Errorf("%w: file contains no route or track points", models.ErrInvalidTrack)
+	}
+	result.SourcePoints = len(points)
+
+	if err := result.build(points, opts); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// build thins the points and converts the remaining ones into waypoints.
+func (r *TrackResult) build(points []trackPoint, opts TrackOptions) error {
+	line := make([]geo.Point, len(points))
+	for i, p := range points {
+		line[i] = geo.Point{Lat: p.lat, Lon: p.lon}
+	}
+	kept := geo.Simplify(line, opts.Tolerance, opts.MaxWaypoints)
+
+	r.Trajectory = &models.TrajectoryCommand{Waypoints: make([]models.Waypoint, 0, len(kept))}
+	missingAltitude, missingSpeed := 0, 0
+	for k, i := range kept {
+		p := points[i]
+		waypoint := models.Waypoint{
+			Position: models.Position{Latitude: p.lat, Longitude: p.lon},
+		}
+
+		switch {
+		case opts.ExtractAltitude && p.altitude != nil:
+			waypoint.Position.Altitude = *p.altitude
+			waypoint.AltitudeRef = p.altitudeRef
+		case opts.Altitude != nil:
+			waypoint.Position.Altitude = *opts.Altitude
+		default:
+			return fmt.Errorf("%w: point %d has no altitude, give a default altitude", models.ErrInvalidTrack, i)
+		}
+		if opts.ExtractAltitude && p.altitude == nil {
+			missingAltitude++
+		}
+
+		waypoint.Speed = opts.Speed
+		if opts.ExtractSpeed {
+			previous := -1
+			if k > 0 {
+				previous = kept[k-1]
+			}
+			if speed, ok := segmentSpeed(points, previous, i); ok {
+				waypoint.Speed = &speed
+			} else {
+				missingSpeed++
+			}
+		}
+
+		r.Trajectory.Waypoints = append(r.Trajectory.Waypoints, waypoint)
+	}
+
+	if missingAltitude > 0 {
+		r.warnf("%d waypoints have no altitude in the file, default altitude used", missingAltitude)
+	}
+	if missingSpeed > 0 {
+		r.warnf("%d waypoints have no speed or timestamps in the file, default speed used", missingSpeed)
+	}
+	if dropped := len(points) - len(kept); dropped > 0 {
+		r.warnf("%d of %d points removed by thinning", dropped, len(points))
+	}
+	return nil
+}
+
+// segmentSpeed returns the speed flown from point previous to point i: the
+// average speed along the original track between their timestamps, or the
+// recorded speed at i. For the first waypoint (previous < 0) the speed of
+// the following segment is used.
+func segmentSpeed(points []trackPoint, previous, i int) (float64, bool) {
+	if previous < 0 {
+		if i+1 < len(points) {
+			if speed, ok := segmentSpeed(points, i, i+1); ok {
+				return speed, true
+			}
+		}
+		if points[i].speed != nil && *points[i].speed > 0 {
+			return *points[i].speed, true
+		}
+		return 0, false
+	}
+
+	from, to := points[previous], points[i]
+	if from.time != nil && to.time != nil {
+		if seconds := to.time.Sub(*from.time).Seconds(); seconds > 0 {
+			distance := 0.0
+			for j := previous + 1; j <= i; j++ {
+				distance += geo.Haversine(points[j-1].lat, points[j-1].lon, points[j].lat, points[j].lon)
+			}
+			if distance > 0 {
+				return distance / seconds, true
+			}
+		}
+	}
+	if to.speed != nil && *to.speed > 0 {
+		return *to.speed, true
+	}
+	return 0, false
+}
+
+// warnf records an import warning.
+func (r *TrackResult) warnf(format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// parseTime parses an ISO 8601 timestamp, returning nil if it is empty or invalid.
+func parseTime(text string) *time.Time {
+	t, err := time.Parse(time.RFC3339, strings.TrimSpace(text))
+	if err != nil {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,169 @@
+package flightplan
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+)
+
+const testGPXTrack = `<?xml version="1.0" encoding="UTF-8"?>
+<gpx version="1.1" creator="test" xmlns="http://www.topografix.com/GPX/1/1">
+  <trk>
+    <name>Morning flight</name>
+    <trkseg>
+      <trkpt lat="32.00" lon="34.00"><ele>500</ele><time>2024-05-01T10:00:00Z</time></trkpt>
+      <trkpt lat="32.01" lon="34.00"><ele>600</ele><time>2024-05-01T10:00:10Z</time></trkpt>
+    </trkseg>
+    <trkseg>
+      <trkpt lat="32.02" lon="34.00"><ele>700</ele><time>2024-05-01T10:00:20Z</time></trkpt>
+    </trkseg>
+  </trk>
+</gpx>`
+
+const testKML = `<?xml version="1.0" encoding="UTF-8"?>
+<kml xmlns="http://www.opengis.net/kml/2.2">
+  <Document>
+    <Folder>
+      <Placemark>
+        <name>Start</name>
+        <Point><coordinates>34.0,32.0,0</coordinates></Point>
+      </Placemark>
+      <Placemark>
+        <name>Survey line</name>
+        <LineString>
+          <altitudeMode>relativeToGround</altitudeMode>
+          <coordinates>
+            34.00,32.00,150 34.01,32.01,150
+            34.02,32.02,150
+          </coordinates>
+        </LineString>
+      </Placemark>
+    </Folder>
+  </Document>
+</kml>`
+
+func TestImportTrack_GPX(t *testing.T) {
+	result, err := ImportTrack([]byte(testGPXTrack), DetectTrackFormat("", []byte(testGPXTrack)), TrackOptions{
+		ExtractAltitude: true,
+		ExtractSpeed:    true,
+	})
+	if err != nil {
+		t.Fatalf("ImportTrack() error = %v", err)
+	}
+
+	// Segments are joined; the points are collinear so only the ends remain
+	waypoints := result.Trajectory.Waypoints
+	if result.SourcePoints != 3 || len(waypoints) != 2 {
+		t.Fatalf("ImportTrack() = %d source points, %d waypoints, want 3 and 2", result.SourcePoints, len(waypoints))
+	}
+	if waypoints[1].Position.Altitude != 700 {
+		t.Errorf("last waypoint altitude = %f, want 700", waypoints[1].Position.Altitude)
+	}
+
+	// ~2224m along the track in 20 seconds
+	if waypoints[1].Speed == nil || math.Abs(*waypoints[1].Speed-111.2) > 0.5 {
+		t.Errorf("derived speed = %v, want ~111.2", waypoints[1].Speed)
+	}
+}
+
+func TestImportTrack_GPXRoute(t *testing.T) {
+	gpx := `<gpx version="1.0"><rte><name>Plan</name>
+		<rtept lat="32.0" lon="34.0"><speed>40</speed></rtept>
+		<rtept lat="32.1" lon="34.1"><speed>50</speed></rtept>
+	</rte></gpx>`
+	altitude := 1200.0
+
+	result, err := ImportTrack([]byte(gpx), FormatGPX, TrackOptions{Altitude: &altitude, ExtractSpeed: true, ExtractAltitude: true})
+	if err != nil {
+		t.Fatalf("ImportTrack() error = %v", err)
+	}
+	waypoints := result.Trajectory.Waypoints
+	if len(waypoints) != 2 || waypoints[0].Position.Altitude != altitude || *waypoints[1].Speed != 50 {
+		t.Errorf("ImportTrack() = %+v, want default altitude and recorded speeds", waypoints)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "no altitude") {
+		t.Errorf("Warnings = %v, want one about missing altitudes", result.Warnings)
+	}
+}
+
+func TestImportTrack_KML(t *testing.T) {
+	result, err := ImportTrack([]byte(testKML), DetectTrackFormat("survey.kml", nil), TrackOptions{ExtractAltitude: true})
+	if err != nil {
+		t.Fatalf("ImportTrack() error = %v", err)
+	}
+
+	// The line is used and the Point placemark ignored
+	waypoints := result.Trajectory.Waypoints
+	if len(waypoints) != 2 {
+		t.Fatalf("ImportTrack() returned %d waypoints, want 2", len(waypoints))
+	}
+	if waypoints[0].AltitudeRef != models.AltitudeReferenceAGL || waypoints[0].Position.Altitude != 150 {
+		t.Errorf("relativeToGround waypoint = %+v, want 150m AGL", waypoints[0])
+	}
+}
+
+func TestImportTrack_GeoJSON(t *testing.T) {
+	// A zig-zag line with timestamps, thinned to 4 waypoints
+	var coordinates, times []string
+	for i := 0; i < 20; i++ {
+		coordinates = append(coordinates, fmt.Sprintf("[%f, %f, 800]", 34.0+float64(i)*0.01, 32.0+float64(i%2)*0.005))
+		times = append(times, fmt.Sprintf(`"2024-05-01T10:%02d:00Z"`, i))
+	}
+	geojson := fmt.Sprintf(`{"type": "Feature", "properties": {"coordTimes": [%s]},
+		"geometry": {"type": "LineString", "coordinates": [%s]}}`, strings.Join(times, ","), strings.Join(coordinates, ","))
+
+	result, err := ImportTrack([]byte(geojson), DetectTrackFormat("", []byte(geojson)), TrackOptions{
+		ExtractAltitude: true,
+		ExtractSpeed:    true,
+		MaxWaypoints:    4,
+	})
+	if err != nil {
+		t.Fatalf("ImportTrack() error = %v", err)
+	}
+
+	waypoints := result.Trajectory.Waypoints
+	if len(waypoints) != 4 {
+		t.Fatalf("ImportTrack() returned %d waypoints, want 4", len(waypoints))
+	}
+	first, last := waypoints[0].Position, waypoints[3].Position
+	if first.Longitude != 34.0 || math.Abs(last.Longitude-34.19) > 1e-9 {
+		t.Errorf("thinned line = %v .. %v, want the original end points", first, last)
+	}
+	for i, wp := range waypoints {
+		if wp.Speed == nil || *wp.Speed <= 0 {
+			t.Errorf("waypoint %d speed = %v, want derived from timestamps", i, wp.Speed)
+		}
+	}
+	if !strings.Contains(strings.Join(result.Warnings, ";"), "16 of 20 points removed") {
+		t.Errorf("Warnings = %v, want thinning report", result.Warnings)
+	}
+}
+
+func TestImportTrack_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		format TrackFormat
+	}{
+		{"invalid xml", "<gpx><trk>", FormatGPX},
+		{"empty gpx", "<gpx></gpx>", FormatGPX},
+		{"no altitude", `<gpx><rte><rtept lat="32" lon="34"/></rte></gpx>`, FormatGPX},
+		{"bad kml coordinate", "<kml><Placemark><LineString><coordinates>34.0</coordinates></LineString></Placemark></kml>", FormatKML},
+		{"invalid geojson", `{"type": `, FormatGeoJSON},
+		{"polygon only", `{"type": "Polygon", "coordinates": [[[34, 32], [34.1, 32], [34, 32.1], [34, 32]]]}`, FormatGeoJSON},
+		{"unknown format", testKML, TrackFormat("shp")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ImportTrack([]byte(tt.data), tt.format, TrackOptions{ExtractAltitude: true})
+			if !errors.Is(err, models.ErrInvalidTrack) {
+				t.Errorf("ImportTrack() error = %v, want ErrInvalidTrack", err)
+			}
+		})
+	}
+}
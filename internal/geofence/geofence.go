@@ -0,0 +1,357 @@
+package geofence
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/pkg/geo"
+)
+
+const (
+	// pathSampleSpacing is the distance between samples when checking a path (meters).
+	pathSampleSpacing = 100.0
+
+	// maxSamplesPerSegment bounds the work done for very long path segments.
+	maxSamplesPerSegment = 1000
+)
+
+// ConflictError reports a path that violates a geofence.
+// It wraps models.ErrGeofenceConflict.
+type ConflictError struct {
+	ZoneID       string
+	Name         string
+	Mode         models.GeofenceMode
+	SegmentIndex int             // index of the path segment (0 = from current position)
+	Position     models.Position // first violating sample
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s: segment %d violates %s zone %q", models.ErrGeofenceConflict, e.SegmentIndex, e.Mode, e.zoneLabel())
+}
+
+func (e *ConflictError) Unwrap() error {
+	return models.ErrGeofenceConflict
+}
+
+func (e *ConflictError) zoneLabel() string {
+	if e.Name != "" {
+		return e.Name
+	}
+	return e.ZoneID
+}
+
+// Manager stores geofences and checks positions and paths against them.
+// It is safe for concurrent use: API handlers modify zones while the
+// simulator checks the aircraft against them every tick.
+type Manager struct {
+	mu    sync.RWMutex
+	zones map[string]models.Geofence
+	order []string // insertion order for stable listing
+}
+
+// ChangeType identifies a geofence modification.
+type ChangeType string
+
+const (
+	ChangeAdd    ChangeType = "add"
+	ChangeUpdate ChangeType = "update"
+	ChangeDelete ChangeType = "delete"
+)
+
+// Change describes a modification of the stored geofences.
+type Change struct {
+	Type ChangeType
+	ID   string
+	Zone models.Geofence // the zone to store, empty for deletions
+}
+
+// NewManager creates an empty geofence manager.
+func NewManager() *Manager {
+	return &Manager{
+		zones: make(map[string]models.Geofence),
+	}
+}
+
+// Add stores a geofence, assigning an ID if it has none.
+// An existing zone with the same ID is replaced.
+func (m *Manager) Add(zone models.Geofence) models.Geofence {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if zone.ID == "" {
+		zone.ID = uuid.New().String()
+	}
+	if _, exists := m.zones[zone.ID]; !exists {
+		m.order = append(m.order, zone.ID)
+	}
+	m.zones[zone.ID] = cloneZone(zone)
+	return cloneZone(zone)
+}
+
+// Update replaces an existing geofence.
+func (m *Manager) Update(id string, zone models.Geofence) (models.Geofence, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.zones[id]; !exists {
+		return models.Geofence{}, fmt.Errorf("%w: %s", models.ErrGeofenceNotFound, id)
+	}
+	zone.ID = id
+	m.zones[id] = cloneZone(zone)
+	return cloneZone(zone), nil
+}
+
+// Delete removes a geofence.
+func (m *Manager) Delete(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.zones[id]; !exists {
+		return fmt.Errorf("%w: %s", models.ErrGeofenceNotFound, id)
+	}
+	delete(m.zones, id)
+	for i, zoneID := range m.order {
+		if zoneID == id {
+			m.order = append(m.order[:i], m.order[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// Reset replaces all geofences with zones, keeping their IDs. A nil manager
+// is left unchanged.
+func (m *Manager) Reset(zones []models.Geofence) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.zones = make(map[string]models.Geofence, len(zones))
+	m.order = make([]string, 0, len(zones))
+	for _, zone := range zones {
+		if _, exists := m.zones[zone.ID]; !exists {
+			m.order = append(m.order, zone.ID)
+		}
+		m.zones[zone.ID] = cloneZone(zone)
+	}
+}
+
+// Get returns a geofence by ID.
+func (m *Manager) Get(id string) (models.Geofence, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	zone, exists := m.zones[id]
+	if !exists {
+		return models.Geofence{}, fmt.Errorf("%w: %s", models.ErrGeofenceNotFound, id)
+	}
+	return cloneZone(zone), nil
+}
+
+// List returns all geofences in insertion order.
+func (m *Manager) List() []models.Geofence {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	zones := make([]models.Geofence, 0, len(m.order))
+	for _, id := range m.order {
+		zones = append(zones, cloneZone(m.zones[id]))
+	}
+	return zones
+}
+
+// Count returns the number of stored geofences.
+func (m *Manager) Count() int {
+	if m == nil {
+		return 0
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.zones)
+}
+
+// Check returns the zones a position currently violates.
+// Exclusion zones are violated when the position is inside them. Inclusion
+// zones act as a union: the position must be inside at least one of them,
+// otherwise every inclusion zone is reported. A nil manager reports no breaches.
+func (m *Manager) Check(pos models.Position) []models.GeofenceBreach {
+	if m == nil {
+		return nil
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var breaches []models.GeofenceBreach
+	var inclusion []models.GeofenceBreach
+	insideInclusion := false
+
+	for _, id := range m.order {
+		zone := m.zones[id]
+		breach := models.GeofenceBreach{ZoneID: zone.ID, Name: zone.Name, Mode: zone.Mode}
+
+		switch zone.Mode {
+		case models.GeofenceModeExclusion:
+			if Contains(zone, pos) {
+				breaches = append(breaches, breach)
+			}
+		case models.GeofenceModeInclusion:
+			if Contains(zone, pos) {
+				insideInclusion = true
+			}
+			inclusion = append(inclusion, breach)
+		}
+	}
+
+	if len(inclusion) > 0 && !insideInclusion {
+		breaches = append(breaches, inclusion...)
+	}
+
+	return breaches
+}
+
+// CheckPath verifies that a path does not enter an exclusion zone or leave
+// the inclusion zones. The path is sampled along each great-circle segment
+// with the altitude interpolated linearly. The first point is the aircraft's
+// current position; zones it already violates are ignored so that a path
+// leading out of a breach is accepted.
+func (m *Manager) CheckPath(path []models.Position) error {
+	if m.Count() == 0 || len(path) == 0 {
+		return nil
+	}
+
+	ignore := make(map[string]bool)
+	for _, breach := range m.Check(path[0]) {
+		ignore[breach.ZoneID] = true
+	}
+
+	for i := 1; i < len(path); i++ {
+		from, to := path[i-1], path[i]
+		distance := geo.Haversine(from.Latitude, from.Longitude, to.Latitude, to.Longitude)
+		samples := int(math.Ceil(distance / pathSampleSpacing))
+		samples = max(1, min(samples, maxSamplesPerSegment))
+
+		for j := 1; j <= samples; j++ {
+			f := float64(j) / float64(samples)
+			lat, lon := geo.Interpolate(from.Latitude, from.Longitude, to.Latitude, to.Longitude, f)
+			sample := models.Position{
+				Latitude:  lat,
+				Longitude: lon,
+				Altitude:  from.Altitude + (to.Altitude-from.Altitude)*f,
+			}
+			if err := m.checkSample(sample, i-1, ignore); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkSample returns a ConflictError if a single path sample violates a
+// zone that is not in the ignore set.
+func (m *Manager) checkSample(pos models.Position, segment int, ignore map[string]bool) error {
+	for _, breach := range m.Check(pos) {
+		if ignore[breach.ZoneID] {
+			continue
+		}
+		return &ConflictError{
+			ZoneID:       breach.ZoneID,
+			Name:         breach.Name,
+			Mode:         breach.Mode,
+			SegmentIndex: segment,
+			Position:     pos,
+		}
+	}
+	return nil
+}
+
+// Query returns the zones of any mode that contain a point. If alt is nil
+// only the horizontal extent is checked.
+func (m *Manager) Query(lat, lon float64, alt *float64) []models.Geofence {
+	if m == nil {
+		return nil
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	zones := make([]models.Geofence, 0)
+	for _, id := range m.order {
+		zone := m.zones[id]
+		if !containsHorizontal(zone, lat, lon) {
+			continue
+		}
+		if alt != nil && !withinLimits(zone, *alt) {
+			continue
+		}
+		zones = append(zones, cloneZone(zone))
+	}
+	return zones
+}
+
+// Contains reports whether a position lies within a zone, including its
+// altitude floor and ceiling.
+func Contains(zone models.Geofence, pos models.Position) bool {
+	return withinLimits(zone, pos.Altitude) && containsHorizontal(zone, pos.Latitude, pos.Longitude)
+}
+
+// withinLimits reports whether an altitude lies between a zone's floor and ceiling.
+func withinLimits(zone models.Geofence, altitude float64) bool {
+	if zone.FloorM != nil && altitude < *zone.FloorM {
+		return false
+	}
+	if zone.CeilingM != nil && altitude > *zone.CeilingM {
+		return false
+	}
+	return true
+}
+
+// containsHorizontal reports whether a point lies within a zone's horizontal extent.
+func containsHorizontal(zone models.Geofence, lat, lon float64) bool {
+	p := geo.Point{Lat: lat, Lon: lon}
+	switch zone.Shape {
+	case models.GeofenceShapePolygon:
+		return geo.PointInPolygon(p, toPoints(zone.Vertices))
+	case models.GeofenceShapeCircle:
+		if zone.Center == nil {
+			return false
+		}
+		center := geo.Point{Lat: zone.Center.Latitude, Lon: zone.Center.Longitude}
+		return geo.PointInCircle(p, center, zone.RadiusM)
+	default:
+		return false
+	}
+}
+
+// toPoints converts model coordinates to geo points.
+func toPoints(coords []models.Coordinate) []geo.Point {
+	points := make([]geo.Point, len(coords))
+	for i, c := range coords {
+		points[i] = geo.Point{Lat: c.Latitude, Lon: c.Longitude}
+	}
+	return points
+}
+
+// cloneZone returns a copy of a zone that shares no mutable state.
+func cloneZone(zone models.Geofence) models.Geofence {
+	if zone.Vertices != nil {
+		zone.Vertices = append([]models.Coordinate(nil), zone.Vertices...)
+	}
+	if zone.Center != nil {
+		center := *zone.Center
+		zone.Center = &center
+	}
+	if zone.FloorM != nil {
+		floor := *zone.FloorM
+		zone.FloorM = &floor
+	}
+	if zone.CeilingM != nil {
+		ceiling := *zone.CeilingM
+		zone.CeilingM = &ceiling
+	}
+	return zone
+}
@@ -0,0 +1,179 @@
+package geofence
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+)
+
+func squareZone(mode models.GeofenceMode) models.Geofence {
+	return models.Geofence{
+		Name:  "square",
+		Shape: models.GeofenceShapePolygon,
+		Mode:  mode,
+		Vertices: []models.Coordinate{
+			{Latitude: 32.0, Longitude: 34.0},
+			{Latitude: 32.0, Longitude: 34.1},
+			{Latitude: 32.1, Longitude: 34.1},
+			{Latitude: 32.1, Longitude: 34.0},
+		},
+	}
+}
+
+func ptr(f float64) *float64 {
+	return &f
+}
+
+func TestContains(t *testing.T) {
+	polygon := squareZone(models.GeofenceModeExclusion)
+	polygon.FloorM = ptr(500)
+	polygon.CeilingM = ptr(2000)
+
+	circle := models.Geofence{
+		Shape:   models.GeofenceShapeCircle,
+		Mode:    models.GeofenceModeExclusion,
+		Center:  &models.Coordinate{Latitude: 32.0, Longitude: 34.0},
+		RadiusM: 1000,
+	}
+
+	tests := []struct {
+		name     string
+		zone     models.Geofence
+		pos      models.Position
+		expected bool
+	}{
+		{name: "Inside polygon within limits", zone: polygon, pos: models.Position{Latitude: 32.05, Longitude: 34.05, Altitude: 1000}, expected: true},
+		{name: "Inside polygon below floor", zone: polygon, pos: models.Position{Latitude: 32.05, Longitude: 34.05, Altitude: 100}, expected: false},
+		{name: "Inside polygon above ceiling", zone: polygon, pos: models.Position{Latitude: 32.05, Longitude: 34.05, Altitude: 2500}, expected: false},
+		{name: "Outside polygon", zone: polygon, pos: models.Position{Latitude: 32.2, Longitude: 34.05, Altitude: 1000}, expected: false},
+		{name: "Inside circle", zone: circle, pos: models.Position{Latitude: 32.005, Longitude: 34.0, Altitude: 1000}, expected: true},
+		{name: "Outside circle", zone: circle, pos: models.Position{Latitude: 32.02, Longitude: 34.0, Altitude: 1000}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := Contains(tt.zone, tt.pos); result != tt.expected {
+				t.Errorf("Contains() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestManager_CRUD(t *testing.T) {
+	m := NewManager()
+
+	zone := m.Add(squareZone(models.GeofenceModeExclusion))
+	if zone.ID == "" {
+		t.Fatal("Add() did not assign an ID")
+	}
+
+	zone.Name = "renamed"
+	if _, err := m.Update(zone.ID, zone); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	got, err := m.Get(zone.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Name != "renamed" {
+		t.Errorf("Get().Name = %q, want %q", got.Name, "renamed")
+	}
+
+	if err := m.Delete(zone.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := m.Get(zone.ID); !errors.Is(err, models.ErrGeofenceNotFound) {
+		t.Errorf("Get() after delete error = %v, want %v", err, models.ErrGeofenceNotFound)
+	}
+	if len(m.List()) != 0 {
+		t.Errorf("List() length = %d, want 0", len(m.List()))
+	}
+}
+
+func TestManager_Reset(t *testing.T) {
+	m := NewManager()
+	zone := m.Add(squareZone(models.GeofenceModeExclusion))
+	m.Add(squareZone(models.GeofenceModeInclusion))
+
+	m.Reset([]models.Geofence{zone})
+	if got, err := m.Get(zone.ID); err != nil || got.ID != zone.ID {
+		t.Errorf("Get() after Reset = %v, %v", got.ID, err)
+	}
+	if n := len(m.List()); n != 1 {
+		t.Errorf("List() after Reset = %d zones, want 1", n)
+	}
+}
+
+func TestManager_Check(t *testing.T) {
+	m := NewManager()
+	m.Add(squareZone(models.GeofenceModeInclusion))
+
+	inside := models.Position{Latitude: 32.05, Longitude: 34.05, Altitude: 1000}
+	outside := models.Position{Latitude: 32.5, Longitude: 34.05, Altitude: 1000}
+
+	if breaches := m.Check(inside); len(breaches) != 0 {
+		t.Errorf("Check(inside inclusion) = %v, want no breaches", breaches)
+	}
+	if breaches := m.Check(outside); len(breaches) != 1 {
+		t.Errorf("Check(outside inclusion) returned %d breaches, want 1", len(breaches))
+	}
+
+	var nilManager *Manager
+	if breaches := nilManager.Check(inside); breaches != nil {
+		t.Errorf("nil Check() = %v, want nil", breaches)
+	}
+}
+
+func TestManager_CheckPath(t *testing.T) {
+	m := NewManager()
+	m.Add(squareZone(models.GeofenceModeExclusion))
+
+	tests := []struct {
+		name      string
+		path      []models.Position
+		wantError bool
+	}{
+		{
+			name: "Path crossing exclusion zone",
+			path: []models.Position{
+				{Latitude: 32.05, Longitude: 33.9, Altitude: 1000},
+				{Latitude: 32.05, Longitude: 34.2, Altitude: 1000},
+			},
+			wantError: true,
+		},
+		{
+			name: "Path around exclusion zone",
+			path: []models.Position{
+				{Latitude: 32.2, Longitude: 33.9, Altitude: 1000},
+				{Latitude: 32.2, Longitude: 34.2, Altitude: 1000},
+			},
+			wantError: false,
+		},
+		{
+			name: "Path leaving exclusion zone",
+			path: []models.Position{
+				{Latitude: 32.05, Longitude: 34.05, Altitude: 1000},
+				{Latitude: 32.05, Longitude: 34.2, Altitude: 1000},
+			},
+			wantError: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := m.CheckPath(tt.path)
+			if tt.wantError {
+				var conflict *ConflictError
+				if !errors.As(err, &conflict) {
+					t.Fatalf("CheckPath() error = %v, want *ConflictError", err)
+				}
+				if !errors.Is(err, models.ErrGeofenceConflict) {
+					t.Errorf("CheckPath() error does not wrap %v", models.ErrGeofenceConflict)
+				}
+			} else if err != nil {
+				t.Errorf("CheckPath() unexpected error: %v", err)
+			}
+		})
+	}
+}
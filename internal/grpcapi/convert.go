@@ -0,0 +1,242 @@
+package grpcapi
+
+import (
+	"github.com/meiron-tzhori/Flight-Simulator/internal/api/handlers"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	pb "github.com/meiron-tzhori/Flight-Simulator/pkg/flightsimv1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// toCommandRequest converts a command message to the request the command
+// handler validates, as if its parameters were a REST request body.
+func toCommandRequest(cmd *pb.Command) handlers.CommandRequest {
+	req := handlers.CommandRequest{Type: cmd.GetType()}
+
+	if g := cmd.GetGoto(); g != nil {
+		req.GoTo = &handlers.GoToRequest{
+			Lat:    g.GetTarget().GetLatitude(),
+			Lon:    g.GetTarget().GetLongitude(),
+			Fix:    g.GetFix(),
+			Alt:    g.GetTarget().GetAltitude(),
+			Speed:  g.Speed,
+			AltRef: g.GetAltitudeRef(),
+
+			CallbackURL:    g.GetCallbackUrl(),
+			CallbackSecret: g.GetCallbackSecret(),
+		}
+	}
+
+	if t := cmd.GetTrajectory(); t != nil {
+		req.Trajectory = &handlers.TrajectoryRequest{
+			Waypoints:      make([]handlers.WaypointRequest, len(t.GetWaypoints())),
+			Loop:           t.GetLoop(),
+			ReturnToHome:   t.GetReturnToHome(),
+			CallbackURL:    t.GetCallbackUrl(),
+			CallbackSecret: t.GetCallbackSecret(),
+		}
+		for i, wp := range t.GetWaypoints() {
+			req.Trajectory.Waypoints[i] = handlers.WaypointRequest{
+				Lat:    wp.GetPosition().GetLatitude(),
+				Lon:    wp.GetPosition().GetLongitude(),
+				Fix:    wp.GetFix(),
+				Alt:    wp.GetPosition().GetAltitude(),
+				Speed:  wp.Speed,
+				AltRef: wp.GetAltitudeRef(),
+				Hold:   wp.GetHoldSeconds(),
+			}
+		}
+	}
+
+	if r := cmd.GetRth(); r != nil {
+		req.RTH = &handlers.RTHRequest{Alt: r.Altitude}
+	}
+
+	if t := cmd.GetTakeoff(); t != nil {
+		req.Takeoff = &handlers.TakeoffRequest{
+			Runway: toRunwayRequest(t.GetRunway()),
+			Alt:    t.GetAltitude(),
+			Speed:  t.Speed,
+		}
+	}
+
+	if l := cmd.GetLand(); l != nil {
+		req.Land = &handlers.LandRequest{
+			Runway:     toRunwayRequest(l.GetRunway()),
+			GlideSlope: l.GetGlideSlope(),
+		}
+	}
+
+	return req
+}
+
+// toRunwayRequest converts a runway message. The threshold altitude is the
+// runway elevation.
+func toRunwayRequest(runway *pb.Runway) *handlers.RunwayRequest {
+	if runway == nil {
+		return nil
+	}
+	elevation := runway.GetThreshold().GetAltitude()
+	heading := runway.GetHeading()
+	return &handlers.RunwayRequest{
+		Lat:       runway.GetThreshold().GetLatitude(),
+		Lon:       runway.GetThreshold().GetLongitude(),
+		Elevation: &elevation,
+		Heading:   &heading,
+	}
+}
+
+// fromCommandResponse converts a command response.
+func fromCommandResponse(response models.CommandResponse) *pb.CommandResponse {
+	msg := &pb.CommandResponse{
+		Status:            response.Status,
+		CommandId:         response.CommandID,
+		Message:           response.Message,
+		Target:            fromPositionPtr(response.Target),
+		WaypointCount:     int32(response.WaypointCount),
+		EtaSeconds:        response.ETASeconds,
+		HoldPosition:      fromPositionPtr(response.HoldPosition),
+		OrbitRadiusMeters: response.OrbitRadiusM,
+		Warnings:          response.Warnings,
+	}
+	for _, wp := range response.Waypoints {
+		msg.Waypoints = append(msg.Waypoints, &pb.Waypoint{
+			Position:    fromPosition(wp.Position),
+			Speed:       wp.Speed,
+			AltitudeRef: string(wp.AltitudeRef),
+			Fix:         wp.Fix,
+			HoldSeconds: wp.HoldSeconds,
+		})
+	}
+	return msg
+}
+
+// fromAircraftState converts an aircraft state.
+func fromAircraftState(state models.AircraftState) *pb.AircraftState {
+	msg := &pb.AircraftState{
+		Position: fromPosition(state.Position),
+		Velocity: &pb.Velocity{
+			GroundSpeed:   state.Velocity.GroundSpeed,
+			VerticalSpeed: state.Velocity.VerticalSpeed,
+		},
+		Heading:       state.Heading,
+		Timestamp:     timestamppb.New(state.Timestamp),
+		SimTime:       state.SimTime,
+		Terrain:       fromTerrainState(state.Terrain),
+		LinkLost:      state.LinkLost,
+		EngineFailure: state.EngineFailure,
+		Phase:         string(state.Phase),
+		Fuel:          state.Fuel,
+	}
+
+	if cmd := state.ActiveCommand; cmd != nil {
+		msg.ActiveCommand = &pb.CommandInfo{
+			Id:            cmd.ID,
+			Type:          cmd.Type,
+			Target:        fromPositionPtr(cmd.Target),
+			EtaSeconds:    cmd.ETASeconds,
+			WaypointIndex: fromIndex(cmd.WaypointIndex),
+		}
+	}
+
+	if env := state.Environment; env != nil {
+		msg.Environment = &pb.EnvironmentState{
+			Wind:       fromWind(env.Wind),
+			Turbulence: env.Turbulence,
+			Humidity:   env.Humidity,
+		}
+	}
+
+	for _, breach := range state.GeofenceBreaches {
+		msg.GeofenceBreaches = append(msg.GeofenceBreaches, fromGeofenceBreach(&breach))
+	}
+
+	return msg
+}
+
+// fromEvent converts a simulation event.
+func fromEvent(event models.Event) *pb.Event {
+	msg := &pb.Event{
+		Id:            event.ID,
+		Type:          string(event.Type),
+		Timestamp:     timestamppb.New(event.Timestamp),
+		SimTime:       event.SimTime,
+		Position:      fromPosition(event.Position),
+		Message:       event.Message,
+		WaypointIndex: fromIndex(event.WaypointIndex),
+		Geofence:      fromGeofenceBreach(event.Geofence),
+		Terrain:       fromTerrainState(event.Terrain),
+	}
+
+	if cmd := event.Command; cmd != nil {
+		msg.Command = &pb.EventCommand{
+			Id:           cmd.ID,
+			Type:         string(cmd.Type),
+			SupersededBy: cmd.SupersededBy,
+		}
+	}
+
+	if phase := event.Phase; phase != nil {
+		msg.Phase = &pb.PhaseChange{From: string(phase.From), To: string(phase.To)}
+	}
+
+	if conditions := event.Conditions; conditions != nil {
+		msg.Conditions = &pb.ConditionsUpdate{
+			Wind:          fromWind(conditions.Wind),
+			EngineFailure: conditions.EngineFailure,
+		}
+	}
+
+	return msg
+}
+
+func fromPosition(pos models.Position) *pb.Position {
+	return &pb.Position{
+		Latitude:  pos.Latitude,
+		Longitude: pos.Longitude,
+		Altitude:  pos.Altitude,
+	}
+}
+
+func fromPositionPtr(pos *models.Position) *pb.Position {
+	if pos == nil {
+		return nil
+	}
+	return fromPosition(*pos)
+}
+
+func fromWind(wind *models.WindVector) *pb.WindVector {
+	if wind == nil {
+		return nil
+	}
+	return &pb.WindVector{Direction: wind.Direction, Speed: wind.Speed}
+}
+
+func fromTerrainState(terrain *models.TerrainState) *pb.TerrainState {
+	if terrain == nil {
+		return nil
+	}
+	return &pb.TerrainState{
+		Elevation: terrain.Elevation,
+		HeightAgl: terrain.HeightAGL,
+		PullUp:    terrain.PullUp,
+	}
+}
+
+func fromGeofenceBreach(breach *models.GeofenceBreach) *pb.GeofenceBreach {
+	if breach == nil {
+		return nil
+	}
+	return &pb.GeofenceBreach{
+		ZoneId: breach.ZoneID,
+		Name:   breach.Name,
+		Mode:   string(breach.Mode),
+	}
+}
+
+func fromIndex(index *int) *int32 {
+	if index == nil {
+		return nil
+	}
+	i := int32(*index)
+	return &i
+}
@@ -0,0 +1,263 @@
+// Package grpcapi serves the simulator over gRPC, with the service defined in
+// proto/flightsim/v1/flightsim.proto.
+package grpcapi
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/api/handlers"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/config"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/navdata"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/pubsub"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/simulator"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/webhook"
+	pb "github.com/meiron-tzhori/Flight-Simulator/pkg/flightsimv1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// errorDomain is the domain of the ErrorInfo details of rejected
+	// commands.
+	errorDomain = "flight-simulator"
+
+	// maxBufferSize limits the state buffer a client may ask for.
+	maxBufferSize = 1000
+)
+
+// Server represents the gRPC API server.
+type Server struct {
+	pb.UnimplementedFlightSimulatorServer
+
+	grpcServer *grpc.Server
+	addr       string
+	streamCfg  config.StreamingConfig
+	commands   *handlers.CommandHandler
+	simulator  *simulator.Simulator
+	logger     *slog.Logger
+}
+
+// NewServer creates a new gRPC API server. Commands are submitted through a
+// command handler so they get the same checks and callbacks as the REST
+// command endpoints. State streams are buffered and handle lagging clients as
+// configured in streamCfg, unless the client asks otherwise.
+func NewServer(cfg config.GRPCConfig, simCfg config.SimulationConfig, streamCfg config.StreamingConfig, sim *simulator.Simulator, nav *navdata.Database, webhooks *webhook.Manager, logger *slog.Logger) *Server {
+	s := &Server{
+		grpcServer: grpc.NewServer(),
+		addr:       fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		streamCfg:  streamCfg,
+		commands:   handlers.NewCommandHandler(sim, nav, webhooks, logger, simCfg.MaxSpeed),
+		simulator:  sim,
+		logger:     logger,
+	}
+	pb.RegisterFlightSimulatorServer(s.grpcServer, s)
+	return s
+}
+
+// Start listens on the configured address and serves until ctx is
+// cancelled.
+func (s *Server) Start(ctx context.Context) error {
+	lis, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	return s.Serve(ctx, lis)
+}
+
+// Serve serves on lis until ctx is cancelled.
+func (s *Server) Serve(ctx context.Context, lis net.Listener) error {
+	s.logger.Info("Starting gRPC server", "addr", lis.Addr().String())
+
+	// Start server in background
+	errChan := make(chan error, 1)
+	go func() {
+		if err := s.grpcServer.Serve(lis); err != nil {
+			errChan <- err
+		}
+	}()
+
+	// Wait for context cancellation or error
+	select {
+	case <-ctx.Done():
+		s.logger.Info("Shutting down gRPC server")
+		// Streams only end when their clients go away, so they are cut off
+		// rather than waited for
+		s.grpcServer.Stop()
+		return nil
+	case err := <-errChan:
+		return err
+	}
+}
+
+// SubmitCommand validates and submits a command.
+func (s *Server) SubmitCommand(ctx context.Context, cmd *pb.Command) (*pb.CommandResponse, error) {
+	response, cmdErr := s.commands.Submit(ctx, toCommandRequest(cmd))
+	if cmdErr != nil {
+		return nil, commandStatus(cmdErr)
+	}
+	return fromCommandResponse(response), nil
+}
+
+// GetState returns the current aircraft state.
+func (s *Server) GetState(ctx context.Context, _ *pb.GetStateRequest) (*pb.AircraftState, error) {
+	state, err := s.simulator.GetState(ctx)
+	if err != nil {
+		s.logger.Error("Failed to get state", "error", err)
+		return nil, status.Error(codes.Unavailable, "Failed to retrieve aircraft state")
+	}
+	return fromAircraftState(state), nil
+}
+
+// StreamState streams the published aircraft states until the client goes
+// away or is evicted for lagging.
+func (s *Server) StreamState(req *pb.StreamStateRequest, stream pb.FlightSimulator_StreamStateServer) error {
+	opts, err := s.subscribeOptions(req)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	subID := uuid.New().String()
+	publisher := s.simulator.GetPublisher()
+	stateChan, err := publisher.Subscribe(subID, opts)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	defer publisher.Unsubscribe(subID)
+
+	s.logger.Info("gRPC state stream opened", "subscriber_id", subID, "policy", opts.Policy)
+	defer s.logger.Info("gRPC state stream closed", "subscriber_id", subID)
+
+	for {
+		select {
+		case msg, ok := <-stateChan:
+			if !ok {
+				// Only the broker closes an open subscription
+				return status.Error(codes.ResourceExhausted, "state stream evicted, too many states dropped while lagging")
+			}
+			if err := stream.Send(fromAircraftState(msg.Payload)); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+// subscribeOptions returns the subscription options of a state stream, the
+// configured ones unless the request overrides them.
+func (s *Server) subscribeOptions(req *pb.StreamStateRequest) (pubsub.SubscribeOptions, error) {
+	opts := pubsub.SubscribeOptions{
+		Topics:     []string{simulator.StateTopic(s.simulator.AircraftID())},
+		Kind:       "grpc",
+		BufferSize: s.streamCfg.BufferSize,
+		MaxDrops:   s.streamCfg.MaxDrops,
+	}
+
+	policy, err := pubsub.ParseSlowPolicy(cmp.Or(req.GetSlowPolicy(), s.streamCfg.SlowPolicy))
+	if err != nil {
+		return opts, err
+	}
+	opts.Policy = policy
+
+	if req.MaxDrops != nil {
+		if req.GetMaxDrops() < 0 {
+			return opts, fmt.Errorf("max_drops must not be negative")
+		}
+		opts.MaxDrops = int(req.GetMaxDrops())
+	}
+	if req.GetBufferSize() < 0 || req.GetBufferSize() > maxBufferSize {
+		return opts, fmt.Errorf("buffer_size must be between 0 and %d", maxBufferSize)
+	}
+	if req.GetBufferSize() > 0 {
+		opts.BufferSize = int(req.GetBufferSize())
+	}
+	return opts, nil
+}
+
+// StreamEvents streams simulation events until the client goes away, after
+// the kept events following req.Since.
+func (s *Server) StreamEvents(req *pb.StreamEventsRequest, stream pb.FlightSimulator_StreamEventsServer) error {
+	subID := uuid.New().String()
+	broker := s.simulator.GetEventBroker()
+	eventChan, err := broker.Subscribe(subID, pubsub.SubscribeOptions{
+		Topics: []string{simulator.EventsPattern(s.simulator.AircraftID())},
+		Kind:   "grpc",
+	})
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	defer broker.Unsubscribe(subID)
+
+	s.logger.Info("gRPC event stream opened", "subscriber_id", subID)
+	defer s.logger.Info("gRPC event stream closed", "subscriber_id", subID)
+
+	// Subscribing first means no event is missed between the replay and the
+	// live events; live events already replayed are skipped
+	var lastSent uint64
+	if req.GetSince() > 0 {
+		events, _ := s.simulator.GetEvents().Since(req.GetSince())
+		for _, event := range events {
+			if err := stream.Send(fromEvent(event)); err != nil {
+				return err
+			}
+			lastSent = event.ID
+		}
+	}
+
+	for {
+		select {
+		case msg, ok := <-eventChan:
+			if !ok {
+				return nil
+			}
+			if msg.Payload.ID <= lastSent {
+				continue
+			}
+			if err := stream.Send(fromEvent(msg.Payload)); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+// commandStatus converts a rejected command to a gRPC status. The code
+// follows the HTTP status, and the REST error code is the reason of an
+// ErrorInfo detail.
+func commandStatus(cmdErr *handlers.CommandError) error {
+	detail := cmdErr.Response.Error
+	st := status.New(httpStatusCode(cmdErr.Status), detail.Message)
+	info := &errdetails.ErrorInfo{Reason: detail.Code, Domain: errorDomain}
+	if detail.Field != "" {
+		info.Metadata = map[string]string{"field": detail.Field}
+	}
+	if withDetails, err := st.WithDetails(info); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
+// httpStatusCode maps an HTTP error status to a gRPC code.
+func httpStatusCode(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict, http.StatusUnprocessableEntity:
+		return codes.FailedPrecondition
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	default:
+		return codes.Internal
+	}
+}
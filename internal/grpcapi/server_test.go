@@ -0,0 +1,304 @@
+package grpcapi
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/config"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/navdata"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/simulator"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/webhook"
+	pb "github.com/meiron-tzhori/Flight-Simulator/pkg/flightsimv1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newTestClient starts a simulator and a gRPC server on an in-process
+// listener and returns a client connected to it.
+func newTestClient(t *testing.T) pb.FlightSimulatorClient {
+	t.Helper()
+
+	simCfg := config.SimulationConfig{
+		TickRateHz:       10.0,
+		CommandQueueSize: 10,
+		InitialPosition: config.PositionConfig{
+			Latitude:  32.0,
+			Longitude: 34.0,
+			Altitude:  1000.0,
+		},
+		DefaultSpeed:      100.0,
+		MaxSpeed:          250.0,
+		MaxClimbRate:      15.0,
+		MaxDescentRate:    10.0,
+		PositionTolerance: 10.0,
+		HeadingChangeRate: 30.0,
+		SpeedChangeRate:   50.0,
+		Fuel:              config.FuelConfig{Enabled: true, Capacity: 500},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelWarn}))
+
+	sim, err := simulator.New(simCfg, config.EnvironmentConfig{}, logger)
+	if err != nil {
+		t.Fatalf("Failed to create simulator: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go sim.Run(ctx)
+
+	lis := bufconn.Listen(1 << 20)
+	webhooks := webhook.NewManager(config.WebhookConfig{}, sim, logger)
+	server := NewServer(config.GRPCConfig{}, simCfg, config.StreamingConfig{BufferSize: 10}, sim, navdata.NewDatabase(), webhooks, logger)
+	go server.Serve(ctx, lis)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return pb.NewFlightSimulatorClient(conn)
+}
+
+func TestSubmitCommand(t *testing.T) {
+	client := newTestClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	speed := 120.0
+	response, err := client.SubmitCommand(ctx, &pb.Command{
+		Type: "goto",
+		Goto: &pb.GoToCommand{
+			Target: &pb.Position{Latitude: 32.1, Longitude: 34.1, Altitude: 1500},
+			Speed:  &speed,
+		},
+	})
+	if err != nil {
+		t.Fatalf("SubmitCommand(goto) error = %v", err)
+	}
+	if response.GetStatus() != "accepted" || response.GetCommandId() == "" {
+		t.Errorf("SubmitCommand(goto) = %v, want an accepted command", response)
+	}
+	if response.GetTarget().GetLatitude() != 32.1 {
+		t.Errorf("target latitude = %v, want 32.1", response.GetTarget().GetLatitude())
+	}
+
+	// Callbacks are watched like over REST
+	response, err = client.SubmitCommand(ctx, &pb.Command{
+		Type: "goto",
+		Goto: &pb.GoToCommand{
+			Target:         &pb.Position{Latitude: 32.2, Longitude: 34.1, Altitude: 1500},
+			CallbackUrl:    "https://example.com/done",
+			CallbackSecret: "s3cret",
+		},
+	})
+	if err != nil || response.GetStatus() != "accepted" {
+		t.Errorf("SubmitCommand(goto with callback) = %v, %v, want an accepted command", response, err)
+	}
+
+	tests := []struct {
+		name     string
+		cmd      *pb.Command
+		wantCode codes.Code
+		reason   string
+	}{
+		{
+			name: "invalid latitude",
+			cmd: &pb.Command{Type: "goto", Goto: &pb.GoToCommand{
+				Target: &pb.Position{Latitude: 95, Longitude: 34.1, Altitude: 1500},
+			}},
+			wantCode: codes.InvalidArgument,
+			reason:   "INVALID_LATITUDE",
+		},
+		{
+			name: "unsigned callback",
+			cmd: &pb.Command{Type: "goto", Goto: &pb.GoToCommand{
+				Target:      &pb.Position{Latitude: 32.1, Longitude: 34.1, Altitude: 1500},
+				CallbackUrl: "https://example.com/done",
+			}},
+			wantCode: codes.InvalidArgument,
+			reason:   "MISSING_CALLBACK_SECRET",
+		},
+		{
+			name: "invalid callback url",
+			cmd: &pb.Command{Type: "trajectory", Trajectory: &pb.TrajectoryCommand{
+				Waypoints:      []*pb.Waypoint{{Position: &pb.Position{Latitude: 32.1, Longitude: 34.1, Altitude: 1500}}},
+				CallbackUrl:    "done",
+				CallbackSecret: "s3cret",
+			}},
+			wantCode: codes.InvalidArgument,
+			reason:   "INVALID_CALLBACK_URL",
+		},
+		{
+			name:     "missing parameters",
+			cmd:      &pb.Command{Type: "trajectory"},
+			wantCode: codes.InvalidArgument,
+			reason:   "INVALID_REQUEST",
+		},
+		{
+			name:     "unknown type",
+			cmd:      &pb.Command{Type: "loop"},
+			wantCode: codes.InvalidArgument,
+			reason:   "INVALID_REQUEST",
+		},
+		{
+			name:     "takeoff while airborne",
+			cmd:      &pb.Command{Type: "takeoff", Takeoff: &pb.TakeoffCommand{Altitude: 1500}},
+			wantCode: codes.FailedPrecondition,
+			reason:   "INVALID_FLIGHT_PHASE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.SubmitCommand(ctx, tt.cmd)
+			st := status.Convert(err)
+			if st.Code() != tt.wantCode {
+				t.Fatalf("code = %v, want %v (%v)", st.Code(), tt.wantCode, err)
+			}
+
+			var reason string
+			for _, detail := range st.Details() {
+				if info, ok := detail.(*errdetails.ErrorInfo); ok {
+					reason = info.GetReason()
+				}
+			}
+			if reason != tt.reason {
+				t.Errorf("reason = %q, want %q", reason, tt.reason)
+			}
+		})
+	}
+}
+
+func TestGetState(t *testing.T) {
+	client := newTestClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	state, err := client.GetState(ctx, &pb.GetStateRequest{})
+	if err != nil {
+		t.Fatalf("GetState() error = %v", err)
+	}
+
+	if state.GetPosition().GetLatitude() != 32.0 || state.GetPosition().GetAltitude() != 1000.0 {
+		t.Errorf("position = %v, want the initial position", state.GetPosition())
+	}
+	if state.GetPhase() != "cruise" {
+		t.Errorf("phase = %q, want cruise", state.GetPhase())
+	}
+	if state.GetTimestamp().AsTime().IsZero() {
+		t.Error("timestamp not set")
+	}
+	if state.Fuel == nil || state.GetFuel() <= 0 || state.GetFuel() > 500 {
+		t.Errorf("fuel = %v, want up to the 500 kg capacity", state.Fuel)
+	}
+}
+
+func TestStreamState(t *testing.T) {
+	client := newTestClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.StreamState(ctx, &pb.StreamStateRequest{})
+	if err != nil {
+		t.Fatalf("StreamState() error = %v", err)
+	}
+
+	// The simulator publishes every tick
+	var last float64
+	for i := 0; i < 3; i++ {
+		state, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv() error = %v", err)
+		}
+		if i > 0 && state.GetSimTime() <= last {
+			t.Errorf("sim_time = %v after %v, want increasing", state.GetSimTime(), last)
+		}
+		last = state.GetSimTime()
+	}
+}
+
+func TestStreamStateOptions(t *testing.T) {
+	client := newTestClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tests := []struct {
+		name     string
+		req      *pb.StreamStateRequest
+		wantCode codes.Code
+	}{
+		{name: "coalesce", req: &pb.StreamStateRequest{SlowPolicy: "coalesce", BufferSize: 1}, wantCode: codes.OK},
+		{name: "unknown policy", req: &pb.StreamStateRequest{SlowPolicy: "block"}, wantCode: codes.InvalidArgument},
+		{name: "buffer too large", req: &pb.StreamStateRequest{BufferSize: maxBufferSize + 1}, wantCode: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := client.StreamState(ctx, tt.req)
+			if err != nil {
+				t.Fatalf("StreamState() error = %v", err)
+			}
+			_, err = stream.Recv()
+			if code := status.Code(err); code != tt.wantCode {
+				t.Errorf("Recv() code = %v, want %v (%v)", code, tt.wantCode, err)
+			}
+		})
+	}
+}
+
+func TestStreamEvents(t *testing.T) {
+	client := newTestClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := client.SubmitCommand(ctx, &pb.Command{Type: "hold"}); err != nil {
+		t.Fatalf("SubmitCommand(hold) error = %v", err)
+	}
+
+	// Without since only new events are streamed, not the hold command's
+	stream, err := client.StreamEvents(ctx, &pb.StreamEventsRequest{Since: 0})
+	if err != nil {
+		t.Fatalf("StreamEvents() error = %v", err)
+	}
+	time.Sleep(100 * time.Millisecond) // Let the stream subscribe
+
+	response, err := client.SubmitCommand(ctx, &pb.Command{Type: "stop"})
+	if err != nil {
+		t.Fatalf("SubmitCommand(stop) error = %v", err)
+	}
+
+	event, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv() error = %v", err)
+	}
+	if event.GetType() != "command_accepted" || event.GetCommand().GetId() != response.GetCommandId() {
+		t.Errorf("event = %v, want the stop command accepted", event)
+	}
+
+	// Since replays the kept events first
+	replay, err := client.StreamEvents(ctx, &pb.StreamEventsRequest{Since: event.GetId() - 1})
+	if err != nil {
+		t.Fatalf("StreamEvents(since) error = %v", err)
+	}
+	replayed, err := replay.Recv()
+	if err != nil {
+		t.Fatalf("Recv() error = %v", err)
+	}
+	if replayed.GetId() != event.GetId() {
+		t.Errorf("replayed event %d, want %d", replayed.GetId(), event.GetId())
+	}
+}
@@ -4,12 +4,43 @@ import "time"
 
 // AircraftState represents the complete state of the aircraft at a point in time.
 type AircraftState struct {
-	Position      Position          `json:"position"`
-	Velocity      Velocity          `json:"velocity"`
-	Heading       float64           `json:"heading"` // degrees, 0-360 (0=North)
-	Timestamp     time.Time         `json:"timestamp"`
-	ActiveCommand *CommandInfo      `json:"active_command,omitempty"`
-	Environment   *EnvironmentState `json:"environment,omitempty"`
+	Position         Position          `json:"position"`
+	Velocity         Velocity          `json:"velocity"`
+	Heading          float64           `json:"heading"` // degrees, 0-360 (0=North)
+	Timestamp        time.Time         `json:"timestamp"`
+	SimTime          float64           `json:"sim_time"` // seconds of simulation time since start
+	ActiveCommand    *CommandInfo      `json:"active_command,omitempty"`
+	Environment      *EnvironmentState `json:"environment,omitempty"`
+	Terrain          *TerrainState     `json:"terrain,omitempty"`
+	GeofenceBreaches []GeofenceBreach  `json:"geofence_breaches,omitempty"`
+	LinkLost         bool              `json:"link_lost,omitempty"`
+	EngineFailure    bool              `json:"engine_failure,omitempty"` // the aircraft glides and cannot climb
+	Phase            FlightPhase       `json:"phase"`
+	Fuel             *float64          `json:"fuel,omitempty"` // kg on board, nil without the fuel model
+}
+
+// FlightPhase is the aircraft's current phase of flight.
+type FlightPhase string
+
+const (
+	FlightPhaseParked      FlightPhase = "parked"       // on the ground, stationary
+	FlightPhaseTaxi        FlightPhase = "taxi"         // on the ground, moving to the runway
+	FlightPhaseTakeoffRoll FlightPhase = "takeoff_roll" // accelerating along the runway
+	FlightPhaseClimb       FlightPhase = "climb"
+	FlightPhaseCruise      FlightPhase = "cruise"
+	FlightPhaseDescent     FlightPhase = "descent"
+	FlightPhaseApproach    FlightPhase = "approach" // established on the glide slope
+	FlightPhaseLanded      FlightPhase = "landed"   // on the ground after landing
+)
+
+// OnGround reports whether the phase is a ground phase.
+func (p FlightPhase) OnGround() bool {
+	switch p {
+	case FlightPhaseParked, FlightPhaseTaxi, FlightPhaseTakeoffRoll, FlightPhaseLanded:
+		return true
+	default:
+		return false
+	}
 }
 
 // Position represents geographic coordinates.
@@ -25,17 +56,30 @@ type Velocity struct {
 	VerticalSpeed float64 `json:"vertical_speed"` // m/s (positive = climbing)
 }
 
+// StateUpdate repositions the aircraft. Fields left nil keep their current
+// value.
+type StateUpdate struct {
+	Position      Position `json:"position"`
+	Heading       *float64 `json:"heading,omitempty"`        // degrees, 0-360
+	GroundSpeed   *float64 `json:"ground_speed,omitempty"`   // m/s
+	VerticalSpeed *float64 `json:"vertical_speed,omitempty"` // m/s
+	ClearCommand  bool     `json:"clear_command,omitempty"`  // drop the active command and its progress
+}
+
 // CommandInfo contains information about the currently executing command.
 type CommandInfo struct {
-	Type       string    `json:"type"` // "goto", "trajectory", "hold", "stop"
-	Target     *Position `json:"target,omitempty"`
-	ETASeconds float64   `json:"eta_seconds,omitempty"`
+	ID            string    `json:"id"`
+	Type          string    `json:"type"` // "goto", "trajectory", "hold", "stop", ...
+	Target        *Position `json:"target,omitempty"`
+	ETASeconds    float64   `json:"eta_seconds,omitempty"`
+	WaypointIndex *int      `json:"waypoint_index,omitempty"` // trajectory commands: the waypoint being flown to
 }
 
 // EnvironmentState represents environmental conditions.
 type EnvironmentState struct {
-	Wind     *WindVector `json:"wind,omitempty"`
-	Humidity *float64    `json:"humidity,omitempty"` // 0-100%
+	Wind       *WindVector `json:"wind,omitempty"`
+	Turbulence *float64    `json:"turbulence,omitempty"` // RMS gust speed, m/s
+	Humidity   *float64    `json:"humidity,omitempty"`   // 0-100%
 }
 
 // WindVector represents wind direction and speed.
@@ -43,3 +87,17 @@ type WindVector struct {
 	Direction float64 `json:"direction"` // degrees
 	Speed     float64 `json:"speed"`     // m/s
 }
+
+// ConditionsUpdate changes the simulated conditions while the simulator runs.
+// Fields left nil keep their current value.
+type ConditionsUpdate struct {
+	Wind          *WindVector `json:"wind,omitempty"`
+	EngineFailure *bool       `json:"engine_failure,omitempty"`
+}
+
+// TerrainState reports terrain clearance below the aircraft.
+type TerrainState struct {
+	Elevation float64 `json:"elevation"`  // meters MSL
+	HeightAGL float64 `json:"height_agl"` // meters above ground
+	PullUp    bool    `json:"pull_up"`    // automatic pull-up active
+}
@@ -10,6 +10,17 @@ const (
 	CommandTypeTrajectory CommandType = "trajectory"
 	CommandTypeStop       CommandType = "stop"
 	CommandTypeHold       CommandType = "hold"
+	CommandTypeRTH        CommandType = "rth"
+	CommandTypeLand       CommandType = "land"
+	CommandTypeTakeoff    CommandType = "takeoff"
+)
+
+// AltitudeReference identifies the datum an altitude is measured from.
+type AltitudeReference string
+
+const (
+	AltitudeReferenceMSL AltitudeReference = "msl" // meters above mean sea level (default)
+	AltitudeReferenceAGL AltitudeReference = "agl" // meters above ground level (terrain following)
 )
 
 // Command represents a command to the aircraft.
@@ -18,24 +29,58 @@ type Command struct {
 	Type       CommandType        `json:"type"`
 	GoTo       *GoToCommand       `json:"goto,omitempty"`
 	Trajectory *TrajectoryCommand `json:"trajectory,omitempty"`
+	RTH        *RTHCommand        `json:"rth,omitempty"`
+	Takeoff    *TakeoffCommand    `json:"takeoff,omitempty"`
+	Land       *LandCommand       `json:"land,omitempty"`
 }
 
 // GoToCommand directs the aircraft to a specific point.
 type GoToCommand struct {
-	Target Position `json:"target"`
-	Speed  *float64 `json:"speed,omitempty"` // m/s, optional
+	Target      Position          `json:"target"`
+	Speed       *float64          `json:"speed,omitempty"`        // m/s, optional
+	AltitudeRef AltitudeReference `json:"altitude_ref,omitempty"` // defaults to MSL
+	Fix         string            `json:"fix,omitempty"`          // navdata identifier the target was resolved from
 }
 
 // TrajectoryCommand directs the aircraft to follow a sequence of waypoints.
 type TrajectoryCommand struct {
-	Waypoints []Waypoint `json:"waypoints"`
-	Loop      bool       `json:"loop"`
+	Waypoints    []Waypoint `json:"waypoints"`
+	Loop         bool       `json:"loop"`
+	ReturnToHome bool       `json:"return_to_home,omitempty"` // return home after the last waypoint (ignored when looping)
+}
+
+// RTHCommand directs the aircraft to climb to a safe altitude and fly home.
+type RTHCommand struct {
+	Altitude *float64 `json:"altitude,omitempty"` // meters MSL, optional (default: configured RTH altitude)
+}
+
+// Runway describes the runway used for a takeoff or landing.
+type Runway struct {
+	Threshold Position `json:"threshold"` // altitude is the threshold elevation, meters MSL
+	Heading   float64  `json:"heading"`   // degrees true, direction of takeoff/landing
+}
+
+// TakeoffCommand directs a parked aircraft to take off and climb out.
+type TakeoffCommand struct {
+	Runway   *Runway  `json:"runway,omitempty"` // optional (default: current position and heading)
+	Altitude float64  `json:"altitude"`         // climb-out altitude, meters MSL
+	Speed    *float64 `json:"speed,omitempty"`  // m/s, optional
+}
+
+// LandCommand directs the aircraft to land. Without a runway the aircraft
+// descends vertically at its current position.
+type LandCommand struct {
+	Runway     *Runway `json:"runway,omitempty"`
+	GlideSlope float64 `json:"glide_slope,omitempty"` // degrees, optional (default: configured glide slope)
 }
 
 // Waypoint represents a point in a trajectory.
 type Waypoint struct {
-	Position Position `json:"position"`
-	Speed    *float64 `json:"speed,omitempty"` // m/s, optional
+	Position    Position          `json:"position"`
+	Speed       *float64          `json:"speed,omitempty"`        // m/s, optional
+	AltitudeRef AltitudeReference `json:"altitude_ref,omitempty"` // defaults to MSL
+	Fix         string            `json:"fix,omitempty"`          // navdata identifier the position was resolved from
+	HoldSeconds float64           `json:"hold_seconds,omitempty"` // time to hold at the waypoint before continuing
 }
 
 // NewCommand creates a new command with a unique ID.
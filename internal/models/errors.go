@@ -4,13 +4,29 @@ import "errors"
 
 // Validation errors
 var (
-	ErrInvalidLatitude  = errors.New("latitude must be between -90 and 90 degrees")
-	ErrInvalidLongitude = errors.New("longitude must be between -180 and 180 degrees")
-	ErrInvalidAltitude  = errors.New("altitude must be non-negative")
-	ErrInvalidSpeed     = errors.New("speed must be positive")
-	ErrEmptyWaypoints   = errors.New("trajectory must contain at least one waypoint")
-	ErrInvalidWaypoint  = errors.New("invalid waypoint")
-	ErrSpeedExceedsMax  = errors.New("speed exceeds maximum allowed")
+	ErrInvalidLatitude      = errors.New("latitude must be between -90 and 90 degrees")
+	ErrInvalidLongitude     = errors.New("longitude must be between -180 and 180 degrees")
+	ErrInvalidAltitude      = errors.New("altitude must be non-negative")
+	ErrInvalidSpeed         = errors.New("speed must be positive")
+	ErrEmptyWaypoints       = errors.New("trajectory must contain at least one waypoint")
+	ErrInvalidWaypoint      = errors.New("invalid waypoint")
+	ErrSpeedExceedsMax      = errors.New("speed exceeds maximum allowed")
+	ErrInvalidVerticalSpeed = errors.New("vertical speed exceeds the climb or descent rate")
+
+	ErrInvalidAltitudeReference = errors.New("altitude reference must be 'msl' or 'agl'")
+	ErrInvalidGeofence          = errors.New("invalid geofence")
+	ErrInvalidHeading           = errors.New("heading must be between 0 and 360 degrees")
+	ErrInvalidGlideSlope        = errors.New("glide slope must be between 0 and 10 degrees")
+	ErrUnknownFix               = errors.New("unknown fix identifier")
+	ErrInvalidRoute             = errors.New("invalid route")
+	ErrInvalidMission           = errors.New("invalid mission file")
+	ErrInvalidTrack             = errors.New("invalid route or track file")
+	ErrInvalidSnapshot          = errors.New("invalid snapshot")
+	ErrInvalidScenario          = errors.New("invalid scenario")
+	ErrInvalidStudy             = errors.New("invalid Monte Carlo study")
+	ErrInvalidWebhook           = errors.New("invalid webhook")
+	ErrInvalidCallbackURL       = errors.New("callback URL must be an absolute http or https URL")
+	ErrMissingCallbackSecret    = errors.New("callback secret is required, no default is configured")
 )
 
 // Runtime errors
@@ -19,6 +35,19 @@ var (
 	ErrSimulatorNotRunning = errors.New("simulator is not running")
 	ErrTimeout             = errors.New("operation timeout")
 	ErrTerrainConflict     = errors.New("terrain collision detected")
+	ErrGeofenceConflict    = errors.New("path crosses restricted airspace")
+	ErrGeofenceNotFound    = errors.New("geofence not found")
+	ErrInvalidFlightPhase  = errors.New("command not allowed in current flight phase")
+	ErrReplayActive        = errors.New("simulator is replaying a recorded session")
+	ErrNotReplaying        = errors.New("simulator is not replaying a session")
+	ErrSnapshotNotFound    = errors.New("snapshot not found")
+	ErrEnvironmentDisabled = errors.New("environment effects are disabled")
+	ErrGeofencingDisabled  = errors.New("geofencing is disabled")
+	ErrScenarioNotFound    = errors.New("scenario run not found")
+	ErrScenarioRunning     = errors.New("a scenario is already running")
+	ErrStudyNotFound       = errors.New("study not found")
+	ErrStudyRunning        = errors.New("a Monte Carlo study is already running")
+	ErrWebhookNotFound     = errors.New("webhook not found")
 )
 
 // ErrorResponse represents an API error response.
@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// EventType identifies a simulation event.
+type EventType string
+
+const (
+	EventCommandAccepted    EventType = "command_accepted"   // received from a client
+	EventCommandStarted     EventType = "command_started"    // became the active command
+	EventCommandCompleted   EventType = "command_completed"  // finished its goal
+	EventCommandSuperseded  EventType = "command_superseded" // replaced by a newer command
+	EventCommandCancelled   EventType = "command_cancelled"  // dropped before finishing, e.g. by a forced landing
+	EventWaypointReached    EventType = "waypoint_reached"
+	EventTrajectoryLooped   EventType = "trajectory_looped" // a looping trajectory restarted from its first waypoint
+	EventPhaseChanged       EventType = "phase_changed"
+	EventEnvironmentChanged EventType = "environment_changed" // wind changed or engine failure injected or cleared
+	EventGeofenceBreach     EventType = "geofence_breach"
+	EventGeofenceCleared    EventType = "geofence_cleared"
+	EventTerrainWarning     EventType = "terrain_warning" // terrain pull-up engaged
+)
+
+// EventTypes lists the event types.
+var EventTypes = []EventType{
+	EventCommandAccepted, EventCommandStarted, EventCommandCompleted, EventCommandSuperseded,
+	EventCommandCancelled, EventWaypointReached, EventTrajectoryLooped, EventPhaseChanged,
+	EventEnvironmentChanged, EventGeofenceBreach, EventGeofenceCleared, EventTerrainWarning,
+}
+
+// Event is a notable moment of the simulation. Events are numbered in the
+// order they were published, from 1; only the detail fields of the event's
+// type are set.
+type Event struct {
+	ID        uint64    `json:"id"`
+	Type      EventType `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	SimTime   float64   `json:"sim_time"`
+	Position  Position  `json:"position"`
+	Message   string    `json:"message"`
+
+	Command       *EventCommand     `json:"command,omitempty"`        // command events, waypoint_reached, trajectory_looped
+	WaypointIndex *int              `json:"waypoint_index,omitempty"` // waypoint_reached
+	Phase         *PhaseChange      `json:"phase,omitempty"`          // phase_changed
+	Conditions    *ConditionsUpdate `json:"conditions,omitempty"`     // environment_changed
+	Geofence      *GeofenceBreach   `json:"geofence,omitempty"`       // geofence_breach, geofence_cleared
+	Terrain       *TerrainState     `json:"terrain,omitempty"`        // terrain_warning
+}
+
+// EventCommand identifies the command an event is about.
+type EventCommand struct {
+	ID           string      `json:"id"`
+	Type         CommandType `json:"type"`
+	SupersededBy string      `json:"superseded_by,omitempty"` // ID of the replacing command
+}
+
+// PhaseChange is a flight phase transition.
+type PhaseChange struct {
+	From FlightPhase `json:"from"`
+	To   FlightPhase `json:"to"`
+}
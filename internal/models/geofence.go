@@ -0,0 +1,56 @@
+package models
+
+// GeofenceShape represents the geometry of a geofence.
+type GeofenceShape string
+
+const (
+	GeofenceShapePolygon GeofenceShape = "polygon"
+	GeofenceShapeCircle  GeofenceShape = "circle"
+)
+
+// GeofenceMode determines whether the aircraft must stay inside or outside a zone.
+type GeofenceMode string
+
+const (
+	GeofenceModeInclusion GeofenceMode = "inclusion" // aircraft must stay inside
+	GeofenceModeExclusion GeofenceMode = "exclusion" // aircraft must stay outside
+	GeofenceModeAdvisory  GeofenceMode = "advisory"  // reported by airspace queries, not enforced
+)
+
+// BreachAction is the simulator's response to a geofence breach.
+type BreachAction string
+
+const (
+	BreachActionEvent  BreachAction = "event"  // report only
+	BreachActionHold   BreachAction = "hold"   // hold at the breach position
+	BreachActionReturn BreachAction = "return" // fly back to the last safe point
+)
+
+// Coordinate represents a horizontal geographic coordinate.
+type Coordinate struct {
+	Latitude  float64 `json:"latitude"`  // degrees, -90 to 90
+	Longitude float64 `json:"longitude"` // degrees, -180 to 180
+}
+
+// Geofence represents a polygon or circular zone with optional altitude limits.
+type Geofence struct {
+	ID           string        `json:"id"`
+	Name         string        `json:"name,omitempty"`
+	Shape        GeofenceShape `json:"shape"`
+	Mode         GeofenceMode  `json:"mode"`
+	Vertices     []Coordinate  `json:"vertices,omitempty"`      // polygon only
+	Center       *Coordinate   `json:"center,omitempty"`        // circle only
+	RadiusM      float64       `json:"radius_m,omitempty"`      // circle only, meters
+	FloorM       *float64      `json:"floor_m,omitempty"`       // meters MSL, nil = surface
+	CeilingM     *float64      `json:"ceiling_m,omitempty"`     // meters MSL, nil = unlimited
+	BreachAction BreachAction  `json:"breach_action,omitempty"` // defaults to configured action
+	Class        string        `json:"class,omitempty"`         // airspace class, e.g. "R", "CTR"
+	Source       string        `json:"source,omitempty"`        // import file, empty if created via API
+}
+
+// GeofenceBreach describes a zone the aircraft is currently violating.
+type GeofenceBreach struct {
+	ZoneID string       `json:"zone_id"`
+	Name   string       `json:"name,omitempty"`
+	Mode   GeofenceMode `json:"mode"`
+}
@@ -0,0 +1,90 @@
+package models
+
+import "time"
+
+// StudyState is the state of a Monte Carlo study.
+type StudyState string
+
+const (
+	StudyRunning   StudyState = "running"
+	StudyCompleted StudyState = "completed"
+	StudyAborted   StudyState = "aborted" // stopped through the API
+)
+
+// StudyStatus reports a Monte Carlo study.
+type StudyStatus struct {
+	ID         string       `json:"id"`
+	Scenario   string       `json:"scenario"` // scenario name
+	State      StudyState   `json:"state"`
+	StartedAt  time.Time    `json:"started_at"`
+	FinishedAt *time.Time   `json:"finished_at,omitempty"`
+	Runs       int          `json:"runs"`
+	Completed  int          `json:"completed"` // runs finished so far
+	Report     *StudyReport `json:"report,omitempty"`
+}
+
+// StudyReport aggregates the runs of a Monte Carlo study.
+type StudyReport struct {
+	Scenario string                `json:"scenario"`
+	Runs     int                   `json:"runs"`
+	Seed     int64                 `json:"seed"`
+	Outcomes map[ScenarioState]int `json:"outcomes"` // runs per scenario outcome
+
+	Arrived     int           `json:"arrived"`                // runs that met the scenario's until condition
+	ArrivalTime *Distribution `json:"arrival_time,omitempty"` // scenario seconds, arrived runs only
+
+	CrossTrack *Distribution `json:"max_cross_track,omitempty"` // largest cross-track error of each run, meters
+
+	FuelAtLanding *Distribution `json:"fuel_at_landing,omitempty"` // kg, landed runs with the fuel model only
+
+	TerrainViolations  ViolationCount `json:"terrain_violations"`
+	GeofenceViolations ViolationCount `json:"geofence_violations"`
+
+	WallTime float64     `json:"wall_time"` // seconds the study took
+	Results  []RunResult `json:"results"`
+}
+
+// Distribution summarizes a sample of values.
+type Distribution struct {
+	Count  int     `json:"count"`
+	Min    float64 `json:"min"`
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"std_dev"`
+	P5     float64 `json:"p5"`
+	P50    float64 `json:"p50"`
+	P90    float64 `json:"p90"`
+	P95    float64 `json:"p95"`
+	P99    float64 `json:"p99"`
+	Max    float64 `json:"max"`
+}
+
+// ViolationCount counts violations over all runs.
+type ViolationCount struct {
+	Runs  int `json:"runs"`  // runs with at least one violation
+	Total int `json:"total"` // violations in all runs
+}
+
+// RunResult is the outcome of one Monte Carlo run.
+type RunResult struct {
+	Run                int           `json:"run"`
+	Seed               int64         `json:"seed"` // reproduces the run's conditions
+	Conditions         RunConditions `json:"conditions"`
+	State              ScenarioState `json:"state"`
+	Message            string        `json:"message,omitempty"`
+	ArrivalTime        *float64      `json:"arrival_time,omitempty"`
+	MaxCrossTrack      float64       `json:"max_cross_track"`
+	FuelAtLanding      *float64      `json:"fuel_at_landing,omitempty"` // kg, runs that landed with the fuel model
+	TerrainViolations  int           `json:"terrain_violations"`
+	GeofenceViolations int           `json:"geofence_violations"`
+}
+
+// RunConditions are the randomized conditions of one Monte Carlo run.
+type RunConditions struct {
+	WindSpeed      float64 `json:"wind_speed"`      // added to every wind, m/s
+	WindDirection  float64 `json:"wind_direction"`  // added to every wind, degrees
+	TurbulenceSeed int64   `json:"turbulence_seed"` // turbulence gust sequence
+	North          float64 `json:"north"`           // initial position error, meters
+	East           float64 `json:"east"`            // initial position error, meters
+	Altitude       float64 `json:"altitude"`        // initial altitude error, meters
+	Performance    float64 `json:"performance"`     // factor on climb, descent, turn and acceleration rates
+}
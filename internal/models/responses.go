@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // HealthResponse represents the health check response.
 type HealthResponse struct {
 	Status            string  `json:"status"`
@@ -11,12 +13,42 @@ type HealthResponse struct {
 
 // CommandResponse represents the response to a command submission.
 type CommandResponse struct {
-	Status        string    `json:"status"`
-	CommandID     string    `json:"command_id"`
-	Message       string    `json:"message"`
-	Target        *Position `json:"target,omitempty"`
-	WaypointCount int       `json:"waypoint_count,omitempty"`
-	ETASeconds    float64   `json:"eta_seconds,omitempty"`
-	HoldPosition  *Position `json:"hold_position,omitempty"`
-	OrbitRadiusM  float64   `json:"orbit_radius_meters,omitempty"`
+	Status        string     `json:"status"`
+	CommandID     string     `json:"command_id"`
+	Message       string     `json:"message"`
+	Target        *Position  `json:"target,omitempty"`
+	WaypointCount int        `json:"waypoint_count,omitempty"`
+	Waypoints     []Waypoint `json:"waypoints,omitempty"`
+	ETASeconds    float64    `json:"eta_seconds,omitempty"`
+	HoldPosition  *Position  `json:"hold_position,omitempty"`
+	OrbitRadiusM  float64    `json:"orbit_radius_meters,omitempty"`
+	Warnings      []string   `json:"warnings,omitempty"` // parts of an imported file that were not used
+}
+
+// ReplayStatus describes the playback of a recorded session.
+type ReplayStatus struct {
+	SimTime        float64   `json:"sim_time"`           // seconds of simulation time played
+	Duration       *float64  `json:"duration,omitempty"` // seconds, unknown if the session did not end cleanly
+	Speed          float64   `json:"speed"`              // playback speed factor, 0 = paused
+	Finished       bool      `json:"finished"`
+	StartedAt      time.Time `json:"started_at"` // wall-clock start of the recorded session
+	EntriesApplied int       `json:"entries_applied"`
+	Entries        int       `json:"entries"`
+}
+
+// SubscriberStats describes a channel subscriber of a broker and how far it
+// is behind.
+type SubscriberStats struct {
+	ID           string     `json:"id"`
+	Kind         string     `json:"kind,omitempty"` // grpc, websocket, mqtt, webhook, scenario
+	Topics       []string   `json:"topics"`         // topic patterns
+	Policy       string     `json:"policy"`         // drop_newest, drop_oldest, coalesce or disconnect
+	BufferSize   int        `json:"buffer_size"`
+	MaxDrops     *int       `json:"max_drops,omitempty"` // disconnect: drops tolerated before eviction
+	Queued       int        `json:"queued"`              // messages waiting to be read
+	LagSeconds   float64    `json:"lag_seconds"`         // age of the oldest queued message
+	Delivered    uint64     `json:"delivered"`
+	Dropped      uint64     `json:"dropped"`
+	SubscribedAt time.Time  `json:"subscribed_at"`
+	LastDropAt   *time.Time `json:"last_drop_at,omitempty"`
 }
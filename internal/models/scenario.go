@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// ScenarioState is the state of a scenario run.
+type ScenarioState string
+
+const (
+	ScenarioRunning ScenarioState = "running"
+	ScenarioPassed  ScenarioState = "passed"
+	ScenarioFailed  ScenarioState = "failed"  // an assertion failed
+	ScenarioAborted ScenarioState = "aborted" // stopped through the API
+	ScenarioError   ScenarioState = "error"   // an event could not be applied
+)
+
+// AssertionKind is when a scenario assertion is checked.
+type AssertionKind string
+
+const (
+	AssertionAlways     AssertionKind = "always"
+	AssertionEventually AssertionKind = "eventually"
+	AssertionAtEnd      AssertionKind = "at_end"
+)
+
+// AssertionResult reports a scenario assertion.
+type AssertionResult struct {
+	Kind      AssertionKind `json:"kind"`
+	Condition string        `json:"condition"`
+	Status    string        `json:"status"`         // "pending", "passed" or "failed"
+	Time      *float64      `json:"time,omitempty"` // scenario seconds when it was decided
+}
+
+// ScenarioStatus reports a scenario run.
+type ScenarioStatus struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	State       ScenarioState     `json:"state"`
+	StartedAt   time.Time         `json:"started_at"`
+	FinishedAt  *time.Time        `json:"finished_at,omitempty"`
+	Elapsed     float64           `json:"elapsed"`   // seconds of simulation time since the scenario start
+	UntilMet    bool              `json:"until_met"` // the scenario ended because its until condition held
+	EventsFired int               `json:"events_fired"`
+	Events      int               `json:"events"`
+	Assertions  []AssertionResult `json:"assertions,omitempty"`
+	Message     string            `json:"message,omitempty"` // why the scenario failed or ended
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Snapshot is a saved copy of the complete simulator state: the aircraft
+// state (including the wind and engine failure, which are restored with it),
+// the active command and its progress. It is plain data, so it can be stored
+// as JSON and restored later by a simulator with the same configuration.
+type Snapshot struct {
+	ID            string             `json:"id"`
+	Name          string             `json:"name,omitempty"`
+	CreatedAt     time.Time          `json:"created_at"`
+	State         AircraftState      `json:"state"`
+	ActiveCommand *Command           `json:"active_command,omitempty"`
+	Mission       *TrajectoryCommand `json:"mission,omitempty"` // last trajectory flown
+	Progress      SnapshotProgress   `json:"progress"`
+}
+
+// SnapshotProgress holds the simulator's progress through the active command
+// and its failsafe state. Stages are the simulator's internal stage numbers;
+// nil stages mean no stage was in progress.
+type SnapshotProgress struct {
+	WaypointIndex    *int     `json:"waypoint_index,omitempty"` // trajectory commands
+	HoldRemaining    float64  `json:"hold_remaining,omitempty"` // seconds left holding at the waypoint
+	TakeoffStage     *int     `json:"takeoff_stage,omitempty"`
+	TakeoffRunway    *Runway  `json:"takeoff_runway,omitempty"`
+	LandingStage     *int     `json:"landing_stage,omitempty"`
+	RTHPhase         *int     `json:"rth_phase,omitempty"`
+	RTHAltitude      float64  `json:"rth_altitude,omitempty"` // meters MSL
+	PullUpActive     bool     `json:"pull_up_active,omitempty"`
+	BreachedZones    []string `json:"breached_zones,omitempty"`
+	LastSafePosition Position `json:"last_safe_position"`
+	SinceContact     float64  `json:"since_contact"` // seconds since the last command or heartbeat
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Webhook is a registered receiver of simulation events.
+type Webhook struct {
+	ID         string      `json:"id"`
+	URL        string      `json:"url"`
+	EventTypes []EventType `json:"event_types"` // none = all event types
+	CreatedAt  time.Time   `json:"created_at"`
+	Secret     string      `json:"-"` // HMAC key of the signatures, never returned
+}
+
+// DeliveryState is the state of a webhook delivery.
+type DeliveryState string
+
+const (
+	DeliveryPending   DeliveryState = "pending" // being sent or waiting for a retry
+	DeliveryDelivered DeliveryState = "delivered"
+	DeliveryFailed    DeliveryState = "failed" // rejected, or attempts exhausted
+)
+
+// WebhookDelivery reports the delivery of an event to a webhook or a
+// command callback.
+type WebhookDelivery struct {
+	ID             string        `json:"id"`
+	WebhookID      string        `json:"webhook_id,omitempty"` // webhook deliveries
+	CommandID      string        `json:"command_id,omitempty"` // command callbacks
+	URL            string        `json:"url"`
+	EventID        uint64        `json:"event_id"`
+	EventType      EventType     `json:"event_type"`
+	State          DeliveryState `json:"state"`
+	Attempts       int           `json:"attempts"`
+	ResponseStatus int           `json:"response_status,omitempty"` // of the last attempt
+	Error          string        `json:"error,omitempty"`           // of the last failed attempt
+	CreatedAt      time.Time     `json:"created_at"`
+	FinishedAt     *time.Time    `json:"finished_at,omitempty"`
+}
@@ -0,0 +1,205 @@
+package montecarlo
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/config"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/geofence"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+)
+
+// maxStudies is the number of finished studies kept for status reporting.
+const maxStudies = 20
+
+// Manager runs studies in the background, one at a time, and keeps the
+// status of recent studies. It is safe for concurrent use.
+type Manager struct {
+	simCfg    config.SimulationConfig
+	envCfg    config.EnvironmentConfig
+	geofences *geofence.Manager // nil when geofencing is disabled
+	logger    *slog.Logger
+
+	mu      sync.Mutex
+	studies map[string]*job
+	active  *job
+}
+
+// job is one execution of a study. Its status is guarded by Manager.mu.
+type job struct {
+	status models.StudyStatus
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewManager creates a study manager. Studies start from the simulator
+// configuration and the geofences loaded when they start.
+func NewManager(simCfg config.SimulationConfig, envCfg config.EnvironmentConfig, geofences *geofence.Manager, logger *slog.Logger) *Manager {
+	return &Manager{
+		simCfg:    simCfg,
+		envCfg:    envCfg,
+		geofences: geofences,
+		logger:    logger,
+		studies:   make(map[string]*job),
+	}
+}
+
+// Start validates a study and starts running it in the background. Only
+// one study runs at a time, since each uses all workers it is given.
+func (m *Manager) Start(study *Study) (models.StudyStatus, error) {
+	if err := study.Validate(m.simCfg, m.envCfg); err != nil {
+		return models.StudyStatus{}, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.active != nil {
+		return models.StudyStatus{}, fmt.Errorf("%w: %s", models.ErrStudyRunning, m.active.status.ID)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	j := &job{
+		cancel: cancel,
+		done:   make(chan struct{}),
+		status: models.StudyStatus{
+			ID:        uuid.New().String(),
+			Scenario:  study.Scenario.Name,
+			State:     models.StudyRunning,
+			StartedAt: time.Now(),
+			Runs:      study.Runs,
+		},
+	}
+	m.studies[j.status.ID] = j
+	m.active = j
+	m.prune()
+
+	base := Base{Simulation: m.simCfg, Environment: m.envCfg}
+	if m.geofences != nil {
+		base.Geofences = m.geofences.List()
+	}
+
+	m.logger.Info("Monte Carlo study started",
+		"study_id", j.status.ID,
+		"scenario", study.Scenario.Name,
+		"runs", study.Runs,
+	)
+	go m.execute(ctx, j, study, base)
+
+	return j.status, nil
+}
+
+// Get returns the status of a study.
+func (m *Manager) Get(id string) (models.StudyStatus, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	j, exists := m.studies[id]
+	if !exists {
+		return models.StudyStatus{}, fmt.Errorf("%w: %s", models.ErrStudyNotFound, id)
+	}
+	return j.status, nil
+}
+
+// List returns the status of all kept studies without their reports,
+// oldest first.
+func (m *Manager) List() []models.StudyStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	statuses := make([]models.StudyStatus, 0, len(m.studies))
+	for _, j := range m.studies {
+		status := j.status
+		status.Report = nil
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, k int) bool {
+		return statuses[i].StartedAt.Before(statuses[k].StartedAt)
+	})
+	return statuses
+}
+
+// Abort stops a running study and waits for it to stop.
+func (m *Manager) Abort(ctx context.Context, id string) (models.StudyStatus, error) {
+	m.mu.Lock()
+	j, exists := m.studies[id]
+	m.mu.Unlock()
+	if !exists {
+		return models.StudyStatus{}, fmt.Errorf("%w: %s", models.ErrStudyNotFound, id)
+	}
+
+	j.cancel()
+	return m.Wait(ctx, id)
+}
+
+// Wait waits for a study to finish and returns its final status.
+func (m *Manager) Wait(ctx context.Context, id string) (models.StudyStatus, error) {
+	m.mu.Lock()
+	j, exists := m.studies[id]
+	m.mu.Unlock()
+	if !exists {
+		return models.StudyStatus{}, fmt.Errorf("%w: %s", models.ErrStudyNotFound, id)
+	}
+
+	select {
+	case <-j.done:
+		return m.Get(id)
+	case <-ctx.Done():
+		return models.StudyStatus{}, ctx.Err()
+	}
+}
+
+// execute runs a study and records its report.
+func (m *Manager) execute(ctx context.Context, j *job, study *Study, base Base) {
+	defer close(j.done)
+
+	report, err := Run(ctx, study, base, func(completed int) {
+		m.mu.Lock()
+		j.status.Completed = max(j.status.Completed, completed)
+		m.mu.Unlock()
+	})
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	j.status.FinishedAt = &now
+	j.status.State = models.StudyCompleted
+	if err != nil {
+		j.status.State = models.StudyAborted
+	}
+	j.status.Report = report
+	if m.active == j {
+		m.active = nil
+	}
+
+	m.logger.Info("Monte Carlo study finished",
+		"study_id", j.status.ID,
+		"state", j.status.State,
+		"completed", j.status.Completed,
+	)
+}
+
+// prune drops the oldest finished studies beyond maxStudies.
+func (m *Manager) prune() {
+	if len(m.studies) <= maxStudies {
+		return
+	}
+	finished := make([]*job, 0, len(m.studies))
+	for _, j := range m.studies {
+		if j.status.FinishedAt != nil {
+			finished = append(finished, j)
+		}
+	}
+	sort.Slice(finished, func(i, k int) bool {
+		return finished[i].status.StartedAt.Before(finished[k].status.StartedAt)
+	})
+	for _, j := range finished[:min(len(finished), len(m.studies)-maxStudies)] {
+		delete(m.studies, j.status.ID)
+	}
+}
@@ -0,0 +1,234 @@
+package montecarlo
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"os"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/config"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+)
+
+func testBase() Base {
+	return Base{
+		Simulation: config.SimulationConfig{
+			TickRateHz:        10,
+			CommandQueueSize:  10,
+			InitialPosition:   config.PositionConfig{Latitude: 32.0, Longitude: 34.0, Altitude: 1000},
+			DefaultSpeed:      100,
+			MaxSpeed:          250,
+			MaxClimbRate:      15,
+			MaxDescentRate:    10,
+			PositionTolerance: 50,
+			HeadingChangeRate: 10,
+			SpeedChangeRate:   5,
+			Geofence:          config.GeofenceConfig{Enabled: true, DefaultAction: "event"},
+		},
+		Environment: config.EnvironmentConfig{
+			Enabled: true,
+			Wind:    config.WindConfig{Enabled: true, Direction: 270, Speed: 5},
+		},
+		Geofences: []models.Geofence{{
+			ID:      "zone",
+			Shape:   models.GeofenceShapeCircle,
+			Mode:    models.GeofenceModeExclusion,
+			Center:  &models.Coordinate{Latitude: 32.03, Longitude: 34.02},
+			RadiusM: 300,
+		}},
+	}
+}
+
+const testStudy = `
+runs: 12
+seed: 42
+dispersion:
+  wind_speed: 3
+  wind_direction: 20
+  turbulence: 1
+  position: 200
+  altitude: 20
+  performance: 0.1
+scenario:
+  name: leg
+  initial_state:
+    position: {latitude: 32.0, longitude: 34.0, altitude: 1000}
+    heading: 45
+    ground_speed: 100
+  duration: 3m
+  until: command == none
+  events:
+    - at: 0s
+      command:
+        type: trajectory
+        trajectory:
+          waypoints:
+            - position: {latitude: 32.02, longitude: 34.0, altitude: 1000}
+            - position: {latitude: 32.04, longitude: 34.04, altitude: 1200}
+  assertions:
+    - always: altitude > 500
+`
+
+func TestStudy_Validate(t *testing.T) {
+	base := testBase()
+	disabled := config.EnvironmentConfig{}
+
+	tests := []struct {
+		name  string
+		yaml  string
+		env   config.EnvironmentConfig
+		valid bool
+	}{
+		{"example", testStudy, base.Environment, true},
+		{"defaults", "scenario: {name: empty}", base.Environment, true},
+		{"no scenario", "runs: 10", base.Environment, false},
+		{"too many runs", "runs: 100000\nscenario: {name: empty}", base.Environment, false},
+		{"negative dispersion", "dispersion: {position: -1}\nscenario: {name: empty}", base.Environment, false},
+		{"performance too large", "dispersion: {performance: 0.5}\nscenario: {name: empty}", base.Environment, false},
+		{"wind without environment", "dispersion: {wind_speed: 2}\nscenario: {name: empty}", disabled, false},
+		{"invalid scenario", "scenario: {assertions: [{always: fuel > 0}]}", base.Environment, false},
+		{"not yaml", "runs: [", base.Environment, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			study, err := Parse([]byte(tt.yaml))
+			if err == nil {
+				err = study.Validate(base.Simulation, tt.env)
+			}
+			if tt.valid && err != nil {
+				t.Errorf("Parse/Validate() error = %v", err)
+			}
+			if !tt.valid && !errors.Is(err, models.ErrInvalidStudy) {
+				t.Errorf("Parse/Validate() error = %v, want %v", err, models.ErrInvalidStudy)
+			}
+		})
+	}
+
+	study, _ := Parse([]byte("scenario: {name: empty}"))
+	study.Validate(base.Simulation, base.Environment)
+	if study.Runs != DefaultRuns {
+		t.Errorf("default runs = %d, want %d", study.Runs, DefaultRuns)
+	}
+}
+
+func TestRun_Deterministic(t *testing.T) {
+	base := testBase()
+	run := func(workers int) *models.StudyReport {
+		study, err := Parse([]byte(testStudy))
+		if err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		if err := study.Validate(base.Simulation, base.Environment); err != nil {
+			t.Fatalf("Validate() error = %v", err)
+		}
+		study.Workers = workers
+
+		var calls atomic.Int64
+		report, err := Run(context.Background(), study, base, func(int) { calls.Add(1) })
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if calls.Load() != int64(study.Runs) {
+			t.Errorf("progress called %d times, want %d", calls.Load(), study.Runs)
+		}
+		return report
+	}
+
+	serial, parallel := run(1), run(4)
+	if !reflect.DeepEqual(serial.Results, parallel.Results) {
+		t.Fatal("results depend on the number of workers")
+	}
+
+	report := serial
+	if report.Runs != 12 || report.Outcomes[models.ScenarioPassed] != 12 {
+		t.Errorf("outcomes = %v, want 12 passed runs", report.Outcomes)
+	}
+	if report.Arrived != 12 || report.ArrivalTime == nil || report.ArrivalTime.Min >= report.ArrivalTime.Max {
+		t.Errorf("arrival time = %+v, want a spread over 12 runs", report.ArrivalTime)
+	}
+	if report.CrossTrack == nil || report.CrossTrack.P50 <= 0 || report.CrossTrack.P95 < report.CrossTrack.P50 {
+		t.Errorf("cross-track = %+v, want increasing positive percentiles", report.CrossTrack)
+	}
+	if report.GeofenceViolations.Runs == 0 {
+		t.Errorf("geofence violations = %+v, want the zone on the second leg entered", report.GeofenceViolations)
+	}
+
+	// The conditions are drawn from each run's seed
+	first := report.Results[0]
+	if first.Seed != 42 || first.Conditions.Performance == 1 || first.Conditions.North == 0 {
+		t.Errorf("run 0 = %+v, want randomized conditions from seed 42", first)
+	}
+}
+
+func TestManager_Abort(t *testing.T) {
+	base := testBase()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	manager := NewManager(base.Simulation, base.Environment, nil, logger)
+
+	study, err := Parse([]byte("runs: 10000\nworkers: 1\nscenario: {name: long, duration: 1h}"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	started, err := manager.Start(study)
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if _, err := manager.Start(study); !errors.Is(err, models.ErrStudyRunning) {
+		t.Errorf("second Start() error = %v, want %v", err, models.ErrStudyRunning)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	status, err := manager.Abort(ctx, started.ID)
+	if err != nil {
+		t.Fatalf("Abort() error = %v", err)
+	}
+	if status.State != models.StudyAborted || status.Report != nil || status.FinishedAt == nil {
+		t.Errorf("status = %+v, want aborted without a report", status)
+	}
+	if _, err := manager.Get("unknown"); !errors.Is(err, models.ErrStudyNotFound) {
+		t.Errorf("Get() error = %v, want %v", err, models.ErrStudyNotFound)
+	}
+}
+
+func TestMetrics_FuelAtLanding(t *testing.T) {
+	fuel := func(kg float64) *float64 { return &kg }
+	states := []models.AircraftState{
+		{Phase: models.FlightPhaseLanded, Fuel: fuel(500)}, // starts on the ground
+		{Phase: models.FlightPhaseTakeoffRoll, Fuel: fuel(498)},
+		{Phase: models.FlightPhaseClimb, Fuel: fuel(450)},
+		{Phase: models.FlightPhaseApproach, Fuel: fuel(300)},
+		{Phase: models.FlightPhaseLanded, Fuel: fuel(290)},
+		{Phase: models.FlightPhaseLanded, Fuel: fuel(290)},
+	}
+
+	var m metrics
+	for i, state := range states {
+		m.observe(state, float64(i))
+	}
+	if m.fuelAtLanding == nil || *m.fuelAtLanding != 290 {
+		t.Errorf("fuel at landing = %v, want 290", m.fuelAtLanding)
+	}
+}
+
+func TestDistribution(t *testing.T) {
+	d := distribution([]float64{4, 1, 3, 2, 5})
+	want := models.Distribution{Count: 5, Min: 1, Mean: 3, StdDev: math.Sqrt(2), P5: 1.2, P50: 3, P90: 4.6, P95: 4.8, P99: 4.96, Max: 5}
+	for _, pair := range [][2]float64{
+		{d.Min, want.Min}, {d.Mean, want.Mean}, {d.StdDev, want.StdDev}, {d.P5, want.P5}, {d.P50, want.P50},
+		{d.P90, want.P90}, {d.P95, want.P95}, {d.P99, want.P99}, {d.Max, want.Max},
+	} {
+		if math.Abs(pair[0]-pair[1]) > 1e-9 {
+			t.Fatalf("distribution() = %+v, want %+v", *d, want)
+		}
+	}
+	if distribution(nil) != nil {
+		t.Error("distribution(nil) != nil")
+	}
+}
@@ -0,0 +1,362 @@
+package montecarlo
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"math"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/config"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/scenario"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/simulator"
+	"github.com/meiron-tzhori/Flight-Simulator/pkg/geo"
+)
+
+// minPerformance is the smallest performance factor a run is given.
+const minPerformance = 0.1
+
+// Base is the configuration every run starts from before it is randomized.
+type Base struct {
+	Simulation  config.SimulationConfig
+	Environment config.EnvironmentConfig
+	Geofences   []models.Geofence
+}
+
+// Run executes a validated study and returns its report. Runs are spread
+// over the study's workers; each run draws its conditions from its own seed,
+// so the report does not depend on the number of workers. progress, if not
+// nil, is called concurrently with the number of runs completed so far.
+// When ctx is cancelled, Run stops and returns ctx.Err().
+func Run(ctx context.Context, study *Study, base Base, progress func(completed int)) (*models.StudyReport, error) {
+	started := time.Now()
+
+	workers := study.Workers
+	if workers == 0 {
+		workers = runtime.NumCPU()
+	}
+	workers = min(workers, study.Runs)
+
+	results := make([]models.RunResult, study.Runs)
+	indices := make(chan int)
+	var completed atomic.Int64
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				results[i] = runOnce(ctx, study, base, i)
+				n := completed.Add(1)
+				if progress != nil {
+					progress(int(n))
+				}
+			}
+		}()
+	}
+
+feed:
+	for i := 0; i < study.Runs; i++ {
+		select {
+		case indices <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indices)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	report := aggregate(study, results)
+	report.WallTime = time.Since(started).Seconds()
+	return report, nil
+}
+
+// discardLogger silences the headless simulators.
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// runOnce runs the scenario once under randomized conditions.
+func runOnce(ctx context.Context, study *Study, base Base, index int) models.RunResult {
+	seed := study.Seed + int64(index)
+	conditions := draw(rand.New(rand.NewSource(seed)), study.Dispersion)
+	result := models.RunResult{Run: index, Seed: seed, Conditions: conditions}
+
+	simCfg, envCfg, sc := randomize(study, base, conditions)
+	sim, err := simulator.NewHeadless(simCfg, envCfg, base.Geofences, discardLogger)
+	if err != nil {
+		result.State = models.ScenarioError
+		result.Message = err.Error()
+		return result
+	}
+
+	var m metrics
+	status := scenario.RunHeadless(ctx, sc, sim, m.observe)
+
+	result.State = status.State
+	result.Message = status.Message
+	if status.UntilMet {
+		arrival := status.Elapsed
+		result.ArrivalTime = &arrival
+	}
+	result.MaxCrossTrack = m.maxCrossTrack
+	result.FuelAtLanding = m.fuelAtLanding
+	result.TerrainViolations = m.terrainViolations
+	result.GeofenceViolations = m.geofenceViolations
+	return result
+}
+
+// draw draws the conditions of one run. Every value is drawn, even with a
+// zero dispersion, so that a run's conditions only depend on its seed.
+func draw(rng *rand.Rand, d Dispersion) models.RunConditions {
+	return models.RunConditions{
+		WindSpeed:      rng.NormFloat64() * d.WindSpeed,
+		WindDirection:  rng.NormFloat64() * d.WindDirection,
+		TurbulenceSeed: rng.Int63(),
+		North:          rng.NormFloat64() * d.Position,
+		East:           rng.NormFloat64() * d.Position,
+		Altitude:       rng.NormFloat64() * d.Altitude,
+		Performance:    math.Max(1+rng.NormFloat64()*d.Performance, minPerformance),
+	}
+}
+
+// randomize applies the conditions of a run to the base configuration and
+// to a copy of the scenario.
+func randomize(study *Study, base Base, c models.RunConditions) (config.SimulationConfig, config.EnvironmentConfig, *scenario.Scenario) {
+	simCfg, envCfg := base.Simulation, base.Environment
+	d := study.Dispersion
+
+	// Headless runs keep no track and have no client to lose the link to
+	simCfg.Recorder.Enabled = false
+	simCfg.LostLink.Enabled = false
+
+	simCfg.MaxClimbRate *= c.Performance
+	simCfg.MaxDescentRate *= c.Performance
+	simCfg.HeadingChangeRate *= c.Performance
+	simCfg.SpeedChangeRate *= c.Performance
+
+	if d.WindSpeed > 0 || d.WindDirection > 0 {
+		// A calm configuration gets a wind of its own
+		envCfg.Wind.Enabled = true
+		wind := shiftWind(models.WindVector{Direction: envCfg.Wind.Direction, Speed: envCfg.Wind.Speed}, c)
+		envCfg.Wind.Direction, envCfg.Wind.Speed = wind.Direction, wind.Speed
+	}
+	if d.Turbulence > 0 {
+		envCfg.Turbulence = config.TurbulenceConfig{Enabled: true, Intensity: d.Turbulence}
+	}
+	envCfg.Turbulence.Seed = c.TurbulenceSeed
+
+	// Copy the scenario parts that change; events and assertions are only read
+	sc := *study.Scenario
+	if sc.Environment != nil && sc.Environment.Wind != nil {
+		environment := *sc.Environment
+		environment.Wind = shiftWind(*environment.Wind, c)
+		sc.Environment = &environment
+	}
+	sc.Events = append([]scenario.Event(nil), sc.Events...)
+	for i := range sc.Events {
+		if wind := sc.Events[i].Wind; wind != nil {
+			sc.Events[i].Wind = shiftWind(*wind, c)
+		}
+	}
+
+	if sc.InitialState != nil {
+		initial := *sc.InitialState
+		initial.Position = displace(initial.Position, c)
+		sc.InitialState = &initial
+	} else {
+		if simCfg.Home == (config.PositionConfig{}) {
+			// Home stays at the nominal initial position
+			simCfg.Home = simCfg.InitialPosition
+		}
+		p := displace(models.Position{
+			Latitude:  simCfg.InitialPosition.Latitude,
+			Longitude: simCfg.InitialPosition.Longitude,
+			Altitude:  simCfg.InitialPosition.Altitude,
+		}, c)
+		simCfg.InitialPosition = config.PositionConfig{Latitude: p.Latitude, Longitude: p.Longitude, Altitude: p.Altitude}
+	}
+
+	return simCfg, envCfg, &sc
+}
+
+// shiftWind adds the wind offsets of a run to a wind.
+func shiftWind(wind models.WindVector, c models.RunConditions) *models.WindVector {
+	return &models.WindVector{
+		Direction: math.Mod(math.Mod(wind.Direction+c.WindDirection, 360)+360, 360),
+		Speed:     math.Max(wind.Speed+c.WindSpeed, 0),
+	}
+}
+
+// displace adds the initial position error of a run to a position.
+func displace(p models.Position, c models.RunConditions) models.Position {
+	if distance := math.Hypot(c.North, c.East); distance > 0 {
+		bearing := math.Atan2(c.East, c.North) * 180 / math.Pi
+		p.Latitude, p.Longitude = geo.Destination(p.Latitude, p.Longitude, bearing, distance)
+	}
+	p.Altitude = math.Max(p.Altitude+c.Altitude, 0)
+	return p
+}
+
+// metrics follows the states of one run.
+type metrics struct {
+	maxCrossTrack      float64
+	terrainViolations  int
+	geofenceViolations int
+
+	pullUp   bool
+	breached map[string]bool
+
+	// Fuel on board at the first landing after being airborne
+	airborne      bool
+	fuelAtLanding *float64
+
+	// Current leg of the active command
+	commandID string
+	legStart  models.Position
+	legEnd    *models.Position
+}
+
+// observe updates the metrics with a state.
+func (m *metrics) observe(state models.AircraftState, elapsed float64) {
+	// A terrain violation is every engagement of the terrain pull-up
+	pullUp := state.Terrain != nil && state.Terrain.PullUp
+	if pullUp && !m.pullUp {
+		m.terrainViolations++
+	}
+	m.pullUp = pullUp
+
+	// A geofence violation is every entry into a breached zone
+	breached := make(map[string]bool, len(state.GeofenceBreaches))
+	for _, breach := range state.GeofenceBreaches {
+		if !m.breached[breach.ZoneID] {
+			m.geofenceViolations++
+		}
+		breached[breach.ZoneID] = true
+	}
+	m.breached = breached
+
+	if !state.Phase.OnGround() {
+		m.airborne = true
+	} else if m.airborne && m.fuelAtLanding == nil && state.Phase == models.FlightPhaseLanded && state.Fuel != nil {
+		fuel := *state.Fuel
+		m.fuelAtLanding = &fuel
+	}
+
+	// Cross-track error is measured from the leg being flown: from where
+	// the command started or the previous target to the current target
+	cmd := state.ActiveCommand
+	if cmd == nil || cmd.Target == nil {
+		m.commandID, m.legEnd = "", nil
+		return
+	}
+	switch {
+	case cmd.ID != m.commandID || m.legEnd == nil:
+		m.commandID = cmd.ID
+		m.legStart = state.Position
+	case *cmd.Target != *m.legEnd:
+		m.legStart = *m.legEnd
+	}
+	target := *cmd.Target
+	m.legEnd = &target
+
+	crossTrack := geo.SegmentDistance(
+		geo.Point{Lat: state.Position.Latitude, Lon: state.Position.Longitude},
+		geo.Point{Lat: m.legStart.Latitude, Lon: m.legStart.Longitude},
+		geo.Point{Lat: target.Latitude, Lon: target.Longitude},
+	)
+	m.maxCrossTrack = math.Max(m.maxCrossTrack, crossTrack)
+}
+
+// aggregate summarizes the runs of a study.
+func aggregate(study *Study, results []models.RunResult) *models.StudyReport {
+	report := &models.StudyReport{
+		Scenario: study.Scenario.Name,
+		Runs:     len(results),
+		Seed:     study.Seed,
+		Outcomes: make(map[models.ScenarioState]int),
+		Results:  results,
+	}
+
+	var arrivals, crossTracks, fuels []float64
+	for _, r := range results {
+		report.Outcomes[r.State]++
+		if r.State == models.ScenarioError {
+			continue
+		}
+		if r.ArrivalTime != nil {
+			arrivals = append(arrivals, *r.ArrivalTime)
+		}
+		if r.FuelAtLanding != nil {
+			fuels = append(fuels, *r.FuelAtLanding)
+		}
+		crossTracks = append(crossTracks, r.MaxCrossTrack)
+		countViolations(&report.TerrainViolations, r.TerrainViolations)
+		countViolations(&report.GeofenceViolations, r.GeofenceViolations)
+	}
+
+	report.Arrived = len(arrivals)
+	report.ArrivalTime = distribution(arrivals)
+	report.CrossTrack = distribution(crossTracks)
+	report.FuelAtLanding = distribution(fuels)
+	return report
+}
+
+// countViolations adds the violations of one run.
+func countViolations(count *models.ViolationCount, violations int) {
+	if violations > 0 {
+		count.Runs++
+		count.Total += violations
+	}
+}
+
+// distribution summarizes values, or returns nil if there are none.
+func distribution(values []float64) *models.Distribution {
+	if len(values) == 0 {
+		return nil
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	var sum float64
+	for _, v := range sorted {
+		sum += v
+	}
+	mean := sum / float64(len(sorted))
+	var squares float64
+	for _, v := range sorted {
+		squares += (v - mean) * (v - mean)
+	}
+
+	return &models.Distribution{
+		Count:  len(sorted),
+		Min:    sorted[0],
+		Mean:   mean,
+		StdDev: math.Sqrt(squares / float64(len(sorted))),
+		P5:     percentile(sorted, 5),
+		P50:    percentile(sorted, 50),
+		P90:    percentile(sorted, 90),
+		P95:    percentile(sorted, 95),
+		P99:    percentile(sorted, 99),
+		Max:    sorted[len(sorted)-1],
+	}
+}
+
+// percentile interpolates the p-th percentile of sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(rank)
+	if lower+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[lower] + (rank-float64(lower))*(sorted[lower+1]-sorted[lower])
+}
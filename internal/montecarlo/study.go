@@ -0,0 +1,117 @@
+// Package montecarlo runs a scenario many times on headless simulators with
+// randomized wind, turbulence, initial position and aircraft performance,
+// and aggregates the outcomes into dispersion statistics.
+package montecarlo
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/config"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/scenario"
+	"gopkg.in/yaml.v3"
+)
+
+// Study limits.
+const (
+	DefaultRuns = 100
+	MaxRuns     = 10000
+)
+
+// maxPerformance limits the performance dispersion so that no run loses
+// most of its climb, turn or acceleration performance.
+const maxPerformance = 0.3
+
+// Study is a Monte Carlo study definition. Study files are YAML (or JSON)
+// with the scenario inline, in the scenario file format.
+type Study struct {
+	Runs       int                `json:"runs,omitempty"`    // default 100
+	Seed       int64              `json:"seed,omitempty"`    // the same seed reproduces the same runs
+	Workers    int                `json:"workers,omitempty"` // parallel runs, default the number of CPUs
+	Dispersion Dispersion         `json:"dispersion"`
+	Scenario   *scenario.Scenario `json:"scenario"`
+}
+
+// Dispersion sets the standard deviations of the randomized conditions.
+// Zero keeps a condition as configured.
+type Dispersion struct {
+	WindSpeed     float64 `json:"wind_speed,omitempty"`     // m/s, added to every wind
+	WindDirection float64 `json:"wind_direction,omitempty"` // degrees, added to every wind
+	Turbulence    float64 `json:"turbulence,omitempty"`     // RMS gust speed (m/s); not random itself
+	Position      float64 `json:"position,omitempty"`       // initial position error north and east, meters
+	Altitude      float64 `json:"altitude,omitempty"`       // initial altitude error, meters
+	Performance   float64 `json:"performance,omitempty"`    // relative, e.g. 0.05 for 5%
+}
+
+// Load reads a study file.
+func Load(path string) (*Study, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read study: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse decodes a YAML or JSON study. Call Validate before running it.
+func Parse(data []byte) (*Study, error) {
+	// Decode the YAML generically, then through JSON, like scenario files
+	var raw any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrInvalidStudy, err)
+	}
+	if raw == nil {
+		return nil, fmt.Errorf("%w: empty study", models.ErrInvalidStudy)
+	}
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrInvalidStudy, err)
+	}
+
+	var study Study
+	if err := json.Unmarshal(encoded, &study); err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrInvalidStudy, err)
+	}
+	return &study, nil
+}
+
+// Validate checks the study against the configuration the runs start from,
+// fills in defaults and validates the scenario.
+func (st *Study) Validate(simCfg config.SimulationConfig, envCfg config.EnvironmentConfig) error {
+	if st.Runs == 0 {
+		st.Runs = DefaultRuns
+	}
+	if st.Runs < 0 || st.Runs > MaxRuns {
+		return fmt.Errorf("%w: runs must be between 1 and %d", models.ErrInvalidStudy, MaxRuns)
+	}
+	if st.Workers < 0 {
+		return fmt.Errorf("%w: negative workers", models.ErrInvalidStudy)
+	}
+
+	d := st.Dispersion
+	for _, sigma := range []float64{d.WindSpeed, d.WindDirection, d.Turbulence, d.Position, d.Altitude, d.Performance} {
+		if sigma < 0 {
+			return fmt.Errorf("%w: negative dispersion", models.ErrInvalidStudy)
+		}
+	}
+	if d.Performance > maxPerformance {
+		return fmt.Errorf("%w: performance dispersion above %.1f", models.ErrInvalidStudy, maxPerformance)
+	}
+	if (d.WindSpeed > 0 || d.WindDirection > 0 || d.Turbulence > 0) && !envCfg.Enabled {
+		return fmt.Errorf("%w: wind and turbulence dispersion need the environment enabled: %w",
+			models.ErrInvalidStudy, models.ErrEnvironmentDisabled)
+	}
+
+	if st.Scenario == nil {
+		return fmt.Errorf("%w: no scenario", models.ErrInvalidStudy)
+	}
+	if err := st.Scenario.Validate(simCfg); err != nil {
+		return fmt.Errorf("%w: %w", models.ErrInvalidStudy, err)
+	}
+	if st.Scenario.UsesWind() && !envCfg.Enabled {
+		return fmt.Errorf("%w: wind events need the environment enabled: %w",
+			models.ErrInvalidStudy, models.ErrEnvironmentDisabled)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package mqttapi
+
+import (
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+
+	mqtt "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/listeners"
+	"github.com/mochi-mqtt/server/v2/packets"
+)
+
+// accessHook lets any client connect and subscribe, but reserves the
+// simulator's topics: under prefix, clients may only publish commands.
+type accessHook struct {
+	mqtt.HookBase
+	prefix string // "sim/<aircraft_id>/"
+}
+
+// ID returns the ID of the hook.
+func (h *accessHook) ID() string {
+	return "simulator-access"
+}
+
+// Provides reports the hook methods the hook implements.
+func (h *accessHook) Provides(b byte) bool {
+	return b == mqtt.OnConnectAuthenticate || b == mqtt.OnACLCheck
+}
+
+// OnConnectAuthenticate lets every client connect; like the REST API, the
+// broker has no authentication.
+func (h *accessHook) OnConnectAuthenticate(*mqtt.Client, packets.Packet) bool {
+	return true
+}
+
+// OnACLCheck allows subscribing to any topic, and publishing to the
+// simulator's topics only on its command topics, so clients cannot publish
+// states, events or replies.
+func (h *accessHook) OnACLCheck(_ *mqtt.Client, topic string, write bool) bool {
+	rest, ok := strings.CutPrefix(topic, h.prefix)
+	if !write || !ok {
+		return true
+	}
+	commandType, ok := strings.CutPrefix(rest, "cmd/")
+	return ok && commandType != "" && !strings.Contains(commandType, "/")
+}
+
+// listener serves the broker's clients on a net.Listener, so the server can
+// be served on any listener like the gRPC server.
+type listener struct {
+	id     string
+	lis    net.Listener
+	logger *slog.Logger
+	closed sync.Once
+}
+
+// Init keeps the broker's logger; the listener is already listening.
+func (l *listener) Init(logger *slog.Logger) error {
+	l.logger = logger
+	return nil
+}
+
+// Serve accepts connections until the listener is closed and establishes
+// each as a client.
+func (l *listener) Serve(establish listeners.EstablishFn) {
+	for {
+		conn, err := l.lis.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			if err := establish(l.id, conn); err != nil {
+				l.logger.Warn("MQTT client connection failed", "error", err)
+			}
+		}()
+	}
+}
+
+// ID returns the ID of the listener.
+func (l *listener) ID() string {
+	return l.id
+}
+
+// Address returns the address of the listener.
+func (l *listener) Address() string {
+	return l.lis.Addr().String()
+}
+
+// Protocol returns the protocol of the listener.
+func (l *listener) Protocol() string {
+	return "tcp"
+}
+
+// Close closes the listener and its clients.
+func (l *listener) Close(closeClients listeners.CloseFn) {
+	l.closed.Do(func() {
+		closeClients(l.id)
+		l.lis.Close()
+	})
+}
@@ -0,0 +1,308 @@
+// Package mqttapi serves the simulator over an embedded MQTT broker: states
+// and events are published to MQTT topics, and commands are accepted from
+// them.
+package mqttapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/api/handlers"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/config"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/navdata"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/pubsub"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/simulator"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/webhook"
+	mqtt "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/packets"
+)
+
+const (
+	// defaultStateRateHz is the state publish rate when streaming sets none.
+	defaultStateRateHz = 10
+
+	// commandTimeout bounds the submission of a command message.
+	commandTimeout = 5 * time.Second
+
+	// Outbound QoS limits: a missed state is replaced by the next one, a
+	// missed event is not.
+	stateQoS = 0
+	eventQoS = 1
+)
+
+// Reply is the answer to a command message, published on the command's
+// topic followed by "/reply".
+type Reply struct {
+	Type     string                  `json:"type"`         // ack or error
+	ID       string                  `json:"id,omitempty"` // echoed from the command message
+	Response *models.CommandResponse `json:"response,omitempty"`
+	Status   int                     `json:"status,omitempty"` // error: the command endpoint's HTTP status
+	Error    *models.ErrorDetail     `json:"error,omitempty"`
+}
+
+// Server represents the embedded MQTT broker and the simulator's bridge to
+// it.
+type Server struct {
+	broker    *mqtt.Server
+	addr      string
+	rateHz    float64
+	commands  *handlers.CommandHandler
+	simulator *simulator.Simulator
+	logger    *slog.Logger
+}
+
+// NewServer creates a new MQTT server. States are published at the
+// streaming update rate. Commands are submitted through a command handler
+// so they get the same checks, and callback URLs, as the REST command
+// endpoints.
+func NewServer(cfg config.MQTTConfig, simCfg config.SimulationConfig, streamCfg config.StreamingConfig, sim *simulator.Simulator, nav *navdata.Database, webhooks *webhook.Manager, logger *slog.Logger) *Server {
+	broker := mqtt.New(&mqtt.Options{
+		InlineClient: true,
+		Logger:       logger,
+	})
+
+	rateHz := float64(streamCfg.UpdateRateHz)
+	if rateHz <= 0 {
+		rateHz = defaultStateRateHz
+	}
+
+	return &Server{
+		broker:    broker,
+		addr:      fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		rateHz:    rateHz,
+		commands:  handlers.NewCommandHandler(sim, nav, webhooks, logger, simCfg.MaxSpeed),
+		simulator: sim,
+		logger:    logger,
+	}
+}
+
+// StateTopic is the MQTT topic of an aircraft's states.
+func StateTopic(aircraftID string) string {
+	return "sim/" + aircraftID + "/state"
+}
+
+// EventsTopic is the MQTT topic of an aircraft's events.
+func EventsTopic(aircraftID string) string {
+	return "sim/" + aircraftID + "/events"
+}
+
+// CommandTopic is the MQTT topic of an aircraft's commands of a type.
+func CommandTopic(aircraftID, commandType string) string {
+	return "sim/" + aircraftID + "/cmd/" + commandType
+}
+
+// ReplyTopic is the MQTT topic of the replies to a command topic.
+func ReplyTopic(commandTopic string) string {
+	return commandTopic + "/reply"
+}
+
+// Start listens on the configured address and serves until ctx is
+// cancelled.
+func (s *Server) Start(ctx context.Context) error {
+	lis, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	return s.Serve(ctx, lis)
+}
+
+// Serve serves MQTT clients on lis and bridges the simulator to the broker
+// until ctx is cancelled.
+func (s *Server) Serve(ctx context.Context, lis net.Listener) error {
+	s.logger.Info("Starting MQTT broker", "addr", lis.Addr().String())
+
+	aircraftID := s.simulator.AircraftID()
+	if err := s.broker.AddHook(&accessHook{prefix: "sim/" + aircraftID + "/"}, nil); err != nil {
+		lis.Close()
+		return err
+	}
+	if err := s.broker.AddListener(&listener{id: "tcp", lis: lis}); err != nil {
+		lis.Close()
+		return err
+	}
+	if err := s.broker.Subscribe(CommandTopic(aircraftID, "+"), 1, s.commandHandler(ctx)); err != nil {
+		return err
+	}
+
+	// Events are subscribed to before serving, so none is missed
+	subID := uuid.New().String()
+	events := s.simulator.GetEventBroker()
+	eventChan, err := events.Subscribe(subID, pubsub.SubscribeOptions{
+		Topics: []string{simulator.EventsPattern(aircraftID)},
+		Kind:   "mqtt",
+	})
+	if err != nil {
+		return err
+	}
+	defer events.Unsubscribe(subID)
+
+	if err := s.broker.Serve(); err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.publishStates(ctx)
+	}()
+	s.publishEvents(ctx, eventChan)
+	<-done
+
+	s.logger.Info("Shutting down MQTT broker")
+	return s.broker.Close()
+}
+
+// publishStates publishes the latest state at the server's rate, when it
+// changed, until ctx is cancelled. States are retained, so new subscribers
+// get the latest at once.
+func (s *Server) publishStates(ctx context.Context) {
+	topic := StateTopic(s.simulator.AircraftID())
+	publisher := s.simulator.GetPublisher()
+	stateTopic := simulator.StateTopic(s.simulator.AircraftID())
+
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / s.rateHz))
+	defer ticker.Stop()
+
+	var lastSeq uint64
+	for {
+		select {
+		case <-ticker.C:
+			msg := publisher.Latest(stateTopic)
+			if msg == nil || msg.Seq == lastSeq {
+				continue
+			}
+			data, err := msg.Encoded("mqtt", encodeJSON[models.AircraftState])
+			if err != nil {
+				s.logger.Error("Failed to encode state", "error", err)
+				continue
+			}
+			if err := s.broker.Publish(topic, data, true, stateQoS); err != nil {
+				s.logger.Error("Failed to publish state", "error", err)
+			}
+			lastSeq = msg.Seq
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// publishEvents publishes the simulation events until ctx is cancelled.
+func (s *Server) publishEvents(ctx context.Context, eventChan <-chan *pubsub.Message[models.Event]) {
+	topic := EventsTopic(s.simulator.AircraftID())
+	for {
+		select {
+		case msg, ok := <-eventChan:
+			if !ok {
+				s.logger.Warn("MQTT event subscription evicted")
+				<-ctx.Done()
+				return
+			}
+			data, err := msg.Encoded("mqtt", encodeJSON[models.Event])
+			if err != nil {
+				s.logger.Error("Failed to encode event", "error", err)
+				continue
+			}
+			if err := s.broker.Publish(topic, data, false, eventQoS); err != nil {
+				s.logger.Error("Failed to publish event", "error", err)
+			}
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// encodeJSON encodes a payload as JSON.
+func encodeJSON[T any](v *T) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// commandHandler returns the handler of the command topics, which submits
+// each command message and publishes the reply. Commands are handled in
+// the order their client sent them.
+func (s *Server) commandHandler(ctx context.Context) mqtt.InlineSubFn {
+	return func(_ *mqtt.Client, _ packets.Subscription, pk packets.Packet) {
+		commandType := pk.TopicName[strings.LastIndex(pk.TopicName, "/")+1:]
+		reply := s.handleCommand(ctx, commandType, pk.Payload)
+
+		data, err := json.Marshal(reply)
+		if err != nil {
+			s.logger.Error("Failed to encode command reply", "error", err)
+			return
+		}
+		if err := s.broker.Publish(ReplyTopic(pk.TopicName), data, false, eventQoS); err != nil {
+			s.logger.Error("Failed to publish command reply", "error", err)
+		}
+	}
+}
+
+// handleCommand validates and submits a command message and returns the
+// reply.
+func (s *Server) handleCommand(ctx context.Context, commandType string, payload []byte) Reply {
+	var envelope struct {
+		ID string `json:"id"`
+	}
+	req, err := commandRequest(commandType, payload, &envelope)
+	if err != nil {
+		s.logger.Warn("Invalid MQTT command message", "type", commandType, "error", err)
+		detail := models.ErrorDetail{Code: "MALFORMED_JSON", Message: err.Error()}
+		return Reply{Type: "error", Status: http.StatusBadRequest, Error: &detail}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	response, cmdErr := s.commands.Submit(ctx, req)
+	if cmdErr != nil {
+		detail := cmdErr.Response.Error
+		return Reply{Type: "error", ID: envelope.ID, Status: cmdErr.Status, Error: &detail}
+	}
+	return Reply{Type: "ack", ID: envelope.ID, Response: &response}
+}
+
+// commandRequest builds the request of a command message, whose payload is
+// the request body of the command's endpoint, optionally with an "id" that
+// is read into envelope. An empty payload is a command without parameters.
+func commandRequest(commandType string, payload []byte, envelope any) (handlers.CommandRequest, error) {
+	req := handlers.CommandRequest{Type: commandType}
+	if len(bytes.TrimSpace(payload)) == 0 {
+		return req, nil
+	}
+	if err := json.Unmarshal(payload, envelope); err != nil {
+		return req, err
+	}
+
+	var params any
+	switch models.CommandType(commandType) {
+	case models.CommandTypeGoTo:
+		req.GoTo = new(handlers.GoToRequest)
+		params = req.GoTo
+	case models.CommandTypeTrajectory:
+		req.Trajectory = new(handlers.TrajectoryRequest)
+		params = req.Trajectory
+	case models.CommandTypeRTH:
+		req.RTH = new(handlers.RTHRequest)
+		params = req.RTH
+	case models.CommandTypeTakeoff:
+		req.Takeoff = new(handlers.TakeoffRequest)
+		params = req.Takeoff
+	case models.CommandTypeLand:
+		req.Land = new(handlers.LandRequest)
+		params = req.Land
+	default:
+		// Commands without parameters, and unknown types, which Submit
+		// rejects
+		return req, nil
+	}
+	return req, json.Unmarshal(payload, params)
+}
@@ -0,0 +1,201 @@
+package mqttapi
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/config"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/navdata"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/simulator"
+	mqtt "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/packets"
+)
+
+// newTestServer starts a simulator and an MQTT server on a local listener.
+// Tests use the broker's inline client, so they need no MQTT client.
+func newTestServer(t *testing.T) (*Server, string) {
+	t.Helper()
+
+	simCfg := config.SimulationConfig{
+		TickRateHz:       10.0,
+		CommandQueueSize: 10,
+		InitialPosition: config.PositionConfig{
+			Latitude:  32.0,
+			Longitude: 34.0,
+			Altitude:  1000.0,
+		},
+		DefaultSpeed:      100.0,
+		MaxSpeed:          250.0,
+		MaxClimbRate:      15.0,
+		MaxDescentRate:    10.0,
+		PositionTolerance: 10.0,
+		HeadingChangeRate: 30.0,
+		SpeedChangeRate:   50.0,
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelWarn}))
+
+	sim, err := simulator.New(simCfg, config.EnvironmentConfig{}, logger)
+	if err != nil {
+		t.Fatalf("Failed to create simulator: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go sim.Run(ctx)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	server := NewServer(config.MQTTConfig{}, simCfg, config.StreamingConfig{UpdateRateHz: 10}, sim, navdata.NewDatabase(), nil, logger)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		server.Serve(ctx, lis)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	return server, sim.AircraftID()
+}
+
+// subscribe subscribes to a topic with the broker's inline client and
+// returns a channel of the payloads.
+func subscribe(t *testing.T, server *Server, topic string, id int) <-chan []byte {
+	t.Helper()
+
+	payloads := make(chan []byte, 100)
+	err := server.broker.Subscribe(topic, id, func(_ *mqtt.Client, _ packets.Subscription, pk packets.Packet) {
+		select {
+		case payloads <- pk.Payload:
+		default:
+		}
+	})
+	if err != nil {
+		t.Fatalf("Subscribe(%s) error = %v", topic, err)
+	}
+	return payloads
+}
+
+// receive returns the next payload of a subscription.
+func receive(t *testing.T, payloads <-chan []byte) []byte {
+	t.Helper()
+
+	select {
+	case payload := <-payloads:
+		return payload
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message within 5s")
+		return nil
+	}
+}
+
+func TestStatesAndEvents(t *testing.T) {
+	server, aircraftID := newTestServer(t)
+	states := subscribe(t, server, StateTopic(aircraftID), 1)
+	events := subscribe(t, server, EventsTopic(aircraftID), 2)
+	replies := subscribe(t, server, ReplyTopic(CommandTopic(aircraftID, "+")), 3)
+
+	var state models.AircraftState
+	if err := json.Unmarshal(receive(t, states), &state); err != nil {
+		t.Fatalf("state is not JSON: %v", err)
+	}
+	if state.Position.Latitude != 32.0 {
+		t.Errorf("state latitude = %v, want 32.0", state.Position.Latitude)
+	}
+
+	// The state is being published, so the server is serving commands
+	command := `{"id": "cmd-1", "lat": 32.1, "lon": 34.1, "alt": 1500}`
+	if err := server.broker.Publish(CommandTopic(aircraftID, "goto"), []byte(command), false, 1); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	var reply Reply
+	if err := json.Unmarshal(receive(t, replies), &reply); err != nil {
+		t.Fatalf("reply is not JSON: %v", err)
+	}
+	if reply.Type != "ack" || reply.ID != "cmd-1" || reply.Response == nil || reply.Response.Status != "accepted" {
+		t.Fatalf("reply = %+v, want an ack of cmd-1", reply)
+	}
+
+	for {
+		var event models.Event
+		if err := json.Unmarshal(receive(t, events), &event); err != nil {
+			t.Fatalf("event is not JSON: %v", err)
+		}
+		if event.Type == models.EventCommandAccepted {
+			if event.Command == nil || event.Command.ID != reply.Response.CommandID {
+				t.Errorf("event command = %+v, want %s", event.Command, reply.Response.CommandID)
+			}
+			break
+		}
+	}
+}
+
+func TestCommandErrors(t *testing.T) {
+	server, aircraftID := newTestServer(t)
+	receive(t, subscribe(t, server, StateTopic(aircraftID), 1))
+
+	tests := []struct {
+		name        string
+		commandType string
+		payload     string
+		wantStatus  int
+		wantCode    string
+	}{
+		{name: "malformed JSON", commandType: "goto", payload: `{"lat":`, wantStatus: http.StatusBadRequest, wantCode: "MALFORMED_JSON"},
+		{name: "missing parameters", commandType: "trajectory", payload: "", wantStatus: http.StatusBadRequest, wantCode: "INVALID_REQUEST"},
+		{name: "invalid latitude", commandType: "goto", payload: `{"lat": 95, "lon": 34.1, "alt": 1500}`, wantStatus: http.StatusBadRequest, wantCode: "INVALID_LATITUDE"},
+		{name: "unknown type", commandType: "loop", payload: "", wantStatus: http.StatusBadRequest, wantCode: "INVALID_REQUEST"},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			topic := CommandTopic(aircraftID, tt.commandType)
+			replies := subscribe(t, server, ReplyTopic(topic), 10+i)
+			if err := server.broker.Publish(topic, []byte(tt.payload), false, 1); err != nil {
+				t.Fatalf("Publish() error = %v", err)
+			}
+
+			var reply Reply
+			if err := json.Unmarshal(receive(t, replies), &reply); err != nil {
+				t.Fatalf("reply is not JSON: %v", err)
+			}
+			if reply.Type != "error" || reply.Status != tt.wantStatus || reply.Error == nil || reply.Error.Code != tt.wantCode {
+				t.Errorf("reply = %+v, want %d %s", reply, tt.wantStatus, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestAccessHook(t *testing.T) {
+	hook := &accessHook{prefix: "sim/aircraft-1/"}
+	tests := []struct {
+		topic string
+		write bool
+		want  bool
+	}{
+		{topic: "sim/aircraft-1/state", write: false, want: true},
+		{topic: "sim/aircraft-1/state", write: true, want: false},
+		{topic: "sim/aircraft-1/events", write: true, want: false},
+		{topic: "sim/aircraft-1/cmd/goto", write: true, want: true},
+		{topic: "sim/aircraft-1/cmd/goto/reply", write: true, want: false},
+		{topic: "sim/aircraft-1/cmd/", write: true, want: false},
+		{topic: "sim/aircraft-2/state", write: true, want: true},
+		{topic: "ground/telemetry", write: true, want: true},
+	}
+
+	for _, tt := range tests {
+		if got := hook.OnACLCheck(nil, tt.topic, tt.write); got != tt.want {
+			t.Errorf("OnACLCheck(%s, write=%v) = %v, want %v", tt.topic, tt.write, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package navdata
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// feetToMeters converts elevations given in feet.
+const feetToMeters = 0.3048
+
+// columnAliases maps fix fields to the CSV header names they may appear
+// under. OurAirports airports.csv and navaids.csv are supported as well as
+// simple ident,name,type,lat,lon files.
+var columnAliases = map[string][]string{
+	"ident":        {"ident", "id", "identifier"},
+	"name":         {"name"},
+	"type":         {"type"},
+	"lat":          {"latitude_deg", "latitude", "lat"},
+	"lon":          {"longitude_deg", "longitude", "lon"},
+	"elevation_ft": {"elevation_ft"},
+	"elevation_m":  {"elevation_m", "elevation"},
+	"country":      {"iso_country", "country"},
+	"iata":         {"iata_code", "iata"},
+}
+
+// ParseCSV parses fixes from a CSV file with a header row. Rows with invalid
+// coordinates and closed airports are skipped.
+func ParseCSV(r io.Reader) ([]Fix, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	columns := mapColumns(header)
+	for _, required := range []string{"ident", "lat", "lon"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing %s column", required)
+		}
+	}
+
+	var fixes []Fix
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		lat, latErr := strconv.ParseFloat(field("lat"), 64)
+		lon, lonErr := strconv.ParseFloat(field("lon"), 64)
+		if latErr != nil || lonErr != nil || lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+			continue
+		}
+
+		fix := Fix{
+			Ident:     field("ident"),
+			Name:      field("name"),
+			Type:      field("type"),
+			Latitude:  lat,
+			Longitude: lon,
+			Country:   field("country"),
+			IATA:      field("iata"),
+		}
+		if fix.Type == "closed" {
+			continue
+		}
+		if ft, err := strconv.ParseFloat(field("elevation_ft"), 64); err == nil {
+			fix.Elevation = ft * feetToMeters
+		} else if m, err := strconv.ParseFloat(field("elevation_m"), 64); err == nil {
+			fix.Elevation = m
+		}
+
+		fixes = append(fixes, fix)
+	}
+
+	return fixes, nil
+}
+
+// mapColumns returns the column index of each known field.
+// OurAirports files have a numeric "id" column before "ident"; the more
+// specific alias wins.
+func mapColumns(header []string) map[string]int {
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	columns := make(map[string]int)
+	for field, aliases := range columnAliases {
+		for _, alias := range aliases {
+			if i, ok := index[alias]; ok {
+				columns[field] = i
+				break
+			}
+		}
+	}
+	return columns
+}
@@ -0,0 +1,208 @@
+// Package navdata provides a database of airports and named fixes that
+// commands can reference by identifier instead of raw coordinates.
+package navdata
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+)
+
+// Fix types. Airport types follow OurAirports (large_airport, heliport, ...);
+// navaid types are imported as given (VOR, NDB, ...).
+const (
+	TypeFix = "fix"
+)
+
+// Fix is a named position: an airport, navaid or waypoint.
+type Fix struct {
+	Ident     string  `json:"ident"`
+	Name      string  `json:"name,omitempty"`
+	Type      string  `json:"type"`
+	Latitude  float64 `json:"lat"`
+	Longitude float64 `json:"lon"`
+	Elevation float64 `json:"elevation_m"` // meters MSL
+	Country   string  `json:"country,omitempty"`
+	IATA      string  `json:"iata,omitempty"` // airports only
+}
+
+// IsAirport reports whether the fix is an airport, heliport or similar landing site.
+func (f Fix) IsAirport() bool {
+	switch f.Type {
+	case "heliport", "seaplane_base", "balloonport":
+		return true
+	}
+	return strings.HasSuffix(f.Type, "airport")
+}
+
+// Position returns the fix position at the given altitude.
+func (f Fix) Position(altitude float64) models.Position {
+	return models.Position{Latitude: f.Latitude, Longitude: f.Longitude, Altitude: altitude}
+}
+
+// Database is an in-memory index of fixes. Identifiers are not globally
+// unique, so each identifier may map to several fixes.
+// It is safe for concurrent use.
+type Database struct {
+	mu      sync.RWMutex
+	byIdent map[string][]Fix
+	count   int
+}
+
+// NewDatabase creates an empty database.
+func NewDatabase() *Database {
+	return &Database{
+		byIdent: make(map[string][]Fix),
+	}
+}
+
+// Load creates a database from CSV and JSON files.
+func Load(paths ...string) (*Database, error) {
+	db := NewDatabase()
+	for _, path := range paths {
+		if err := db.LoadFile(path); err != nil {
+			return nil, err
+		}
+	}
+	return db, nil
+}
+
+// LoadFile adds the fixes in a CSV or JSON file, chosen by file extension.
+func (d *Database) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open navdata file: %w", err)
+	}
+	defer f.Close()
+
+	var fixes []Fix
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		if err := json.NewDecoder(f).Decode(&fixes); err != nil {
+			return fmt.Errorf("failed to parse navdata file %s: %w", path, err)
+		}
+	} else {
+		fixes, err = ParseCSV(f)
+		if err != nil {
+			return fmt.Errorf("failed to parse navdata file %s: %w", path, err)
+		}
+	}
+
+	for _, fix := range fixes {
+		d.Add(fix)
+	}
+	return nil
+}
+
+// Add adds a fix to the database. Fixes without an identifier are ignored.
+func (d *Database) Add(fix Fix) {
+	fix.Ident = strings.ToUpper(strings.TrimSpace(fix.Ident))
+	if fix.Ident == "" {
+		return
+	}
+	if fix.Type == "" {
+		fix.Type = TypeFix
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.byIdent[fix.Ident] = append(d.byIdent[fix.Ident], fix)
+	if fix.IATA != "" && fix.IATA != fix.Ident {
+		iata := strings.ToUpper(fix.IATA)
+		d.byIdent[iata] = append(d.byIdent[iata], fix)
+	}
+	d.count++
+}
+
+// Count returns the number of fixes in the database.
+func (d *Database) Count() int {
+	if d == nil {
+		return 0
+	}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.count
+}
+
+// Lookup returns the fix with an identifier (case-insensitive, airports also
+// match their IATA code). When several fixes share the identifier an airport
+// is preferred, otherwise the first one loaded is returned.
+func (d *Database) Lookup(ident string) (Fix, error) {
+	if d == nil {
+		return Fix{}, fmt.Errorf("%w: %s", models.ErrUnknownFix, ident)
+	}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	fixes := d.byIdent[strings.ToUpper(strings.TrimSpace(ident))]
+	if len(fixes) == 0 {
+		return Fix{}, fmt.Errorf("%w: %s", models.ErrUnknownFix, ident)
+	}
+	for _, fix := range fixes {
+		if fix.IsAirport() {
+			return fix, nil
+		}
+	}
+	return fixes[0], nil
+}
+
+// Search returns up to limit fixes matching a query, best matches first:
+// exact identifiers, then identifier prefixes, then names containing the query.
+func (d *Database) Search(query string, limit int) []Fix {
+	results := make([]Fix, 0)
+	if d == nil || limit <= 0 {
+		return results
+	}
+	query = strings.ToUpper(strings.TrimSpace(query))
+	if query == "" {
+		return results
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	type match struct {
+		fix   Fix
+		score int
+	}
+	var matches []match
+	for ident, fixes := range d.byIdent {
+		for _, fix := range fixes {
+			if ident != fix.Ident {
+				continue // IATA alias; matched through the primary identifier
+			}
+			switch {
+			case ident == query || strings.EqualFold(fix.IATA, query):
+				matches = append(matches, match{fix, 0})
+			case strings.HasPrefix(ident, query):
+				matches = append(matches, match{fix, 1})
+			case strings.Contains(strings.ToUpper(fix.Name), query):
+				matches = append(matches, match{fix, 2})
+			}
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score < matches[j].score
+		}
+		if matches[i].fix.Ident != matches[j].fix.Ident {
+			return matches[i].fix.Ident < matches[j].fix.Ident
+		}
+		return matches[i].fix.Type < matches[j].fix.Type
+	})
+
+	for _, m := range matches {
+		if len(results) == limit {
+			break
+		}
+		results = append(results, m.fix)
+	}
+	return results
+}
@@ -0,0 +1,144 @@
+package navdata
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+)
+
+const ourAirportsCSV = `"id","ident","type","name","latitude_deg","longitude_deg","elevation_ft","continent","iso_country","iso_region","municipality","scheduled_service","gps_code","iata_code","local_code"
+4185,"LLBG","large_airport","Ben Gurion International Airport",32.0114,34.8867,135,"AS","IL","IL-M","Tel Aviv","yes","LLBG","TLV",
+4186,"LLHA","medium_airport","Haifa International Airport",32.8094,35.0431,28,"AS","IL","IL-HA","Haifa","yes","LLHA","HFA",
+4187,"LLXX","closed","Closed Field",32.5,35.0,100,"AS","IL","IL-HA","","no","","",
+4188,"LLBAD","small_airport","Bad Coordinates",,,0,"AS","IL","IL-HA","","no","","",
+`
+
+const fixesCSV = `ident,name,type,lat,lon
+DAFNA,,fix,32.1,34.0
+LLBG,Ben Gurion VOR,VOR,32.0,34.9
+`
+
+func TestParseCSV(t *testing.T) {
+	fixes, err := ParseCSV(strings.NewReader(ourAirportsCSV))
+	if err != nil {
+		t.Fatalf("ParseCSV() error = %v", err)
+	}
+
+	if len(fixes) != 2 {
+		t.Fatalf("ParseCSV() returned %d fixes, want 2 (closed and invalid rows skipped)", len(fixes))
+	}
+
+	llbg := fixes[0]
+	if llbg.Ident != "LLBG" || llbg.IATA != "TLV" || llbg.Country != "IL" || !llbg.IsAirport() {
+		t.Errorf("ParseCSV() first fix = %+v", llbg)
+	}
+	if math.Abs(llbg.Elevation-135*feetToMeters) > 1e-9 {
+		t.Errorf("Elevation = %f, want %f", llbg.Elevation, 135*feetToMeters)
+	}
+
+	if _, err := ParseCSV(strings.NewReader("name,type\nfoo,fix\n")); err == nil {
+		t.Error("ParseCSV() without coordinate columns should fail")
+	}
+}
+
+func TestDatabase_Lookup(t *testing.T) {
+	dir := t.TempDir()
+	airports := filepath.Join(dir, "airports.csv")
+	fixes := filepath.Join(dir, "fixes.csv")
+	waypoints := filepath.Join(dir, "waypoints.json")
+	os.WriteFile(airports, []byte(ourAirportsCSV), 0o644)
+	os.WriteFile(fixes, []byte(fixesCSV), 0o644)
+	os.WriteFile(waypoints, []byte(`[{"ident": "gilad", "lat": 32.3, "lon": 34.5}]`), 0o644)
+
+	// Fixes load first so the airport preference is exercised
+	db, err := Load(fixes, airports, waypoints)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if db.Count() != 5 {
+		t.Errorf("Count() = %d, want 5", db.Count())
+	}
+
+	tests := []struct {
+		ident     string
+		wantType  string
+		wantLat   float64
+		wantError error
+	}{
+		{ident: "LLBG", wantType: "large_airport", wantLat: 32.0114}, // airport preferred over VOR
+		{ident: "tlv", wantType: "large_airport", wantLat: 32.0114},  // IATA alias
+		{ident: "DAFNA", wantType: TypeFix, wantLat: 32.1},
+		{ident: "GILAD", wantType: TypeFix, wantLat: 32.3},
+		{ident: "NOWHERE", wantError: models.ErrUnknownFix},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ident, func(t *testing.T) {
+			fix, err := db.Lookup(tt.ident)
+			if tt.wantError != nil {
+				if !errors.Is(err, tt.wantError) {
+					t.Errorf("Lookup() error = %v, want %v", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Lookup() error = %v", err)
+			}
+			if fix.Type != tt.wantType || fix.Latitude != tt.wantLat {
+				t.Errorf("Lookup() = %+v, want type %s lat %f", fix, tt.wantType, tt.wantLat)
+			}
+		})
+	}
+}
+
+func TestDatabase_Search(t *testing.T) {
+	db := NewDatabase()
+	for _, fix := range []Fix{
+		{Ident: "LLBG", Name: "Ben Gurion International Airport", Type: "large_airport", IATA: "TLV"},
+		{Ident: "LLBS", Name: "Beersheba Airport", Type: "small_airport"},
+		{Ident: "LL", Name: "Test", Type: TypeFix},
+		{Ident: "HAIFA", Name: "Haifa VOR", Type: "VOR"},
+	} {
+		db.Add(fix)
+	}
+
+	tests := []struct {
+		query string
+		limit int
+		want  []string
+	}{
+		{query: "LL", limit: 10, want: []string{"LL", "LLBG", "LLBS"}},
+		{query: "ll", limit: 2, want: []string{"LL", "LLBG"}},
+		{query: "tlv", limit: 10, want: []string{"LLBG"}},
+		{query: "airport", limit: 10, want: []string{"LLBG", "LLBS"}},
+		{query: "nothing", limit: 10, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			results := db.Search(tt.query, tt.limit)
+			got := make([]string, len(results))
+			for i, fix := range results {
+				got[i] = fix.Ident
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDatabase_Nil(t *testing.T) {
+	var db *Database
+	if _, err := db.Lookup("LLBG"); !errors.Is(err, models.ErrUnknownFix) {
+		t.Errorf("nil Lookup() error = %v, want %v", err, models.ErrUnknownFix)
+	}
+	if results := db.Search("LL", 10); len(results) != 0 {
+		t.Errorf("nil Search() = %v, want empty", results)
+	}
+}
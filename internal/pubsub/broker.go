@@ -0,0 +1,394 @@
+package pubsub
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+)
+
+// SlowPolicy decides what a channel subscription does with a message
+// published while its buffer is full.
+type SlowPolicy string
+
+const (
+	PolicyDropNewest SlowPolicy = "drop_newest" // the new message is dropped (default)
+	PolicyDropOldest SlowPolicy = "drop_oldest" // the oldest buffered message is dropped
+	PolicyCoalesce   SlowPolicy = "coalesce"    // the buffered messages are dropped, only the latest is kept
+	PolicyDisconnect SlowPolicy = "disconnect"  // the new message is dropped, and the subscriber is evicted after MaxDrops drops
+)
+
+// ParseSlowPolicy returns the policy named s; an empty name is the default
+// policy.
+func ParseSlowPolicy(s string) (SlowPolicy, error) {
+	switch policy := SlowPolicy(s); policy {
+	case "":
+		return PolicyDropNewest, nil
+	case PolicyDropNewest, PolicyDropOldest, PolicyCoalesce, PolicyDisconnect:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown slow subscriber policy %q, want drop_newest, drop_oldest, coalesce or disconnect", s)
+	}
+}
+
+// SubscribeOptions configure a channel subscription.
+type SubscribeOptions struct {
+	Topics     []string   // topic patterns, none = all topics
+	Kind       string     // what the subscriber is, e.g. "grpc", for the stats
+	BufferSize int        // messages buffered, 0 = the broker's buffer size
+	Policy     SlowPolicy // "" = PolicyDropNewest
+	MaxDrops   int        // PolicyDisconnect: drops tolerated before the subscriber is evicted
+}
+
+// Sequence numbers the messages of the brokers sharing it, so readers of
+// several brokers can put their messages in order, and notifies readers of
+// new messages.
+type Sequence struct {
+	mu      sync.Mutex
+	last    uint64
+	changed chan struct{} // closed and replaced on every message
+}
+
+// NewSequence creates a new sequence, numbering from 1.
+func NewSequence() *Sequence {
+	return &Sequence{changed: make(chan struct{})}
+}
+
+// next returns the next sequence number and notifies the readers waiting
+// for a change.
+func (s *Sequence) next() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.last++
+	close(s.changed)
+	s.changed = make(chan struct{})
+	return s.last
+}
+
+// Last returns the number of the last message. A broker numbers a message
+// while holding its lock, so all messages up to Last are returned by the
+// next Since of their broker.
+func (s *Sequence) Last() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.last
+}
+
+// Changed returns a channel that is closed when the next message is
+// published. Get the channel before reading, so no change is missed.
+func (s *Sequence) Changed() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.changed
+}
+
+// Message is a published payload. Messages are shared by all readers and
+// must not be modified.
+type Message[T any] struct {
+	Seq     uint64
+	Topic   string
+	Payload T
+
+	mu      sync.Mutex
+	encoded map[string][]byte
+}
+
+// Encoded returns the payload encoded by encode. The encoding is computed
+// once per key and shared, so readers using the same key and encoding get
+// the same byte slice, which must not be modified.
+func (m *Message[T]) Encoded(key string, encode func(*T) ([]byte, error)) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if data, ok := m.encoded[key]; ok {
+		return data, nil
+	}
+	data, err := encode(&m.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if m.encoded == nil {
+		m.encoded = make(map[string][]byte)
+	}
+	m.encoded[key] = data
+	return data, nil
+}
+
+// Broker distributes messages of one payload type by topic. The latest
+// message of each topic is kept: streaming clients wait for the broadcast
+// notification of Changed and read Latest or Since, so publishing costs the
+// same for any number of them, and each message is encoded once per format
+// (see Message.Encoded). Subscribers that need every message of their
+// topics get their own buffered channel, with a policy for when they fall
+// behind (see SlowPolicy).
+//
+// The broker also keeps the most recent messages, numbered by its sequence,
+// so stream clients can resume after a reconnect.
+type Broker[T any] struct {
+	mu          sync.RWMutex
+	seq         *Sequence
+	subscribers map[string]*subscription[T]
+	subscribed  uint64 // subscriptions made, orders them
+	bufferSize  int
+	logger      *slog.Logger
+	latest      map[string]*Message[T] // by topic
+	history     []*Message[T]          // oldest first
+	historySize int
+	trimmed     uint64 // sequence number of the last message dropped from the history
+}
+
+// subscription is a channel subscriber and its delivery statistics.
+type subscription[T any] struct {
+	order        uint64
+	ch           chan *Message[T]
+	opts         SubscribeOptions
+	subscribedAt time.Time
+	delivered    uint64
+	dropped      uint64
+	lastDropAt   time.Time
+
+	// sentAt holds the publish times of the last messages sent, as a ring;
+	// the queued messages are the newest of them
+	sentAt []time.Time
+	next   int
+}
+
+// NewBroker creates a new broker numbering its messages with seq and
+// keeping the last historySize messages. Channel subscribers buffer
+// bufferSize messages unless they ask otherwise.
+func NewBroker[T any](seq *Sequence, bufferSize, historySize int, logger *slog.Logger) *Broker[T] {
+	return &Broker[T]{
+		seq:         seq,
+		subscribers: make(map[string]*subscription[T]),
+		bufferSize:  bufferSize,
+		logger:      logger,
+		latest:      make(map[string]*Message[T]),
+		history:     make([]*Message[T], 0, historySize),
+		historySize: historySize,
+	}
+}
+
+// Subscribe creates a new subscription to the topics matching
+// opts.Topics and returns a channel for their messages. The channel is
+// closed when the subscriber is evicted.
+func (b *Broker[T]) Subscribe(id string, opts SubscribeOptions) (<-chan *Message[T], error) {
+	for _, pattern := range opts.Topics {
+		if err := ValidatePattern(pattern); err != nil {
+			return nil, err
+		}
+	}
+	if len(opts.Topics) == 0 {
+		opts.Topics = []string{wildcardRest}
+	}
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = max(b.bufferSize, 1)
+	}
+	if opts.Policy == "" {
+		opts.Policy = PolicyDropNewest
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.subscribed++
+	sub := &subscription[T]{
+		order:        b.subscribed,
+		ch:           make(chan *Message[T], opts.BufferSize),
+		opts:         opts,
+		subscribedAt: time.Now(),
+		sentAt:       make([]time.Time, opts.BufferSize),
+	}
+	b.subscribers[id] = sub
+	return sub.ch, nil
+}
+
+// Unsubscribe removes a subscription and closes its channel.
+func (b *Broker[T]) Unsubscribe(id string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if sub, exists := b.subscribers[id]; exists {
+		close(sub.ch)
+		delete(b.subscribers, id)
+	}
+}
+
+// Publish numbers a payload, makes it the latest of its topic, keeps it and
+// sends it to the subscribers of the topic (non-blocking). Subscribers that
+// are lagging get it according to their policy. It returns the message.
+func (b *Broker[T]) Publish(topic string, payload T) *Message[T] {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	msg := &Message[T]{Seq: b.seq.next(), Topic: topic, Payload: payload}
+	b.latest[topic] = msg
+	if b.historySize > 0 {
+		if len(b.history) == b.historySize {
+			b.trimmed = b.history[0].Seq
+			copy(b.history, b.history[1:])
+			b.history = b.history[:len(b.history)-1]
+		}
+		b.history = append(b.history, msg)
+	}
+
+	now := time.Now()
+	for id, sub := range b.subscribers {
+		if !MatchAny(sub.opts.Topics, topic) || sub.deliver(msg, now) {
+			continue
+		}
+
+		b.logger.Warn("Subscriber evicted, too many messages dropped",
+			"subscriber_id", id, "kind", sub.opts.Kind, "topics", sub.opts.Topics,
+			"dropped", sub.dropped, "max_drops", sub.opts.MaxDrops)
+		close(sub.ch)
+		delete(b.subscribers, id)
+	}
+	return msg
+}
+
+// deliver sends a message to a subscriber, applying its policy when its
+// buffer is full. It returns false when the subscriber is to be evicted.
+// The broker is the only sender, so after making room a send cannot block.
+func (s *subscription[T]) deliver(msg *Message[T], now time.Time) bool {
+	select {
+	case s.ch <- msg:
+		s.sent(now)
+		return true
+	default:
+	}
+
+	dropped := uint64(1)
+	switch s.opts.Policy {
+	case PolicyDropOldest:
+		select {
+		case <-s.ch:
+		default:
+		}
+		s.ch <- msg
+		s.sent(now)
+
+	case PolicyCoalesce:
+		dropped = 0
+	drain:
+		for {
+			select {
+			case <-s.ch:
+				dropped++
+			default:
+				break drain
+			}
+		}
+		s.ch <- msg
+		s.sent(now)
+	}
+
+	s.dropped += dropped
+	s.lastDropAt = now
+	return s.opts.Policy != PolicyDisconnect || s.dropped <= uint64(s.opts.MaxDrops)
+}
+
+// sent counts a message sent at publish time t.
+func (s *subscription[T]) sent(t time.Time) {
+	s.delivered++
+	s.sentAt[s.next] = t
+	s.next = (s.next + 1) % len(s.sentAt)
+}
+
+// stats returns the statistics of the subscription at time now.
+func (s *subscription[T]) stats(id string, now time.Time) models.SubscriberStats {
+	queued := len(s.ch)
+	stats := models.SubscriberStats{
+		ID:           id,
+		Kind:         s.opts.Kind,
+		Topics:       s.opts.Topics,
+		Policy:       string(s.opts.Policy),
+		BufferSize:   s.opts.BufferSize,
+		Queued:       queued,
+		Delivered:    s.delivered,
+		Dropped:      s.dropped,
+		SubscribedAt: s.subscribedAt,
+	}
+	if s.opts.Policy == PolicyDisconnect {
+		maxDrops := s.opts.MaxDrops
+		stats.MaxDrops = &maxDrops
+	}
+	if queued > 0 {
+		// The oldest queued message is the queued-th newest sent
+		oldest := s.sentAt[(s.next-queued+len(s.sentAt))%len(s.sentAt)]
+		stats.LagSeconds = now.Sub(oldest).Seconds()
+	}
+	if !s.lastDropAt.IsZero() {
+		lastDropAt := s.lastDropAt
+		stats.LastDropAt = &lastDropAt
+	}
+	return stats
+}
+
+// Latest returns the last message published on a topic, or nil before the
+// first.
+func (b *Broker[T]) Latest(topic string) *Message[T] {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.latest[topic]
+}
+
+// Sequence returns the sequence numbering the broker's messages.
+func (b *Broker[T]) Sequence() *Sequence {
+	return b.seq
+}
+
+// Changed returns a channel that is closed when the next message of the
+// broker's sequence is published. Get the channel before reading Latest or
+// Since, so no change is missed.
+func (b *Broker[T]) Changed() <-chan struct{} {
+	return b.seq.Changed()
+}
+
+// Since returns the kept messages with a sequence number greater than seq,
+// oldest first. missed reports that messages after seq were published but
+// are no longer kept, so a reader that fell that far behind lost some.
+func (b *Broker[T]) Since(seq uint64) (messages []*Message[T], missed bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	// The sequence is shared, so numbers have gaps, but they increase
+	start := sort.Search(len(b.history), func(i int) bool {
+		return b.history[i].Seq > seq
+	})
+	messages = make([]*Message[T], len(b.history)-start)
+	copy(messages, b.history[start:])
+	return messages, b.trimmed > seq
+}
+
+// SubscriberCount returns the current number of subscribers.
+func (b *Broker[T]) SubscriberCount() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subscribers)
+}
+
+// Subscribers returns the statistics of the channel subscribers, oldest
+// subscription first.
+func (b *Broker[T]) Subscribers() []models.SubscriberStats {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	ids := make([]string, 0, len(b.subscribers))
+	for id := range b.subscribers {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return b.subscribers[ids[i]].order < b.subscribers[ids[j]].order
+	})
+
+	now := time.Now()
+	stats := make([]models.SubscriberStats, len(ids))
+	for i, id := range ids {
+		stats[i] = b.subscribers[id].stats(id, now)
+	}
+	return stats
+}
@@ -0,0 +1,407 @@
+package pubsub
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+)
+
+const (
+	// benchmarkSubscribers is the number of clients of the benchmarks.
+	benchmarkSubscribers = 1000
+
+	stateTopic = "aircraft/a1/state"
+)
+
+var testLogger = slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+func testState(simTime float64) models.AircraftState {
+	return models.AircraftState{
+		Position: models.Position{Latitude: 32.0, Longitude: 34.0, Altitude: 1000.0},
+		Velocity: models.Velocity{GroundSpeed: 100.0},
+		Heading:  90.0,
+		SimTime:  simTime,
+		Phase:    models.FlightPhaseCruise,
+	}
+}
+
+func newTestBroker(bufferSize, historySize int) *Broker[models.AircraftState] {
+	return NewBroker[models.AircraftState](NewSequence(), bufferSize, historySize, testLogger)
+}
+
+func TestLatestAndChanged(t *testing.T) {
+	seq := NewSequence()
+	states := NewBroker[models.AircraftState](seq, 10, 10, testLogger)
+	events := NewBroker[models.Event](seq, 10, 10, testLogger)
+	if states.Latest(stateTopic) != nil {
+		t.Fatal("Latest() before the first state should be nil")
+	}
+
+	changed := states.Changed()
+	select {
+	case <-changed:
+		t.Fatal("Changed() closed before a state was published")
+	default:
+	}
+
+	states.Publish(stateTopic, testState(1))
+	select {
+	case <-changed:
+	case <-time.After(time.Second):
+		t.Fatal("Changed() not closed after Publish")
+	}
+
+	latest := states.Latest(stateTopic)
+	if latest == nil || latest.Seq != 1 || latest.Topic != stateTopic || latest.Payload.SimTime != 1 {
+		t.Fatalf("Latest() = %+v, want state 1", latest)
+	}
+	if states.Latest("aircraft/a2/state") != nil {
+		t.Error("Latest() of another topic should be nil")
+	}
+
+	// A broker sharing the sequence numbers after the states and notifies
+	// the same readers
+	changed = states.Changed()
+	event := events.Publish("aircraft/a1/events/command_accepted", models.Event{Type: models.EventCommandAccepted})
+	select {
+	case <-changed:
+	case <-time.After(time.Second):
+		t.Fatal("Changed() not closed after an event was published")
+	}
+	if event.Seq != 2 || seq.Last() != 2 {
+		t.Errorf("event seq = %d, last = %d, want 2", event.Seq, seq.Last())
+	}
+	if states.Latest(stateTopic) != latest {
+		t.Error("an event replaced the latest state")
+	}
+}
+
+func TestMessageEncoded(t *testing.T) {
+	broker := newTestBroker(10, 10)
+	msg := broker.Publish(stateTopic, testState(1))
+
+	calls := 0
+	encode := func(state *models.AircraftState) ([]byte, error) {
+		calls++
+		return json.Marshal(state)
+	}
+
+	first, err := msg.Encoded("json", encode)
+	if err != nil {
+		t.Fatalf("Encoded() error = %v", err)
+	}
+	second, err := msg.Encoded("json", encode)
+	if err != nil {
+		t.Fatalf("Encoded() error = %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("encode called %d times for one key, want 1", calls)
+	}
+	if &first[0] != &second[0] {
+		t.Error("Encoded() returned different slices for the same key")
+	}
+
+	if _, err := msg.Encoded("other", encode); err != nil {
+		t.Fatalf("Encoded() error = %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("encode called %d times for two keys, want 2", calls)
+	}
+
+	// Failed encodings are not kept
+	failing := func(*models.AircraftState) ([]byte, error) {
+		return nil, fmt.Errorf("failed")
+	}
+	if _, err := msg.Encoded("failing", failing); err == nil {
+		t.Error("Encoded() error = nil, want the encoding error")
+	}
+	if _, err := msg.Encoded("failing", encode); err != nil {
+		t.Errorf("Encoded() after a failure error = %v", err)
+	}
+}
+
+func TestSince(t *testing.T) {
+	seq := NewSequence()
+	broker := NewBroker[models.AircraftState](seq, 10, 3, testLogger)
+	other := NewBroker[models.Event](seq, 10, 3, testLogger)
+
+	// States get the odd numbers, 1 to 9, events the even ones
+	for i := 1; i <= 5; i++ {
+		broker.Publish(stateTopic, testState(float64(i)))
+		other.Publish("aircraft/a1/events/phase_changed", models.Event{})
+	}
+
+	tests := []struct {
+		seq        uint64
+		wantSeqs   []uint64
+		wantMissed bool
+	}{
+		{seq: 0, wantSeqs: []uint64{5, 7, 9}, wantMissed: true},
+		{seq: 2, wantSeqs: []uint64{5, 7, 9}, wantMissed: true},
+		{seq: 3, wantSeqs: []uint64{5, 7, 9}},
+		{seq: 5, wantSeqs: []uint64{7, 9}},
+		{seq: 6, wantSeqs: []uint64{7, 9}},
+		{seq: 9, wantSeqs: nil},
+	}
+
+	for _, tt := range tests {
+		messages, missed := broker.Since(tt.seq)
+		if missed != tt.wantMissed {
+			t.Errorf("Since(%d) missed = %v, want %v", tt.seq, missed, tt.wantMissed)
+		}
+		if len(messages) != len(tt.wantSeqs) {
+			t.Errorf("Since(%d) returned %d messages, want %d", tt.seq, len(messages), len(tt.wantSeqs))
+			continue
+		}
+		for i, msg := range messages {
+			if msg.Seq != tt.wantSeqs[i] {
+				t.Errorf("Since(%d)[%d] = %d, want %d", tt.seq, i, msg.Seq, tt.wantSeqs[i])
+			}
+		}
+	}
+}
+
+func TestSubscribeTopics(t *testing.T) {
+	broker := newTestBroker(10, 0)
+	all, err := broker.Subscribe("all", SubscribeOptions{Topics: []string{"aircraft/*/state"}})
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	one, err := broker.Subscribe("one", SubscribeOptions{Topics: []string{"aircraft/a2/**"}})
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	if _, err := broker.Subscribe("invalid", SubscribeOptions{Topics: []string{"aircraft/**/state"}}); err == nil {
+		t.Error("Subscribe() with an invalid pattern error = nil, want an error")
+	}
+
+	broker.Publish("aircraft/a1/state", testState(1))
+	broker.Publish("aircraft/a2/state", testState(2))
+
+	if got := len(all); got != 2 {
+		t.Errorf("aircraft/*/state got %d messages, want 2", got)
+	}
+	if got := len(one); got != 1 {
+		t.Fatalf("aircraft/a2/** got %d messages, want 1", got)
+	}
+	if msg := <-one; msg.Topic != "aircraft/a2/state" || msg.Payload.SimTime != 2 {
+		t.Errorf("aircraft/a2/** got %+v, want the a2 state", msg)
+	}
+}
+
+func TestSlowPolicies(t *testing.T) {
+	tests := []struct {
+		policy      SlowPolicy
+		wantStates  []float64 // sim times left in the buffer
+		wantDropped uint64
+		wantEvicted bool
+	}{
+		{policy: PolicyDropNewest, wantStates: []float64{1, 2}, wantDropped: 3},
+		{policy: PolicyDropOldest, wantStates: []float64{4, 5}, wantDropped: 3},
+		{policy: PolicyCoalesce, wantStates: []float64{5}, wantDropped: 4},
+		{policy: PolicyDisconnect, wantStates: []float64{1, 2}, wantDropped: 2, wantEvicted: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			broker := newTestBroker(10, 0)
+			ch, err := broker.Subscribe("slow", SubscribeOptions{BufferSize: 2, Policy: tt.policy, MaxDrops: 1})
+			if err != nil {
+				t.Fatalf("Subscribe() error = %v", err)
+			}
+
+			// The subscriber reads nothing while five states are published
+			for i := 1; i <= 5; i++ {
+				broker.Publish(stateTopic, testState(float64(i)))
+			}
+
+			stats := broker.Subscribers()
+			if tt.wantEvicted {
+				if len(stats) != 0 {
+					t.Errorf("Subscribers() = %+v, want the subscriber evicted", stats)
+				}
+			} else {
+				if len(stats) != 1 {
+					t.Fatalf("Subscribers() returned %d subscribers, want 1", len(stats))
+				}
+				if stats[0].Dropped != tt.wantDropped {
+					t.Errorf("dropped = %d, want %d", stats[0].Dropped, tt.wantDropped)
+				}
+				if stats[0].Queued != len(tt.wantStates) {
+					t.Errorf("queued = %d, want %d", stats[0].Queued, len(tt.wantStates))
+				}
+				if stats[0].LagSeconds <= 0 || stats[0].LastDropAt == nil {
+					t.Errorf("stats = %+v, want a lag and a last drop", stats[0])
+				}
+				broker.Unsubscribe("slow")
+			}
+
+			var got []float64
+			for msg := range ch {
+				got = append(got, msg.Payload.SimTime)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.wantStates) {
+				t.Errorf("states = %v, want %v", got, tt.wantStates)
+			}
+		})
+	}
+}
+
+func TestSubscribers(t *testing.T) {
+	broker := newTestBroker(10, 0)
+	if _, err := broker.Subscribe("grpc-1", SubscribeOptions{
+		Topics:   []string{stateTopic},
+		Kind:     "grpc",
+		Policy:   PolicyDisconnect,
+		MaxDrops: 5,
+	}); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	ch, err := broker.Subscribe("default", SubscribeOptions{})
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	broker.Publish(stateTopic, testState(1))
+	<-ch
+
+	stats := broker.Subscribers()
+	if len(stats) != 2 {
+		t.Fatalf("Subscribers() returned %d subscribers, want 2", len(stats))
+	}
+
+	first, second := stats[0], stats[1]
+	if first.ID != "grpc-1" || first.Kind != "grpc" || first.BufferSize != 10 {
+		t.Errorf("first = %+v, want grpc-1 with the broker's buffer size", first)
+	}
+	if first.MaxDrops == nil || *first.MaxDrops != 5 {
+		t.Errorf("max_drops = %v, want 5", first.MaxDrops)
+	}
+	if first.Queued != 1 || first.Delivered != 1 || first.Dropped != 0 {
+		t.Errorf("first = %+v, want one queued state", first)
+	}
+
+	if second.Policy != string(PolicyDropNewest) || second.MaxDrops != nil {
+		t.Errorf("second = %+v, want the default policy", second)
+	}
+	if fmt.Sprint(second.Topics) != "[**]" {
+		t.Errorf("second topics = %v, want all topics", second.Topics)
+	}
+	if second.Queued != 0 || second.LagSeconds != 0 {
+		t.Errorf("second = %+v, want no lag after reading", second)
+	}
+}
+
+func TestParseSlowPolicy(t *testing.T) {
+	if policy, err := ParseSlowPolicy(""); err != nil || policy != PolicyDropNewest {
+		t.Errorf("ParseSlowPolicy(\"\") = %q, %v, want drop_newest", policy, err)
+	}
+	if policy, err := ParseSlowPolicy("coalesce"); err != nil || policy != PolicyCoalesce {
+		t.Errorf("ParseSlowPolicy(coalesce) = %q, %v, want coalesce", policy, err)
+	}
+	if _, err := ParseSlowPolicy("block"); err == nil {
+		t.Error("ParseSlowPolicy(block) error = nil, want an error")
+	}
+}
+
+// BenchmarkPublishChannels publishes to channel subscribers that each encode
+// the state.
+func BenchmarkPublishChannels(b *testing.B) {
+	broker := newTestBroker(1, 0)
+	var wg sync.WaitGroup
+
+	for i := 0; i < benchmarkSubscribers; i++ {
+		ch, err := broker.Subscribe(fmt.Sprintf("sub-%d", i), SubscribeOptions{Topics: []string{stateTopic}})
+		if err != nil {
+			b.Fatal(err)
+		}
+		go func() {
+			for msg := range ch {
+				if _, err := json.Marshal(&msg.Payload); err != nil {
+					b.Error(err)
+				}
+				wg.Done()
+			}
+		}()
+	}
+	defer func() {
+		for i := 0; i < benchmarkSubscribers; i++ {
+			broker.Unsubscribe(fmt.Sprintf("sub-%d", i))
+		}
+	}()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		wg.Add(benchmarkSubscribers)
+		broker.Publish(stateTopic, testState(float64(i)))
+		wg.Wait()
+	}
+}
+
+// BenchmarkBroadcastEncodeOnce publishes to readers of the latest state that
+// share its encoding.
+func BenchmarkBroadcastEncodeOnce(b *testing.B) {
+	encode := func(state *models.AircraftState) ([]byte, error) {
+		return json.Marshal(state)
+	}
+	benchmarkBroadcast(b, func(msg *Message[models.AircraftState]) error {
+		_, err := msg.Encoded("json", encode)
+		return err
+	})
+}
+
+// BenchmarkBroadcastEncodeEach publishes to readers of the latest state that
+// each encode it, for comparison.
+func BenchmarkBroadcastEncodeEach(b *testing.B) {
+	benchmarkBroadcast(b, func(msg *Message[models.AircraftState]) error {
+		_, err := json.Marshal(&msg.Payload)
+		return err
+	})
+}
+
+// benchmarkBroadcast publishes states to readers waiting for changes, which
+// read the latest state with read. Each publish waits for all readers.
+func benchmarkBroadcast(b *testing.B, read func(*Message[models.AircraftState]) error) {
+	broker := newTestBroker(1, 0)
+	stop := make(chan struct{})
+	defer close(stop)
+
+	var ready, done sync.WaitGroup
+	ready.Add(benchmarkSubscribers)
+	for i := 0; i < benchmarkSubscribers; i++ {
+		go func() {
+			changed := broker.Changed()
+			ready.Done()
+			for {
+				select {
+				case <-changed:
+					// Wait for the next change before this one is done, so
+					// none is missed
+					changed = broker.Changed()
+					if err := read(broker.Latest(stateTopic)); err != nil {
+						b.Error(err)
+					}
+					done.Done()
+				case <-stop:
+					return
+				}
+			}
+		}()
+	}
+	ready.Wait()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		done.Add(benchmarkSubscribers)
+		broker.Publish(stateTopic, testState(float64(i)))
+		done.Wait()
+	}
+}
@@ -0,0 +1,57 @@
+package pubsub
+
+import (
+	"sync"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+)
+
+// EventBus numbers simulation events and keeps the most recent ones for
+// polling clients. Streaming clients get events from a Broker.
+type EventBus struct {
+	mu          sync.RWMutex
+	lastID      uint64
+	history     []models.Event // oldest first
+	historySize int
+}
+
+// NewEventBus creates an event bus keeping the last historySize events.
+func NewEventBus(historySize int) *EventBus {
+	return &EventBus{
+		history:     make([]models.Event, 0, historySize),
+		historySize: historySize,
+	}
+}
+
+// Publish assigns the event the next ID and keeps it. It returns the
+// numbered event.
+func (b *EventBus) Publish(event models.Event) models.Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	event.ID = b.lastID
+
+	if len(b.history) == b.historySize {
+		copy(b.history, b.history[1:])
+		b.history = b.history[:len(b.history)-1]
+	}
+	b.history = append(b.history, event)
+	return event
+}
+
+// Since returns the kept events with an ID greater than id, oldest first,
+// and the ID of the last event published.
+func (b *EventBus) Since(id uint64) ([]models.Event, uint64) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	// IDs are consecutive, so the first newer event is found by offset
+	start := 0
+	if len(b.history) > 0 && id >= b.history[0].ID {
+		start = min(int(id-b.history[0].ID)+1, len(b.history))
+	}
+	events := make([]models.Event, len(b.history)-start)
+	copy(events, b.history[start:])
+	return events, b.lastID
+}
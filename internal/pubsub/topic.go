@@ -0,0 +1,63 @@
+package pubsub
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Topics are made of levels separated by slashes, e.g.
+// "aircraft/aircraft-1/state". Subscriptions match topics with patterns, in
+// which a "*" level matches any one level and a final "**" level matches any
+// number of remaining levels, including none.
+const (
+	wildcardLevel = "*"
+	wildcardRest  = "**"
+)
+
+// ValidatePattern checks that pattern is a topic pattern: non-empty levels,
+// wildcards as whole levels and "**" only as the last level.
+func ValidatePattern(pattern string) error {
+	levels := strings.Split(pattern, "/")
+	for i, level := range levels {
+		switch {
+		case level == "":
+			return fmt.Errorf("topic pattern %q has an empty level", pattern)
+		case level == wildcardRest && i != len(levels)-1:
+			return fmt.Errorf("topic pattern %q has %q before its last level", pattern, wildcardRest)
+		case level != wildcardLevel && level != wildcardRest && strings.Contains(level, "*"):
+			return fmt.Errorf("topic pattern %q has a wildcard inside a level", pattern)
+		}
+	}
+	return nil
+}
+
+// MatchTopic reports whether a topic matches a pattern.
+func MatchTopic(pattern, topic string) bool {
+	for {
+		level, rest, more := strings.Cut(pattern, "/")
+		if level == wildcardRest {
+			return true
+		}
+
+		topicLevel, topicRest, topicMore := strings.Cut(topic, "/")
+		if level != wildcardLevel && level != topicLevel {
+			return false
+		}
+
+		if !more || !topicMore {
+			// A final "**" also matches the topic without more levels
+			return more == topicMore || (more && rest == wildcardRest)
+		}
+		pattern, topic = rest, topicRest
+	}
+}
+
+// MatchAny reports whether a topic matches any of the patterns.
+func MatchAny(patterns []string, topic string) bool {
+	for _, pattern := range patterns {
+		if MatchTopic(pattern, topic) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package pubsub
+
+import "testing"
+
+func TestMatchTopic(t *testing.T) {
+	tests := []struct {
+		pattern string
+		topic   string
+		want    bool
+	}{
+		{"aircraft/a1/state", "aircraft/a1/state", true},
+		{"aircraft/a1/state", "aircraft/a2/state", false},
+		{"aircraft/*/state", "aircraft/a2/state", true},
+		{"aircraft/*/state", "aircraft/a2/events/phase_changed", false},
+		{"aircraft/*", "aircraft/a1/state", false},
+		{"aircraft/a1/**", "aircraft/a1/state", true},
+		{"aircraft/a1/**", "aircraft/a1/events/phase_changed", true},
+		{"aircraft/a1/**", "aircraft/a1", true},
+		{"aircraft/a1/**", "aircraft/a2/state", false},
+		{"**", "aircraft/a1/state", true},
+		{"aircraft/*/events/*", "aircraft/a1/events/waypoint_reached", true},
+		{"aircraft/a1/state/more", "aircraft/a1/state", false},
+	}
+
+	for _, tt := range tests {
+		if got := MatchTopic(tt.pattern, tt.topic); got != tt.want {
+			t.Errorf("MatchTopic(%q, %q) = %v, want %v", tt.pattern, tt.topic, got, tt.want)
+		}
+	}
+}
+
+func TestValidatePattern(t *testing.T) {
+	valid := []string{"aircraft/a1/state", "aircraft/*/state", "aircraft/**", "**"}
+	for _, pattern := range valid {
+		if err := ValidatePattern(pattern); err != nil {
+			t.Errorf("ValidatePattern(%q) error = %v", pattern, err)
+		}
+	}
+
+	invalid := []string{"", "aircraft//state", "aircraft/**/state", "aircraft/a*/state", "/aircraft"}
+	for _, pattern := range invalid {
+		if err := ValidatePattern(pattern); err == nil {
+			t.Errorf("ValidatePattern(%q) error = nil, want an error", pattern)
+		}
+	}
+}
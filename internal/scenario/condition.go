@@ -0,0 +1,176 @@
+package scenario
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+)
+
+// Condition is a parsed condition such as "altitude > 2000 and phase ==
+// descent": comparisons of state variables with constants, joined by "and".
+type Condition struct {
+	text    string
+	clauses []clause
+}
+
+// clause is one comparison.
+type clause struct {
+	variable variable
+	op       string
+	number   float64 // numeric variables
+	value    string  // string and boolean variables
+}
+
+// observation is what conditions are evaluated against.
+type observation struct {
+	state   models.AircraftState
+	elapsed float64 // seconds since the scenario start
+}
+
+// variableKind is the type of a condition variable.
+type variableKind int
+
+const (
+	numberVariable variableKind = iota
+	stringVariable
+	boolVariable
+)
+
+// variable is a value conditions can test.
+type variable struct {
+	name   string
+	kind   variableKind
+	number func(observation) float64
+	text   func(observation) string
+}
+
+// variables lists the condition variables by name.
+var variables = map[string]variable{
+	"time":     {kind: numberVariable, number: func(o observation) float64 { return o.elapsed }},
+	"sim_time": {kind: numberVariable, number: func(o observation) float64 { return o.state.SimTime }},
+	"altitude": {kind: numberVariable, number: func(o observation) float64 { return o.state.Position.Altitude }},
+	"height_agl": {kind: numberVariable, number: func(o observation) float64 {
+		if o.state.Terrain != nil {
+			return o.state.Terrain.HeightAGL
+		}
+		return o.state.Position.Altitude
+	}},
+	"latitude":       {kind: numberVariable, number: func(o observation) float64 { return o.state.Position.Latitude }},
+	"longitude":      {kind: numberVariable, number: func(o observation) float64 { return o.state.Position.Longitude }},
+	"ground_speed":   {kind: numberVariable, number: func(o observation) float64 { return o.state.Velocity.GroundSpeed }},
+	"vertical_speed": {kind: numberVariable, number: func(o observation) float64 { return o.state.Velocity.VerticalSpeed }},
+	"heading":        {kind: numberVariable, number: func(o observation) float64 { return o.state.Heading }},
+	"waypoint": {kind: numberVariable, number: func(o observation) float64 {
+		if cmd := o.state.ActiveCommand; cmd != nil && cmd.WaypointIndex != nil {
+			return float64(*cmd.WaypointIndex)
+		}
+		return -1
+	}},
+	"geofence_breaches": {kind: numberVariable, number: func(o observation) float64 { return float64(len(o.state.GeofenceBreaches)) }},
+	"phase":             {kind: stringVariable, text: func(o observation) string { return string(o.state.Phase) }},
+	"command": {kind: stringVariable, text: func(o observation) string {
+		if o.state.ActiveCommand == nil {
+			return "none"
+		}
+		return o.state.ActiveCommand.Type
+	}},
+	"link_lost":      {kind: boolVariable, text: func(o observation) string { return strconv.FormatBool(o.state.LinkLost) }},
+	"engine_failure": {kind: boolVariable, text: func(o observation) string { return strconv.FormatBool(o.state.EngineFailure) }},
+}
+
+// comparisonPattern matches one comparison, e.g. "altitude >= 1500".
+var comparisonPattern = regexp.MustCompile(`^([a-z_]+)\s*(>=|<=|==|!=|>|<)\s*(\S+)$`)
+
+// andPattern splits conjunctions.
+var andPattern = regexp.MustCompile(`\s+and\s+`)
+
+// ParseCondition parses a condition.
+func ParseCondition(text string) (Condition, error) {
+	cond := Condition{text: text}
+	for _, part := range andPattern.Split(strings.TrimSpace(text), -1) {
+		match := comparisonPattern.FindStringSubmatch(part)
+		if match == nil {
+			return Condition{}, fmt.Errorf("invalid condition %q: want <variable> <op> <value>", part)
+		}
+
+		v, ok := variables[match[1]]
+		if !ok {
+			return Condition{}, fmt.Errorf("unknown variable %q (known: %s)", match[1], variableNames())
+		}
+		v.name = match[1]
+		c := clause{variable: v, op: match[2], value: match[3]}
+
+		switch v.kind {
+		case numberVariable:
+			number, err := strconv.ParseFloat(c.value, 64)
+			if err != nil {
+				return Condition{}, fmt.Errorf("%s needs a number, got %q", v.name, c.value)
+			}
+			c.number = number
+		case boolVariable:
+			if c.value != "true" && c.value != "false" {
+				return Condition{}, fmt.Errorf("%s needs true or false, got %q", v.name, c.value)
+			}
+			fallthrough
+		case stringVariable:
+			if c.op != "==" && c.op != "!=" {
+				return Condition{}, fmt.Errorf("%s can only be compared with == or !=", v.name)
+			}
+		}
+		cond.clauses = append(cond.clauses, c)
+	}
+	return cond, nil
+}
+
+// String returns the condition text.
+func (c Condition) String() string {
+	return c.text
+}
+
+// holds reports whether every comparison holds.
+func (c Condition) holds(o observation) bool {
+	for _, cl := range c.clauses {
+		if !cl.holds(o) {
+			return false
+		}
+	}
+	return len(c.clauses) > 0
+}
+
+// holds evaluates one comparison.
+func (c clause) holds(o observation) bool {
+	if c.variable.kind != numberVariable {
+		equal := c.variable.text(o) == c.value
+		return equal == (c.op == "==")
+	}
+
+	value := c.variable.number(o)
+	switch c.op {
+	case ">":
+		return value > c.number
+	case ">=":
+		return value >= c.number
+	case "<":
+		return value < c.number
+	case "<=":
+		return value <= c.number
+	case "==":
+		return value == c.number
+	default:
+		return value != c.number
+	}
+}
+
+// variableNames lists the condition variables for error messages.
+func variableNames() string {
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,48 @@
+package scenario
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/simulator"
+)
+
+// RunHeadless runs a validated scenario to its end on a headless simulator,
+// without waiting for real time, and returns its final status. observe, if
+// not nil, is called with every state and the scenario time. The run is
+// aborted when ctx is cancelled.
+func RunHeadless(ctx context.Context, sc *Scenario, sim *simulator.Headless, observe func(state models.AircraftState, elapsed float64)) models.ScenarioStatus {
+	r := newRun(sc)
+
+	start, err := setUp(ctx, sim, sc)
+	if err != nil {
+		r.end(models.ScenarioError, fmt.Sprintf("setup failed: %v", err))
+		return r.snapshot()
+	}
+
+	for {
+		if ctx.Err() != nil {
+			r.end(models.ScenarioAborted, "aborted")
+			return r.snapshot()
+		}
+
+		state := sim.Step()
+		elapsed := state.SimTime - start
+		if observe != nil {
+			observe(state, elapsed)
+		}
+
+		due, done := r.step(observation{state: state, elapsed: elapsed})
+		if done {
+			r.end("", "")
+			return r.snapshot()
+		}
+		for _, event := range due {
+			if err := apply(ctx, sim, event); err != nil {
+				r.end(models.ScenarioError, fmt.Sprintf("event failed: %v", err))
+				return r.snapshot()
+			}
+		}
+	}
+}
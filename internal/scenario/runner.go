@@ -0,0 +1,450 @@
+package scenario
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/config"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/pubsub"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/simulator"
+)
+
+// maxRuns is the number of finished runs kept for status reporting.
+const maxRuns = 100
+
+// stateBufferSize is the number of states buffered while the runner applies
+// events through the simulator.
+const stateBufferSize = 100
+
+// Manager runs scenarios against a simulator, one at a time, and keeps the
+// status of recent runs. It is safe for concurrent use.
+type Manager struct {
+	simulator *simulator.Simulator
+	simCfg    config.SimulationConfig
+	logger    *slog.Logger
+
+	mu     sync.Mutex
+	runs   map[string]*run
+	active *run
+}
+
+// run is one execution of a scenario. Its status is guarded by Manager.mu.
+type run struct {
+	scenario *Scenario
+	status   models.ScenarioStatus
+	fired    []bool
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// NewManager creates a scenario manager. simCfg supplies the speed and climb
+// limits scenarios are validated against.
+func NewManager(sim *simulator.Simulator, simCfg config.SimulationConfig, logger *slog.Logger) *Manager {
+	return &Manager{
+		simulator: sim,
+		simCfg:    simCfg,
+		logger:    logger,
+		runs:      make(map[string]*run),
+	}
+}
+
+// Start validates a scenario and starts running it in the background. Only
+// one scenario runs at a time, since all of them fly the same aircraft.
+func (m *Manager) Start(sc *Scenario) (models.ScenarioStatus, error) {
+	if m.simulator.Replaying() {
+		return models.ScenarioStatus{}, models.ErrReplayActive
+	}
+	if err := sc.Validate(m.simCfg); err != nil {
+		return models.ScenarioStatus{}, err
+	}
+	if sc.UsesWind() && !m.simulator.GetEnvironment().IsEnabled() {
+		return models.ScenarioStatus{}, fmt.Errorf("%w: wind events need the environment enabled: %w",
+			models.ErrInvalidScenario, models.ErrEnvironmentDisabled)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.active != nil {
+		return models.ScenarioStatus{}, fmt.Errorf("%w: %s", models.ErrScenarioRunning, m.active.status.ID)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := newRun(sc)
+	r.cancel = cancel
+	m.runs[r.status.ID] = r
+	m.active = r
+	m.prune()
+
+	m.logger.Info("Scenario started", "scenario_id", r.status.ID, "name", sc.Name)
+	go m.execute(ctx, r)
+
+	return r.snapshot(), nil
+}
+
+// Get returns the status of a run.
+func (m *Manager) Get(id string) (models.ScenarioStatus, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, exists := m.runs[id]
+	if !exists {
+		return models.ScenarioStatus{}, fmt.Errorf("%w: %s", models.ErrScenarioNotFound, id)
+	}
+	return r.snapshot(), nil
+}
+
+// List returns the status of all kept runs, oldest first.
+func (m *Manager) List() []models.ScenarioStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	statuses := make([]models.ScenarioStatus, 0, len(m.runs))
+	for _, r := range m.runs {
+		statuses = append(statuses, r.snapshot())
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].StartedAt.Before(statuses[j].StartedAt)
+	})
+	return statuses
+}
+
+// Abort stops a running scenario and waits for it to stop. The aircraft
+// keeps its current state and command.
+func (m *Manager) Abort(ctx context.Context, id string) (models.ScenarioStatus, error) {
+	m.mu.Lock()
+	r, exists := m.runs[id]
+	m.mu.Unlock()
+	if !exists {
+		return models.ScenarioStatus{}, fmt.Errorf("%w: %s", models.ErrScenarioNotFound, id)
+	}
+
+	r.cancel()
+	return m.Wait(ctx, id)
+}
+
+// Wait waits for a run to finish and returns its final status.
+func (m *Manager) Wait(ctx context.Context, id string) (models.ScenarioStatus, error) {
+	m.mu.Lock()
+	r, exists := m.runs[id]
+	m.mu.Unlock()
+	if !exists {
+		return models.ScenarioStatus{}, fmt.Errorf("%w: %s", models.ErrScenarioNotFound, id)
+	}
+
+	select {
+	case <-r.done:
+		return m.Get(id)
+	case <-ctx.Done():
+		return models.ScenarioStatus{}, ctx.Err()
+	}
+}
+
+// execute runs a scenario until it ends, fails or is aborted.
+func (m *Manager) execute(ctx context.Context, r *run) {
+	defer close(r.done)
+
+	// Subscribe before setting up so that no state after the start is
+	// missed. Assertions must see every state, so a runner that falls behind
+	// is evicted at its first dropped state and the run fails.
+	subscriberID := "scenario-" + r.status.ID
+	publisher := m.simulator.GetPublisher()
+	states, err := publisher.Subscribe(subscriberID, pubsub.SubscribeOptions{
+		Topics:     []string{simulator.StateTopic(m.simulator.AircraftID())},
+		Kind:       "scenario",
+		BufferSize: stateBufferSize,
+		Policy:     pubsub.PolicyDisconnect,
+	})
+	if err != nil {
+		m.finish(r, models.ScenarioError, fmt.Sprintf("state subscription failed: %v", err))
+		return
+	}
+	defer publisher.Unsubscribe(subscriberID)
+
+	start, err := setUp(ctx, m.simulator, r.scenario)
+	if err != nil {
+		m.finish(r, models.ScenarioError, fmt.Sprintf("setup failed: %v", err))
+		return
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			m.finish(r, models.ScenarioAborted, "aborted")
+			return
+
+		case msg, ok := <-states:
+			if !ok {
+				m.finish(r, models.ScenarioError, "state subscription evicted: the runner fell behind and missed states")
+				return
+			}
+			state := msg.Payload
+
+			// Skip states published before the setup was applied
+			if state.SimTime <= start {
+				continue
+			}
+
+			m.mu.Lock()
+			due, done := r.step(observation{state: state, elapsed: state.SimTime - start})
+			m.mu.Unlock()
+			if done {
+				m.finish(r, "", "")
+				return
+			}
+
+			for _, event := range due {
+				if err := apply(ctx, m.simulator, event); err != nil {
+					m.finish(r, models.ScenarioError, fmt.Sprintf("event failed: %v", err))
+					return
+				}
+			}
+		}
+	}
+}
+
+// driver is the simulator interface scenarios are run through. It is
+// implemented by simulator.Simulator and simulator.Headless.
+type driver interface {
+	GetState(ctx context.Context) (models.AircraftState, error)
+	SubmitCommand(ctx context.Context, cmd *models.Command) error
+	SetState(ctx context.Context, update models.StateUpdate) (models.AircraftState, error)
+	SetConditions(ctx context.Context, update models.ConditionsUpdate) error
+}
+
+// setUp applies the initial state and environment and returns the
+// simulation time the scenario starts at.
+func setUp(ctx context.Context, sim driver, sc *Scenario) (float64, error) {
+	noFailure := false
+	conditions := models.ConditionsUpdate{EngineFailure: &noFailure}
+	if sc.Environment != nil {
+		conditions.Wind = sc.Environment.Wind
+		if sc.Environment.EngineFailure != nil {
+			conditions.EngineFailure = sc.Environment.EngineFailure
+		}
+	}
+	if err := sim.SetConditions(ctx, conditions); err != nil {
+		return 0, err
+	}
+
+	if sc.InitialState != nil {
+		update := *sc.InitialState
+		update.ClearCommand = true
+		state, err := sim.SetState(ctx, update)
+		return state.SimTime, err
+	}
+	state, err := sim.GetState(ctx)
+	return state.SimTime, err
+}
+
+// apply performs the actions of an event through the simulator.
+func apply(ctx context.Context, sim driver, event *Event) error {
+	if event.State != nil {
+		if _, err := sim.SetState(ctx, *event.State); err != nil {
+			return err
+		}
+	}
+	if event.Command != nil {
+		// Every submission is a new command
+		cmd := *event.Command
+		cmd.ID = uuid.New().String()
+		if err := sim.SubmitCommand(ctx, &cmd); err != nil {
+			return err
+		}
+	}
+
+	var conditions models.ConditionsUpdate
+	conditions.Wind = event.Wind
+	if event.Inject == FailureEngine {
+		failed := true
+		conditions.EngineFailure = &failed
+	}
+	if event.Clear == FailureEngine {
+		failed := false
+		conditions.EngineFailure = &failed
+	}
+	if conditions.Wind != nil || conditions.EngineFailure != nil {
+		return sim.SetConditions(ctx, conditions)
+	}
+	return nil
+}
+
+// finish ends a run. An empty state keeps the outcome set by step.
+func (m *Manager) finish(r *run, state models.ScenarioState, message string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r.end(state, message)
+	if m.active == r {
+		m.active = nil
+	}
+
+	m.logger.Info("Scenario finished",
+		"scenario_id", r.status.ID,
+		"name", r.status.Name,
+		"state", r.status.State,
+		"message", r.status.Message,
+		"elapsed", r.status.Elapsed,
+	)
+}
+
+// prune drops the oldest finished runs beyond maxRuns.
+func (m *Manager) prune() {
+	if len(m.runs) <= maxRuns {
+		return
+	}
+	finished := make([]*run, 0, len(m.runs))
+	for _, r := range m.runs {
+		if r.status.FinishedAt != nil {
+			finished = append(finished, r)
+		}
+	}
+	sort.Slice(finished, func(i, j int) bool {
+		return finished[i].status.StartedAt.Before(finished[j].status.StartedAt)
+	})
+	for _, r := range finished[:min(len(finished), len(m.runs)-maxRuns)] {
+		delete(m.runs, r.status.ID)
+	}
+}
+
+// newRun creates a run of a validated scenario.
+func newRun(sc *Scenario) *run {
+	r := &run{
+		scenario: sc,
+		fired:    make([]bool, len(sc.Events)),
+		done:     make(chan struct{}),
+		status: models.ScenarioStatus{
+			ID:        uuid.New().String(),
+			Name:      sc.Name,
+			State:     models.ScenarioRunning,
+			StartedAt: time.Now(),
+			Events:    len(sc.Events),
+		},
+	}
+	for _, a := range sc.Assertions {
+		kind, text := a.kind()
+		r.status.Assertions = append(r.status.Assertions, models.AssertionResult{
+			Kind:      kind,
+			Condition: text,
+			Status:    "pending",
+		})
+	}
+	return r
+}
+
+// step evaluates one published state: it checks the assertions, returns
+// the events that are due and reports whether the scenario has ended.
+func (r *run) step(o observation) ([]*Event, bool) {
+	sc := r.scenario
+	r.status.Elapsed = o.elapsed
+
+	for i, a := range sc.Assertions {
+		result := &r.status.Assertions[i]
+		if result.Status != "pending" {
+			continue
+		}
+		switch result.Kind {
+		case models.AssertionAlways:
+			if !a.condition.holds(o) {
+				// Fail fast; the other pending assertions stay undecided
+				decide(result, "failed", o.elapsed)
+				r.status.State = models.ScenarioFailed
+				r.status.Message = fmt.Sprintf("always %q violated at %.1f s", a.Always, o.elapsed)
+				return nil, true
+			}
+		case models.AssertionEventually:
+			if a.condition.holds(o) {
+				decide(result, "passed", o.elapsed)
+			}
+		}
+	}
+
+	if o.elapsed >= sc.duration().Seconds() {
+		r.conclude(o, "duration reached")
+		return nil, true
+	}
+
+	var due []*Event
+	for i := range sc.Events {
+		event := &sc.Events[i]
+		if r.fired[i] {
+			continue
+		}
+		if (event.At != nil && o.elapsed >= event.At.Seconds()) || (event.When != "" && event.when.holds(o)) {
+			r.fired[i] = true
+			r.status.EventsFired++
+			due = append(due, event)
+		}
+	}
+
+	// A state that fires events does not end the scenario, so that e.g.
+	// "until: command == none" waits for the first command
+	if len(due) == 0 && sc.Until != "" && sc.until.holds(o) {
+		r.status.UntilMet = true
+		r.conclude(o, fmt.Sprintf("until %q met", sc.Until))
+		return nil, true
+	}
+	return due, false
+}
+
+// conclude decides the remaining assertions on the final state and sets the
+// outcome.
+func (r *run) conclude(o observation, reason string) {
+	for i, a := range r.scenario.Assertions {
+		result := &r.status.Assertions[i]
+		if result.Status != "pending" {
+			continue
+		}
+		switch result.Kind {
+		case models.AssertionAlways:
+			decide(result, "passed", o.elapsed)
+		case models.AssertionEventually:
+			decide(result, "failed", o.elapsed)
+		case models.AssertionAtEnd:
+			if a.condition.holds(o) {
+				decide(result, "passed", o.elapsed)
+			} else {
+				decide(result, "failed", o.elapsed)
+			}
+		}
+	}
+
+	r.status.State = models.ScenarioPassed
+	r.status.Message = reason
+	for _, result := range r.status.Assertions {
+		if result.Status == "failed" {
+			r.status.State = models.ScenarioFailed
+			r.status.Message = fmt.Sprintf("%s: %s %q not met", reason, result.Kind, result.Condition)
+			break
+		}
+	}
+}
+
+// end marks the run finished. An empty state keeps the outcome set by step.
+func (r *run) end(state models.ScenarioState, message string) {
+	if state != "" {
+		r.status.State = state
+		r.status.Message = message
+	}
+	now := time.Now()
+	r.status.FinishedAt = &now
+}
+
+// decide records the outcome of an assertion.
+func decide(result *models.AssertionResult, status string, elapsed float64) {
+	result.Status = status
+	result.Time = &elapsed
+}
+
+// snapshot returns a copy of the run status.
+func (r *run) snapshot() models.ScenarioStatus {
+	status := r.status
+	status.Assertions = append([]models.AssertionResult(nil), r.status.Assertions...)
+	return status
+}
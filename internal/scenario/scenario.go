@@ -0,0 +1,316 @@
+// Package scenario runs scripted test scenarios against the simulator: an
+// initial state and environment, timed and conditional events, and
+// assertions that decide whether the scenario passed.
+package scenario
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/api/validation"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/config"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultDuration limits scenarios that do not set a duration.
+const defaultDuration = 10 * time.Minute
+
+// Failures that events can inject or clear.
+const (
+	FailureEngine = "engine_failure"
+)
+
+// Scenario is a scenario definition. Scenario files are YAML (or JSON) with
+// the field names of the JSON API.
+type Scenario struct {
+	Name         string                   `json:"name"`
+	Description  string                   `json:"description,omitempty"`
+	InitialState *models.StateUpdate      `json:"initial_state,omitempty"` // the active command is always cleared
+	Environment  *models.ConditionsUpdate `json:"environment,omitempty"`
+	Duration     Duration                 `json:"duration,omitempty"` // simulation time limit, default 10m
+	Until        string                   `json:"until,omitempty"`    // condition that ends the scenario early
+	Events       []Event                  `json:"events,omitempty"`
+	Assertions   []Assertion              `json:"assertions,omitempty"`
+
+	until Condition
+}
+
+// Event is one scripted action, triggered once either at a scenario time or
+// the first time a condition holds.
+type Event struct {
+	At   *Duration `json:"at,omitempty"`   // time since the scenario start
+	When string    `json:"when,omitempty"` // e.g. "altitude > 2000"
+
+	Command *models.Command     `json:"command,omitempty"`
+	State   *models.StateUpdate `json:"state,omitempty"` // reposition the aircraft
+	Wind    *models.WindVector  `json:"wind,omitempty"`
+	Inject  string              `json:"inject,omitempty"` // failure to inject, e.g. "engine_failure"
+	Clear   string              `json:"clear,omitempty"`  // failure to clear
+
+	when Condition
+}
+
+// Assertion is a condition checked while the scenario runs. Exactly one of
+// its fields is set.
+type Assertion struct {
+	Always     string `json:"always,omitempty"`     // must hold in every state
+	Eventually string `json:"eventually,omitempty"` // must hold in at least one state
+	AtEnd      string `json:"at_end,omitempty"`     // must hold in the final state
+
+	condition Condition
+}
+
+// kind returns the assertion kind and its condition text.
+func (a Assertion) kind() (models.AssertionKind, string) {
+	switch {
+	case a.Always != "":
+		return models.AssertionAlways, a.Always
+	case a.Eventually != "":
+		return models.AssertionEventually, a.Eventually
+	default:
+		return models.AssertionAtEnd, a.AtEnd
+	}
+}
+
+// Duration is a time.Duration written as a Go duration string ("90s",
+// "1m30s") or a number of seconds.
+type Duration time.Duration
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var seconds float64
+	if err := json.Unmarshal(data, &seconds); err == nil {
+		*d = Duration(seconds * float64(time.Second))
+		return nil
+	}
+
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return fmt.Errorf("duration must be a string or a number of seconds")
+	}
+	parsed, err := time.ParseDuration(text)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Seconds returns the duration in seconds.
+func (d Duration) Seconds() float64 {
+	return time.Duration(d).Seconds()
+}
+
+// Load reads a scenario file.
+func Load(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse decodes a YAML or JSON scenario. Call Validate before running it.
+func Parse(data []byte) (*Scenario, error) {
+	// Decode the YAML generically, then through JSON so that scenarios share
+	// the field names and types of the API models
+	var raw any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrInvalidScenario, err)
+	}
+	if raw == nil {
+		return nil, fmt.Errorf("%w: empty scenario", models.ErrInvalidScenario)
+	}
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrInvalidScenario, err)
+	}
+
+	var sc Scenario
+	if err := json.Unmarshal(encoded, &sc); err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrInvalidScenario, err)
+	}
+	return &sc, nil
+}
+
+// Validate checks the scenario and compiles its conditions against the speed
+// and climb limits of simCfg.
+func (sc *Scenario) Validate(simCfg config.SimulationConfig) error {
+	if sc.Duration < 0 {
+		return invalid("negative duration")
+	}
+	if sc.InitialState != nil {
+		if err := validateState(sc.InitialState, simCfg); err != nil {
+			return invalid("initial_state: %v", err)
+		}
+	}
+	if sc.Environment != nil && sc.Environment.Wind != nil {
+		if err := validateWind(sc.Environment.Wind); err != nil {
+			return invalid("environment: %v", err)
+		}
+	}
+
+	var err error
+	if sc.Until != "" {
+		if sc.until, err = ParseCondition(sc.Until); err != nil {
+			return invalid("until: %v", err)
+		}
+	}
+
+	for i := range sc.Events {
+		if err := sc.Events[i].validate(simCfg); err != nil {
+			return invalid("event %d: %v", i+1, err)
+		}
+	}
+
+	for i := range sc.Assertions {
+		a := &sc.Assertions[i]
+		set := 0
+		for _, text := range []string{a.Always, a.Eventually, a.AtEnd} {
+			if text != "" {
+				set++
+			}
+		}
+		if set != 1 {
+			return invalid("assertion %d: set exactly one of always, eventually and at_end", i+1)
+		}
+		_, text := a.kind()
+		if a.condition, err = ParseCondition(text); err != nil {
+			return invalid("assertion %d: %v", i+1, err)
+		}
+	}
+	return nil
+}
+
+// UsesWind reports whether the scenario changes the wind.
+func (sc *Scenario) UsesWind() bool {
+	if sc.Environment != nil && sc.Environment.Wind != nil {
+		return true
+	}
+	for _, event := range sc.Events {
+		if event.Wind != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// duration returns the simulation time limit.
+func (sc *Scenario) duration() time.Duration {
+	if sc.Duration == 0 {
+		return defaultDuration
+	}
+	return time.Duration(sc.Duration)
+}
+
+// validate checks an event and compiles its condition.
+func (e *Event) validate(simCfg config.SimulationConfig) error {
+	switch {
+	case e.At == nil && e.When == "":
+		return fmt.Errorf("set at or when")
+	case e.At != nil && e.When != "":
+		return fmt.Errorf("set only one of at and when")
+	case e.At != nil && *e.At < 0:
+		return fmt.Errorf("negative time")
+	}
+	if e.When != "" {
+		var err error
+		if e.when, err = ParseCondition(e.When); err != nil {
+			return err
+		}
+	}
+
+	if e.Command == nil && e.State == nil && e.Wind == nil && e.Inject == "" && e.Clear == "" {
+		return fmt.Errorf("no action: set command, state, wind, inject or clear")
+	}
+	if e.Command != nil {
+		if err := validateCommand(e.Command, simCfg.MaxSpeed); err != nil {
+			return fmt.Errorf("command: %w", err)
+		}
+	}
+	if e.State != nil {
+		if err := validateState(e.State, simCfg); err != nil {
+			return fmt.Errorf("state: %w", err)
+		}
+	}
+	if e.Wind != nil {
+		if err := validateWind(e.Wind); err != nil {
+			return err
+		}
+	}
+	for _, failure := range []string{e.Inject, e.Clear} {
+		if failure != "" && failure != FailureEngine {
+			return fmt.Errorf("unknown failure %q (supported: %s)", failure, FailureEngine)
+		}
+	}
+	return nil
+}
+
+// validateCommand checks a scripted command like the API checks submitted
+// validateState validates a state update against the limits of simCfg.
+func validateState(update *models.StateUpdate, simCfg config.SimulationConfig) error {
+	return validation.ValidateStateUpdate(update, simCfg.MaxSpeed, simCfg.MaxClimbRate, simCfg.MaxDescentRate)
+}
+
+// commands, filling in empty land and return-to-home parameters.
+func validateCommand(cmd *models.Command, maxSpeed float64) error {
+	var err error
+	switch cmd.Type {
+	case models.CommandTypeGoTo:
+		if cmd.GoTo == nil {
+			return fmt.Errorf("goto command without goto parameters")
+		}
+		err = validation.ValidateGoToCommand(cmd.GoTo, maxSpeed)
+	case models.CommandTypeTrajectory:
+		if cmd.Trajectory == nil {
+			return fmt.Errorf("trajectory command without trajectory parameters")
+		}
+		err = validation.ValidateTrajectoryCommand(cmd.Trajectory, maxSpeed)
+	case models.CommandTypeTakeoff:
+		if cmd.Takeoff == nil {
+			return fmt.Errorf("takeoff command without takeoff parameters")
+		}
+		err = validation.ValidateTakeoffCommand(cmd.Takeoff, maxSpeed)
+	case models.CommandTypeLand:
+		if cmd.Land == nil {
+			cmd.Land = &models.LandCommand{}
+		}
+		err = validation.ValidateLandCommand(cmd.Land)
+	case models.CommandTypeRTH:
+		if cmd.RTH == nil {
+			cmd.RTH = &models.RTHCommand{}
+		}
+		if cmd.RTH.Altitude != nil && *cmd.RTH.Altitude < 0 {
+			err = fmt.Errorf("%w: %f", models.ErrInvalidAltitude, *cmd.RTH.Altitude)
+		}
+	case models.CommandTypeHold, models.CommandTypeStop:
+	default:
+		return fmt.Errorf("unknown command type %q", cmd.Type)
+	}
+	return err
+}
+
+// validateWind checks a wind vector.
+func validateWind(wind *models.WindVector) error {
+	if wind.Direction < 0 || wind.Direction >= 360 {
+		return fmt.Errorf("wind direction %f out of range (0 to 360)", wind.Direction)
+	}
+	if wind.Speed < 0 {
+		return fmt.Errorf("negative wind speed %f", wind.Speed)
+	}
+	return nil
+}
+
+// invalid returns a validation error wrapping models.ErrInvalidScenario.
+func invalid(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", models.ErrInvalidScenario, strings.TrimSpace(fmt.Sprintf(format, args...)))
+}
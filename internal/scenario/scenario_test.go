@@ -0,0 +1,282 @@
+package scenario
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/config"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/pubsub"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/simulator"
+)
+
+// testLimits are the speed and climb limits scenarios are validated against.
+var testLimits = config.SimulationConfig{MaxSpeed: 250, MaxClimbRate: 15, MaxDescentRate: 10}
+
+func TestLoad_ExampleScenario(t *testing.T) {
+	sc, err := Load("../../configs/scenarios/engine-failure.yaml")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if err := sc.Validate(testLimits); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	if sc.Name != "engine-failure-after-waypoint-2" || sc.InitialState.Position.Altitude != 1500 {
+		t.Errorf("Load() = %+v, want the example scenario", sc)
+	}
+	if sc.duration() != 15*time.Minute || !sc.UsesWind() {
+		t.Errorf("duration = %v, uses wind = %v, want 15m with wind", sc.duration(), sc.UsesWind())
+	}
+	if len(sc.Events) != 3 || sc.Events[0].At == nil || sc.Events[0].Command.Trajectory == nil ||
+		len(sc.Events[0].Command.Trajectory.Waypoints) != 3 || sc.Events[2].Inject != FailureEngine {
+		t.Errorf("events = %+v, want a trajectory, a wind change and an engine failure", sc.Events)
+	}
+	if len(sc.Assertions) != 3 {
+		t.Errorf("assertions = %+v, want 3", sc.Assertions)
+	}
+}
+
+func TestScenario_Validate(t *testing.T) {
+	tests := []struct {
+		name  string
+		yaml  string
+		valid bool
+	}{
+		{"minimal", "name: empty", true},
+		{"duration in seconds", "duration: 90\nuntil: phase == landed", true},
+		{"json", `{"name": "json", "events": [{"at": "1s", "command": {"type": "hold"}}]}`, true},
+		{"event without trigger", "events: [{command: {type: hold}}]", false},
+		{"event with two triggers", "events: [{at: 1s, when: altitude > 1, command: {type: hold}}]", false},
+		{"event without action", "events: [{at: 1s}]", false},
+		{"unknown command", "events: [{at: 1s, command: {type: loop}}]", false},
+		{"goto without target", "events: [{at: 1s, command: {type: goto}}]", false},
+		{"goto too fast", "events: [{at: 1s, command: {type: goto, goto: {target: {latitude: 32, longitude: 34, altitude: 100}, speed: 400}}}]", false},
+		{"unknown failure", "events: [{at: 1s, inject: fire}]", false},
+		{"invalid wind", "events: [{at: 1s, wind: {direction: 400, speed: 5}}]", false},
+		{"invalid initial state", "initial_state: {position: {latitude: 95, longitude: 34, altitude: 100}}", false},
+		{"assertion with two kinds", "assertions: [{always: altitude > 0, at_end: altitude > 0}]", false},
+		{"unknown variable", "assertions: [{always: fuel > 0}]", false},
+		{"invalid duration", "duration: soon", false},
+		{"not yaml", "name: [", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc, err := Parse([]byte(tt.yaml))
+			if err == nil {
+				err = sc.Validate(testLimits)
+			}
+			if tt.valid && err != nil {
+				t.Errorf("Parse/Validate() error = %v", err)
+			}
+			if !tt.valid && !errors.Is(err, models.ErrInvalidScenario) {
+				t.Errorf("Parse/Validate() error = %v, want %v", err, models.ErrInvalidScenario)
+			}
+		})
+	}
+}
+
+func TestParseCondition(t *testing.T) {
+	index := 2
+	state := models.AircraftState{
+		Position:      models.Position{Latitude: 32, Longitude: 34, Altitude: 2100},
+		Velocity:      models.Velocity{GroundSpeed: 100, VerticalSpeed: -3},
+		Phase:         models.FlightPhaseDescent,
+		ActiveCommand: &models.CommandInfo{Type: "trajectory", WaypointIndex: &index},
+		EngineFailure: true,
+	}
+	o := observation{state: state, elapsed: 30}
+
+	tests := []struct {
+		text  string
+		holds bool
+		err   bool
+	}{
+		{text: "altitude > 2000", holds: true},
+		{text: "altitude>=2100", holds: true},
+		{text: "altitude < 2000", holds: false},
+		{text: "time >= 30 and phase == descent", holds: true},
+		{text: "time >= 30 and phase == climb", holds: false},
+		{text: "waypoint >= 2", holds: true},
+		{text: "command != none", holds: true},
+		{text: "engine_failure == true", holds: true},
+		{text: "link_lost == true", holds: false},
+		{text: "vertical_speed < 0", holds: true},
+		{text: "height_agl == 2100", holds: true},
+		{text: "fuel > 0", err: true},
+		{text: "altitude > high", err: true},
+		{text: "phase > landed", err: true},
+		{text: "link_lost == maybe", err: true},
+		{text: "altitude", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			cond, err := ParseCondition(tt.text)
+			if tt.err {
+				if err == nil {
+					t.Errorf("ParseCondition() error = nil, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseCondition() error = %v", err)
+			}
+			if got := cond.holds(o); got != tt.holds {
+				t.Errorf("holds() = %v, want %v", got, tt.holds)
+			}
+		})
+	}
+}
+
+func TestRun_Step(t *testing.T) {
+	sc, err := Parse([]byte(`
+name: step
+duration: 60s
+until: phase == landed
+events:
+  - at: 10s
+    command: {type: hold}
+  - when: altitude < 500
+    inject: engine_failure
+assertions:
+  - always: altitude >= 0
+  - eventually: altitude < 500
+  - at_end: phase == landed
+  - eventually: ground_speed > 200
+`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if err := sc.Validate(testLimits); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	r := newRun(sc)
+
+	step := func(elapsed, altitude float64, phase models.FlightPhase) ([]*Event, bool) {
+		state := models.AircraftState{Position: models.Position{Altitude: altitude}, Phase: phase}
+		return r.step(observation{state: state, elapsed: elapsed})
+	}
+
+	if due, done := step(5, 1000, models.FlightPhaseCruise); len(due) != 0 || done {
+		t.Fatalf("step(5s) = %d events, done %v, want none", len(due), done)
+	}
+	if due, _ := step(10, 800, models.FlightPhaseCruise); len(due) != 1 || due[0].Command == nil {
+		t.Fatalf("step(10s) = %+v, want the hold command", due)
+	}
+	if due, _ := step(20, 400, models.FlightPhaseDescent); len(due) != 1 || due[0].Inject != FailureEngine {
+		t.Fatalf("step(20s) = %+v, want the engine failure", due)
+	}
+	// Events fire once
+	if due, _ := step(21, 300, models.FlightPhaseDescent); len(due) != 0 {
+		t.Fatalf("step(21s) = %+v, want no events", due)
+	}
+	if _, done := step(30, 0, models.FlightPhaseLanded); !done {
+		t.Fatal("step() did not end the scenario when the until condition was met")
+	}
+
+	status := r.snapshot()
+	if status.State != models.ScenarioFailed || status.EventsFired != 2 || status.Elapsed != 30 {
+		t.Errorf("status = %+v, want failed after 2 events at 30 s", status)
+	}
+	want := []string{"passed", "passed", "passed", "failed"}
+	for i, result := range status.Assertions {
+		if result.Status != want[i] {
+			t.Errorf("assertion %d (%s %s) = %s, want %s", i, result.Kind, result.Condition, result.Status, want[i])
+		}
+	}
+	if *status.Assertions[1].Time != 20 {
+		t.Errorf("eventually decided at %.0f s, want 20 s", *status.Assertions[1].Time)
+	}
+}
+
+func TestManager_RunsScenario(t *testing.T) {
+	simCfg := config.SimulationConfig{
+		TickRateHz:        50,
+		CommandQueueSize:  10,
+		InitialPosition:   config.PositionConfig{Latitude: 32.0, Longitude: 34.0, Altitude: 1000},
+		DefaultSpeed:      100,
+		MaxSpeed:          250,
+		MaxClimbRate:      15,
+		MaxDescentRate:    10,
+		PositionTolerance: 10,
+		HeadingChangeRate: 30,
+		SpeedChangeRate:   50,
+	}
+	envCfg := config.EnvironmentConfig{Enabled: true}
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	sim, err := simulator.New(simCfg, envCfg, logger)
+	if err != nil {
+		t.Fatalf("simulator.New() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go sim.Run(ctx)
+
+	sc, err := Parse([]byte(`
+name: climb
+initial_state:
+  position: {latitude: 31.5, longitude: 34.5, altitude: 500}
+  heading: 90
+  ground_speed: 100
+environment:
+  wind: {direction: 270, speed: 10}
+duration: 1s
+events:
+  - at: 0s
+    command: {type: goto, goto: {target: {latitude: 31.5, longitude: 34.6, altitude: 2000}}}
+  - when: time >= 0.5
+    inject: engine_failure
+assertions:
+  - always: longitude >= 34.5
+  - eventually: command == goto
+  - at_end: engine_failure == true and vertical_speed < 0
+`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	manager := NewManager(sim, simCfg, logger)
+	started, err := manager.Start(sc)
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if _, err := manager.Start(sc); !errors.Is(err, models.ErrScenarioRunning) {
+		t.Errorf("second Start() error = %v, want %v", err, models.ErrScenarioRunning)
+	}
+
+	// The runner must see every state
+	deadline := time.Now().Add(time.Second)
+	for sim.GetPublisher().SubscriberCount() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	subscribers := sim.GetPublisher().Subscribers()
+	if len(subscribers) != 1 || subscribers[0].Policy != string(pubsub.PolicyDisconnect) ||
+		subscribers[0].MaxDrops == nil || *subscribers[0].MaxDrops != 0 {
+		t.Errorf("state subscribers = %+v, want the runner, evicted at its first drop", subscribers)
+	}
+
+	waitCtx, waitCancel := context.WithTimeout(ctx, 5*time.Second)
+	defer waitCancel()
+	status, err := manager.Wait(waitCtx, started.ID)
+	if err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if status.State != models.ScenarioPassed || status.EventsFired != 2 || status.FinishedAt == nil {
+		t.Errorf("status = %+v, want passed with 2 events", status)
+	}
+
+	state, _ := sim.GetState(ctx)
+	if state.Environment == nil || state.Environment.Wind == nil || state.Environment.Wind.Speed != 10 {
+		t.Errorf("wind = %+v, want the scenario wind", state.Environment)
+	}
+	if list := manager.List(); len(list) != 1 || list[0].ID != started.ID {
+		t.Errorf("List() = %+v, want the finished run", list)
+	}
+}
@@ -0,0 +1,252 @@
+// Package session writes and reads session logs: an append-only record of
+// everything that drives a simulation run, from which the run can be
+// replayed exactly.
+//
+// A session log is a JSON Lines file. The first line is the Header with the
+// configuration and initial state; every following line is an Entry tagged
+// with the simulation tick it was applied before.
+package session
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/config"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+)
+
+// Version is the session log format version.
+const Version = 1
+
+// maxLineBytes bounds a single log line (a large trajectory or airspace zone).
+const maxLineBytes = 16 << 20
+
+// EntryType identifies what a session log entry records.
+type EntryType string
+
+const (
+	EntryCommand        EntryType = "command"
+	EntryHeartbeat      EntryType = "heartbeat"
+	EntryGeofenceAdd    EntryType = "geofence_add"
+	EntryGeofenceUpdate EntryType = "geofence_update"
+	EntryGeofenceDelete EntryType = "geofence_delete"
+	EntryRestore        EntryType = "restore"    // snapshot restore or state update
+	EntryConditions     EntryType = "conditions" // wind change or engine failure
+	EntryEnd            EntryType = "end"        // written when the simulator stops
+)
+
+// Header is the first line of a session log.
+type Header struct {
+	Version      int                      `json:"version"`
+	StartedAt    time.Time                `json:"started_at"` // wall-clock time at simulation time zero
+	Simulation   config.SimulationConfig  `json:"simulation"`
+	Environment  config.EnvironmentConfig `json:"environment"`
+	InitialState models.AircraftState     `json:"initial_state"`
+	Geofences    []models.Geofence        `json:"geofences,omitempty"` // zones loaded before the first tick
+}
+
+// Entry is one recorded event. Tick is the number of simulation ticks that
+// had run when the event was applied.
+type Entry struct {
+	Tick       uint64                   `json:"tick"`
+	Time       time.Time                `json:"time"` // wall-clock time, informational
+	Type       EntryType                `json:"type"`
+	Command    *models.Command          `json:"command,omitempty"`
+	Geofence   *models.Geofence         `json:"geofence,omitempty"`
+	GeofenceID string                   `json:"geofence_id,omitempty"`
+	Snapshot   *models.Snapshot         `json:"snapshot,omitempty"`
+	Conditions *models.ConditionsUpdate `json:"conditions,omitempty"`
+}
+
+// Log is a session log read back for replay.
+type Log struct {
+	Header  Header
+	Entries []Entry // ordered by tick
+}
+
+// EndTick returns the tick at which the recorded session stopped, or false
+// if the log has no end entry (the simulator did not shut down cleanly).
+func (l *Log) EndTick() (uint64, bool) {
+	if n := len(l.Entries); n > 0 && l.Entries[n-1].Type == EntryEnd {
+		return l.Entries[n-1].Tick, true
+	}
+	return 0, false
+}
+
+// Writer appends entries to a session log file. Every entry is written with
+// a single write call, so a crash loses at most the entry being written.
+// It is safe for concurrent use; a nil Writer discards entries.
+type Writer struct {
+	mu   sync.Mutex
+	file *os.File
+	path string
+	err  error // first write error, later entries are dropped
+}
+
+// Create creates a new session log in dir, named after the start time, and
+// writes its header.
+func Create(dir string, header Header) (*Writer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create session directory: %w", err)
+	}
+
+	name := "session-" + header.StartedAt.UTC().Format("20060102T150405Z") + ".log"
+	path := filepath.Join(dir, name)
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session log: %w", err)
+	}
+
+	header.Version = Version
+	w := &Writer{file: file, path: path}
+	if err := w.writeLine(header); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to write session header: %w", err)
+	}
+	return w, nil
+}
+
+// Path returns the session log file path.
+func (w *Writer) Path() string {
+	if w == nil {
+		return ""
+	}
+	return w.path
+}
+
+// Append writes an entry. Write errors are kept and returned by Close.
+func (w *Writer) Append(entry Entry) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.err != nil || w.file == nil {
+		return
+	}
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	w.err = w.writeLine(entry)
+}
+
+// Close closes the log file and returns the first write error, if any.
+func (w *Writer) Close() error {
+	if w == nil {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return w.err
+	}
+	if err := w.file.Close(); err != nil && w.err == nil {
+		w.err = err
+	}
+	w.file = nil
+	return w.err
+}
+
+// writeLine encodes v as one line. The caller holds the lock, or owns w.
+func (w *Writer) writeLine(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.file.Write(append(data, '\n'))
+	return err
+}
+
+// Open reads a session log file.
+func Open(path string) (*Log, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open session log: %w", err)
+	}
+	defer file.Close()
+
+	return Read(file)
+}
+
+// Read reads a session log. A truncated last line, left by a crash while it
+// was being written, is ignored.
+func Read(r io.Reader) (*Log, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineBytes)
+
+	var lines [][]byte
+	for scanner.Scan() {
+		if line := bytes.TrimSpace(scanner.Bytes()); len(line) > 0 {
+			lines = append(lines, append([]byte(nil), line...))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read session log: %w", err)
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("session log is empty")
+	}
+
+	log := &Log{}
+	if err := json.Unmarshal(lines[0], &log.Header); err != nil {
+		return nil, fmt.Errorf("invalid session header: %w", err)
+	}
+	if log.Header.Version != Version {
+		return nil, fmt.Errorf("unsupported session log version %d", log.Header.Version)
+	}
+
+	for i, line := range lines[1:] {
+		var entry Entry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			if i == len(lines)-2 {
+				break // truncated last line
+			}
+			return nil, fmt.Errorf("invalid session entry on line %d: %w", i+2, err)
+		}
+		if err := checkEntry(entry); err != nil {
+			return nil, fmt.Errorf("invalid session entry on line %d: %w", i+2, err)
+		}
+		log.Entries = append(log.Entries, entry)
+	}
+
+	return log, nil
+}
+
+// checkEntry verifies that an entry carries the data its type needs.
+func checkEntry(entry Entry) error {
+	switch entry.Type {
+	case EntryCommand:
+		if entry.Command == nil {
+			return fmt.Errorf("command entry without a command")
+		}
+	case EntryGeofenceAdd, EntryGeofenceUpdate:
+		if entry.Geofence == nil {
+			return fmt.Errorf("%s entry without a geofence", entry.Type)
+		}
+	case EntryGeofenceDelete:
+		if entry.GeofenceID == "" {
+			return fmt.Errorf("geofence_delete entry without a geofence_id")
+		}
+	case EntryRestore:
+		if entry.Snapshot == nil {
+			return fmt.Errorf("restore entry without a snapshot")
+		}
+	case EntryConditions:
+		if entry.Conditions == nil {
+			return fmt.Errorf("conditions entry without conditions")
+		}
+	case EntryHeartbeat, EntryEnd:
+	default:
+		return fmt.Errorf("unknown entry type %q", entry.Type)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package session
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/config"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+)
+
+func TestWriterAndOpen(t *testing.T) {
+	started := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	header := Header{
+		StartedAt:    started,
+		Simulation:   config.SimulationConfig{TickRateHz: 20, DefaultSpeed: 100},
+		InitialState: models.AircraftState{Position: models.Position{Latitude: 32.1, Longitude: 34.8, Altitude: 1000}},
+		Geofences:    []models.Geofence{{ID: "zone-1", Name: "Restricted"}},
+	}
+
+	w, err := Create(t.TempDir(), header)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if !strings.HasSuffix(w.Path(), "session-20240501T100000Z.log") {
+		t.Errorf("Path() = %q", w.Path())
+	}
+
+	cmd := models.NewCommand(models.CommandTypeHold)
+	w.Append(Entry{Tick: 3, Type: EntryCommand, Command: cmd})
+	w.Append(Entry{Tick: 4, Type: EntryGeofenceDelete, GeofenceID: "zone-1"})
+	w.Append(Entry{Tick: 5, Type: EntryHeartbeat})
+	w.Append(Entry{Tick: 9, Type: EntryEnd})
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	log, err := Open(w.Path())
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if log.Header.Version != Version || !log.Header.StartedAt.Equal(started) ||
+		log.Header.Simulation.TickRateHz != 20 || log.Header.InitialState.Position.Altitude != 1000 ||
+		len(log.Header.Geofences) != 1 {
+		t.Errorf("Header = %+v", log.Header)
+	}
+
+	wantTypes := []EntryType{EntryCommand, EntryGeofenceDelete, EntryHeartbeat, EntryEnd}
+	if len(log.Entries) != len(wantTypes) {
+		t.Fatalf("got %d entries, want %d", len(log.Entries), len(wantTypes))
+	}
+	for i, want := range wantTypes {
+		if log.Entries[i].Type != want {
+			t.Errorf("entry %d type = %s, want %s", i, log.Entries[i].Type, want)
+		}
+	}
+	if log.Entries[0].Command.ID != cmd.ID {
+		t.Errorf("command ID = %q, want %q", log.Entries[0].Command.ID, cmd.ID)
+	}
+	if end, ok := log.EndTick(); !ok || end != 9 {
+		t.Errorf("EndTick() = %d, %v, want 9, true", end, ok)
+	}
+
+	var disabled *Writer
+	disabled.Append(Entry{Type: EntryHeartbeat})
+	if err := disabled.Close(); err != nil {
+		t.Errorf("nil Writer Close() error = %v", err)
+	}
+}
+
+func TestRead(t *testing.T) {
+	const header = `{"version":1,"started_at":"2024-05-01T10:00:00Z","simulation":{},"environment":{},"initial_state":{"position":{"latitude":32,"longitude":34,"altitude":0},"velocity":{"ground_speed":0,"vertical_speed":0},"heading":0,"timestamp":"2024-05-01T10:00:00Z","sim_time":0,"phase":"parked"}}`
+
+	tests := []struct {
+		name    string
+		data    string
+		entries int
+		wantErr string
+	}{
+		{"header only", header + "\n", 0, ""},
+		{"truncated last line", header + "\n" + `{"tick":1,"type":"heartbeat"}` + "\n" + `{"tick":2,"ty`, 1, ""},
+		{"empty", "", 0, "empty"},
+		{"unsupported version", `{"version":2}` + "\n", 0, "version"},
+		{"invalid entry", header + "\n" + `{"tick":1` + "\n" + `{"tick":2,"type":"heartbeat"}` + "\n", 0, "line 2"},
+		{"unknown type", header + "\n" + `{"tick":1,"type":"teleport"}` + "\n", 0, "unknown entry type"},
+		{"command without body", header + "\n" + `{"tick":1,"type":"command"}` + "\n", 0, "without a command"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log, err := Read(strings.NewReader(tt.data))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Read() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if len(log.Entries) != tt.entries {
+				t.Errorf("got %d entries, want %d", len(log.Entries), tt.entries)
+			}
+			if _, ok := log.EndTick(); ok {
+				t.Error("EndTick() reported an end for a log without one")
+			}
+		})
+	}
+}
+
+func TestCreate_DoesNotOverwrite(t *testing.T) {
+	dir := t.TempDir()
+	header := Header{StartedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)}
+
+	w, err := Create(dir, header)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	defer w.Close()
+
+	if _, err := Create(dir, header); err == nil {
+		t.Error("Create() overwrote an existing session log")
+	}
+	if _, err := os.Stat(w.Path()); err != nil {
+		t.Errorf("session log missing: %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package simulator
+
+import (
+	"context"
+	"time"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+)
+
+// glideRatio is the distance flown per unit of height lost with the engine
+// failed.
+const glideRatio = 10.0
+
+// conditionsRequest asks the simulation loop to change the simulated
+// conditions.
+type conditionsRequest struct {
+	update models.ConditionsUpdate
+	reply  chan struct{}
+}
+
+// SetConditions changes the wind or injects or clears an engine failure
+// between two ticks. Changing the wind needs the environment to be enabled.
+func (s *Simulator) SetConditions(ctx context.Context, update models.ConditionsUpdate) error {
+	if s.replay != nil {
+		return models.ErrReplayActive
+	}
+	if update.Wind != nil && !s.environment.IsEnabled() {
+		return models.ErrEnvironmentDisabled
+	}
+
+	req := conditionsRequest{
+		update: update,
+		reply:  make(chan struct{}, 1),
+	}
+
+	select {
+	case s.conditionRequests <- req:
+		<-req.reply
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(1 * time.Second):
+		return models.ErrTimeout
+	}
+}
+
+// applyConditions changes the simulated conditions.
+func (s *Simulator) applyConditions(update models.ConditionsUpdate) {
+	if update.Wind != nil {
+		s.environment.SetWind(update.Wind)
+		s.logger.Info("Wind changed", "direction", update.Wind.Direction, "speed_ms", update.Wind.Speed)
+		s.emit(models.Event{
+			Type:       models.EventEnvironmentChanged,
+			Message:    "Wind changed",
+			Conditions: &models.ConditionsUpdate{Wind: update.Wind},
+		})
+	}
+	if update.EngineFailure != nil && *update.EngineFailure != s.state.EngineFailure {
+		s.state.EngineFailure = *update.EngineFailure
+		message := "Engine restored"
+		if s.state.EngineFailure {
+			message = "Engine failure"
+			s.logger.Warn("Engine failure", "position", s.state.Position)
+		} else {
+			s.logger.Info("Engine restored", "position", s.state.Position)
+		}
+		s.emit(models.Event{
+			Type:       models.EventEnvironmentChanged,
+			Message:    message,
+			Conditions: &models.ConditionsUpdate{EngineFailure: update.EngineFailure},
+		})
+	}
+}
+
+// burnFuel burns the fuel used over deltaTime seconds. The flow grows from
+// the idle flow at rest to the cruise flow at the default speed, plus the
+// climb flow per m/s of climb. An empty tank fails the engine.
+func (s *Simulator) burnFuel(deltaTime float64) {
+	if s.state.Fuel == nil || s.state.EngineFailure {
+		return
+	}
+	switch s.state.Phase {
+	case models.FlightPhaseParked, models.FlightPhaseLanded:
+		return
+	}
+
+	cfg := s.config.Fuel
+	flow := cfg.IdleFlow + cfg.ClimbFlow*max(s.state.Velocity.VerticalSpeed, 0)
+	if s.config.DefaultSpeed > 0 {
+		flow += (cfg.CruiseFlow - cfg.IdleFlow) * s.state.Velocity.GroundSpeed / s.config.DefaultSpeed
+	}
+	// A new value, as published states share the pointer
+	fuel := max(*s.state.Fuel-max(flow, 0)*deltaTime/3600, 0)
+	s.state.Fuel = &fuel
+	if fuel > 0 {
+		return
+	}
+
+	failed := true
+	s.state.EngineFailure = true
+	s.logger.Warn("Fuel exhausted", "position", s.state.Position)
+	s.emit(models.Event{
+		Type:       models.EventEnvironmentChanged,
+		Message:    "Fuel exhausted",
+		Conditions: &models.ConditionsUpdate{EngineFailure: &failed},
+	})
+}
+
+// glideVerticalSpeed limits a vertical speed while the engine is failed:
+// the aircraft cannot climb and sinks at least at the glide ratio.
+func (s *Simulator) glideVerticalSpeed(verticalSpeed, groundSpeed float64) float64 {
+	if !s.state.EngineFailure || s.onGround() {
+		return verticalSpeed
+	}
+	return min(verticalSpeed, -groundSpeed/glideRatio)
+}
+
+// forcedLanding ends an engine-out glide on the ground.
+func (s *Simulator) forcedLanding() {
+	s.logger.Warn("Forced landing", "position", s.state.Position)
+	s.cancelCommand("Forced landing")
+	s.trajectoryState = nil
+	s.rthState = nil
+	s.takeoffState = nil
+	s.landingState = nil
+	s.state.Velocity = models.Velocity{}
+	s.setPhase(models.FlightPhaseLanded)
+}
@@ -0,0 +1,75 @@
+package simulator
+
+import (
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/pubsub"
+)
+
+const (
+	// eventHistorySize is the number of recent events kept for polling
+	// clients.
+	eventHistorySize = 1000
+
+	// streamHistorySize is the number of recent states and events kept for
+	// stream clients resuming after a reconnect, about 30 seconds of states
+	// at 30 Hz.
+	streamHistorySize = 1000
+)
+
+// GetEvents returns the event bus for event polling.
+func (s *Simulator) GetEvents() *pubsub.EventBus {
+	return s.events
+}
+
+// GetEventBroker returns the broker of the events, published on EventTopic
+// by type.
+func (s *Simulator) GetEventBroker() *pubsub.Broker[models.Event] {
+	return s.eventBroker
+}
+
+// emit publishes an event at the current state. Like states, events are not
+// published while seeking a replay.
+func (s *Simulator) emit(event models.Event) {
+	if s.muted {
+		return
+	}
+	event.Timestamp = s.now()
+	event.SimTime = s.state.SimTime
+	event.Position = s.state.Position
+	event = s.events.Publish(event)
+	s.eventBroker.Publish(EventTopic(s.aircraftID, event.Type), event)
+}
+
+// emitCommand publishes an event about a command.
+func (s *Simulator) emitCommand(eventType models.EventType, cmd *models.Command, message string) {
+	s.emit(models.Event{
+		Type:    eventType,
+		Message: message,
+		Command: &models.EventCommand{ID: cmd.ID, Type: cmd.Type},
+	})
+}
+
+// receiveCommand starts a command received from a client.
+func (s *Simulator) receiveCommand(cmd *models.Command) {
+	s.restoreLink()
+	s.emitCommand(models.EventCommandAccepted, cmd, "Command accepted")
+	s.handleCommand(cmd)
+}
+
+// completeCommand ends the active command once it reached its goal.
+func (s *Simulator) completeCommand(message string) {
+	if s.activeCommand == nil {
+		return
+	}
+	s.emitCommand(models.EventCommandCompleted, s.activeCommand, message)
+	s.activeCommand = nil
+}
+
+// cancelCommand drops the active command before it reached its goal.
+func (s *Simulator) cancelCommand(message string) {
+	if s.activeCommand == nil {
+		return
+	}
+	s.emitCommand(models.EventCommandCancelled, s.activeCommand, message)
+	s.activeCommand = nil
+}
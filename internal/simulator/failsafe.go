@@ -0,0 +1,177 @@
+package simulator
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/pkg/geo"
+)
+
+const (
+	// defaultLostLinkTimeout is used when the lost-link watchdog has no timeout configured.
+	defaultLostLinkTimeout = 30 * time.Second
+
+	// altitudeTolerance is how close to a target altitude counts as reached (meters).
+	altitudeTolerance = 5.0
+
+	// touchdownTolerance is the height above ground that counts as landed (meters).
+	touchdownTolerance = 0.5
+)
+
+// Lost-link contingency actions.
+const (
+	lostLinkContinue = "continue"
+	lostLinkHold     = "hold"
+	lostLinkRTH      = "rth"
+	lostLinkLand     = "land"
+)
+
+// rthPhase is a stage of a return-to-home.
+type rthPhase int
+
+const (
+	rthClimb  rthPhase = iota // climbing to the return altitude
+	rthCruise                 // flying home at the return altitude
+)
+
+// rthState tracks progress through a return-to-home.
+type rthState struct {
+	phase    rthPhase
+	altitude float64 // return altitude, meters MSL
+}
+
+// Heartbeat records contact from the controlling client, resetting the
+// lost-link watchdog.
+func (s *Simulator) Heartbeat(ctx context.Context) error {
+	if s.replay != nil {
+		return models.ErrReplayActive
+	}
+
+	select {
+	case s.heartbeats <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(1 * time.Second):
+		return models.ErrTimeout
+	}
+}
+
+// GetHome returns the return-to-home position.
+func (s *Simulator) GetHome() models.Position {
+	return s.home
+}
+
+// executeRTH executes a return-to-home command: climb to the return
+// altitude, fly home, then hold over the home position.
+func (s *Simulator) executeRTH(cmd *models.RTHCommand, deltaTime float64) {
+	if s.rthState == nil {
+		s.rthState = &rthState{phase: rthClimb, altitude: s.rthAltitude(cmd)}
+		s.logger.Info("Returning to home",
+			"command_id", s.activeCommand.ID,
+			"home", s.home,
+			"altitude", s.rthState.altitude,
+		)
+	}
+
+	homeBearing := geo.Bearing(
+		s.state.Position.Latitude,
+		s.state.Position.Longitude,
+		s.home.Latitude,
+		s.home.Longitude,
+	)
+
+	switch s.rthState.phase {
+	case rthClimb:
+		if s.state.Position.Altitude >= s.rthState.altitude-altitudeTolerance {
+			s.logger.Info("Return altitude reached", "command_id", s.activeCommand.ID)
+			s.rthState.phase = rthCruise
+			return
+		}
+
+		// Climb over the current position while turning towards home
+		s.adjustSpeed(0, deltaTime)
+		s.adjustHeading(homeBearing, deltaTime)
+		s.state.Velocity.VerticalSpeed = s.config.MaxClimbRate
+		s.updatePosition(deltaTime, s.state.Velocity)
+
+	case rthCruise:
+		distance := geo.Haversine(
+			s.state.Position.Latitude,
+			s.state.Position.Longitude,
+			s.home.Latitude,
+			s.home.Longitude,
+		)
+		if distance < s.config.PositionTolerance {
+			s.logger.Info("Home reached", "command_id", s.activeCommand.ID)
+			s.completeCommand("Home reached")
+			s.handleCommand(models.NewCommand(models.CommandTypeHold))
+			return
+		}
+
+		target := s.home
+		target.Altitude = s.rthState.altitude
+		s.executeGoTo(&models.GoToCommand{Target: target}, deltaTime)
+	}
+}
+
+// rthAltitude returns the altitude to fly home at: the requested or
+// configured return altitude, but never below home or the current altitude.
+func (s *Simulator) rthAltitude(cmd *models.RTHCommand) float64 {
+	altitude := s.config.RTHAltitude
+	if cmd != nil && cmd.Altitude != nil {
+		altitude = *cmd.Altitude
+	}
+	return math.Max(altitude, math.Max(s.home.Altitude, s.state.Position.Altitude))
+}
+
+// checkLostLink advances the lost-link watchdog and triggers the configured
+// contingency once no contact has been received within the timeout.
+func (s *Simulator) checkLostLink(deltaTime float64) {
+	if !s.config.LostLink.Enabled {
+		return
+	}
+
+	s.sinceContact += deltaTime
+	s.state.LinkLost = s.linkLost
+
+	timeout := s.config.LostLink.Timeout
+	if timeout <= 0 {
+		timeout = defaultLostLinkTimeout
+	}
+	if s.linkLost || s.sinceContact < timeout.Seconds() {
+		return
+	}
+
+	s.linkLost = true
+	s.state.LinkLost = true
+	action := s.config.LostLink.Action
+	s.logger.Warn("Lost link", "seconds_since_contact", s.sinceContact, "action", action)
+
+	switch action {
+	case lostLinkHold:
+		s.handleCommand(models.NewCommand(models.CommandTypeHold))
+	case lostLinkRTH:
+		cmd := models.NewCommand(models.CommandTypeRTH)
+		cmd.RTH = &models.RTHCommand{}
+		s.handleCommand(cmd)
+	case lostLinkLand:
+		s.handleCommand(models.NewCommand(models.CommandTypeLand))
+	case lostLinkContinue, "":
+		// Keep executing the current command
+	default:
+		s.logger.Error("Unknown lost-link action, continuing", "action", action)
+	}
+}
+
+// restoreLink resets the lost-link watchdog after contact from the client.
+func (s *Simulator) restoreLink() {
+	s.sinceContact = 0
+	if s.linkLost {
+		s.linkLost = false
+		s.state.LinkLost = false
+		s.logger.Info("Link restored")
+	}
+}
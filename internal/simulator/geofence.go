@@ -0,0 +1,164 @@
+package simulator
+
+import (
+	"context"
+	"time"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/geofence"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/session"
+)
+
+// geofenceRequest asks the simulation loop to change a geofence.
+type geofenceRequest struct {
+	change geofence.Change
+	reply  chan geofenceReply
+}
+
+// geofenceReply is the stored zone after a change, or why it failed.
+type geofenceReply struct {
+	zone models.Geofence
+	err  error
+}
+
+// AddGeofence stores a geofence between two ticks, assigning an ID if it has
+// none, and returns it. Geofencing must be enabled.
+func (s *Simulator) AddGeofence(ctx context.Context, zone models.Geofence) (models.Geofence, error) {
+	return s.geofenceControl(ctx, geofence.Change{Type: geofence.ChangeAdd, ID: zone.ID, Zone: zone})
+}
+
+// UpdateGeofence replaces an existing geofence between two ticks and returns
+// it.
+func (s *Simulator) UpdateGeofence(ctx context.Context, id string, zone models.Geofence) (models.Geofence, error) {
+	return s.geofenceControl(ctx, geofence.Change{Type: geofence.ChangeUpdate, ID: id, Zone: zone})
+}
+
+// DeleteGeofence removes a geofence between two ticks.
+func (s *Simulator) DeleteGeofence(ctx context.Context, id string) error {
+	_, err := s.geofenceControl(ctx, geofence.Change{Type: geofence.ChangeDelete, ID: id})
+	return err
+}
+
+// geofenceControl sends a geofence change to the simulation loop, so that it
+// takes effect, and is logged for replay, at a known tick.
+func (s *Simulator) geofenceControl(ctx context.Context, change geofence.Change) (models.Geofence, error) {
+	if s.replay != nil {
+		return models.Geofence{}, models.ErrReplayActive
+	}
+	if s.geofences == nil {
+		return models.Geofence{}, models.ErrGeofencingDisabled
+	}
+
+	req := geofenceRequest{
+		change: change,
+		reply:  make(chan geofenceReply, 1),
+	}
+
+	select {
+	case s.geofenceRequests <- req:
+		reply := <-req.reply
+		return reply.zone, reply.err
+	case <-ctx.Done():
+		return models.Geofence{}, ctx.Err()
+	case <-time.After(1 * time.Second):
+		return models.Geofence{}, models.ErrTimeout
+	}
+}
+
+// changeGeofence applies a geofence change and logs it for replay.
+func (s *Simulator) changeGeofence(change geofence.Change) (models.Geofence, error) {
+	entry := session.Entry{GeofenceID: change.ID}
+	var zone models.Geofence
+	var err error
+	switch change.Type {
+	case geofence.ChangeAdd:
+		zone = s.geofences.Add(change.Zone)
+		entry.Type = session.EntryGeofenceAdd
+		entry.GeofenceID = zone.ID
+		entry.Geofence = &zone
+	case geofence.ChangeUpdate:
+		zone, err = s.geofences.Update(change.ID, change.Zone)
+		entry.Type = session.EntryGeofenceUpdate
+		entry.Geofence = &zone
+	case geofence.ChangeDelete:
+		err = s.geofences.Delete(change.ID)
+		entry.Type = session.EntryGeofenceDelete
+	}
+	if err != nil {
+		return models.Geofence{}, err
+	}
+
+	s.recordEntry(entry)
+	return zone, nil
+}
+
+// checkGeofences checks the aircraft against all geofences and applies the
+// breach action for every zone entered since the previous tick.
+func (s *Simulator) checkGeofences() {
+	if s.geofences == nil {
+		return
+	}
+
+	breaches := s.geofences.Check(s.state.Position)
+	s.state.GeofenceBreaches = breaches
+
+	current := make(map[string]bool, len(breaches))
+	for _, breach := range breaches {
+		current[breach.ZoneID] = true
+		if !s.breachedZones[breach.ZoneID] {
+			s.onGeofenceBreach(breach)
+		}
+	}
+	for zoneID := range s.breachedZones {
+		if !current[zoneID] {
+			s.logger.Info("Geofence breach cleared", "zone_id", zoneID)
+			cleared := models.GeofenceBreach{ZoneID: zoneID}
+			if zone, err := s.geofences.Get(zoneID); err == nil {
+				cleared.Name, cleared.Mode = zone.Name, zone.Mode
+			}
+			s.emit(models.Event{
+				Type:     models.EventGeofenceCleared,
+				Message:  "Geofence breach cleared",
+				Geofence: &cleared,
+			})
+		}
+	}
+	s.breachedZones = current
+
+	if len(breaches) == 0 {
+		s.lastSafePosition = s.state.Position
+	}
+}
+
+// onGeofenceBreach applies the breach action for a newly breached zone.
+func (s *Simulator) onGeofenceBreach(breach models.GeofenceBreach) {
+	action := models.BreachAction(s.config.Geofence.DefaultAction)
+	if zone, err := s.geofences.Get(breach.ZoneID); err == nil && zone.BreachAction != "" {
+		action = zone.BreachAction
+	}
+	if action == "" {
+		action = models.BreachActionEvent
+	}
+
+	s.logger.Warn("Geofence breach",
+		"zone_id", breach.ZoneID,
+		"zone_name", breach.Name,
+		"mode", breach.Mode,
+		"action", action,
+		"position", s.state.Position,
+	)
+	s.emit(models.Event{
+		Type:     models.EventGeofenceBreach,
+		Message:  "Geofence breach",
+		Geofence: &breach,
+	})
+
+	switch action {
+	case models.BreachActionHold:
+		s.handleCommand(models.NewCommand(models.CommandTypeHold))
+	case models.BreachActionReturn:
+		cmd := models.NewCommand(models.CommandTypeGoTo)
+		cmd.GoTo = &models.GoToCommand{Target: s.lastSafePosition}
+		s.handleCommand(cmd)
+	}
+}
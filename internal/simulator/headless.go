@@ -0,0 +1,71 @@
+package simulator
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/config"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+)
+
+// Headless steps a simulator synchronously from the caller's goroutine
+// instead of Run, as fast as the CPU allows, for batch runs such as Monte
+// Carlo studies. It offers the same control methods as Simulator, applied
+// immediately before the next Step. A Headless is not safe for concurrent
+// use.
+type Headless struct {
+	sim *Simulator
+}
+
+// NewHeadless creates a headless simulator. The geofences are loaded when
+// geofencing is enabled. Timestamps are derived from the simulation time.
+func NewHeadless(cfg config.SimulationConfig, envCfg config.EnvironmentConfig, geofences []models.Geofence, logger *slog.Logger) (*Headless, error) {
+	s, err := New(cfg, envCfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	s.headless = true
+	s.state.Timestamp = s.startTime
+	if s.geofences != nil {
+		s.geofences.Reset(geofences)
+	}
+	return &Headless{sim: s}, nil
+}
+
+// Step runs one tick and returns the new state.
+func (h *Headless) Step() models.AircraftState {
+	h.sim.tick()
+	return h.sim.state
+}
+
+// TickInterval returns the simulation time advanced by each Step.
+func (h *Headless) TickInterval() float64 {
+	return h.sim.tickerInterval.Seconds()
+}
+
+// GetState returns the current aircraft state.
+func (h *Headless) GetState(ctx context.Context) (models.AircraftState, error) {
+	return h.sim.state, nil
+}
+
+// SubmitCommand starts a command, as received through the command queue.
+func (h *Headless) SubmitCommand(ctx context.Context, cmd *models.Command) error {
+	h.sim.receiveCommand(cmd)
+	return nil
+}
+
+// SetState repositions the aircraft and returns the new state.
+func (h *Headless) SetState(ctx context.Context, update models.StateUpdate) (models.AircraftState, error) {
+	h.sim.restoreSnapshot(h.sim.updatedSnapshot(update))
+	return h.sim.state, nil
+}
+
+// SetConditions changes the wind or injects or clears an engine failure.
+// Changing the wind needs the environment to be enabled.
+func (h *Headless) SetConditions(ctx context.Context, update models.ConditionsUpdate) error {
+	if update.Wind != nil && !h.sim.environment.IsEnabled() {
+		return models.ErrEnvironmentDisabled
+	}
+	h.sim.applyConditions(update)
+	return nil
+}
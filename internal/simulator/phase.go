@@ -0,0 +1,373 @@
+package simulator
+
+import (
+	"math"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/environment"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/pkg/geo"
+)
+
+const (
+	// Defaults used when the takeoff/landing parameters are not configured.
+	defaultTaxiSpeed     = 10.0 // m/s
+	defaultRotationSpeed = 70.0 // m/s
+	defaultApproachSpeed = 70.0 // m/s
+	defaultGlideSlope    = 3.0  // degrees
+
+	// finalApproachDistance is the distance of the final approach fix from
+	// the runway threshold (meters).
+	finalApproachDistance = 5000.0
+
+	// fixCaptureRadius is how close to the final approach fix the aircraft
+	// must get before turning onto final (meters).
+	fixCaptureRadius = 1000.0
+
+	// glideSlopeCorrectionTime is the time over which glide slope deviations
+	// are corrected (seconds).
+	glideSlopeCorrectionTime = 5.0
+
+	// lineUpTolerance is how closely the heading must match the runway
+	// heading before the takeoff roll starts (degrees).
+	lineUpTolerance = 1.0
+
+	// phaseVerticalSpeedThreshold separates climb and descent from cruise (m/s).
+	phaseVerticalSpeedThreshold = 1.0
+)
+
+// takeoffStage is a stage of a takeoff.
+type takeoffStage int
+
+const (
+	takeoffTaxi   takeoffStage = iota // taxiing to the runway threshold
+	takeoffLineUp                     // turning onto the runway heading
+	takeoffRoll                       // accelerating to rotation speed
+	takeoffClimb                      // climbing to the climb-out altitude
+)
+
+// takeoffState tracks progress through a takeoff.
+type takeoffState struct {
+	stage  takeoffStage
+	runway models.Runway
+}
+
+// landingStage is a stage of a runway landing.
+type landingStage int
+
+const (
+	landingToFix   landingStage = iota // flying to the final approach fix
+	landingFinal                       // descending on the glide slope
+	landingRollout                     // decelerating on the runway
+)
+
+// landingState tracks progress through a runway landing.
+type landingState struct {
+	stage landingStage
+}
+
+// onGround reports whether the aircraft is on the ground.
+func (s *Simulator) onGround() bool {
+	return s.state.Phase.OnGround()
+}
+
+// placedPhase returns the flight phase of an aircraft placed at a state
+// without a command: a stationary aircraft on the ground is parked, anything
+// else is cruising until the next tick derives the phase.
+func placedPhase(state models.AircraftState, terrain *environment.TerrainMap) models.FlightPhase {
+	ground := terrain.GetAltitude(state.Position.Latitude, state.Position.Longitude)
+	if state.Position.Altitude <= ground+touchdownTolerance && state.Velocity.GroundSpeed == 0 {
+		return models.FlightPhaseParked
+	}
+	return models.FlightPhaseCruise
+}
+
+// setPhase changes the flight phase, logging transitions.
+func (s *Simulator) setPhase(phase models.FlightPhase) {
+	if phase == s.state.Phase {
+		return
+	}
+	s.logger.Info("Flight phase changed", "from", s.state.Phase, "to", phase)
+	s.emit(models.Event{
+		Type:    models.EventPhaseChanged,
+		Message: "Flight phase changed",
+		Phase:   &models.PhaseChange{From: s.state.Phase, To: phase},
+	})
+	s.state.Phase = phase
+}
+
+// updatePhase derives the airborne flight phase from the vertical speed.
+// Takeoff and landing commands manage the phase themselves, and ground
+// phases only change through them.
+func (s *Simulator) updatePhase() {
+	if s.activeCommand != nil {
+		switch s.activeCommand.Type {
+		case models.CommandTypeTakeoff, models.CommandTypeLand:
+			return
+		}
+	}
+	if s.onGround() {
+		return
+	}
+
+	switch {
+	case s.state.Velocity.VerticalSpeed > phaseVerticalSpeedThreshold:
+		s.setPhase(models.FlightPhaseClimb)
+	case s.state.Velocity.VerticalSpeed < -phaseVerticalSpeedThreshold:
+		s.setPhase(models.FlightPhaseDescent)
+	default:
+		s.setPhase(models.FlightPhaseCruise)
+	}
+}
+
+// allowedOnGround reports whether a command may run while on the ground.
+func allowedOnGround(cmdType models.CommandType) bool {
+	switch cmdType {
+	case models.CommandTypeTakeoff, models.CommandTypeLand, models.CommandTypeStop:
+		return true
+	default:
+		return false
+	}
+}
+
+// executeTakeoff executes a takeoff command: taxi to the runway threshold,
+// line up, accelerate to rotation speed, then climb out on the runway heading.
+func (s *Simulator) executeTakeoff(cmd *models.TakeoffCommand, deltaTime float64) {
+	if s.takeoffState == nil {
+		s.takeoffState = s.newTakeoffState(cmd)
+	}
+	runway := s.takeoffState.runway
+
+	targetSpeed := s.config.DefaultSpeed
+	if cmd.Speed != nil {
+		targetSpeed = *cmd.Speed
+	}
+
+	switch s.takeoffState.stage {
+	case takeoffTaxi:
+		s.setPhase(models.FlightPhaseTaxi)
+		distance := geo.Haversine(
+			s.state.Position.Latitude,
+			s.state.Position.Longitude,
+			runway.Threshold.Latitude,
+			runway.Threshold.Longitude,
+		)
+		if distance < s.config.PositionTolerance {
+			s.state.Velocity.GroundSpeed = 0
+			s.takeoffState.stage = takeoffLineUp
+			return
+		}
+
+		// Taxiing aircraft steer directly towards the threshold
+		s.state.Heading = geo.Bearing(
+			s.state.Position.Latitude,
+			s.state.Position.Longitude,
+			runway.Threshold.Latitude,
+			runway.Threshold.Longitude,
+		)
+		s.adjustSpeed(s.taxiSpeed(), deltaTime)
+		s.state.Velocity.VerticalSpeed = 0
+		s.updatePosition(deltaTime, s.state.Velocity)
+
+	case takeoffLineUp:
+		s.setPhase(models.FlightPhaseTaxi)
+		s.adjustHeading(runway.Heading, deltaTime)
+		if headingDifference(s.state.Heading, runway.Heading) < lineUpTolerance {
+			s.logger.Info("Takeoff roll started", "command_id", s.activeCommand.ID, "heading", runway.Heading)
+			s.takeoffState.stage = takeoffRoll
+		}
+
+	case takeoffRoll:
+		s.setPhase(models.FlightPhaseTakeoffRoll)
+		s.adjustHeading(runway.Heading, deltaTime)
+		s.adjustSpeed(targetSpeed, deltaTime)
+		s.state.Velocity.VerticalSpeed = 0
+		s.updatePosition(deltaTime, s.state.Velocity)
+
+		if s.state.Velocity.GroundSpeed >= math.Min(s.rotationSpeed(), targetSpeed) {
+			s.logger.Info("Rotate", "command_id", s.activeCommand.ID, "ground_speed", s.state.Velocity.GroundSpeed)
+			s.takeoffState.stage = takeoffClimb
+		}
+
+	case takeoffClimb:
+		s.setPhase(models.FlightPhaseClimb)
+		if s.state.Position.Altitude >= cmd.Altitude-altitudeTolerance {
+			s.logger.Info("Takeoff complete", "command_id", s.activeCommand.ID)
+			s.completeCommand("Takeoff complete")
+			s.takeoffState = nil
+			s.state.Velocity.VerticalSpeed = 0
+			s.setPhase(models.FlightPhaseCruise)
+			return
+		}
+
+		s.adjustHeading(runway.Heading, deltaTime)
+		s.adjustSpeed(targetSpeed, deltaTime)
+		s.state.Velocity.VerticalSpeed = s.config.MaxClimbRate
+		s.updatePosition(deltaTime, s.state.Velocity)
+	}
+}
+
+// newTakeoffState starts a takeoff. Without a runway the aircraft takes off
+// from its current position on its current heading.
+func (s *Simulator) newTakeoffState(cmd *models.TakeoffCommand) *takeoffState {
+	if cmd.Runway == nil {
+		return &takeoffState{
+			stage: takeoffRoll,
+			runway: models.Runway{
+				Threshold: s.state.Position,
+				Heading:   s.state.Heading,
+			},
+		}
+	}
+	return &takeoffState{stage: takeoffTaxi, runway: *cmd.Runway}
+}
+
+// executeLand executes a land command. With a runway the aircraft flies a
+// glide slope approach to the threshold; otherwise it descends vertically.
+func (s *Simulator) executeLand(cmd *models.LandCommand, deltaTime float64) {
+	if cmd == nil || cmd.Runway == nil {
+		s.executeVerticalLand(deltaTime)
+		return
+	}
+	if s.landingState == nil {
+		s.landingState = &landingState{stage: landingToFix}
+		if s.onGround() {
+			s.landingState.stage = landingRollout
+		}
+	}
+
+	runway := cmd.Runway
+	threshold := runway.Threshold
+	glideSlope := cmd.GlideSlope
+	if glideSlope <= 0 {
+		glideSlope = s.glideSlope()
+	}
+	tanGlideSlope := math.Tan(glideSlope * math.Pi / 180.0)
+
+	switch s.landingState.stage {
+	case landingToFix:
+		s.setPhase(models.FlightPhaseDescent)
+		fixLat, fixLon := geo.Destination(
+			threshold.Latitude,
+			threshold.Longitude,
+			math.Mod(runway.Heading+180, 360),
+			finalApproachDistance,
+		)
+		fix := models.Position{
+			Latitude:  fixLat,
+			Longitude: fixLon,
+			Altitude:  threshold.Altitude + finalApproachDistance*tanGlideSlope,
+		}
+
+		distance := geo.Haversine(s.state.Position.Latitude, s.state.Position.Longitude, fix.Latitude, fix.Longitude)
+		if distance < fixCaptureRadius {
+			s.logger.Info("Established on final approach", "command_id", s.activeCommand.ID)
+			s.landingState.stage = landingFinal
+			return
+		}
+		s.executeGoTo(&models.GoToCommand{Target: fix}, deltaTime)
+
+	case landingFinal:
+		s.setPhase(models.FlightPhaseApproach)
+		distance := geo.Haversine(s.state.Position.Latitude, s.state.Position.Longitude, threshold.Latitude, threshold.Longitude)
+		if distance < s.config.PositionTolerance || s.state.Position.Altitude <= threshold.Altitude+touchdownTolerance {
+			s.logger.Info("Touchdown", "command_id", s.activeCommand.ID, "ground_speed", s.state.Velocity.GroundSpeed)
+			s.state.Position.Altitude = threshold.Altitude
+			s.state.Velocity.VerticalSpeed = 0
+			s.landingState.stage = landingRollout
+			s.setPhase(models.FlightPhaseLanded)
+			return
+		}
+
+		// Track the threshold at approach speed and follow the glide slope
+		s.adjustHeading(geo.Bearing(
+			s.state.Position.Latitude,
+			s.state.Position.Longitude,
+			threshold.Latitude,
+			threshold.Longitude,
+		), deltaTime)
+		s.adjustSpeed(s.approachSpeed(), deltaTime)
+
+		glidePathAltitude := threshold.Altitude + distance*tanGlideSlope
+		verticalSpeed := -s.state.Velocity.GroundSpeed*tanGlideSlope +
+			(glidePathAltitude-s.state.Position.Altitude)/glideSlopeCorrectionTime
+		s.state.Velocity.VerticalSpeed = clamp(verticalSpeed, -s.config.MaxDescentRate, s.config.MaxClimbRate)
+		s.updatePosition(deltaTime, s.state.Velocity)
+
+	case landingRollout:
+		s.setPhase(models.FlightPhaseLanded)
+		if s.state.Velocity.GroundSpeed == 0 {
+			s.logger.Info("Landed", "command_id", s.activeCommand.ID)
+			s.completeCommand("Landed")
+			s.landingState = nil
+			return
+		}
+
+		s.adjustHeading(runway.Heading, deltaTime)
+		s.adjustSpeed(0, deltaTime)
+		s.state.Velocity.VerticalSpeed = 0
+		s.updatePosition(deltaTime, s.state.Velocity)
+	}
+}
+
+// executeVerticalLand stops and descends vertically to the ground.
+func (s *Simulator) executeVerticalLand(deltaTime float64) {
+	ground := s.terrainElevation()
+	height := s.state.Position.Altitude - ground
+
+	if height <= touchdownTolerance && s.state.Velocity.GroundSpeed == 0 {
+		s.logger.Info("Landed", "command_id", s.activeCommand.ID)
+		s.completeCommand("Landed")
+		s.state.Position.Altitude = ground
+		s.state.Velocity.VerticalSpeed = 0
+		s.setPhase(models.FlightPhaseLanded)
+		return
+	}
+
+	// Slow the descent close to the ground
+	s.setPhase(models.FlightPhaseDescent)
+	s.adjustSpeed(0, deltaTime)
+	descentRate := math.Min(s.config.MaxDescentRate, math.Max(1.0, height/2))
+	s.state.Velocity.VerticalSpeed = -descentRate
+	s.updatePosition(deltaTime, s.state.Velocity)
+}
+
+// taxiSpeed returns the configured taxi speed.
+func (s *Simulator) taxiSpeed() float64 {
+	if s.config.TaxiSpeed > 0 {
+		return s.config.TaxiSpeed
+	}
+	return defaultTaxiSpeed
+}
+
+// rotationSpeed returns the configured takeoff rotation speed.
+func (s *Simulator) rotationSpeed() float64 {
+	if s.config.RotationSpeed > 0 {
+		return s.config.RotationSpeed
+	}
+	return defaultRotationSpeed
+}
+
+// approachSpeed returns the configured final approach speed.
+func (s *Simulator) approachSpeed() float64 {
+	if s.config.ApproachSpeed > 0 {
+		return s.config.ApproachSpeed
+	}
+	return defaultApproachSpeed
+}
+
+// glideSlope returns the configured glide slope angle.
+func (s *Simulator) glideSlope() float64 {
+	if s.config.GlideSlope > 0 {
+		return s.config.GlideSlope
+	}
+	return defaultGlideSlope
+}
+
+// headingDifference returns the absolute angle between two headings (0-180).
+func headingDifference(a, b float64) float64 {
+	diff := math.Mod(math.Abs(a-b), 360)
+	if diff > 180 {
+		diff = 360 - diff
+	}
+	return diff
+}
@@ -0,0 +1,254 @@
+package simulator
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/geofence"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/session"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/track"
+)
+
+// MaxReplaySpeed is the fastest supported playback speed factor.
+const MaxReplaySpeed = 100.0
+
+// replayState tracks playback of a session log.
+type replayState struct {
+	log      *session.Log
+	next     int     // index of the next entry to apply
+	speed    float64 // playback speed factor, 0 = paused
+	finished bool
+}
+
+// replayRequest asks the simulation loop to change or report playback.
+type replayRequest struct {
+	seek  *float64 // simulation time to seek to, seconds
+	speed *float64
+	reply chan models.ReplayStatus
+}
+
+// NewReplay creates a simulator that replays a session log instead of
+// accepting commands. It starts from the recorded configuration, initial
+// state and geofences and applies each recorded event before the tick it
+// was originally applied before, so Run reproduces the recorded states
+// exactly, paced at speed times real time.
+func NewReplay(log *session.Log, speed float64, logger *slog.Logger) (*Simulator, error) {
+	s, err := New(log.Header.Simulation, log.Header.Environment, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// Timestamps and track samples use the original session times
+	s.startTime = log.Header.StartedAt
+	if s.recorder != nil {
+		s.recorder = track.NewRecorder(s.config.Recorder.MaxSamples, s.config.Recorder.Interval, s.startTime)
+	}
+
+	s.replay = &replayState{log: log, speed: clamp(speed, 0, MaxReplaySpeed)}
+	s.resetReplay()
+
+	logger.Info("Session replay loaded",
+		"started_at", log.Header.StartedAt,
+		"entries", len(log.Entries),
+		"speed", s.replay.speed,
+	)
+	return s, nil
+}
+
+// Replaying reports whether the simulator replays a session log.
+func (s *Simulator) Replaying() bool {
+	return s.replay != nil
+}
+
+// ReplayStatus returns the playback status.
+func (s *Simulator) ReplayStatus(ctx context.Context) (models.ReplayStatus, error) {
+	return s.replayControl(ctx, replayRequest{})
+}
+
+// SeekReplay moves playback to the given simulation time (seconds),
+// re-simulating from the start of the session when seeking backwards.
+// Seeks past the end of the session stop at the end.
+func (s *Simulator) SeekReplay(ctx context.Context, simTime float64) (models.ReplayStatus, error) {
+	return s.replayControl(ctx, replayRequest{seek: &simTime})
+}
+
+// SetReplaySpeed sets the playback speed factor (1 = real time, 0 = paused).
+func (s *Simulator) SetReplaySpeed(ctx context.Context, speed float64) (models.ReplayStatus, error) {
+	return s.replayControl(ctx, replayRequest{speed: &speed})
+}
+
+// replayControl sends a request to the simulation loop.
+func (s *Simulator) replayControl(ctx context.Context, req replayRequest) (models.ReplayStatus, error) {
+	if s.replay == nil {
+		return models.ReplayStatus{}, models.ErrNotReplaying
+	}
+	req.reply = make(chan models.ReplayStatus, 1)
+
+	select {
+	case s.replayRequests <- req:
+		return <-req.reply, nil
+	case <-ctx.Done():
+		return models.ReplayStatus{}, ctx.Err()
+	case <-time.After(5 * time.Second):
+		return models.ReplayStatus{}, models.ErrTimeout
+	}
+}
+
+// handleReplayRequest applies a playback request in the simulation loop.
+func (s *Simulator) handleReplayRequest(req replayRequest) models.ReplayStatus {
+	if req.speed != nil {
+		s.replay.speed = clamp(*req.speed, 0, MaxReplaySpeed)
+		s.logger.Info("Replay speed changed", "speed", s.replay.speed)
+	}
+	if req.seek != nil {
+		s.seekReplay(*req.seek)
+	}
+	return s.replayStatus()
+}
+
+// playbackInterval returns the wall-clock time between ticks.
+func (s *Simulator) playbackInterval() time.Duration {
+	if s.replay == nil || s.replay.speed <= 0 {
+		return s.tickerInterval
+	}
+	return time.Duration(float64(s.tickerInterval) / s.replay.speed)
+}
+
+// replayTick advances playback by one tick unless it is paused or finished.
+func (s *Simulator) replayTick() {
+	if s.replay.speed <= 0 || s.replay.finished {
+		return
+	}
+	s.replayStep()
+}
+
+// replayStep applies the entries recorded before the next tick and runs it.
+func (s *Simulator) replayStep() {
+	r := s.replay
+	for r.next < len(r.log.Entries) && r.log.Entries[r.next].Tick <= s.ticks {
+		s.applyEntry(r.log.Entries[r.next])
+		r.next++
+	}
+
+	if end, ok := r.log.EndTick(); ok && s.ticks >= end {
+		if !r.finished {
+			s.logger.Info("Replay finished", "sim_time", s.state.SimTime)
+		}
+		r.finished = true
+		return
+	}
+	s.tick()
+}
+
+// applyEntry applies a recorded event.
+func (s *Simulator) applyEntry(entry session.Entry) {
+	switch entry.Type {
+	case session.EntryCommand:
+		s.receiveCommand(entry.Command)
+	case session.EntryHeartbeat:
+		s.restoreLink()
+	case session.EntryRestore:
+		s.restoreSnapshot(*entry.Snapshot)
+	case session.EntryConditions:
+		s.applyConditions(*entry.Conditions)
+	case session.EntryGeofenceAdd, session.EntryGeofenceUpdate, session.EntryGeofenceDelete:
+		if s.geofences == nil {
+			return
+		}
+		change := geofence.Change{ID: entry.GeofenceID}
+		switch entry.Type {
+		case session.EntryGeofenceAdd:
+			change.Type, change.Zone = geofence.ChangeAdd, *entry.Geofence
+		case session.EntryGeofenceUpdate:
+			change.Type, change.Zone = geofence.ChangeUpdate, *entry.Geofence
+		case session.EntryGeofenceDelete:
+			change.Type = geofence.ChangeDelete
+		}
+		if _, err := s.changeGeofence(change); err != nil {
+			s.logger.Warn("Replayed geofence change failed", "error", err)
+		}
+	}
+}
+
+// seekReplay moves playback to the tick nearest simTime without publishing
+// the states in between, then publishes the state reached.
+func (s *Simulator) seekReplay(simTime float64) {
+	target := uint64(math.Round(math.Max(simTime, 0) / s.tickerInterval.Seconds()))
+	if last := s.replayEndTick(); target > last {
+		target = last
+	}
+
+	if target < s.ticks {
+		s.resetReplay()
+	}
+
+	s.muted = true
+	for s.ticks < target && !s.replay.finished {
+		s.replayStep()
+	}
+	s.muted = false
+
+	s.logger.Info("Replay seek", "sim_time", s.state.SimTime)
+	s.publishState()
+}
+
+// replayEndTick returns the last tick of the session: its end entry, or the
+// last recorded event if the session did not end cleanly.
+func (s *Simulator) replayEndTick() uint64 {
+	if end, ok := s.replay.log.EndTick(); ok {
+		return end
+	}
+	if n := len(s.replay.log.Entries); n > 0 {
+		return s.replay.log.Entries[n-1].Tick
+	}
+	return 0
+}
+
+// resetReplay restores the recorded initial state, geofences and an empty
+// track, ready to play the session from the start.
+func (s *Simulator) resetReplay() {
+	header := s.replay.log.Header
+
+	// Playing from the start again is not a cancellation
+	s.activeCommand = nil
+	s.restoreSnapshot(models.Snapshot{
+		State:    header.InitialState,
+		Progress: models.SnapshotProgress{LastSafePosition: header.InitialState.Position},
+	})
+	s.state.SimTime = header.InitialState.SimTime
+	s.state.Timestamp = header.InitialState.Timestamp
+	s.ticks = 0
+
+	var wind *models.WindVector
+	if cfg := header.Environment.Wind; cfg.Enabled {
+		wind = &models.WindVector{Direction: cfg.Direction, Speed: cfg.Speed}
+	}
+	s.environment.SetWind(wind)
+	s.environment.ResetTurbulence()
+	s.geofences.Reset(header.Geofences)
+	s.recorder.Reset()
+
+	s.replay.next = 0
+	s.replay.finished = false
+}
+
+// replayStatus reports the playback state.
+func (s *Simulator) replayStatus() models.ReplayStatus {
+	r := s.replay
+	status := models.ReplayStatus{
+		SimTime:        s.state.SimTime,
+		Speed:          r.speed,
+		Finished:       r.finished,
+		StartedAt:      r.log.Header.StartedAt,
+		EntriesApplied: r.next,
+		Entries:        len(r.log.Entries),
+	}
+	if end, ok := r.log.EndTick(); ok {
+		duration := float64(end) * s.tickerInterval.Seconds()
+		status.Duration = &duration
+	}
+	return status
+}
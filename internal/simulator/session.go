@@ -0,0 +1,39 @@
+package simulator
+
+import (
+	"github.com/meiron-tzhori/Flight-Simulator/internal/session"
+)
+
+// SessionHeader returns the session log header for the current run: the
+// configuration, the initial state and the loaded geofences. Call it before
+// Run, once the initial geofences are loaded.
+func (s *Simulator) SessionHeader() session.Header {
+	header := session.Header{
+		StartedAt:    s.startTime,
+		Simulation:   s.config,
+		Environment:  s.envConfig,
+		InitialState: s.state,
+	}
+	if s.geofences != nil {
+		header.Geofences = s.geofences.List()
+	}
+	return header
+}
+
+// RecordSession logs every accepted command, heartbeat, snapshot restore, state
+// update, conditions change and geofence change to w, tagged with the tick it
+// applies before, so that the run can be replayed with NewReplay. Call it
+// before Run; w must already hold the header from SessionHeader. The caller
+// closes w after Run returns.
+func (s *Simulator) RecordSession(w *session.Writer) {
+	s.session = w
+}
+
+// recordEntry logs an event applied by the simulation loop before the next tick.
+func (s *Simulator) recordEntry(entry session.Entry) {
+	if s.session == nil {
+		return
+	}
+	entry.Tick = s.ticks
+	s.session.Append(entry)
+}
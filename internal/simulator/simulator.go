@@ -9,8 +9,11 @@ import (
 
 	"github.com/meiron-tzhori/Flight-Simulator/internal/config"
 	"github.com/meiron-tzhori/Flight-Simulator/internal/environment"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/geofence"
 	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
 	"github.com/meiron-tzhori/Flight-Simulator/internal/pubsub"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/session"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/track"
 	"github.com/meiron-tzhori/Flight-Simulator/pkg/geo"
 )
 
@@ -21,19 +24,53 @@ type Simulator struct {
 	state           models.AircraftState
 	activeCommand   *models.Command
 	trajectoryState *trajectoryState
+	mission         *models.TrajectoryCommand // last trajectory received, kept after it completes
 	startTime       time.Time
+	pullUpActive    bool
+	takeoffState    *takeoffState
+	landingState    *landingState
+	ticks           uint64 // ticks run so far
+	muted           bool   // suppress publishing while seeking a replay
+	headless        bool   // stepped by a Headless driver instead of Run
+
+	// Geofence tracking
+	breachedZones    map[string]bool
+	lastSafePosition models.Position
+
+	// Return-to-home and lost-link tracking
+	home         models.Position
+	rthState     *rthState
+	sinceContact float64 // seconds since the last command or heartbeat
+	linkLost     bool
 
 	// Communication channels
-	commandQueue  chan *models.Command
-	stateRequests chan stateRequest
+	commandQueue      chan *models.Command
+	stateRequests     chan stateRequest
+	missionRequests   chan missionRequest
+	heartbeats        chan struct{}
+	replayRequests    chan replayRequest
+	snapshotRequests  chan snapshotRequest
+	conditionRequests chan conditionsRequest
+	geofenceRequests  chan geofenceRequest
 
 	// Components
-	publisher   *pubsub.StatePublisher
-	environment *environment.Environment
+	aircraftID      string
+	publisher       *pubsub.Broker[models.AircraftState]
+	eventBroker     *pubsub.Broker[models.Event]
+	environmentFeed *pubsub.Broker[models.EnvironmentState]
+	lastEnvironment *models.EnvironmentState // last published on the environment topic
+	events          *pubsub.EventBus
+	environment     *environment.Environment
+	geofences       *geofence.Manager // nil when geofencing is disabled
+	recorder        *track.Recorder   // nil when track recording is disabled
+	session         *session.Writer   // nil unless the session is being logged
+	replay          *replayState      // nil unless replaying a session log
 
 	// Configuration
-	tickerInterval time.Duration
-	config         config.SimulationConfig
+	tickerInterval   time.Duration
+	config           config.SimulationConfig
+	envConfig        config.EnvironmentConfig
+	lookAheadSeconds float64
 
 	// Logger
 	logger *slog.Logger
@@ -44,9 +81,15 @@ type stateRequest struct {
 	reply chan models.AircraftState
 }
 
+// missionRequest represents a request for the current mission.
+type missionRequest struct {
+	reply chan *models.TrajectoryCommand
+}
+
 // trajectoryState tracks progress through a trajectory.
 type trajectoryState struct {
 	currentWaypointIndex int
+	holdRemaining        float64 // seconds left holding at the current waypoint
 }
 
 // New creates a new simulator instance.
@@ -75,25 +118,89 @@ func New(cfg config.SimulationConfig, envCfg config.EnvironmentConfig, logger *s
 	}
 
 	// Create environment
-	env := environment.New(envCfg)
+	env, err := environment.New(envCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create environment: %w", err)
+	}
+
+	initialState.Phase = placedPhase(initialState, env.GetTerrain())
+	if cfg.Fuel.Enabled {
+		fuel := cfg.Fuel.Capacity
+		initialState.Fuel = &fuel
+	}
+
+	lookAheadSeconds := envCfg.Terrain.LookAheadSeconds
+	if lookAheadSeconds <= 0 {
+		lookAheadSeconds = defaultLookAheadSeconds
+	}
+
+	// Home defaults to the initial position
+	home := models.Position{
+		Latitude:  cfg.Home.Latitude,
+		Longitude: cfg.Home.Longitude,
+		Altitude:  cfg.Home.Altitude,
+	}
+	if cfg.Home == (config.PositionConfig{}) {
+		home = initialState.Position
+	}
+
+	// Create geofence manager
+	var geofences *geofence.Manager
+	if cfg.Geofence.Enabled {
+		geofences = geofence.NewManager()
+	}
+
+	// Create track recorder
+	startTime := time.Now()
+	var recorder *track.Recorder
+	if cfg.Recorder.Enabled {
+		recorder = track.NewRecorder(cfg.Recorder.MaxSamples, cfg.Recorder.Interval, startTime)
+	}
+
+	aircraftID := cfg.AircraftID
+	if aircraftID == "" {
+		aircraftID = defaultAircraftID
+	}
+
+	// The brokers number their messages in one sequence, so stream clients
+	// get states and events in order
+	sequence := pubsub.NewSequence()
 
 	s := &Simulator{
-		state:           initialState,
-		activeCommand:   nil,
-		trajectoryState: nil,
-		startTime:       time.Now(),
-		commandQueue:    make(chan *models.Command, cfg.CommandQueueSize),
-		stateRequests:   make(chan stateRequest),
-		publisher:       pubsub.NewStatePublisher(10), // 10-item buffer per subscriber
-		environment:     env,
-		tickerInterval:  tickerInterval,
-		config:          cfg,
-		logger:          logger,
+		state:             initialState,
+		activeCommand:     nil,
+		trajectoryState:   nil,
+		startTime:         startTime,
+		breachedZones:     make(map[string]bool),
+		lastSafePosition:  initialState.Position,
+		home:              home,
+		commandQueue:      make(chan *models.Command, cfg.CommandQueueSize),
+		stateRequests:     make(chan stateRequest),
+		missionRequests:   make(chan missionRequest),
+		heartbeats:        make(chan struct{}),
+		replayRequests:    make(chan replayRequest),
+		snapshotRequests:  make(chan snapshotRequest),
+		conditionRequests: make(chan conditionsRequest),
+		geofenceRequests:  make(chan geofenceRequest),
+		aircraftID:        aircraftID,
+		publisher:         pubsub.NewBroker[models.AircraftState](sequence, 10, streamHistorySize, logger), // 10-item buffer per subscriber
+		eventBroker:       pubsub.NewBroker[models.Event](sequence, 100, streamHistorySize, logger),        // 100-event buffer per subscriber
+		environmentFeed:   pubsub.NewBroker[models.EnvironmentState](sequence, 10, environmentHistorySize, logger),
+		events:            pubsub.NewEventBus(eventHistorySize),
+		environment:       env,
+		geofences:         geofences,
+		recorder:          recorder,
+		tickerInterval:    tickerInterval,
+		config:            cfg,
+		envConfig:         envCfg,
+		lookAheadSeconds:  lookAheadSeconds,
+		logger:            logger,
 	}
 
 	logger.Info("Simulator initialized",
 		"tick_interval", tickerInterval,
 		"initial_position", initialState.Position,
+		"phase", initialState.Phase,
 		"environment_enabled", env != nil && env.IsEnabled(),
 	)
 
@@ -104,6 +211,18 @@ func New(cfg config.SimulationConfig, envCfg config.EnvironmentConfig, logger *s
 				"speed_ms", wind.GetVector().Speed,
 			)
 		}
+		if envCfg.Turbulence.Enabled {
+			logger.Info("Turbulence enabled",
+				"intensity_ms", envCfg.Turbulence.Intensity,
+				"seed", envCfg.Turbulence.Seed,
+			)
+		}
+		if terrain := env.GetTerrain(); terrain != nil {
+			logger.Info("Terrain avoidance enabled",
+				"safety_margin", terrain.SafetyMargin(),
+				"look_ahead_seconds", lookAheadSeconds,
+			)
+		}
 	}
 
 	return s, nil
@@ -113,30 +232,78 @@ func New(cfg config.SimulationConfig, envCfg config.EnvironmentConfig, logger *s
 func (s *Simulator) Run(ctx context.Context) error {
 	s.logger.Info("Starting simulation loop")
 
-	ticker := time.NewTicker(s.tickerInterval)
+	ticker := time.NewTicker(s.playbackInterval())
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
 			s.logger.Info("Simulation loop shutting down")
+			s.recordEntry(session.Entry{Type: session.EntryEnd})
 			return ctx.Err()
 
 		case <-ticker.C:
-			s.tick()
+			if s.replay != nil {
+				s.replayTick()
+			} else {
+				s.tick()
+			}
 
 		case cmd := <-s.commandQueue:
-			s.handleCommand(cmd)
+			s.recordEntry(session.Entry{Type: session.EntryCommand, Command: cmd})
+			s.receiveCommand(cmd)
+
+		case <-s.heartbeats:
+			s.recordEntry(session.Entry{Type: session.EntryHeartbeat})
+			s.restoreLink()
+
+		case req := <-s.snapshotRequests:
+			if req.update != nil {
+				snap := s.updatedSnapshot(*req.update)
+				s.recordEntry(session.Entry{Type: session.EntryRestore, Snapshot: &snap})
+				s.restoreSnapshot(snap)
+				s.logger.Info("Aircraft repositioned",
+					"position", s.state.Position,
+					"heading", s.state.Heading,
+					"command_cleared", req.update.ClearCommand,
+				)
+			}
+			if req.restore != nil {
+				s.recordEntry(session.Entry{Type: session.EntryRestore, Snapshot: req.restore})
+				s.restoreSnapshot(*req.restore)
+				s.logger.Info("Snapshot restored", "snapshot_id", req.restore.ID, "position", s.state.Position)
+			}
+			req.reply <- s.captureSnapshot()
+
+		case req := <-s.conditionRequests:
+			s.recordEntry(session.Entry{Type: session.EntryConditions, Conditions: &req.update})
+			s.applyConditions(req.update)
+			req.reply <- struct{}{}
+
+		case req := <-s.geofenceRequests:
+			zone, err := s.changeGeofence(req.change)
+			req.reply <- geofenceReply{zone: zone, err: err}
+
+		case req := <-s.replayRequests:
+			req.reply <- s.handleReplayRequest(req)
+			ticker.Reset(s.playbackInterval())
 
 		case req := <-s.stateRequests:
 			// Synchronous state query
 			req.reply <- s.state
+
+		case req := <-s.missionRequests:
+			req.reply <- s.mission
 		}
 	}
 }
 
 // SubmitCommand submits a command to the simulator.
 func (s *Simulator) SubmitCommand(ctx context.Context, cmd *models.Command) error {
+	if s.replay != nil {
+		return models.ErrReplayActive
+	}
+
 	select {
 	case s.commandQueue <- cmd:
 		s.logger.Debug("Command queued", "command_id", cmd.ID, "type", cmd.Type)
@@ -165,11 +332,46 @@ func (s *Simulator) GetState(ctx context.Context) (models.AircraftState, error)
 	}
 }
 
-// GetPublisher returns the state publisher for SSE subscriptions.
-func (s *Simulator) GetPublisher() *pubsub.StatePublisher {
+// GetMission returns the most recently received trajectory, or nil if no
+// trajectory has been flown.
+func (s *Simulator) GetMission(ctx context.Context) (*models.TrajectoryCommand, error) {
+	req := missionRequest{
+		reply: make(chan *models.TrajectoryCommand, 1),
+	}
+
+	select {
+	case s.missionRequests <- req:
+		return <-req.reply, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(1 * time.Second):
+		return nil, models.ErrTimeout
+	}
+}
+
+// GetPublisher returns the broker of the aircraft states, published on
+// StateTopic.
+func (s *Simulator) GetPublisher() *pubsub.Broker[models.AircraftState] {
 	return s.publisher
 }
 
+// GetEnvironment returns the simulation environment (nil if disabled).
+// The terrain is immutable after construction; the wind is only changed by
+// the simulation loop, through SetConditions.
+func (s *Simulator) GetEnvironment() *environment.Environment {
+	return s.environment
+}
+
+// GetGeofences returns the geofence manager (nil if geofencing is disabled).
+func (s *Simulator) GetGeofences() *geofence.Manager {
+	return s.geofences
+}
+
+// GetRecorder returns the track recorder (nil if recording is disabled).
+func (s *Simulator) GetRecorder() *track.Recorder {
+	return s.recorder
+}
+
 // tick performs one simulation step.
 func (s *Simulator) tick() {
 	// Calculate time since last tick
@@ -178,9 +380,20 @@ func (s *Simulator) tick() {
 	// Apply environment effects if enabled
 	effectiveVelocity := s.state.Velocity
 	if s.environment != nil && s.environment.IsEnabled() {
+		s.environment.Advance(deltaTime)
 		effectiveVelocity = s.environment.ApplyEffects(s.state.Heading, s.state.Velocity)
 	}
 
+	// Only takeoff, landing and stop commands run on the ground
+	if s.activeCommand != nil && s.onGround() && !allowedOnGround(s.activeCommand.Type) {
+		s.logger.Warn("Command ignored on the ground",
+			"command_id", s.activeCommand.ID,
+			"type", s.activeCommand.Type,
+			"phase", s.state.Phase,
+		)
+		s.cancelCommand("Command not allowed on the ground")
+	}
+
 	// Execute active command if present
 	if s.activeCommand != nil {
 		switch s.activeCommand.Type {
@@ -190,24 +403,54 @@ func (s *Simulator) tick() {
 			s.executeTrajectory(s.activeCommand.Trajectory, deltaTime, effectiveVelocity)
 		case models.CommandTypeHold:
 			s.executeHold(deltaTime, effectiveVelocity)
+		case models.CommandTypeRTH:
+			s.executeRTH(s.activeCommand.RTH, deltaTime)
+		case models.CommandTypeTakeoff:
+			s.executeTakeoff(s.activeCommand.Takeoff, deltaTime)
+		case models.CommandTypeLand:
+			s.executeLand(s.activeCommand.Land, deltaTime)
 		case models.CommandTypeStop:
 			// Aircraft is stopped, no movement
 		}
+	} else if s.onGround() {
+		// Parked or landed - no movement
+		s.state.Velocity = models.Velocity{}
 	} else {
 		// No active command - maintain current heading and speed
 		s.updatePosition(deltaTime, effectiveVelocity)
 	}
+	s.updatePhase()
+	s.burnFuel(deltaTime)
+	s.state.ActiveCommand = s.commandInfo()
 
 	// Add environment state to aircraft state
 	if s.environment != nil {
 		s.state.Environment = s.environment.GetState()
 	}
+	s.updateTerrainState()
+	s.checkGeofences()
+	s.checkLostLink(deltaTime)
+
+	// Advance the simulation clock and update the timestamp
+	s.state.SimTime += deltaTime
+	s.state.Timestamp = s.now()
+	s.ticks++
+
+	// Publish state to subscribers and record it
+	if !s.muted {
+		s.publishState()
+	}
+	s.recorder.Record(s.state)
+}
 
-	// Update timestamp
-	s.state.Timestamp = time.Now()
-
-	// Publish state to subscribers
-	s.publisher.Publish(s.state)
+// now returns the timestamp for the current state: the wall clock, or the
+// start time plus the simulation time when replaying a session or running
+// headless.
+func (s *Simulator) now() time.Time {
+	if s.replay != nil || s.headless {
+		return s.startTime.Add(time.Duration(s.state.SimTime * float64(time.Second)))
+	}
+	return time.Now()
 }
 
 // handleCommand processes a newly received command.
@@ -221,13 +464,60 @@ func (s *Simulator) handleCommand(cmd *models.Command) {
 		}
 	}
 
+	if s.activeCommand != nil {
+		s.emit(models.Event{
+			Type:    models.EventCommandSuperseded,
+			Message: "Command superseded",
+			Command: &models.EventCommand{ID: s.activeCommand.ID, Type: s.activeCommand.Type, SupersededBy: cmd.ID},
+		})
+	}
+	s.emitCommand(models.EventCommandStarted, cmd, "Command started")
+
 	// Store as active command
 	s.activeCommand = cmd
+	s.rthState = nil
+	s.takeoffState = nil
+	s.landingState = nil
 
 	// Reset trajectory state for new trajectory commands
 	if cmd.Type == models.CommandTypeTrajectory {
 		s.trajectoryState = &trajectoryState{currentWaypointIndex: 0}
+		s.mission = cmd.Trajectory
+	}
+}
+
+// commandInfo describes the active command for the published state.
+func (s *Simulator) commandInfo() *models.CommandInfo {
+	cmd := s.activeCommand
+	if cmd == nil {
+		return nil
+	}
+
+	info := &models.CommandInfo{ID: cmd.ID, Type: string(cmd.Type)}
+	switch cmd.Type {
+	case models.CommandTypeGoTo:
+		info.Target = &cmd.GoTo.Target
+	case models.CommandTypeTrajectory:
+		if s.trajectoryState != nil && s.trajectoryState.currentWaypointIndex < len(cmd.Trajectory.Waypoints) {
+			index := s.trajectoryState.currentWaypointIndex
+			info.WaypointIndex = &index
+			info.Target = &cmd.Trajectory.Waypoints[index].Position
+		}
+	case models.CommandTypeRTH:
+		home := s.home
+		info.Target = &home
 	}
+
+	if info.Target != nil && s.state.Velocity.GroundSpeed > 0 {
+		distance := geo.Haversine(
+			s.state.Position.Latitude,
+			s.state.Position.Longitude,
+			info.Target.Latitude,
+			info.Target.Longitude,
+		)
+		info.ETASeconds = distance / s.state.Velocity.GroundSpeed
+	}
+	return info
 }
 
 // updatePosition updates aircraft position based on current velocity and heading.
@@ -252,14 +542,54 @@ func (s *Simulator) updatePosition(deltaTime float64, velocity models.Velocity)
 	s.state.Position.Latitude += deltaLat
 	s.state.Position.Longitude += deltaLon
 
+	// Ground-collision avoidance overrides the commanded vertical speed
+	verticalSpeed, pullUp := velocity.VerticalSpeed, false
+	if s.terrainAvoidanceArmed() {
+		verticalSpeed, pullUp = s.terrainPullUp(velocity.VerticalSpeed)
+	}
+	if pullUp != s.pullUpActive {
+		if pullUp {
+			elevation := s.terrainElevation()
+			s.logger.Warn("Terrain pull-up engaged",
+				"altitude", s.state.Position.Altitude,
+				"terrain_elevation", elevation,
+			)
+			s.emit(models.Event{
+				Type:    models.EventTerrainWarning,
+				Message: "Terrain pull-up engaged",
+				Terrain: &models.TerrainState{
+					Elevation: elevation,
+					HeightAGL: s.state.Position.Altitude - elevation,
+					PullUp:    true,
+				},
+			})
+		} else {
+			s.logger.Info("Terrain pull-up released", "altitude", s.state.Position.Altitude)
+		}
+		s.pullUpActive = pullUp
+	}
+	if pullUp {
+		s.state.Velocity.VerticalSpeed = verticalSpeed
+	}
+
+	// Without an engine the aircraft can only glide
+	if glide := s.glideVerticalSpeed(verticalSpeed, velocity.GroundSpeed); glide != verticalSpeed {
+		verticalSpeed = glide
+		s.state.Velocity.VerticalSpeed = glide
+	}
+
 	// Update altitude
-	deltaAlt := velocity.VerticalSpeed * deltaTime
+	deltaAlt := verticalSpeed * deltaTime
 	s.state.Position.Altitude += deltaAlt
 
-	// Ensure altitude doesn't go negative
-	if s.state.Position.Altitude < 0 {
-		s.state.Position.Altitude = 0
+	// Ensure altitude doesn't go below ground
+	ground := s.terrainElevation()
+	if s.state.Position.Altitude < ground {
+		s.state.Position.Altitude = ground
 		s.state.Velocity.VerticalSpeed = 0
+		if s.state.EngineFailure && !s.onGround() {
+			s.forcedLanding()
+		}
 	}
 }
 
@@ -276,7 +606,7 @@ func (s *Simulator) executeGoTo(cmd *models.GoToCommand, deltaTime float64) {
 	// Check if target reached
 	if distance < s.config.PositionTolerance {
 		s.logger.Info("Target reached", "command_id", s.activeCommand.ID)
-		s.activeCommand = nil // Command complete
+		s.completeCommand("Target reached")
 		s.state.Velocity.GroundSpeed = 0
 		s.state.Velocity.VerticalSpeed = 0
 		return
@@ -303,7 +633,10 @@ func (s *Simulator) executeGoTo(cmd *models.GoToCommand, deltaTime float64) {
 	// Calculate target vertical speed for altitude change
 	altitudeDiff := cmd.Target.Altitude - s.state.Position.Altitude
 	timeToTarget := distance / s.state.Velocity.GroundSpeed
-	if timeToTarget > 0 {
+	if cmd.AltitudeRef == models.AltitudeReferenceAGL {
+		// Hold height above ground along the track
+		s.state.Velocity.VerticalSpeed = s.terrainFollowingVerticalSpeed(cmd.Target.Altitude)
+	} else if timeToTarget > 0 {
 		desiredVerticalSpeed := altitudeDiff / timeToTarget
 		// Clamp to max rates
 		desiredVerticalSpeed = clamp(desiredVerticalSpeed, -s.config.MaxDescentRate, s.config.MaxClimbRate)
@@ -327,10 +660,23 @@ func (s *Simulator) executeTrajectory(cmd *models.TrajectoryCommand, deltaTime f
 			// Restart from beginning
 			s.trajectoryState.currentWaypointIndex = 0
 			s.logger.Info("Trajectory looping", "command_id", s.activeCommand.ID)
+			s.emit(models.Event{
+				Type:    models.EventTrajectoryLooped,
+				Message: "Trajectory looping",
+				Command: &models.EventCommand{ID: s.activeCommand.ID, Type: s.activeCommand.Type},
+			})
+		} else if cmd.ReturnToHome {
+			s.logger.Info("Trajectory complete, returning to home", "command_id", s.activeCommand.ID)
+			s.completeCommand("Trajectory complete, returning to home")
+			s.trajectoryState = nil
+			rth := models.NewCommand(models.CommandTypeRTH)
+			rth.RTH = &models.RTHCommand{}
+			s.handleCommand(rth)
+			return
 		} else {
 			// Trajectory complete
 			s.logger.Info("Trajectory complete", "command_id", s.activeCommand.ID)
-			s.activeCommand = nil
+			s.completeCommand("Trajectory complete")
 			s.trajectoryState = nil
 			s.state.Velocity.GroundSpeed = 0
 			s.state.Velocity.VerticalSpeed = 0
@@ -341,10 +687,21 @@ func (s *Simulator) executeTrajectory(cmd *models.TrajectoryCommand, deltaTime f
 	// Get current waypoint
 	waypoint := cmd.Waypoints[s.trajectoryState.currentWaypointIndex]
 
+	// Hold at a reached waypoint before moving on
+	if s.trajectoryState.holdRemaining > 0 {
+		s.executeHold(deltaTime, velocity)
+		s.trajectoryState.holdRemaining -= deltaTime
+		if s.trajectoryState.holdRemaining <= 0 {
+			s.trajectoryState.currentWaypointIndex++
+		}
+		return
+	}
+
 	// Create a temporary go-to command for current waypoint
 	gotoCmd := &models.GoToCommand{
-		Target: waypoint.Position,
-		Speed:  waypoint.Speed,
+		Target:      waypoint.Position,
+		Speed:       waypoint.Speed,
+		AltitudeRef: waypoint.AltitudeRef,
 	}
 
 	// Calculate distance to waypoint
@@ -361,6 +718,22 @@ func (s *Simulator) executeTrajectory(cmd *models.TrajectoryCommand, deltaTime f
 			"command_id", s.activeCommand.ID,
 			"waypoint_index", s.trajectoryState.currentWaypointIndex,
 		)
+		index := s.trajectoryState.currentWaypointIndex
+		s.emit(models.Event{
+			Type:          models.EventWaypointReached,
+			Message:       "Waypoint reached",
+			Command:       &models.EventCommand{ID: s.activeCommand.ID, Type: s.activeCommand.Type},
+			WaypointIndex: &index,
+		})
+		if waypoint.HoldSeconds > 0 {
+			s.logger.Info("Holding at waypoint",
+				"command_id", s.activeCommand.ID,
+				"waypoint_index", s.trajectoryState.currentWaypointIndex,
+				"seconds", waypoint.HoldSeconds,
+			)
+			s.trajectoryState.holdRemaining = waypoint.HoldSeconds
+			return
+		}
 		s.trajectoryState.currentWaypointIndex++
 		return
 	}
@@ -409,6 +782,11 @@ func (s *Simulator) adjustHeading(targetHeading, deltaTime float64) {
 // adjustSpeed smoothly adjusts speed towards target.
 func (s *Simulator) adjustSpeed(targetSpeed, deltaTime float64) {
 	currentSpeed := s.state.Velocity.GroundSpeed
+
+	// Without an engine the aircraft glides at the approach speed
+	if s.state.EngineFailure && !s.onGround() {
+		targetSpeed = s.approachSpeed()
+	}
 	diff := targetSpeed - currentSpeed
 
 	// Apply acceleration limit
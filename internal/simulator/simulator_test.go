@@ -2,13 +2,19 @@ package simulator
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"log/slog"
+	"math"
 	"os"
+	"slices"
 	"testing"
 	"time"
 
 	"github.com/meiron-tzhori/Flight-Simulator/internal/config"
 	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/session"
+	"github.com/meiron-tzhori/Flight-Simulator/pkg/geo"
 )
 
 func createTestConfig() (config.SimulationConfig, config.EnvironmentConfig) {
@@ -258,6 +264,807 @@ func TestSimulator_Run_Context_Cancellation(t *testing.T) {
 	}
 }
 
+func createTerrainTestSimulator(t *testing.T, baseElevation float64) *Simulator {
+	t.Helper()
+
+	simCfg, envCfg := createTestConfig()
+	envCfg = config.EnvironmentConfig{
+		Enabled: true,
+		Terrain: config.TerrainConfig{
+			Enabled:          true,
+			SafetyMargin:     50.0,
+			BaseElevation:    baseElevation,
+			LookAheadSeconds: 20.0,
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	sim, err := New(simCfg, envCfg, logger)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return sim
+}
+
+func TestSimulator_TerrainFollowing(t *testing.T) {
+	sim := createTerrainTestSimulator(t, 500.0)
+
+	cmd := models.NewCommand(models.CommandTypeGoTo)
+	cmd.GoTo = &models.GoToCommand{
+		Target:      models.Position{Latitude: 32.5, Longitude: 34.0, Altitude: 200.0},
+		Speed:       ptr(100.0),
+		AltitudeRef: models.AltitudeReferenceAGL,
+	}
+	sim.handleCommand(cmd)
+
+	// Descend from 1000m MSL to 200m above 500m terrain (~80s at max descent rate)
+	for i := 0; i < 1200; i++ {
+		sim.tick()
+	}
+
+	if sim.state.Terrain == nil {
+		t.Fatal("Terrain state not reported")
+	}
+	if diff := sim.state.Terrain.HeightAGL - 200.0; diff > 1 || diff < -1 {
+		t.Errorf("Height AGL = %.2f, want 200.0", sim.state.Terrain.HeightAGL)
+	}
+}
+
+func TestSimulator_TerrainPullUp(t *testing.T) {
+	sim := createTerrainTestSimulator(t, 980.0)
+
+	// Aircraft at 1000m MSL is only 20m above terrain, inside the 50m margin
+	sim.tick()
+
+	if !sim.state.Terrain.PullUp {
+		t.Error("Pull-up not engaged below safety margin")
+	}
+	if sim.state.Velocity.VerticalSpeed != sim.config.MaxClimbRate {
+		t.Errorf("Vertical speed = %.2f, want %.2f", sim.state.Velocity.VerticalSpeed, sim.config.MaxClimbRate)
+	}
+
+	// Climb until clear of the margin
+	for i := 0; i < 100; i++ {
+		sim.tick()
+	}
+	if sim.state.Terrain.PullUp {
+		t.Error("Pull-up still engaged after climbing clear")
+	}
+}
+
+func TestSimulator_GeofenceBreachHold(t *testing.T) {
+	simCfg, envCfg := createTestConfig()
+	simCfg.Geofence = config.GeofenceConfig{Enabled: true, DefaultAction: "hold"}
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	sim, err := New(simCfg, envCfg, logger)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	// Exclusion zone just north of the initial position
+	sim.GetGeofences().Add(models.Geofence{
+		Shape: models.GeofenceShapePolygon,
+		Mode:  models.GeofenceModeExclusion,
+		Vertices: []models.Coordinate{
+			{Latitude: 32.001, Longitude: 33.99},
+			{Latitude: 32.001, Longitude: 34.01},
+			{Latitude: 32.02, Longitude: 34.01},
+			{Latitude: 32.02, Longitude: 33.99},
+		},
+	})
+
+	cmd := models.NewCommand(models.CommandTypeGoTo)
+	cmd.GoTo = &models.GoToCommand{
+		Target: models.Position{Latitude: 32.1, Longitude: 34.0, Altitude: 1000},
+		Speed:  ptr(100.0),
+	}
+	sim.handleCommand(cmd)
+
+	for i := 0; i < 50 && len(sim.state.GeofenceBreaches) == 0; i++ {
+		sim.tick()
+	}
+
+	if len(sim.state.GeofenceBreaches) == 0 {
+		t.Fatal("Geofence breach not detected")
+	}
+	if sim.activeCommand == nil || sim.activeCommand.Type != models.CommandTypeHold {
+		t.Errorf("Active command after breach = %v, want hold", sim.activeCommand)
+	}
+}
+
+func TestSimulator_ReturnToHome(t *testing.T) {
+	simCfg, envCfg := createTestConfig()
+	simCfg.Home = config.PositionConfig{Latitude: 32.01, Longitude: 34.0, Altitude: 0}
+	simCfg.RTHAltitude = 1200.0
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	sim, err := New(simCfg, envCfg, logger)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	cmd := models.NewCommand(models.CommandTypeRTH)
+	cmd.RTH = &models.RTHCommand{}
+	sim.handleCommand(cmd)
+
+	for i := 0; i < 3000 && sim.activeCommand != nil && sim.activeCommand.Type == models.CommandTypeRTH; i++ {
+		sim.tick()
+	}
+
+	if sim.activeCommand == nil || sim.activeCommand.Type != models.CommandTypeHold {
+		t.Fatalf("Active command after RTH = %v, want hold", sim.activeCommand)
+	}
+	if math.Abs(sim.state.Position.Latitude-32.01) > 0.001 {
+		t.Errorf("Latitude after RTH = %f, want ~32.01", sim.state.Position.Latitude)
+	}
+	if math.Abs(sim.state.Position.Altitude-1200.0) > 10.0 {
+		t.Errorf("Altitude after RTH = %.1f, want ~1200", sim.state.Position.Altitude)
+	}
+}
+
+func TestSimulator_MissionHoldAndReturn(t *testing.T) {
+	simCfg, envCfg := createTestConfig()
+	simCfg.RTHAltitude = 1000.0
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	sim, err := New(simCfg, envCfg, logger)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	cmd := models.NewCommand(models.CommandTypeTrajectory)
+	cmd.Trajectory = &models.TrajectoryCommand{
+		Waypoints: []models.Waypoint{
+			{Position: models.Position{Latitude: 32.005, Longitude: 34.0, Altitude: 1000.0}, HoldSeconds: 5},
+		},
+		ReturnToHome: true,
+	}
+	sim.handleCommand(cmd)
+
+	for i := 0; i < 1000 && (sim.trajectoryState == nil || sim.trajectoryState.holdRemaining == 0); i++ {
+		sim.tick()
+	}
+	if sim.trajectoryState == nil || sim.trajectoryState.holdRemaining == 0 {
+		t.Fatal("Aircraft did not start holding at the waypoint")
+	}
+
+	ticks := 0
+	for ; ticks < 100 && sim.activeCommand.Type == models.CommandTypeTrajectory; ticks++ {
+		sim.tick()
+	}
+	if ticks < 50 {
+		t.Errorf("Hold lasted %d ticks, want 5 seconds at 10 Hz", ticks)
+	}
+	if sim.activeCommand == nil || sim.activeCommand.Type != models.CommandTypeRTH {
+		t.Fatalf("Active command after mission = %v, want rth", sim.activeCommand)
+	}
+	if sim.mission != cmd.Trajectory {
+		t.Error("Mission was not kept after the trajectory completed")
+	}
+}
+
+func TestSimulator_LostLink(t *testing.T) {
+	simCfg, envCfg := createTestConfig()
+	simCfg.LostLink = config.LostLinkConfig{Enabled: true, Timeout: 2 * time.Second, Action: "hold"}
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	sim, err := New(simCfg, envCfg, logger)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	for i := 0; i < 15; i++ {
+		sim.tick()
+	}
+	if sim.state.LinkLost {
+		t.Fatal("Link lost before timeout")
+	}
+
+	for i := 0; i < 10; i++ {
+		sim.tick()
+	}
+	if !sim.state.LinkLost {
+		t.Fatal("Link not lost after timeout")
+	}
+	if sim.activeCommand == nil || sim.activeCommand.Type != models.CommandTypeHold {
+		t.Errorf("Active command after lost link = %v, want hold", sim.activeCommand)
+	}
+
+	sim.restoreLink()
+	sim.tick()
+	if sim.state.LinkLost {
+		t.Error("Link still lost after contact")
+	}
+}
+
+func TestSimulator_Takeoff(t *testing.T) {
+	simCfg, envCfg := createTestConfig()
+	simCfg.InitialPosition.Altitude = 0
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	sim, err := New(simCfg, envCfg, logger)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if sim.state.Phase != models.FlightPhaseParked {
+		t.Fatalf("Initial phase = %s, want parked", sim.state.Phase)
+	}
+
+	// Parked aircraft ignore airborne commands
+	gotoCmd := models.NewCommand(models.CommandTypeGoTo)
+	gotoCmd.GoTo = &models.GoToCommand{Target: models.Position{Latitude: 32.1, Longitude: 34.0, Altitude: 500}}
+	sim.handleCommand(gotoCmd)
+	for i := 0; i < 10; i++ {
+		sim.tick()
+	}
+	if sim.state.Position.Latitude != 32.0 || sim.state.Velocity.GroundSpeed != 0 {
+		t.Fatalf("Parked aircraft moved: position %+v, speed %.1f", sim.state.Position, sim.state.Velocity.GroundSpeed)
+	}
+
+	cmd := models.NewCommand(models.CommandTypeTakeoff)
+	cmd.Takeoff = &models.TakeoffCommand{Altitude: 300}
+	sim.handleCommand(cmd)
+
+	phases := make(map[models.FlightPhase]bool)
+	for i := 0; i < 1000 && sim.activeCommand != nil; i++ {
+		sim.tick()
+		phases[sim.state.Phase] = true
+	}
+
+	if sim.activeCommand != nil {
+		t.Fatal("Takeoff did not complete")
+	}
+	for _, phase := range []models.FlightPhase{models.FlightPhaseTakeoffRoll, models.FlightPhaseClimb, models.FlightPhaseCruise} {
+		if !phases[phase] {
+			t.Errorf("Phase %s not reported during takeoff", phase)
+		}
+	}
+	if sim.state.Position.Altitude < 295 {
+		t.Errorf("Altitude after takeoff = %.1f, want ~300", sim.state.Position.Altitude)
+	}
+}
+
+func TestSimulator_RunwayLanding(t *testing.T) {
+	simCfg, envCfg := createTestConfig()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	sim, err := New(simCfg, envCfg, logger)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	threshold := models.Position{Latitude: 32.1, Longitude: 34.0, Altitude: 20}
+	cmd := models.NewCommand(models.CommandTypeLand)
+	cmd.Land = &models.LandCommand{Runway: &models.Runway{Threshold: threshold, Heading: 0}}
+	sim.handleCommand(cmd)
+
+	approach := false
+	for i := 0; i < 5000 && sim.activeCommand != nil; i++ {
+		sim.tick()
+		approach = approach || sim.state.Phase == models.FlightPhaseApproach
+	}
+
+	if sim.activeCommand != nil {
+		t.Fatal("Landing did not complete")
+	}
+	if !approach {
+		t.Error("Approach phase not reported")
+	}
+	if sim.state.Phase != models.FlightPhaseLanded {
+		t.Errorf("Phase after landing = %s, want landed", sim.state.Phase)
+	}
+	if sim.state.Position.Altitude != threshold.Altitude {
+		t.Errorf("Altitude after landing = %.1f, want %.1f", sim.state.Position.Altitude, threshold.Altitude)
+	}
+	distance := geo.Haversine(sim.state.Position.Latitude, sim.state.Position.Longitude, threshold.Latitude, threshold.Longitude)
+	if distance > 1000 {
+		t.Errorf("Landed %.0f m from threshold, want within 1000 m", distance)
+	}
+}
+
+func TestSimulator_EngineFailure(t *testing.T) {
+	simCfg, envCfg := createTestConfig()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	sim, err := New(simCfg, envCfg, logger)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	cmd := models.NewCommand(models.CommandTypeGoTo)
+	cmd.GoTo = &models.GoToCommand{Target: models.Position{Latitude: 32.5, Longitude: 34.0, Altitude: 3000}}
+	sim.handleCommand(cmd)
+	for i := 0; i < 50; i++ {
+		sim.tick()
+	}
+	if sim.state.Velocity.VerticalSpeed <= 0 {
+		t.Fatalf("Vertical speed before the failure = %.1f, want climbing", sim.state.Velocity.VerticalSpeed)
+	}
+
+	failed := true
+	sim.applyConditions(models.ConditionsUpdate{EngineFailure: &failed})
+
+	// The aircraft glides down at the approach speed and lands where it reaches the ground
+	altitude := sim.state.Position.Altitude
+	for i := 0; i < 5000 && sim.state.Phase != models.FlightPhaseLanded; i++ {
+		sim.tick()
+		if sim.state.Position.Altitude > altitude {
+			t.Fatalf("Altitude rose from %.1f to %.1f without an engine", altitude, sim.state.Position.Altitude)
+		}
+		altitude = sim.state.Position.Altitude
+		if i == 100 && math.Abs(sim.state.Velocity.GroundSpeed-defaultApproachSpeed) > 1 {
+			t.Errorf("Glide speed = %.1f, want %.1f", sim.state.Velocity.GroundSpeed, defaultApproachSpeed)
+		}
+	}
+
+	if sim.state.Phase != models.FlightPhaseLanded || sim.activeCommand != nil {
+		t.Fatalf("Phase = %s with command %v, want a forced landing", sim.state.Phase, sim.activeCommand)
+	}
+	if sim.state.Position.Altitude != 0 || sim.state.Velocity != (models.Velocity{}) {
+		t.Errorf("State after forced landing = %+v, want stopped on the ground", sim.state)
+	}
+}
+
+func TestSimulator_FuelExhaustion(t *testing.T) {
+	simCfg, envCfg := createTestConfig()
+	simCfg.Fuel = config.FuelConfig{Enabled: true, Capacity: 1, IdleFlow: 100, CruiseFlow: 600, ClimbFlow: 20}
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	sim, err := New(simCfg, envCfg, logger)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if sim.state.Fuel == nil || *sim.state.Fuel != 1 {
+		t.Fatalf("Initial fuel = %v, want 1 kg", sim.state.Fuel)
+	}
+
+	cmd := models.NewCommand(models.CommandTypeGoTo)
+	cmd.GoTo = &models.GoToCommand{Target: models.Position{Latitude: 32.5, Longitude: 34.0, Altitude: 1000}}
+	sim.handleCommand(cmd)
+
+	// 1 kg lasts at least 6 s at the cruise flow
+	fuel := *sim.state.Fuel
+	for i := 0; i < 50; i++ {
+		sim.tick()
+		if *sim.state.Fuel >= fuel {
+			t.Fatalf("Fuel = %.4f after %.4f, want burning", *sim.state.Fuel, fuel)
+		}
+		fuel = *sim.state.Fuel
+	}
+	if sim.state.EngineFailure {
+		t.Fatal("Engine failed with fuel left")
+	}
+
+	for i := 0; i < 200 && !sim.state.EngineFailure; i++ {
+		sim.tick()
+	}
+	if !sim.state.EngineFailure || *sim.state.Fuel != 0 {
+		t.Fatalf("Fuel = %.4f, engine failure = %v, want an empty tank failing the engine", *sim.state.Fuel, sim.state.EngineFailure)
+	}
+	events, _ := sim.GetEvents().Since(0)
+	last := events[len(events)-1]
+	if last.Type != models.EventEnvironmentChanged || last.Message != "Fuel exhausted" {
+		t.Errorf("Last event = %s %q, want environment_changed \"Fuel exhausted\"", last.Type, last.Message)
+	}
+}
+
+func TestSimulator_SessionReplay(t *testing.T) {
+	simCfg, envCfg := createTestConfig()
+	simCfg.TickRateHz = 100
+	simCfg.Geofence = config.GeofenceConfig{Enabled: true, DefaultAction: "hold"}
+	simCfg.Recorder = config.RecorderConfig{Enabled: true, MaxSamples: 1000}
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	sim, err := New(simCfg, envCfg, logger)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	writer, err := session.Create(t.TempDir(), sim.SessionHeader())
+	if err != nil {
+		t.Fatalf("session.Create() error = %v", err)
+	}
+	sim.RecordSession(writer)
+
+	// Run live with commands, a heartbeat, a geofence change and a failure at
+	// arbitrary wall-clock times
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		sim.Run(ctx)
+		close(done)
+	}()
+
+	goTo := models.NewCommand(models.CommandTypeGoTo)
+	goTo.GoTo = &models.GoToCommand{
+		Target: models.Position{Latitude: 32.1, Longitude: 34.05, Altitude: 1200},
+		Speed:  ptr(120.0),
+	}
+	if err := sim.SubmitCommand(ctx, goTo); err != nil {
+		t.Fatalf("SubmitCommand() error = %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if _, err := sim.AddGeofence(ctx, models.Geofence{
+		Shape:   models.GeofenceShapeCircle,
+		Mode:    models.GeofenceModeExclusion,
+		Center:  &models.Coordinate{Latitude: 32.002, Longitude: 34.0},
+		RadiusM: 50,
+	}); err != nil {
+		t.Fatalf("AddGeofence() error = %v", err)
+	}
+	if err := sim.Heartbeat(ctx); err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	hold := models.NewCommand(models.CommandTypeHold)
+	if err := sim.SubmitCommand(ctx, hold); err != nil {
+		t.Fatalf("SubmitCommand() error = %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	failed := true
+	if err := sim.SetConditions(ctx, models.ConditionsUpdate{EngineFailure: &failed}); err != nil {
+		t.Fatalf("SetConditions() error = %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	cancel()
+	<-done
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	live := sim.GetRecorder().Samples(time.Time{}, time.Time{})
+
+	// Replay the log without pacing
+	log, err := session.Open(writer.Path())
+	if err != nil {
+		t.Fatalf("session.Open() error = %v", err)
+	}
+	replay, err := NewReplay(log, 1, logger)
+	if err != nil {
+		t.Fatalf("NewReplay() error = %v", err)
+	}
+	for !replay.replay.finished {
+		replay.replayStep()
+	}
+
+	replayed := replay.GetRecorder().Samples(time.Time{}, time.Time{})
+	if len(replayed) != len(live) || len(live) == 0 {
+		t.Fatalf("replayed %d states, recorded %d", len(replayed), len(live))
+	}
+	for i := range live {
+		if replayed[i].Position != live[i].Position || replayed[i].Velocity != live[i].Velocity ||
+			replayed[i].Heading != live[i].Heading || replayed[i].SimTime != live[i].SimTime {
+			t.Fatalf("state %d differs:\nlive   %+v\nreplay %+v", i, live[i], replayed[i])
+		}
+	}
+	if replay.GetGeofences().Count() != 1 {
+		t.Errorf("replayed geofences = %d, want 1", replay.GetGeofences().Count())
+	}
+
+	// Seeking backwards re-simulates from the start
+	replay.seekReplay(0.05)
+	if replay.state.Position != live[4].Position || replay.state.SimTime != live[4].SimTime {
+		t.Errorf("state after seek = %+v at %.2fs, want %+v", replay.state.Position, replay.state.SimTime, live[4].Position)
+	}
+
+	if err := replay.SubmitCommand(context.Background(), hold); !errors.Is(err, models.ErrReplayActive) {
+		t.Errorf("SubmitCommand() during replay error = %v, want %v", err, models.ErrReplayActive)
+	}
+}
+
+func TestSimulator_SnapshotRestore(t *testing.T) {
+	simCfg, envCfg := createTestConfig()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	sim, err := New(simCfg, envCfg, logger)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	cmd := models.NewCommand(models.CommandTypeTrajectory)
+	cmd.Trajectory = &models.TrajectoryCommand{Waypoints: []models.Waypoint{
+		{Position: models.Position{Latitude: 32.001, Longitude: 34.0, Altitude: 1000}, HoldSeconds: 2},
+		{Position: models.Position{Latitude: 32.01, Longitude: 34.01, Altitude: 1100}},
+	}}
+	sim.handleCommand(cmd)
+	for i := 0; i < 30; i++ {
+		sim.tick()
+	}
+
+	// Snapshots survive a JSON round trip
+	data, err := json.Marshal(sim.captureSnapshot())
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var snap models.Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if snap.Progress.WaypointIndex == nil || snap.ActiveCommand == nil || snap.ActiveCommand.ID != cmd.ID {
+		t.Fatalf("snapshot = %+v, want trajectory progress", snap)
+	}
+
+	// Flying on from the snapshot twice gives the same result
+	for i := 0; i < 50; i++ {
+		sim.tick()
+	}
+	want := sim.state
+
+	sim.restoreSnapshot(snap)
+	if sim.state.Position != snap.State.Position || sim.state.SimTime != want.SimTime {
+		t.Errorf("after restore position = %+v sim time %.1f, want %+v and the clock kept at %.1f",
+			sim.state.Position, sim.state.SimTime, snap.State.Position, want.SimTime)
+	}
+	for i := 0; i < 50; i++ {
+		sim.tick()
+	}
+	if sim.state.Position != want.Position || sim.state.Velocity != want.Velocity || sim.state.Heading != want.Heading {
+		t.Errorf("state after restore and 50 ticks = %+v, want %+v", sim.state, want)
+	}
+	if sim.state.SimTime <= want.SimTime {
+		t.Errorf("sim time went back to %.1f, want after %.1f", sim.state.SimTime, want.SimTime)
+	}
+	if n := len(eventsOf(sim, models.EventCommandCancelled)); n != 0 {
+		t.Errorf("cancelled events = %d, want none: the snapshot continues the command", n)
+	}
+
+	// A command the snapshot does not continue ends with the restore
+	hold := models.NewCommand(models.CommandTypeHold)
+	sim.handleCommand(hold)
+	sim.restoreSnapshot(snap)
+	cancelled := eventsOf(sim, models.EventCommandCancelled)
+	if len(cancelled) != 1 || cancelled[0].Command.ID != hold.ID {
+		t.Errorf("cancelled events = %+v, want the hold cancelled", cancelled)
+	}
+}
+
+func TestSimulator_SnapshotRestoresConditions(t *testing.T) {
+	simCfg, envCfg := createTestConfig()
+	envCfg = config.EnvironmentConfig{
+		Enabled: true,
+		Wind:    config.WindConfig{Enabled: true, Direction: 270, Speed: 5},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	
+	sim, err := New(simCfg, envCfg, logger)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	sim.tick()
+	snap := sim.captureSnapshot()
+	
+	// A scenario sets a strong wind and fails the engine
+	failed := true
+	sim.applyConditions(models.ConditionsUpdate{Wind: &models.WindVector{Direction: 90, Speed: 20}, EngineFailure: &failed})
+	_, lastID := sim.GetEvents().Since(0)
+	
+	sim.restoreSnapshot(snap)
+	if wind := sim.environment.GetState().Wind; wind == nil || *wind != (models.WindVector{Direction: 270, Speed: 5}) {
+		t.Errorf("wind after restore = %+v, want 5 m/s from 270", wind)
+	}
+	if sim.state.EngineFailure {
+		t.Error("engine still failed after restore")
+	}
+	
+	events, _ := sim.GetEvents().Since(lastID)
+	var windRestored, engineRestored bool
+	for _, event := range events {
+		if event.Type != models.EventEnvironmentChanged || event.Conditions == nil {
+			continue
+		}
+		windRestored = windRestored || event.Conditions.Wind != nil
+		engineRestored = engineRestored || event.Conditions.EngineFailure != nil
+	}
+	if !windRestored || !engineRestored {
+		t.Errorf("events after restore = %+v, want environment_changed for the wind and the engine", events)
+	}
+	
+	// Restoring the same conditions changes nothing
+	_, lastID = sim.GetEvents().Since(0)
+	sim.restoreSnapshot(snap)
+	if events, _ := sim.GetEvents().Since(lastID); len(events) != 0 {
+		t.Errorf("events after restoring unchanged conditions = %+v, want none", events)
+	}
+}
+
+func TestSimulator_SetState(t *testing.T) {
+	simCfg, envCfg := createTestConfig()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	sim, err := New(simCfg, envCfg, logger)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go sim.Run(ctx)
+
+	cmd := models.NewCommand(models.CommandTypeGoTo)
+	cmd.GoTo = &models.GoToCommand{Target: models.Position{Latitude: 32.1, Longitude: 34.0, Altitude: 1200}}
+	if err := sim.SubmitCommand(ctx, cmd); err != nil {
+		t.Fatalf("SubmitCommand() error = %v", err)
+	}
+
+	// Keeping the command
+	position := models.Position{Latitude: 31.9, Longitude: 34.1, Altitude: 800}
+	state, err := sim.SetState(ctx, models.StateUpdate{Position: position, Heading: ptr(45), GroundSpeed: ptr(120)})
+	if err != nil {
+		t.Fatalf("SetState() error = %v", err)
+	}
+	if state.Position != position || state.Heading != 45 || state.Velocity.GroundSpeed != 120 {
+		t.Errorf("SetState() = %+v, want position %+v, heading 45 and ground speed 120", state, position)
+	}
+	snap, _ := sim.Snapshot(ctx)
+	if snap.ActiveCommand == nil || snap.ActiveCommand.ID != cmd.ID {
+		t.Errorf("active command after SetState() = %+v, want %s", snap.ActiveCommand, cmd.ID)
+	}
+
+	// Clearing the command and parking on the ground
+	ground := models.Position{Latitude: 32.0, Longitude: 34.0, Altitude: 0}
+	state, err = sim.SetState(ctx, models.StateUpdate{Position: ground, GroundSpeed: ptr(0), VerticalSpeed: ptr(0), ClearCommand: true})
+	if err != nil {
+		t.Fatalf("SetState() error = %v", err)
+	}
+	if state.Position != ground || state.Phase != models.FlightPhaseParked || state.Heading != 45 {
+		t.Errorf("SetState() = %+v, want parked at %+v with the heading kept", state, ground)
+	}
+	snap, _ = sim.Snapshot(ctx)
+	if snap.ActiveCommand != nil || snap.Progress.WaypointIndex != nil {
+		t.Errorf("snapshot after clearing = %+v, want no command", snap)
+	}
+}
+
+func TestSimulator_WaypointEvents(t *testing.T) {
+	simCfg, envCfg := createTestConfig()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	sim, err := New(simCfg, envCfg, logger)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	cmd := models.NewCommand(models.CommandTypeTrajectory)
+	cmd.Trajectory = &models.TrajectoryCommand{
+		Waypoints: []models.Waypoint{
+			{Position: models.Position{Latitude: 32.01, Longitude: 34.0, Altitude: 1000}},
+			{Position: models.Position{Latitude: 32.01, Longitude: 34.01, Altitude: 1000}},
+		},
+		Loop: true,
+	}
+	sim.handleCommand(cmd)
+
+	for i := 0; i < 5000 && len(eventsOf(sim, models.EventTrajectoryLooped)) == 0; i++ {
+		sim.tick()
+	}
+
+	events := eventsOf(sim, models.EventWaypointReached, models.EventTrajectoryLooped)
+	want := []models.EventType{models.EventWaypointReached, models.EventWaypointReached, models.EventTrajectoryLooped}
+	if len(events) != len(want) {
+		t.Fatalf("Events = %d, want %d: two waypoints, then the loop", len(events), len(want))
+	}
+	for i, event := range events {
+		if event.Type != want[i] || event.Command == nil || event.Command.ID != cmd.ID {
+			t.Errorf("Event %d = %s of %v, want %s of %s", i, event.Type, event.Command, want[i], cmd.ID)
+		}
+		if event.Type == models.EventWaypointReached && (event.WaypointIndex == nil || *event.WaypointIndex != i) {
+			t.Errorf("Event %d waypoint index = %v, want %d", i, event.WaypointIndex, i)
+		}
+	}
+	if sim.activeCommand == nil || sim.trajectoryState.currentWaypointIndex != 0 {
+		t.Error("Looping trajectory did not restart from the first waypoint")
+	}
+}
+
+func TestSimulator_PhaseChangedEvents(t *testing.T) {
+	simCfg, envCfg := createTestConfig()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	sim, err := New(simCfg, envCfg, logger)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	cmd := models.NewCommand(models.CommandTypeGoTo)
+	cmd.GoTo = &models.GoToCommand{Target: models.Position{Latitude: 32.05, Longitude: 34.0, Altitude: 1300}}
+	sim.handleCommand(cmd)
+	for i := 0; i < 2000 && sim.activeCommand != nil; i++ {
+		sim.tick()
+	}
+	sim.tick()
+
+	events := eventsOf(sim, models.EventPhaseChanged)
+	want := []models.PhaseChange{
+		{From: models.FlightPhaseCruise, To: models.FlightPhaseClimb},
+		{From: models.FlightPhaseClimb, To: models.FlightPhaseCruise},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("Phase changes = %d, want %d", len(events), len(want))
+	}
+	for i, event := range events {
+		if event.Phase == nil || *event.Phase != want[i] {
+			t.Errorf("Phase change %d = %v, want %v", i, event.Phase, want[i])
+		}
+	}
+}
+
+func TestSimulator_TerrainWarningEvent(t *testing.T) {
+	sim := createTerrainTestSimulator(t, 980.0)
+
+	// One warning per engagement of the pull-up, not one per tick
+	for i := 0; i < 10; i++ {
+		sim.tick()
+	}
+
+	events := eventsOf(sim, models.EventTerrainWarning)
+	if len(events) != 1 {
+		t.Fatalf("Terrain warnings = %d, want 1", len(events))
+	}
+	if events[0].Terrain == nil || !events[0].Terrain.PullUp {
+		t.Errorf("Terrain warning = %+v, want the pull-up engaged", events[0].Terrain)
+	}
+}
+
+func TestSimulator_GeofenceEvents(t *testing.T) {
+	simCfg, envCfg := createTestConfig()
+	simCfg.Geofence = config.GeofenceConfig{Enabled: true, DefaultAction: "event"}
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	sim, err := New(simCfg, envCfg, logger)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	// Exclusion zone across the path
+	zone := sim.GetGeofences().Add(models.Geofence{
+		Name:  "strip",
+		Shape: models.GeofenceShapePolygon,
+		Mode:  models.GeofenceModeExclusion,
+		Vertices: []models.Coordinate{
+			{Latitude: 32.001, Longitude: 33.99},
+			{Latitude: 32.001, Longitude: 34.01},
+			{Latitude: 32.005, Longitude: 34.01},
+			{Latitude: 32.005, Longitude: 33.99},
+		},
+	})
+
+	cmd := models.NewCommand(models.CommandTypeGoTo)
+	cmd.GoTo = &models.GoToCommand{
+		Target: models.Position{Latitude: 32.1, Longitude: 34.0, Altitude: 1000},
+		Speed:  ptr(100.0),
+	}
+	sim.handleCommand(cmd)
+	for i := 0; i < 200; i++ {
+		sim.tick()
+	}
+
+	events := eventsOf(sim, models.EventGeofenceBreach, models.EventGeofenceCleared)
+	want := []models.EventType{models.EventGeofenceBreach, models.EventGeofenceCleared}
+	if len(events) != len(want) {
+		t.Fatalf("Geofence events = %d, want a breach, then its clearing", len(events))
+	}
+	for i, event := range events {
+		if event.Type != want[i] || event.Geofence == nil || event.Geofence.ZoneID != zone.ID || event.Geofence.Name != "strip" {
+			t.Errorf("Event %d = %s of %+v, want %s of %s", i, event.Type, event.Geofence, want[i], zone.ID)
+		}
+	}
+	if sim.activeCommand == nil || sim.activeCommand.ID != cmd.ID {
+		t.Error("The event action changed the active command")
+	}
+}
+
+// eventsOf returns the events of the given types emitted so far, in order.
+func eventsOf(sim *Simulator, types ...models.EventType) []models.Event {
+	events, _ := sim.GetEvents().Since(0)
+	var matching []models.Event
+	for _, event := range events {
+		if slices.Contains(types, event.Type) {
+			matching = append(matching, event)
+		}
+	}
+	return matching
+}
+
 func ptr(f float64) *float64 {
 	return &f
 }
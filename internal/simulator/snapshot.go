@@ -0,0 +1,208 @@
+package simulator
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+)
+
+// snapshotRequest asks the simulation loop to capture or restore a snapshot,
+// or to reposition the aircraft.
+type snapshotRequest struct {
+	restore *models.Snapshot    // nil to capture only
+	update  *models.StateUpdate // applied to a fresh capture, then restored
+	reply   chan models.Snapshot
+}
+
+// Snapshot captures the complete simulator state between two ticks. The
+// caller assigns the snapshot ID and name.
+func (s *Simulator) Snapshot(ctx context.Context) (models.Snapshot, error) {
+	return s.snapshotControl(ctx, snapshotRequest{})
+}
+
+// RestoreSnapshot replaces the simulator state with a snapshot between two
+// ticks and returns the restored state. The simulation clock keeps running:
+// the snapshot's sim_time and timestamp are not restored. The wind and engine
+// failure are restored with the state, see restoreConditions.
+func (s *Simulator) RestoreSnapshot(ctx context.Context, snap models.Snapshot) (models.AircraftState, error) {
+	if s.replay != nil {
+		return models.AircraftState{}, models.ErrReplayActive
+	}
+	restored, err := s.snapshotControl(ctx, snapshotRequest{restore: &snap})
+	return restored.State, err
+}
+
+// SetState repositions the aircraft between two ticks and returns the new
+// state. Unless the update clears it, the active command continues from the
+// new position.
+func (s *Simulator) SetState(ctx context.Context, update models.StateUpdate) (models.AircraftState, error) {
+	if s.replay != nil {
+		return models.AircraftState{}, models.ErrReplayActive
+	}
+	updated, err := s.snapshotControl(ctx, snapshotRequest{update: &update})
+	return updated.State, err
+}
+
+// snapshotControl sends a request to the simulation loop.
+func (s *Simulator) snapshotControl(ctx context.Context, req snapshotRequest) (models.Snapshot, error) {
+	req.reply = make(chan models.Snapshot, 1)
+
+	select {
+	case s.snapshotRequests <- req:
+		return <-req.reply, nil
+	case <-ctx.Done():
+		return models.Snapshot{}, ctx.Err()
+	case <-time.After(1 * time.Second):
+		return models.Snapshot{}, models.ErrTimeout
+	}
+}
+
+// captureSnapshot copies the simulation state.
+func (s *Simulator) captureSnapshot() models.Snapshot {
+	snap := models.Snapshot{
+		CreatedAt:     s.now(),
+		State:         s.state,
+		ActiveCommand: s.activeCommand,
+		Mission:       s.mission,
+		Progress: models.SnapshotProgress{
+			PullUpActive:     s.pullUpActive,
+			LastSafePosition: s.lastSafePosition,
+			SinceContact:     s.sinceContact,
+		},
+	}
+	// The wind may have changed since the last tick
+	snap.State.Environment = s.environment.GetState()
+
+	p := &snap.Progress
+	if s.trajectoryState != nil {
+		index := s.trajectoryState.currentWaypointIndex
+		p.WaypointIndex = &index
+		p.HoldRemaining = s.trajectoryState.holdRemaining
+	}
+	if s.takeoffState != nil {
+		stage := int(s.takeoffState.stage)
+		runway := s.takeoffState.runway
+		p.TakeoffStage = &stage
+		p.TakeoffRunway = &runway
+	}
+	if s.landingState != nil {
+		stage := int(s.landingState.stage)
+		p.LandingStage = &stage
+	}
+	if s.rthState != nil {
+		phase := int(s.rthState.phase)
+		p.RTHPhase = &phase
+		p.RTHAltitude = s.rthState.altitude
+	}
+	for zoneID := range s.breachedZones {
+		p.BreachedZones = append(p.BreachedZones, zoneID)
+	}
+	sort.Strings(p.BreachedZones)
+
+	return snap
+}
+
+// updatedSnapshot captures the simulation state with a state update applied.
+// Restoring it applies the update; the session log records it as a restore.
+func (s *Simulator) updatedSnapshot(update models.StateUpdate) models.Snapshot {
+	snap := s.captureSnapshot()
+	snap.State.Position = update.Position
+	if update.Heading != nil {
+		snap.State.Heading = *update.Heading
+	}
+	if update.GroundSpeed != nil {
+		snap.State.Velocity.GroundSpeed = *update.GroundSpeed
+	}
+	if update.VerticalSpeed != nil {
+		snap.State.Velocity.VerticalSpeed = *update.VerticalSpeed
+	}
+	snap.Progress.PullUpActive = false
+
+	if update.ClearCommand {
+		snap.ActiveCommand = nil
+		snap.State.ActiveCommand = nil
+		snap.Progress = models.SnapshotProgress{
+			BreachedZones:    snap.Progress.BreachedZones,
+			LastSafePosition: snap.Progress.LastSafePosition,
+			SinceContact:     snap.Progress.SinceContact,
+		}
+	}
+	if snap.ActiveCommand == nil {
+		snap.State.Phase = placedPhase(snap.State, s.environment.GetTerrain())
+	}
+	return snap
+}
+
+// restoreSnapshot replaces the simulation state with a snapshot, keeping the
+// simulation clock, and re-applies its wind and engine failure. An active
+// command the snapshot does not continue is cancelled.
+func (s *Simulator) restoreSnapshot(snap models.Snapshot) {
+	if snap.ActiveCommand == nil || s.activeCommand == nil || snap.ActiveCommand.ID != s.activeCommand.ID {
+		s.cancelCommand("State restored")
+	}
+	s.restoreConditions(snap.State)
+
+	simTime, timestamp := s.state.SimTime, s.state.Timestamp
+	s.state = snap.State
+	s.state.SimTime = simTime
+	s.state.Timestamp = timestamp
+
+	p := snap.Progress
+	s.activeCommand = snap.ActiveCommand
+	s.mission = snap.Mission
+	s.pullUpActive = p.PullUpActive
+	s.lastSafePosition = p.LastSafePosition
+	s.sinceContact = p.SinceContact
+	s.linkLost = snap.State.LinkLost
+
+	s.trajectoryState = nil
+	if p.WaypointIndex != nil {
+		s.trajectoryState = &trajectoryState{
+			currentWaypointIndex: max(*p.WaypointIndex, 0),
+			holdRemaining:        p.HoldRemaining,
+		}
+	}
+	s.takeoffState = nil
+	if p.TakeoffStage != nil {
+		s.takeoffState = &takeoffState{stage: takeoffStage(*p.TakeoffStage)}
+		if p.TakeoffRunway != nil {
+			s.takeoffState.runway = *p.TakeoffRunway
+		}
+	}
+	s.landingState = nil
+	if p.LandingStage != nil {
+		s.landingState = &landingState{stage: landingStage(*p.LandingStage)}
+	}
+	s.rthState = nil
+	if p.RTHPhase != nil {
+		s.rthState = &rthState{phase: rthPhase(*p.RTHPhase), altitude: p.RTHAltitude}
+	}
+
+	s.breachedZones = make(map[string]bool, len(p.BreachedZones))
+	for _, zoneID := range p.BreachedZones {
+		s.breachedZones[zoneID] = true
+	}
+}
+
+// restoreConditions changes the wind and engine failure to those of a
+// snapshot's state, emitting environment_changed for each that changes. The
+// wind is kept when the environment is disabled, or the snapshot was taken
+// without it.
+func (s *Simulator) restoreConditions(state models.AircraftState) {
+	var update models.ConditionsUpdate
+	if s.environment.IsEnabled() && state.Environment != nil {
+		if current := s.environment.GetState(); !reflect.DeepEqual(current.Wind, state.Environment.Wind) {
+			update.Wind = state.Environment.Wind
+			if update.Wind == nil {
+				update.Wind = &models.WindVector{} // calm
+			}
+		}
+	}
+	if state.EngineFailure != s.state.EngineFailure {
+		update.EngineFailure = &state.EngineFailure
+	}
+	s.applyConditions(update)
+}
@@ -0,0 +1,133 @@
+package simulator
+
+import (
+	"math"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/pkg/geo"
+)
+
+const (
+	// defaultLookAheadSeconds is used when no look-ahead is configured.
+	defaultLookAheadSeconds = 30.0
+
+	// lookAheadSamples is the number of points sampled along the predicted track.
+	lookAheadSamples = 10
+)
+
+// lookAheadPoint is a terrain sample along the predicted track.
+type lookAheadPoint struct {
+	seconds   float64 // time from now
+	elevation float64 // terrain elevation, meters MSL
+}
+
+// lookAhead samples terrain elevation along the current track.
+// Returns nil when terrain is disabled.
+func (s *Simulator) lookAhead() []lookAheadPoint {
+	terrain := s.environment.GetTerrain()
+	if terrain == nil {
+		return nil
+	}
+
+	step := s.lookAheadSeconds / lookAheadSamples
+
+	points := make([]lookAheadPoint, 0, lookAheadSamples)
+	for i := 1; i <= lookAheadSamples; i++ {
+		t := step * float64(i)
+		lat, lon := geo.Destination(
+			s.state.Position.Latitude,
+			s.state.Position.Longitude,
+			s.state.Heading,
+			s.state.Velocity.GroundSpeed*t,
+		)
+		points = append(points, lookAheadPoint{
+			seconds:   t,
+			elevation: terrain.GetAltitude(lat, lon),
+		})
+	}
+
+	return points
+}
+
+// terrainElevation returns the terrain elevation below the aircraft.
+// Returns 0 (sea level) when terrain is disabled.
+func (s *Simulator) terrainElevation() float64 {
+	return s.environment.GetTerrain().GetAltitude(s.state.Position.Latitude, s.state.Position.Longitude)
+}
+
+// terrainFollowingVerticalSpeed returns the vertical speed needed to hold
+// targetAGL above the terrain along the predicted track.
+func (s *Simulator) terrainFollowingVerticalSpeed(targetAGL float64) float64 {
+	altitude := s.state.Position.Altitude
+	points := s.lookAhead()
+	if len(points) == 0 {
+		// No terrain: AGL is relative to sea level
+		return clamp(targetAGL-altitude, -s.config.MaxDescentRate, s.config.MaxClimbRate)
+	}
+
+	// Aim for the nearest sample, but never descend so fast that a later
+	// sample along the track would be missed
+	desired := (points[0].elevation + targetAGL - altitude) / points[0].seconds
+	for _, p := range points[1:] {
+		required := (p.elevation + targetAGL - altitude) / p.seconds
+		desired = math.Max(desired, required)
+	}
+
+	return clamp(desired, -s.config.MaxDescentRate, s.config.MaxClimbRate)
+}
+
+// terrainPullUp checks the predicted clearance at the given vertical speed
+// and returns the vertical speed to fly. If clearance anywhere along the
+// look-ahead falls below the safety margin, it commands a full-rate climb.
+func (s *Simulator) terrainPullUp(verticalSpeed float64) (float64, bool) {
+	terrain := s.environment.GetTerrain()
+	if terrain == nil {
+		return verticalSpeed, false
+	}
+
+	margin := terrain.SafetyMargin()
+	altitude := s.state.Position.Altitude
+
+	clearance := altitude - s.terrainElevation()
+	for _, p := range s.lookAhead() {
+		predicted := altitude + verticalSpeed*p.seconds
+		clearance = math.Min(clearance, predicted-p.elevation)
+	}
+
+	if clearance >= margin {
+		return verticalSpeed, false
+	}
+
+	return s.config.MaxClimbRate, true
+}
+
+// terrainAvoidanceArmed reports whether ground-collision avoidance may
+// override the vertical speed. It is disarmed on the ground and while taking
+// off or landing, when the aircraft is deliberately close to the terrain.
+func (s *Simulator) terrainAvoidanceArmed() bool {
+	if s.onGround() {
+		return false
+	}
+	if s.activeCommand != nil {
+		switch s.activeCommand.Type {
+		case models.CommandTypeTakeoff, models.CommandTypeLand:
+			return false
+		}
+	}
+	return true
+}
+
+// updateTerrainState refreshes the terrain section of the aircraft state.
+func (s *Simulator) updateTerrainState() {
+	if s.environment.GetTerrain() == nil {
+		s.state.Terrain = nil
+		return
+	}
+
+	elevation := s.terrainElevation()
+	s.state.Terrain = &models.TerrainState{
+		Elevation: elevation,
+		HeightAGL: s.state.Position.Altitude - elevation,
+		PullUp:    s.pullUpActive,
+	}
+}
@@ -0,0 +1,59 @@
+package simulator
+
+import (
+	"reflect"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/pubsub"
+)
+
+const (
+	// defaultAircraftID names the aircraft's topics when none is configured.
+	defaultAircraftID = "aircraft-1"
+
+	// environmentHistorySize is the number of recent environment updates
+	// kept for stream clients resuming after a reconnect.
+	environmentHistorySize = 100
+)
+
+// StateTopic is the topic of an aircraft's states.
+func StateTopic(aircraftID string) string {
+	return "aircraft/" + aircraftID + "/state"
+}
+
+// EnvironmentTopic is the topic of the environment around an aircraft,
+// published when it changes.
+func EnvironmentTopic(aircraftID string) string {
+	return "aircraft/" + aircraftID + "/environment"
+}
+
+// EventTopic is the topic of an aircraft's events of a type.
+func EventTopic(aircraftID string, eventType models.EventType) string {
+	return "aircraft/" + aircraftID + "/events/" + string(eventType)
+}
+
+// EventsPattern is the topic pattern of all of an aircraft's events.
+func EventsPattern(aircraftID string) string {
+	return "aircraft/" + aircraftID + "/events/**"
+}
+
+// AircraftID returns the ID naming the aircraft's topics.
+func (s *Simulator) AircraftID() string {
+	return s.aircraftID
+}
+
+// GetEnvironmentBroker returns the broker of the environment updates,
+// published on EnvironmentTopic.
+func (s *Simulator) GetEnvironmentBroker() *pubsub.Broker[models.EnvironmentState] {
+	return s.environmentFeed
+}
+
+// publishState publishes the current state, and the environment when it
+// changed.
+func (s *Simulator) publishState() {
+	if env := s.state.Environment; env != nil && !reflect.DeepEqual(env, s.lastEnvironment) {
+		s.environmentFeed.Publish(EnvironmentTopic(s.aircraftID), *env)
+		s.lastEnvironment = env
+	}
+	s.publisher.Publish(StateTopic(s.aircraftID), s.state)
+}
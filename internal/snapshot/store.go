@@ -0,0 +1,193 @@
+// Package snapshot stores simulator state snapshots, optionally persisted
+// as one JSON file per snapshot.
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+)
+
+// Store keeps snapshots in memory and, if it has a directory, as
+// <id>.json files in it. It is safe for concurrent use.
+type Store struct {
+	mu        sync.RWMutex
+	snapshots map[string]models.Snapshot
+	dir       string // empty = memory only
+}
+
+// NewStore creates a store. If dir is set, it is created if needed and the
+// snapshots saved in it are loaded.
+func NewStore(dir string) (*Store, error) {
+	s := &Store{
+		snapshots: make(map[string]models.Snapshot),
+		dir:       dir,
+	}
+	if dir == "" {
+		return s, nil
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		snap, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		s.snapshots[snap.ID] = snap
+	}
+	return s, nil
+}
+
+// Load reads a snapshot file.
+func Load(path string) (models.Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return models.Snapshot{}, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	var snap models.Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return models.Snapshot{}, fmt.Errorf("%w: %s: %v", models.ErrInvalidSnapshot, filepath.Base(path), err)
+	}
+	if err := Validate(snap); err != nil {
+		return models.Snapshot{}, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return snap, nil
+}
+
+// Validate checks that a snapshot can be restored.
+func Validate(snap models.Snapshot) error {
+	if snap.ID == "" || strings.ContainsAny(snap.ID, `/\`) {
+		return fmt.Errorf("%w: invalid id %q", models.ErrInvalidSnapshot, snap.ID)
+	}
+
+	cmd := snap.ActiveCommand
+	if cmd == nil {
+		return nil
+	}
+	var payload bool
+	switch cmd.Type {
+	case models.CommandTypeGoTo:
+		payload = cmd.GoTo != nil
+	case models.CommandTypeTrajectory:
+		payload = cmd.Trajectory != nil
+	case models.CommandTypeRTH:
+		payload = cmd.RTH != nil
+	case models.CommandTypeTakeoff:
+		payload = cmd.Takeoff != nil
+	case models.CommandTypeLand:
+		payload = cmd.Land != nil
+	case models.CommandTypeHold, models.CommandTypeStop:
+		payload = true
+	default:
+		return fmt.Errorf("%w: unknown command type %q", models.ErrInvalidSnapshot, cmd.Type)
+	}
+	if !payload {
+		return fmt.Errorf("%w: %s command without parameters", models.ErrInvalidSnapshot, cmd.Type)
+	}
+
+	if index := snap.Progress.WaypointIndex; index != nil && cmd.Trajectory != nil &&
+		(*index < 0 || *index > len(cmd.Trajectory.Waypoints)) {
+		return fmt.Errorf("%w: waypoint index %d out of range", models.ErrInvalidSnapshot, *index)
+	}
+	return nil
+}
+
+// Save stores a snapshot, assigning an ID if it has none, and writes it to
+// the store directory.
+func (s *Store) Save(snap models.Snapshot) (models.Snapshot, error) {
+	if snap.ID == "" {
+		snap.ID = uuid.New().String()
+	}
+	if err := Validate(snap); err != nil {
+		return models.Snapshot{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.dir != "" {
+		data, err := json.MarshalIndent(snap, "", "  ")
+		if err != nil {
+			return models.Snapshot{}, err
+		}
+		if err := os.WriteFile(s.path(snap.ID), append(data, '\n'), 0o644); err != nil {
+			return models.Snapshot{}, fmt.Errorf("failed to write snapshot: %w", err)
+		}
+	}
+	s.snapshots[snap.ID] = snap
+	return snap, nil
+}
+
+// Get returns a snapshot by ID.
+func (s *Store) Get(id string) (models.Snapshot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snap, exists := s.snapshots[id]
+	if !exists {
+		return models.Snapshot{}, fmt.Errorf("%w: %s", models.ErrSnapshotNotFound, id)
+	}
+	return snap, nil
+}
+
+// List returns all snapshots, oldest first.
+func (s *Store) List() []models.Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snapshots := make([]models.Snapshot, 0, len(s.snapshots))
+	for _, snap := range s.snapshots {
+		snapshots = append(snapshots, snap)
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		if !snapshots[i].CreatedAt.Equal(snapshots[j].CreatedAt) {
+			return snapshots[i].CreatedAt.Before(snapshots[j].CreatedAt)
+		}
+		return snapshots[i].ID < snapshots[j].ID
+	})
+	return snapshots
+}
+
+// Count returns the number of stored snapshots.
+func (s *Store) Count() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.snapshots)
+}
+
+// Delete removes a snapshot and its file.
+func (s *Store) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.snapshots[id]; !exists {
+		return fmt.Errorf("%w: %s", models.ErrSnapshotNotFound, id)
+	}
+	if s.dir != "" {
+		if err := os.Remove(s.path(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to delete snapshot: %w", err)
+		}
+	}
+	delete(s.snapshots, id)
+	return nil
+}
+
+// path returns the file path of a snapshot.
+func (s *Store) path(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
@@ -0,0 +1,119 @@
+package snapshot
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+)
+
+func testSnapshot(name string, created time.Time) models.Snapshot {
+	index := 1
+	return models.Snapshot{
+		Name:      name,
+		CreatedAt: created,
+		State: models.AircraftState{
+			Position: models.Position{Latitude: 32.0, Longitude: 34.8, Altitude: 1000},
+			Phase:    models.FlightPhaseApproach,
+		},
+		ActiveCommand: &models.Command{
+			ID:   "cmd-1",
+			Type: models.CommandTypeTrajectory,
+			Trajectory: &models.TrajectoryCommand{Waypoints: []models.Waypoint{
+				{Position: models.Position{Latitude: 32.1, Longitude: 34.8, Altitude: 1000}},
+				{Position: models.Position{Latitude: 32.2, Longitude: 34.8, Altitude: 500}},
+			}},
+		},
+		Progress: models.SnapshotProgress{WaypointIndex: &index},
+	}
+}
+
+func TestStore_PersistsSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	created := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	store, err := NewStore(dir)
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+	first, err := store.Save(testSnapshot("final", created))
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if first.ID == "" {
+		t.Fatal("Save() did not assign an ID")
+	}
+	second, err := store.Save(testSnapshot("downwind", created.Add(time.Minute)))
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, first.ID+".json")); err != nil {
+		t.Errorf("snapshot file not written: %v", err)
+	}
+
+	// A new store loads the saved snapshots
+	reloaded, err := NewStore(dir)
+	if err != nil {
+		t.Fatalf("NewStore() reload error = %v", err)
+	}
+	list := reloaded.List()
+	if len(list) != 2 || list[0].ID != first.ID || list[1].ID != second.ID {
+		t.Fatalf("List() after reload = %+v, want [%s %s]", list, first.ID, second.ID)
+	}
+	got, err := reloaded.Get(first.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Name != "final" || *got.Progress.WaypointIndex != 1 || got.ActiveCommand.Trajectory == nil {
+		t.Errorf("Get() = %+v, want the saved snapshot", got)
+	}
+
+	if err := reloaded.Delete(first.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, first.ID+".json")); !os.IsNotExist(err) {
+		t.Errorf("snapshot file still exists after Delete(): %v", err)
+	}
+	if _, err := reloaded.Get(first.ID); !errors.Is(err, models.ErrSnapshotNotFound) {
+		t.Errorf("Get() after delete error = %v, want %v", err, models.ErrSnapshotNotFound)
+	}
+	if reloaded.Count() != 1 {
+		t.Errorf("Count() = %d, want 1", reloaded.Count())
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := testSnapshot("", time.Time{})
+	valid.ID = "snap-1"
+
+	tests := []struct {
+		name   string
+		modify func(s *models.Snapshot)
+		valid  bool
+	}{
+		{"valid", func(s *models.Snapshot) {}, true},
+		{"no command", func(s *models.Snapshot) { s.ActiveCommand = nil; s.Progress = models.SnapshotProgress{} }, true},
+		{"missing id", func(s *models.Snapshot) { s.ID = "" }, false},
+		{"path in id", func(s *models.Snapshot) { s.ID = "../etc/passwd" }, false},
+		{"unknown command", func(s *models.Snapshot) { s.ActiveCommand = &models.Command{Type: "teleport"} }, false},
+		{"missing parameters", func(s *models.Snapshot) { s.ActiveCommand = &models.Command{Type: models.CommandTypeGoTo} }, false},
+		{"waypoint index out of range", func(s *models.Snapshot) { index := 3; s.Progress.WaypointIndex = &index }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snap := valid
+			tt.modify(&snap)
+			err := Validate(snap)
+			if tt.valid && err != nil {
+				t.Errorf("Validate() error = %v", err)
+			}
+			if !tt.valid && !errors.Is(err, models.ErrInvalidSnapshot) {
+				t.Errorf("Validate() error = %v, want %v", err, models.ErrInvalidSnapshot)
+			}
+		})
+	}
+}
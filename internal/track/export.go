@@ -0,0 +1,282 @@
+package track
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+)
+
+// Format identifies a track export format.
+type Format string
+
+const (
+	FormatKML     Format = "kml"
+	FormatGPX     Format = "gpx"
+	FormatGeoJSON Format = "geojson"
+	FormatCZML    Format = "czml"
+	FormatCSV     Format = "csv"
+)
+
+// timeLayout is ISO 8601 with milliseconds, as accepted by Google Earth,
+// GPX readers and Cesium.
+const timeLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// ContentType returns the MIME type of the format.
+func (f Format) ContentType() string {
+	switch f {
+	case FormatKML:
+		return "application/vnd.google-earth.kml+xml"
+	case FormatGPX:
+		return "application/gpx+xml"
+	case FormatGeoJSON:
+		return "application/geo+json"
+	case FormatCZML:
+		return "application/json"
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// Export writes samples as a flight track. Sample times are the epoch plus
+// each sample's simulation time; altitudes are absolute (meters MSL).
+func Export(w io.Writer, format Format, name string, epoch time.Time, samples []models.AircraftState) error {
+	switch format {
+	case FormatKML:
+		return exportKML(w, name, epoch, samples)
+	case FormatGPX:
+		return exportGPX(w, name, epoch, samples)
+	case FormatGeoJSON:
+		return exportGeoJSON(w, name, epoch, samples)
+	case FormatCZML:
+		return exportCZML(w, name, epoch, samples)
+	case FormatCSV:
+		return exportCSV(w, epoch, samples)
+	default:
+		return fmt.Errorf("unsupported track format %q", format)
+	}
+}
+
+// sampleTime returns the simulation timestamp of a sample in UTC.
+func sampleTime(epoch time.Time, sample models.AircraftState) time.Time {
+	return epoch.Add(time.Duration(sample.SimTime * float64(time.Second))).UTC()
+}
+
+// formatFloat formats a coordinate without trailing zeros.
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// exportKML writes a gx:Track, which Google Earth plays back with its time slider.
+func exportKML(w io.Writer, name string, epoch time.Time, samples []models.AircraftState) error {
+	type kmlTrack struct {
+		AltitudeMode string   `xml:"altitudeMode"`
+		When         []string `xml:"when"`
+		Coords       []string `xml:"gx:coord"`
+	}
+	type kmlPlacemark struct {
+		Name  string   `xml:"name"`
+		Track kmlTrack `xml:"gx:Track"`
+	}
+	type kmlDocument struct {
+		XMLName   xml.Name     `xml:"kml"`
+		Namespace string       `xml:"xmlns,attr"`
+		GxNS      string       `xml:"xmlns:gx,attr"`
+		Name      string       `xml:"Document>name"`
+		Placemark kmlPlacemark `xml:"Document>Placemark"`
+	}
+
+	doc := kmlDocument{
+		Namespace: "http://www.opengis.net/kml/2.2",
+		GxNS:      "http://www.google.com/kml/ext/2.2",
+		Name:      name,
+		Placemark: kmlPlacemark{
+			Name:  name,
+			Track: kmlTrack{AltitudeMode: "absolute"},
+		},
+	}
+	for _, s := range samples {
+		doc.Placemark.Track.When = append(doc.Placemark.Track.When, sampleTime(epoch, s).Format(timeLayout))
+		doc.Placemark.Track.Coords = append(doc.Placemark.Track.Coords, fmt.Sprintf("%s %s %s",
+			formatFloat(s.Position.Longitude), formatFloat(s.Position.Latitude), formatFloat(s.Position.Altitude)))
+	}
+
+	return writeXML(w, doc)
+}
+
+// exportGPX writes a GPX 1.1 track. Elevations are meters MSL.
+func exportGPX(w io.Writer, name string, epoch time.Time, samples []models.AircraftState) error {
+	type gpxPoint struct {
+		Lat       float64 `xml:"lat,attr"`
+		Lon       float64 `xml:"lon,attr"`
+		Elevation float64 `xml:"ele"`
+		Time      string  `xml:"time"`
+	}
+	type gpxDocument struct {
+		XMLName   xml.Name   `xml:"gpx"`
+		Namespace string     `xml:"xmlns,attr"`
+		Version   string     `xml:"version,attr"`
+		Creator   string     `xml:"creator,attr"`
+		Name      string     `xml:"trk>name"`
+		Points    []gpxPoint `xml:"trk>trkseg>trkpt"`
+	}
+
+	doc := gpxDocument{
+		Namespace: "http://www.topografix.com/GPX/1/1",
+		Version:   "1.1",
+		Creator:   "Flight-Simulator",
+		Name:      name,
+	}
+	for _, s := range samples {
+		doc.Points = append(doc.Points, gpxPoint{
+			Lat:       s.Position.Latitude,
+			Lon:       s.Position.Longitude,
+			Elevation: s.Position.Altitude,
+			Time:      sampleTime(epoch, s).Format(timeLayout),
+		})
+	}
+
+	return writeXML(w, doc)
+}
+
+// writeXML writes an indented XML document with a declaration.
+func writeXML(w io.Writer, doc interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// exportGeoJSON writes a LineString feature with per-point "coordTimes".
+func exportGeoJSON(w io.Writer, name string, epoch time.Time, samples []models.AircraftState) error {
+	coordinates := make([][3]float64, len(samples))
+	times := make([]string, len(samples))
+	for i, s := range samples {
+		coordinates[i] = [3]float64{s.Position.Longitude, s.Position.Latitude, s.Position.Altitude}
+		times[i] = sampleTime(epoch, s).Format(timeLayout)
+	}
+
+	feature := map[string]interface{}{
+		"type": "Feature",
+		"properties": map[string]interface{}{
+			"name":       name,
+			"coordTimes": times,
+		},
+		"geometry": map[string]interface{}{
+			"type":        "LineString",
+			"coordinates": coordinates,
+		},
+	}
+	return json.NewEncoder(w).Encode(feature)
+}
+
+// exportCZML writes a CZML document with a time-tagged position for Cesium.
+// Positions are sampled in seconds since the first sample.
+func exportCZML(w io.Writer, name string, epoch time.Time, samples []models.AircraftState) error {
+	if len(samples) == 0 {
+		return json.NewEncoder(w).Encode([]interface{}{czmlDocumentPacket(name, "", "")})
+	}
+
+	start := sampleTime(epoch, samples[0])
+	end := sampleTime(epoch, samples[len(samples)-1])
+	interval := start.Format(timeLayout) + "/" + end.Format(timeLayout)
+
+	positions := make([]float64, 0, len(samples)*4)
+	for _, s := range samples {
+		positions = append(positions,
+			sampleTime(epoch, s).Sub(start).Seconds(),
+			s.Position.Longitude,
+			s.Position.Latitude,
+			s.Position.Altitude,
+		)
+	}
+
+	aircraft := map[string]interface{}{
+		"id":           "aircraft",
+		"name":         name,
+		"availability": interval,
+		"position": map[string]interface{}{
+			"epoch":               start.Format(timeLayout),
+			"cartographicDegrees": positions,
+		},
+		"point": map[string]interface{}{
+			"pixelSize":       10,
+			"heightReference": "NONE", // absolute altitudes
+		},
+		"path": map[string]interface{}{
+			"width":    2,
+			"leadTime": 0,
+			"material": map[string]interface{}{
+				"solidColor": map[string]interface{}{
+					"color": map[string]interface{}{"rgba": []int{255, 255, 0, 255}},
+				},
+			},
+		},
+	}
+
+	return json.NewEncoder(w).Encode([]interface{}{
+		czmlDocumentPacket(name, interval, start.Format(timeLayout)),
+		aircraft,
+	})
+}
+
+// czmlDocumentPacket returns the document packet that sets the clock.
+func czmlDocumentPacket(name, interval, currentTime string) map[string]interface{} {
+	packet := map[string]interface{}{
+		"id":      "document",
+		"name":    name,
+		"version": "1.0",
+	}
+	if interval != "" {
+		packet["clock"] = map[string]interface{}{
+			"interval":    interval,
+			"currentTime": currentTime,
+			"multiplier":  1,
+			"range":       "LOOP_STOP",
+		}
+	}
+	return packet
+}
+
+// exportCSV writes one row per sample.
+func exportCSV(w io.Writer, epoch time.Time, samples []models.AircraftState) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{
+		"time", "sim_time", "latitude", "longitude", "altitude_msl",
+		"ground_speed", "vertical_speed", "heading", "phase",
+	}); err != nil {
+		return err
+	}
+
+	for _, s := range samples {
+		if err := writer.Write([]string{
+			sampleTime(epoch, s).Format(timeLayout),
+			strconv.FormatFloat(s.SimTime, 'f', 3, 64),
+			formatFloat(s.Position.Latitude),
+			formatFloat(s.Position.Longitude),
+			strconv.FormatFloat(s.Position.Altitude, 'f', 2, 64),
+			strconv.FormatFloat(s.Velocity.GroundSpeed, 'f', 2, 64),
+			strconv.FormatFloat(s.Velocity.VerticalSpeed, 'f', 2, 64),
+			strconv.FormatFloat(s.Heading, 'f', 1, 64),
+			string(s.Phase),
+		}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
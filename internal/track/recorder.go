@@ -0,0 +1,115 @@
+// Package track records the aircraft's state history and exports it as
+// KML, GPX, GeoJSON, CZML or CSV flight tracks.
+package track
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+)
+
+// Recorder keeps the most recent aircraft state samples in a ring buffer.
+// Samples are timestamped with simulation time: the recorder epoch plus the
+// state's SimTime. It is safe for concurrent use.
+type Recorder struct {
+	mu         sync.RWMutex
+	samples    []models.AircraftState
+	start      int // index of the oldest sample
+	count      int
+	interval   float64 // seconds of simulation time between samples
+	lastSample float64
+	epoch      time.Time
+}
+
+// NewRecorder creates a recorder that keeps up to maxSamples samples, taken
+// at most once per interval of simulation time (0 = every state). The epoch
+// is the wall-clock time at simulation time zero.
+func NewRecorder(maxSamples int, interval time.Duration, epoch time.Time) *Recorder {
+	if maxSamples <= 0 {
+		maxSamples = 1
+	}
+	return &Recorder{
+		samples:    make([]models.AircraftState, maxSamples),
+		interval:   interval.Seconds(),
+		lastSample: math.Inf(-1),
+		epoch:      epoch,
+	}
+}
+
+// Record adds a state sample, unless the previous sample is more recent than
+// the sampling interval. The oldest sample is dropped when the buffer is full.
+func (r *Recorder) Record(state models.AircraftState) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if state.SimTime-r.lastSample < r.interval {
+		return
+	}
+	r.lastSample = state.SimTime
+
+	// Geofence breaches and environment details are not part of the track
+	state.GeofenceBreaches = nil
+	state.Environment = nil
+
+	index := (r.start + r.count) % len(r.samples)
+	r.samples[index] = state
+	if r.count < len(r.samples) {
+		r.count++
+	} else {
+		r.start = (r.start + 1) % len(r.samples)
+	}
+}
+
+// Samples returns the recorded samples with simulation times between from and
+// to (inclusive), oldest first. A zero from or to leaves that end open.
+func (r *Recorder) Samples(from, to time.Time) []models.AircraftState {
+	samples := make([]models.AircraftState, 0)
+	if r == nil {
+		return samples
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for i := 0; i < r.count; i++ {
+		sample := r.samples[(r.start+i)%len(r.samples)]
+		t := sampleTime(r.epoch, sample)
+		if (!from.IsZero() && t.Before(from)) || (!to.IsZero() && t.After(to)) {
+			continue
+		}
+		samples = append(samples, sample)
+	}
+	return samples
+}
+
+// Count returns the number of recorded samples.
+func (r *Recorder) Count() int {
+	if r == nil {
+		return 0
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.count
+}
+
+// Reset discards all recorded samples.
+func (r *Recorder) Reset() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.start = 0
+	r.count = 0
+	r.lastSample = math.Inf(-1)
+}
+
+// Epoch returns the wall-clock time at simulation time zero.
+func (r *Recorder) Epoch() time.Time {
+	return r.epoch
+}
@@ -0,0 +1,153 @@
+package track
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+)
+
+var testEpoch = time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+func sample(simTime, lat float64) models.AircraftState {
+	return models.AircraftState{
+		Position: models.Position{Latitude: lat, Longitude: 34.0, Altitude: 1000},
+		SimTime:  simTime,
+		Phase:    models.FlightPhaseCruise,
+	}
+}
+
+func TestRecorder_RingBufferAndInterval(t *testing.T) {
+	r := NewRecorder(3, time.Second, testEpoch)
+
+	// 10 Hz for 5 seconds, sampled once per second
+	for i := 0; i <= 50; i++ {
+		r.Record(sample(float64(i)*0.1, 32.0+float64(i)*0.001))
+	}
+
+	samples := r.Samples(time.Time{}, time.Time{})
+	if r.Count() != 3 || len(samples) != 3 {
+		t.Fatalf("Samples() returned %d samples, want the 3 most recent", len(samples))
+	}
+	for i, want := range []float64{3, 4, 5} {
+		if diff := samples[i].SimTime - want; diff > 0.05 || diff < -0.05 {
+			t.Errorf("sample %d sim time = %.1f, want %.0f", i, samples[i].SimTime, want)
+		}
+	}
+
+	window := r.Samples(testEpoch.Add(3500*time.Millisecond), testEpoch.Add(4500*time.Millisecond))
+	if len(window) != 1 {
+		t.Errorf("Samples(3.5s, 4.5s) returned %d samples, want 1", len(window))
+	}
+
+	r.Reset()
+	r.Record(sample(0, 32))
+	if r.Count() != 1 {
+		t.Errorf("Count() after Reset and Record = %d, want 1", r.Count())
+	}
+
+	var disabled *Recorder
+	disabled.Record(sample(0, 32))
+	if disabled.Count() != 0 || len(disabled.Samples(time.Time{}, time.Time{})) != 0 {
+		t.Error("nil recorder should record nothing")
+	}
+}
+
+func TestExport(t *testing.T) {
+	samples := []models.AircraftState{sample(0, 32.0), sample(1.5, 32.001)}
+
+	tests := []struct {
+		format Format
+		check  func(t *testing.T, data []byte)
+	}{
+		{FormatKML, func(t *testing.T, data []byte) {
+			text := string(data)
+			for _, want := range []string{"<altitudeMode>absolute</altitudeMode>", "<when>2024-05-01T10:00:01.500Z</when>", "<gx:coord>34 32.001 1000</gx:coord>"} {
+				if !strings.Contains(text, want) {
+					t.Errorf("KML missing %q:\n%s", want, text)
+				}
+			}
+			if err := xml.Unmarshal(data, new(interface{})); err != nil {
+				t.Errorf("KML is not valid XML: %v", err)
+			}
+		}},
+		{FormatGPX, func(t *testing.T, data []byte) {
+			var doc struct {
+				Points []struct {
+					Elevation float64 `xml:"ele"`
+					Time      string  `xml:"time"`
+				} `xml:"trk>trkseg>trkpt"`
+			}
+			if err := xml.Unmarshal(data, &doc); err != nil {
+				t.Fatalf("GPX is not valid XML: %v", err)
+			}
+			if len(doc.Points) != 2 || doc.Points[1].Elevation != 1000 || doc.Points[1].Time != "2024-05-01T10:00:01.500Z" {
+				t.Errorf("GPX points = %+v", doc.Points)
+			}
+		}},
+		{FormatGeoJSON, func(t *testing.T, data []byte) {
+			var feature struct {
+				Properties struct {
+					CoordTimes []string `json:"coordTimes"`
+				} `json:"properties"`
+				Geometry struct {
+					Type        string      `json:"type"`
+					Coordinates [][]float64 `json:"coordinates"`
+				} `json:"geometry"`
+			}
+			if err := json.Unmarshal(data, &feature); err != nil {
+				t.Fatalf("GeoJSON is not valid JSON: %v", err)
+			}
+			if feature.Geometry.Type != "LineString" || len(feature.Geometry.Coordinates) != 2 || len(feature.Properties.CoordTimes) != 2 {
+				t.Errorf("GeoJSON feature = %+v", feature)
+			}
+		}},
+		{FormatCZML, func(t *testing.T, data []byte) {
+			var packets []struct {
+				ID       string `json:"id"`
+				Position struct {
+					Epoch               string    `json:"epoch"`
+					CartographicDegrees []float64 `json:"cartographicDegrees"`
+				} `json:"position"`
+			}
+			if err := json.Unmarshal(data, &packets); err != nil {
+				t.Fatalf("CZML is not valid JSON: %v", err)
+			}
+			if len(packets) != 2 || packets[0].ID != "document" {
+				t.Fatalf("CZML packets = %+v", packets)
+			}
+			position := packets[1].Position
+			if position.Epoch != "2024-05-01T10:00:00.000Z" || len(position.CartographicDegrees) != 8 || position.CartographicDegrees[4] != 1.5 {
+				t.Errorf("CZML position = %+v", position)
+			}
+		}},
+		{FormatCSV, func(t *testing.T, data []byte) {
+			rows, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+			if err != nil {
+				t.Fatalf("CSV is not valid: %v", err)
+			}
+			if len(rows) != 3 || rows[2][1] != "1.500" || rows[2][8] != "cruise" {
+				t.Errorf("CSV rows = %v", rows)
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Export(&buf, tt.format, "Test", testEpoch, samples); err != nil {
+				t.Fatalf("Export() error = %v", err)
+			}
+			tt.check(t, buf.Bytes())
+		})
+	}
+
+	if err := Export(&bytes.Buffer{}, Format("shp"), "Test", testEpoch, samples); err == nil {
+		t.Error("Export() with unknown format should fail")
+	}
+}
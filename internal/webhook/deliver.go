@@ -0,0 +1,132 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+)
+
+// Delivery request headers. The signature is "sha256=" followed by the hex
+// HMAC-SHA256 of the timestamp, a dot and the body, keyed by the secret of
+// the webhook or command callback (see Sign).
+const (
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderTimestamp = "X-Webhook-Timestamp" // Unix seconds of the attempt
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// maxResponseSize bounds the part of a receiver's response that is read.
+const maxResponseSize = 64 << 10
+
+// Sign returns the signature of a delivery body sent at timestamp, as in
+// the signature header.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// deliver sends an event body to a target, retrying with exponential
+// backoff, and records every attempt in the delivery.
+func (m *Manager) deliver(ctx context.Context, t target, body []byte) {
+	delivery := t.delivery
+	for attempt := 1; ; attempt++ {
+		status, err := m.send(ctx, delivery, t.secret, body)
+
+		retry := err != nil && retryable(status) && attempt < m.cfg.MaxAttempts
+		m.mu.Lock()
+		delivery.Attempts = attempt
+		delivery.ResponseStatus = status
+		delivery.Error = ""
+		if err != nil {
+			delivery.Error = err.Error()
+		}
+		if !retry {
+			m.finish(delivery, err == nil)
+		}
+		m.mu.Unlock()
+
+		if !retry {
+			if err != nil {
+				m.logger.Warn("Webhook delivery failed", "delivery_id", delivery.ID, "url", delivery.URL,
+					"event_id", delivery.EventID, "attempts", attempt, "error", err)
+			}
+			return
+		}
+
+		select {
+		case <-time.After(m.backoff(attempt)):
+		case <-ctx.Done():
+			m.mu.Lock()
+			delivery.Error = ctx.Err().Error()
+			m.finish(delivery, false)
+			m.mu.Unlock()
+			return
+		}
+	}
+}
+
+// finish records the end of a delivery. The manager must be locked.
+func (m *Manager) finish(delivery *models.WebhookDelivery, delivered bool) {
+	now := time.Now()
+	delivery.FinishedAt = &now
+	delivery.State = models.DeliveryFailed
+	if delivered {
+		delivery.State = models.DeliveryDelivered
+	}
+}
+
+// send makes one delivery attempt and returns the receiver's status, 0
+// when it did not answer. Statuses other than 2xx are errors.
+func (m *Manager) send(ctx context.Context, delivery *models.WebhookDelivery, secret string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderDelivery, delivery.ID)
+	req.Header.Set(HeaderEvent, string(delivery.EventType))
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(secret, timestamp, body))
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseSize))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("receiver answered %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// retryable reports whether an attempt that failed with a status may
+// succeed later: the receiver did not answer, is overloaded or failed.
+func retryable(status int) bool {
+	return status == 0 || status == http.StatusTooManyRequests || status >= 500
+}
+
+// backoff returns the wait after a failed attempt: the initial backoff,
+// doubled after every attempt, up to the maximum.
+func (m *Manager) backoff(attempt int) time.Duration {
+	wait := m.cfg.InitialBackoff
+	for i := 1; i < attempt && wait < m.cfg.MaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, m.cfg.MaxBackoff)
+}
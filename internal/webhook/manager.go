@@ -0,0 +1,341 @@
+// Package webhook delivers simulation events to registered webhooks and to
+// the callback URLs of commands.
+package webhook
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/config"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/pubsub"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/simulator"
+)
+
+const (
+	// maxDeliveries is the number of recent deliveries kept in the log.
+	maxDeliveries = 500
+
+	// maxCallbacks is the number of commands watched at once; the oldest
+	// watch is dropped for a new one beyond it.
+	maxCallbacks = 1000
+
+	// eventBufferSize is the number of events buffered while deliveries
+	// are being started.
+	eventBufferSize = 100
+
+	defaultTimeout        = 5 * time.Second
+	defaultMaxAttempts    = 5
+	defaultInitialBackoff = time.Second
+	defaultMaxBackoff     = time.Minute
+)
+
+// Manager keeps the registered webhooks and the command callbacks, and
+// delivers the simulator's events to them while it runs. It is safe for
+// concurrent use.
+type Manager struct {
+	cfg       config.WebhookConfig
+	simulator *simulator.Simulator
+	client    *http.Client
+	logger    *slog.Logger
+
+	mu         sync.Mutex
+	webhooks   map[string]models.Webhook
+	callbacks  map[string]callback       // by command ID
+	watched    uint64                    // commands watched, orders the callbacks
+	deliveries []*models.WebhookDelivery // oldest first
+}
+
+// NewManager creates a webhook manager for the events of a simulator.
+func NewManager(cfg config.WebhookConfig, sim *simulator.Simulator, logger *slog.Logger) *Manager {
+	cfg.Timeout = cmp.Or(cfg.Timeout, defaultTimeout)
+	cfg.MaxAttempts = cmp.Or(cfg.MaxAttempts, defaultMaxAttempts)
+	cfg.InitialBackoff = cmp.Or(cfg.InitialBackoff, defaultInitialBackoff)
+	cfg.MaxBackoff = cmp.Or(cfg.MaxBackoff, defaultMaxBackoff)
+
+	return &Manager{
+		cfg:       cfg,
+		simulator: sim,
+		client:    &http.Client{Timeout: cfg.Timeout},
+		logger:    logger,
+		webhooks:  make(map[string]models.Webhook),
+		callbacks: make(map[string]callback),
+	}
+}
+
+// callback is where and how the event that ends a command is delivered.
+type callback struct {
+	url    string
+	secret string
+	order  uint64
+}
+
+// ValidateURL checks that a webhook or callback URL is an absolute http or
+// https URL.
+func ValidateURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return models.ErrInvalidCallbackURL
+	}
+	return nil
+}
+
+// Register validates and registers a webhook, and returns it with its ID.
+func (m *Manager) Register(webhook models.Webhook) (models.Webhook, error) {
+	if err := ValidateURL(webhook.URL); err != nil {
+		return models.Webhook{}, fmt.Errorf("%w: url must be an absolute http or https URL", models.ErrInvalidWebhook)
+	}
+	if webhook.Secret == "" {
+		return models.Webhook{}, fmt.Errorf("%w: secret is required", models.ErrInvalidWebhook)
+	}
+	for _, eventType := range webhook.EventTypes {
+		if !slices.Contains(models.EventTypes, eventType) {
+			return models.Webhook{}, fmt.Errorf("%w: unknown event type %q", models.ErrInvalidWebhook, eventType)
+		}
+	}
+
+	webhook.ID = uuid.New().String()
+	webhook.EventTypes = slices.Clone(webhook.EventTypes)
+	if webhook.EventTypes == nil {
+		webhook.EventTypes = []models.EventType{}
+	}
+	webhook.CreatedAt = time.Now()
+
+	m.mu.Lock()
+	m.webhooks[webhook.ID] = webhook
+	m.mu.Unlock()
+
+	m.logger.Info("Webhook registered", "webhook_id", webhook.ID, "url", webhook.URL, "event_types", webhook.EventTypes)
+	return webhook, nil
+}
+
+// Get returns a webhook.
+func (m *Manager) Get(id string) (models.Webhook, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	webhook, exists := m.webhooks[id]
+	if !exists {
+		return models.Webhook{}, fmt.Errorf("%w: %s", models.ErrWebhookNotFound, id)
+	}
+	return webhook, nil
+}
+
+// List returns the webhooks, oldest first.
+func (m *Manager) List() []models.Webhook {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	webhooks := make([]models.Webhook, 0, len(m.webhooks))
+	for _, webhook := range m.webhooks {
+		webhooks = append(webhooks, webhook)
+	}
+	sort.Slice(webhooks, func(i, k int) bool {
+		return webhooks[i].CreatedAt.Before(webhooks[k].CreatedAt)
+	})
+	return webhooks
+}
+
+// Delete removes a webhook. Its deliveries in progress are finished.
+func (m *Manager) Delete(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.webhooks[id]; !exists {
+		return fmt.Errorf("%w: %s", models.ErrWebhookNotFound, id)
+	}
+	delete(m.webhooks, id)
+	return nil
+}
+
+// CheckCallback validates the callback URL of a command and checks that it
+// can be signed: with the command's secret, or the configured default.
+func (m *Manager) CheckCallback(callbackURL, secret string) error {
+	if err := ValidateURL(callbackURL); err != nil {
+		return err
+	}
+	if secret == "" && m.cfg.CallbackSecret == "" {
+		return models.ErrMissingCallbackSecret
+	}
+	return nil
+}
+
+// WatchCommand registers the callback URL of a command, which gets the
+// event that ends the command: command_completed, command_superseded or
+// command_cancelled. The delivery is signed with secret, or the configured
+// callback secret if it is empty. Watch a command before submitting it, so
+// it cannot end unseen.
+func (m *Manager) WatchCommand(commandID, callbackURL, secret string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.callbacks) >= maxCallbacks {
+		m.dropOldestCallback()
+	}
+	m.watched++
+	m.callbacks[commandID] = callback{url: callbackURL, secret: cmp.Or(secret, m.cfg.CallbackSecret), order: m.watched}
+}
+
+// dropOldestCallback stops watching the command watched first, whose end
+// event was likely lost.
+func (m *Manager) dropOldestCallback() {
+	var oldestID string
+	var oldest callback
+	for id, cb := range m.callbacks {
+		if oldestID == "" || cb.order < oldest.order {
+			oldestID, oldest = id, cb
+		}
+	}
+	delete(m.callbacks, oldestID)
+	m.logger.Warn("Command callback dropped, too many commands watched",
+		"command_id", oldestID, "url", oldest.url, "max_callbacks", maxCallbacks)
+}
+
+// UnwatchCommand removes the callback of a command, e.g. when its
+// submission failed.
+func (m *Manager) UnwatchCommand(commandID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.callbacks, commandID)
+}
+
+// Deliveries returns the kept deliveries, oldest first, of a webhook or a
+// command when their IDs are set.
+func (m *Manager) Deliveries(webhookID, commandID string) []models.WebhookDelivery {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	deliveries := make([]models.WebhookDelivery, 0, len(m.deliveries))
+	for _, delivery := range m.deliveries {
+		if (webhookID == "" || delivery.WebhookID == webhookID) && (commandID == "" || delivery.CommandID == commandID) {
+			deliveries = append(deliveries, *delivery)
+		}
+	}
+	return deliveries
+}
+
+// Run delivers the simulator's events until ctx is cancelled, then waits
+// for the deliveries in progress, which stop retrying.
+//
+// An event that does not fit in the buffer is lost. The subscription is then
+// evicted, which the broker logs, and Run subscribes again for the next
+// events.
+func (m *Manager) Run(ctx context.Context) error {
+	subID := uuid.New().String()
+	broker := m.simulator.GetEventBroker()
+	subscribe := func() (<-chan *pubsub.Message[models.Event], error) {
+		return broker.Subscribe(subID, pubsub.SubscribeOptions{
+			Topics:     []string{simulator.EventsPattern(m.simulator.AircraftID())},
+			Kind:       "webhook",
+			BufferSize: eventBufferSize,
+			Policy:     pubsub.PolicyDisconnect,
+		})
+	}
+	eventChan, err := subscribe()
+	if err != nil {
+		return err
+	}
+	defer broker.Unsubscribe(subID)
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		select {
+		case msg, ok := <-eventChan:
+			if !ok {
+				m.logger.Warn("Webhook events lost, subscribing again", "subscriber_id", subID)
+				if eventChan, err = subscribe(); err != nil {
+					return err
+				}
+				continue
+			}
+			body, err := json.Marshal(msg.Payload)
+			if err != nil {
+				m.logger.Error("Failed to encode event", "error", err, "event_id", msg.Payload.ID)
+				continue
+			}
+			for _, target := range m.targets(msg.Payload) {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					m.deliver(ctx, target, body)
+				}()
+			}
+
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// target is a delivery to start and the secret to sign it with.
+type target struct {
+	delivery *models.WebhookDelivery
+	secret   string
+}
+
+// targets logs a new delivery of an event for each webhook that wants it
+// and for the callback of the command it ends, and returns them.
+func (m *Manager) targets(event models.Event) []target {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	newDelivery := func(to string) *models.WebhookDelivery {
+		return &models.WebhookDelivery{
+			ID:        uuid.New().String(),
+			URL:       to,
+			EventID:   event.ID,
+			EventType: event.Type,
+			State:     models.DeliveryPending,
+			CreatedAt: time.Now(),
+		}
+	}
+
+	var targets []target
+	for _, webhook := range m.webhooks {
+		if len(webhook.EventTypes) > 0 && !slices.Contains(webhook.EventTypes, event.Type) {
+			continue
+		}
+		delivery := newDelivery(webhook.URL)
+		delivery.WebhookID = webhook.ID
+		targets = append(targets, target{delivery: delivery, secret: webhook.Secret})
+	}
+
+	if event.Command != nil && endsCommand(event.Type) {
+		if cb, watched := m.callbacks[event.Command.ID]; watched {
+			delete(m.callbacks, event.Command.ID)
+			delivery := newDelivery(cb.url)
+			delivery.CommandID = event.Command.ID
+			targets = append(targets, target{delivery: delivery, secret: cb.secret})
+		}
+	}
+
+	for _, target := range targets {
+		m.deliveries = append(m.deliveries, target.delivery)
+	}
+	if extra := len(m.deliveries) - maxDeliveries; extra > 0 {
+		m.deliveries = slices.Delete(m.deliveries, 0, extra)
+	}
+	return targets
+}
+
+// endsCommand reports whether a command event is the command's last.
+func endsCommand(eventType models.EventType) bool {
+	switch eventType {
+	case models.EventCommandCompleted, models.EventCommandSuperseded, models.EventCommandCancelled:
+		return true
+	default:
+		return false
+	}
+}
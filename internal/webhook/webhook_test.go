@@ -0,0 +1,374 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/meiron-tzhori/Flight-Simulator/internal/config"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/models"
+	"github.com/meiron-tzhori/Flight-Simulator/internal/simulator"
+)
+
+// received is a request a test receiver got.
+type received struct {
+	header http.Header
+	body   []byte
+}
+
+// newReceiver starts an HTTP server that answers with the given statuses in
+// turn, then 200, and returns its URL and the requests it got.
+func newReceiver(t *testing.T, statuses ...int) (string, <-chan received) {
+	t.Helper()
+
+	requests := make(chan received, 10)
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- received{header: r.Header, body: body}
+		if call := int(calls.Add(1)); call <= len(statuses) {
+			w.WriteHeader(statuses[call-1])
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server.URL, requests
+}
+
+// newTestManager starts a simulator and a running webhook manager with
+// short backoffs.
+func newTestManager(t *testing.T) (*Manager, *simulator.Simulator) {
+	t.Helper()
+
+	simCfg := config.SimulationConfig{
+		TickRateHz:       10.0,
+		CommandQueueSize: 10,
+		InitialPosition: config.PositionConfig{
+			Latitude:  32.0,
+			Longitude: 34.0,
+			Altitude:  1000.0,
+		},
+		DefaultSpeed:      100.0,
+		MaxSpeed:          250.0,
+		MaxClimbRate:      15.0,
+		MaxDescentRate:    10.0,
+		PositionTolerance: 10.0,
+		HeadingChangeRate: 30.0,
+		SpeedChangeRate:   50.0,
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelWarn}))
+
+	sim, err := simulator.New(simCfg, config.EnvironmentConfig{}, logger)
+	if err != nil {
+		t.Fatalf("Failed to create simulator: %v", err)
+	}
+	manager := NewManager(config.WebhookConfig{
+		MaxAttempts:    3,
+		InitialBackoff: 10 * time.Millisecond,
+		MaxBackoff:     20 * time.Millisecond,
+	}, sim, logger)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go sim.Run(ctx)
+	go func() {
+		defer close(done)
+		manager.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	// Commands are submitted once the manager gets the events
+	deadline := time.Now().Add(5 * time.Second)
+	for sim.GetEventBroker().SubscriberCount() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("webhook manager did not subscribe within 5s")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return manager, sim
+}
+
+// submitGoTo submits a go-to command and returns its ID.
+func submitGoTo(t *testing.T, sim *simulator.Simulator, lat float64) string {
+	t.Helper()
+
+	cmd := models.NewCommand(models.CommandTypeGoTo)
+	cmd.GoTo = &models.GoToCommand{Target: models.Position{Latitude: lat, Longitude: 34.0, Altitude: 1000.0}}
+	if err := sim.SubmitCommand(context.Background(), cmd); err != nil {
+		t.Fatalf("SubmitCommand() error = %v", err)
+	}
+	return cmd.ID
+}
+
+// receive returns the next request of a receiver.
+func receive(t *testing.T, requests <-chan received) received {
+	t.Helper()
+
+	select {
+	case req := <-requests:
+		return req
+	case <-time.After(5 * time.Second):
+		t.Fatal("no request within 5s")
+		return received{}
+	}
+}
+
+// waitDelivery waits until a delivery of a webhook or command is finished
+// and returns it.
+func waitDelivery(t *testing.T, manager *Manager, webhookID, commandID string) models.WebhookDelivery {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		deliveries := manager.Deliveries(webhookID, commandID)
+		if len(deliveries) > 0 && deliveries[0].State != models.DeliveryPending {
+			return deliveries[0]
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("no finished delivery within 5s")
+	return models.WebhookDelivery{}
+}
+
+func TestDeliverSignedWithRetries(t *testing.T) {
+	manager, sim := newTestManager(t)
+	url, requests := newReceiver(t, http.StatusInternalServerError)
+
+	webhook, err := manager.Register(models.Webhook{
+		URL:        url,
+		EventTypes: []models.EventType{models.EventCommandAccepted},
+		Secret:     "s3cret",
+	})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	commandID := submitGoTo(t, sim, 32.1)
+
+	// The first attempt fails, the retry gets the same delivery
+	first := receive(t, requests)
+	retry := receive(t, requests)
+	if first.header.Get(HeaderDelivery) != retry.header.Get(HeaderDelivery) {
+		t.Errorf("retry delivery = %s, want %s", retry.header.Get(HeaderDelivery), first.header.Get(HeaderDelivery))
+	}
+
+	want := Sign("s3cret", retry.header.Get(HeaderTimestamp), retry.body)
+	if got := retry.header.Get(HeaderSignature); got != want {
+		t.Errorf("signature = %s, want %s", got, want)
+	}
+	if got := retry.header.Get(HeaderEvent); got != string(models.EventCommandAccepted) {
+		t.Errorf("event header = %s, want %s", got, models.EventCommandAccepted)
+	}
+	var event models.Event
+	if err := json.Unmarshal(retry.body, &event); err != nil {
+		t.Fatalf("body is not an event: %v", err)
+	}
+	if event.Type != models.EventCommandAccepted || event.Command == nil || event.Command.ID != commandID {
+		t.Errorf("event = %+v, want command_accepted of %s", event, commandID)
+	}
+
+	delivery := waitDelivery(t, manager, webhook.ID, "")
+	if delivery.State != models.DeliveryDelivered || delivery.Attempts != 2 || delivery.ResponseStatus != http.StatusOK {
+		t.Errorf("delivery = %+v, want delivered on attempt 2", delivery)
+	}
+	if n := len(manager.Deliveries(webhook.ID, "")); n != 1 {
+		t.Errorf("deliveries = %d, want 1: the filter passes command_accepted only", n)
+	}
+}
+
+func TestDeliverGivesUp(t *testing.T) {
+	manager, sim := newTestManager(t)
+	url, _ := newReceiver(t, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway)
+
+	webhook, err := manager.Register(models.Webhook{
+		URL:        url,
+		EventTypes: []models.EventType{models.EventCommandAccepted},
+		Secret:     "s3cret",
+	})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	submitGoTo(t, sim, 32.1)
+
+	delivery := waitDelivery(t, manager, webhook.ID, "")
+	if delivery.State != models.DeliveryFailed || delivery.Attempts != 3 || delivery.ResponseStatus != http.StatusBadGateway {
+		t.Errorf("delivery = %+v, want failed after 3 attempts", delivery)
+	}
+	if delivery.FinishedAt == nil {
+		t.Error("failed delivery has no finish time")
+	}
+}
+
+func TestCommandCallback(t *testing.T) {
+	manager, sim := newTestManager(t)
+	url, requests := newReceiver(t)
+
+	cmd := models.NewCommand(models.CommandTypeGoTo)
+	cmd.GoTo = &models.GoToCommand{Target: models.Position{Latitude: 32.1, Longitude: 34.0, Altitude: 1000.0}}
+	manager.WatchCommand(cmd.ID, url, "cmd-s3cret")
+	if err := sim.SubmitCommand(context.Background(), cmd); err != nil {
+		t.Fatalf("SubmitCommand() error = %v", err)
+	}
+
+	// A newer command ends the watched one
+	submitGoTo(t, sim, 32.2)
+
+	req := receive(t, requests)
+	var event models.Event
+	if err := json.Unmarshal(req.body, &event); err != nil {
+		t.Fatalf("body is not an event: %v", err)
+	}
+	if event.Type != models.EventCommandSuperseded || event.Command == nil || event.Command.ID != cmd.ID {
+		t.Errorf("event = %+v, want command_superseded of %s", event, cmd.ID)
+	}
+	want := Sign("cmd-s3cret", req.header.Get(HeaderTimestamp), req.body)
+	if got := req.header.Get(HeaderSignature); got != want {
+		t.Errorf("callback signature = %s, want %s", got, want)
+	}
+
+	delivery := waitDelivery(t, manager, "", cmd.ID)
+	if delivery.State != models.DeliveryDelivered || delivery.URL != url {
+		t.Errorf("delivery = %+v, want delivered to %s", delivery, url)
+	}
+}
+
+func TestRunSubscribesAgainAfterLostEvents(t *testing.T) {
+	manager, sim := newTestManager(t)
+	url, requests := newReceiver(t)
+
+	if _, err := manager.Register(models.Webhook{
+		URL:        url,
+		EventTypes: []models.EventType{models.EventCommandAccepted},
+		Secret:     "s3cret",
+	}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	// Stall the manager while more events are published than it buffers
+	broker := sim.GetEventBroker()
+	topic := simulator.EventTopic(sim.AircraftID(), models.EventWaypointReached)
+	manager.mu.Lock()
+	for i := 0; i < eventBufferSize+2; i++ {
+		broker.Publish(topic, models.Event{Type: models.EventWaypointReached})
+	}
+	evicted := broker.SubscriberCount() == 0
+	manager.mu.Unlock()
+	if !evicted {
+		t.Fatal("subscription kept after an event was lost, want evicted")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for broker.SubscriberCount() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("webhook manager did not subscribe again within 5s")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// Later events are delivered
+	submitGoTo(t, sim, 32.1)
+	if got := receive(t, requests).header.Get(HeaderEvent); got != string(models.EventCommandAccepted) {
+		t.Errorf("event header = %s, want %s", got, models.EventCommandAccepted)
+	}
+}
+
+func TestCheckCallback(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	manager := NewManager(config.WebhookConfig{}, nil, logger)
+	withDefault := NewManager(config.WebhookConfig{CallbackSecret: "default"}, nil, logger)
+
+	tests := []struct {
+		name    string
+		manager *Manager
+		url     string
+		secret  string
+		want    error
+	}{
+		{name: "own secret", manager: manager, url: "https://example.com/done", secret: "s"},
+		{name: "default secret", manager: withDefault, url: "https://example.com/done"},
+		{name: "no secret", manager: manager, url: "https://example.com/done", want: models.ErrMissingCallbackSecret},
+		{name: "relative url", manager: withDefault, url: "/done", want: models.ErrInvalidCallbackURL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.manager.CheckCallback(tt.url, tt.secret); !errors.Is(err, tt.want) {
+				t.Errorf("CheckCallback() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	// The default signs callbacks without a secret of their own
+	withDefault.WatchCommand("c1", "https://example.com/done", "")
+	withDefault.WatchCommand("c2", "https://example.com/done", "own")
+	if got := withDefault.callbacks["c1"].secret; got != "default" {
+		t.Errorf("secret of c1 = %q, want the default", got)
+	}
+	if got := withDefault.callbacks["c2"].secret; got != "own" {
+		t.Errorf("secret of c2 = %q, want its own", got)
+	}
+}
+
+func TestWatchCommandBounded(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	manager := NewManager(config.WebhookConfig{CallbackSecret: "s"}, nil, logger)
+
+	for i := 0; i <= maxCallbacks; i++ {
+		manager.WatchCommand(fmt.Sprintf("c%d", i), "https://example.com/done", "")
+	}
+	if n := len(manager.callbacks); n != maxCallbacks {
+		t.Errorf("callbacks = %d, want %d", n, maxCallbacks)
+	}
+	if _, watched := manager.callbacks["c0"]; watched {
+		t.Error("the oldest callback was kept")
+	}
+	if _, watched := manager.callbacks[fmt.Sprintf("c%d", maxCallbacks)]; !watched {
+		t.Error("the newest callback was dropped")
+	}
+}
+
+func TestRegisterInvalid(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	manager := NewManager(config.WebhookConfig{}, nil, logger)
+
+	tests := []struct {
+		name    string
+		webhook models.Webhook
+	}{
+		{name: "relative url", webhook: models.Webhook{URL: "/hook", Secret: "s"}},
+		{name: "other scheme", webhook: models.Webhook{URL: "ftp://example.com/hook", Secret: "s"}},
+		{name: "no secret", webhook: models.Webhook{URL: "https://example.com/hook"}},
+		{name: "unknown event type", webhook: models.Webhook{URL: "https://example.com/hook", Secret: "s", EventTypes: []models.EventType{"landed"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := manager.Register(tt.webhook); !errors.Is(err, models.ErrInvalidWebhook) {
+				t.Errorf("Register() error = %v, want %v", err, models.ErrInvalidWebhook)
+			}
+		})
+	}
+	if n := len(manager.List()); n != 0 {
+		t.Errorf("List() = %d webhooks, want 0", n)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	manager := &Manager{cfg: config.WebhookConfig{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, w := range want {
+		if got := manager.backoff(i + 1); got != w {
+			t.Errorf("backoff(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+}
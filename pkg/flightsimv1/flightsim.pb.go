@@ -0,0 +1,2490 @@
+// Flight Simulator gRPC API.
+//
+// The messages mirror the JSON models of the REST API (internal/models), with
+// the same field names, units and string values: flight phases, command
+// types, event types and geofence modes are the strings the REST API uses.
+// Regenerate the Go code with `make proto`.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.1
+// 	protoc        (unknown)
+// source: flightsim/v1/flightsim.proto
+
+package flightsimv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Position represents geographic coordinates.
+type Position struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Latitude  float64 `protobuf:"fixed64,1,opt,name=latitude,proto3" json:"latitude,omitempty"`   // degrees, -90 to 90
+	Longitude float64 `protobuf:"fixed64,2,opt,name=longitude,proto3" json:"longitude,omitempty"` // degrees, -180 to 180
+	Altitude  float64 `protobuf:"fixed64,3,opt,name=altitude,proto3" json:"altitude,omitempty"`   // meters MSL
+}
+
+func (x *Position) Reset() {
+	*x = Position{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_flightsim_v1_flightsim_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Position) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Position) ProtoMessage() {}
+
+func (x *Position) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsim_v1_flightsim_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Position.ProtoReflect.Descriptor instead.
+func (*Position) Descriptor() ([]byte, []int) {
+	return file_flightsim_v1_flightsim_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Position) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *Position) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+func (x *Position) GetAltitude() float64 {
+	if x != nil {
+		return x.Altitude
+	}
+	return 0
+}
+
+// Velocity represents the aircraft's velocity vector.
+type Velocity struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GroundSpeed   float64 `protobuf:"fixed64,1,opt,name=ground_speed,json=groundSpeed,proto3" json:"ground_speed,omitempty"`       // m/s
+	VerticalSpeed float64 `protobuf:"fixed64,2,opt,name=vertical_speed,json=verticalSpeed,proto3" json:"vertical_speed,omitempty"` // m/s (positive = climbing)
+}
+
+func (x *Velocity) Reset() {
+	*x = Velocity{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_flightsim_v1_flightsim_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Velocity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Velocity) ProtoMessage() {}
+
+func (x *Velocity) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsim_v1_flightsim_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Velocity.ProtoReflect.Descriptor instead.
+func (*Velocity) Descriptor() ([]byte, []int) {
+	return file_flightsim_v1_flightsim_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Velocity) GetGroundSpeed() float64 {
+	if x != nil {
+		return x.GroundSpeed
+	}
+	return 0
+}
+
+func (x *Velocity) GetVerticalSpeed() float64 {
+	if x != nil {
+		return x.VerticalSpeed
+	}
+	return 0
+}
+
+// AircraftState represents the complete state of the aircraft at a point in
+// time.
+type AircraftState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Position         *Position              `protobuf:"bytes,1,opt,name=position,proto3" json:"position,omitempty"`
+	Velocity         *Velocity              `protobuf:"bytes,2,opt,name=velocity,proto3" json:"velocity,omitempty"`
+	Heading          float64                `protobuf:"fixed64,3,opt,name=heading,proto3" json:"heading,omitempty"` // degrees, 0-360 (0=North)
+	Timestamp        *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	SimTime          float64                `protobuf:"fixed64,5,opt,name=sim_time,json=simTime,proto3" json:"sim_time,omitempty"` // seconds of simulation time since start
+	ActiveCommand    *CommandInfo           `protobuf:"bytes,6,opt,name=active_command,json=activeCommand,proto3" json:"active_command,omitempty"`
+	Environment      *EnvironmentState      `protobuf:"bytes,7,opt,name=environment,proto3" json:"environment,omitempty"`
+	Terrain          *TerrainState          `protobuf:"bytes,8,opt,name=terrain,proto3" json:"terrain,omitempty"`
+	GeofenceBreaches []*GeofenceBreach      `protobuf:"bytes,9,rep,name=geofence_breaches,json=geofenceBreaches,proto3" json:"geofence_breaches,omitempty"`
+	LinkLost         bool                   `protobuf:"varint,10,opt,name=link_lost,json=linkLost,proto3" json:"link_lost,omitempty"`
+	EngineFailure    bool                   `protobuf:"varint,11,opt,name=engine_failure,json=engineFailure,proto3" json:"engine_failure,omitempty"` // the aircraft glides and cannot climb
+	Phase            string                 `protobuf:"bytes,12,opt,name=phase,proto3" json:"phase,omitempty"`                                       // parked, taxi, takeoff_roll, climb, cruise, descent, approach, landed
+	Fuel             *float64               `protobuf:"fixed64,13,opt,name=fuel,proto3,oneof" json:"fuel,omitempty"`                                 // kg on board, unset without the fuel model
+}
+
+func (x *AircraftState) Reset() {
+	*x = AircraftState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_flightsim_v1_flightsim_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AircraftState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AircraftState) ProtoMessage() {}
+
+func (x *AircraftState) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsim_v1_flightsim_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AircraftState.ProtoReflect.Descriptor instead.
+func (*AircraftState) Descriptor() ([]byte, []int) {
+	return file_flightsim_v1_flightsim_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AircraftState) GetPosition() *Position {
+	if x != nil {
+		return x.Position
+	}
+	return nil
+}
+
+func (x *AircraftState) GetVelocity() *Velocity {
+	if x != nil {
+		return x.Velocity
+	}
+	return nil
+}
+
+func (x *AircraftState) GetHeading() float64 {
+	if x != nil {
+		return x.Heading
+	}
+	return 0
+}
+
+func (x *AircraftState) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *AircraftState) GetSimTime() float64 {
+	if x != nil {
+		return x.SimTime
+	}
+	return 0
+}
+
+func (x *AircraftState) GetActiveCommand() *CommandInfo {
+	if x != nil {
+		return x.ActiveCommand
+	}
+	return nil
+}
+
+func (x *AircraftState) GetEnvironment() *EnvironmentState {
+	if x != nil {
+		return x.Environment
+	}
+	return nil
+}
+
+func (x *AircraftState) GetTerrain() *TerrainState {
+	if x != nil {
+		return x.Terrain
+	}
+	return nil
+}
+
+func (x *AircraftState) GetGeofenceBreaches() []*GeofenceBreach {
+	if x != nil {
+		return x.GeofenceBreaches
+	}
+	return nil
+}
+
+func (x *AircraftState) GetLinkLost() bool {
+	if x != nil {
+		return x.LinkLost
+	}
+	return false
+}
+
+func (x *AircraftState) GetEngineFailure() bool {
+	if x != nil {
+		return x.EngineFailure
+	}
+	return false
+}
+
+func (x *AircraftState) GetPhase() string {
+	if x != nil {
+		return x.Phase
+	}
+	return ""
+}
+
+func (x *AircraftState) GetFuel() float64 {
+	if x != nil && x.Fuel != nil {
+		return *x.Fuel
+	}
+	return 0
+}
+
+// CommandInfo contains information about the currently executing command.
+type CommandInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id            string    `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type          string    `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Target        *Position `protobuf:"bytes,3,opt,name=target,proto3" json:"target,omitempty"`
+	EtaSeconds    float64   `protobuf:"fixed64,4,opt,name=eta_seconds,json=etaSeconds,proto3" json:"eta_seconds,omitempty"`
+	WaypointIndex *int32    `protobuf:"varint,5,opt,name=waypoint_index,json=waypointIndex,proto3,oneof" json:"waypoint_index,omitempty"` // trajectory commands: the waypoint being flown to
+}
+
+func (x *CommandInfo) Reset() {
+	*x = CommandInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_flightsim_v1_flightsim_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CommandInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommandInfo) ProtoMessage() {}
+
+func (x *CommandInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsim_v1_flightsim_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommandInfo.ProtoReflect.Descriptor instead.
+func (*CommandInfo) Descriptor() ([]byte, []int) {
+	return file_flightsim_v1_flightsim_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CommandInfo) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CommandInfo) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *CommandInfo) GetTarget() *Position {
+	if x != nil {
+		return x.Target
+	}
+	return nil
+}
+
+func (x *CommandInfo) GetEtaSeconds() float64 {
+	if x != nil {
+		return x.EtaSeconds
+	}
+	return 0
+}
+
+func (x *CommandInfo) GetWaypointIndex() int32 {
+	if x != nil && x.WaypointIndex != nil {
+		return *x.WaypointIndex
+	}
+	return 0
+}
+
+// EnvironmentState represents environmental conditions.
+type EnvironmentState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Wind       *WindVector `protobuf:"bytes,1,opt,name=wind,proto3" json:"wind,omitempty"`
+	Turbulence *float64    `protobuf:"fixed64,2,opt,name=turbulence,proto3,oneof" json:"turbulence,omitempty"` // RMS gust speed, m/s
+	Humidity   *float64    `protobuf:"fixed64,3,opt,name=humidity,proto3,oneof" json:"humidity,omitempty"`     // 0-100%
+}
+
+func (x *EnvironmentState) Reset() {
+	*x = EnvironmentState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_flightsim_v1_flightsim_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EnvironmentState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnvironmentState) ProtoMessage() {}
+
+func (x *EnvironmentState) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsim_v1_flightsim_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnvironmentState.ProtoReflect.Descriptor instead.
+func (*EnvironmentState) Descriptor() ([]byte, []int) {
+	return file_flightsim_v1_flightsim_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *EnvironmentState) GetWind() *WindVector {
+	if x != nil {
+		return x.Wind
+	}
+	return nil
+}
+
+func (x *EnvironmentState) GetTurbulence() float64 {
+	if x != nil && x.Turbulence != nil {
+		return *x.Turbulence
+	}
+	return 0
+}
+
+func (x *EnvironmentState) GetHumidity() float64 {
+	if x != nil && x.Humidity != nil {
+		return *x.Humidity
+	}
+	return 0
+}
+
+// WindVector represents wind direction and speed.
+type WindVector struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Direction float64 `protobuf:"fixed64,1,opt,name=direction,proto3" json:"direction,omitempty"` // degrees
+	Speed     float64 `protobuf:"fixed64,2,opt,name=speed,proto3" json:"speed,omitempty"`         // m/s
+}
+
+func (x *WindVector) Reset() {
+	*x = WindVector{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_flightsim_v1_flightsim_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WindVector) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WindVector) ProtoMessage() {}
+
+func (x *WindVector) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsim_v1_flightsim_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WindVector.ProtoReflect.Descriptor instead.
+func (*WindVector) Descriptor() ([]byte, []int) {
+	return file_flightsim_v1_flightsim_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *WindVector) GetDirection() float64 {
+	if x != nil {
+		return x.Direction
+	}
+	return 0
+}
+
+func (x *WindVector) GetSpeed() float64 {
+	if x != nil {
+		return x.Speed
+	}
+	return 0
+}
+
+// TerrainState reports terrain clearance below the aircraft.
+type TerrainState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Elevation float64 `protobuf:"fixed64,1,opt,name=elevation,proto3" json:"elevation,omitempty"`                  // meters MSL
+	HeightAgl float64 `protobuf:"fixed64,2,opt,name=height_agl,json=heightAgl,proto3" json:"height_agl,omitempty"` // meters above ground
+	PullUp    bool    `protobuf:"varint,3,opt,name=pull_up,json=pullUp,proto3" json:"pull_up,omitempty"`           // automatic pull-up active
+}
+
+func (x *TerrainState) Reset() {
+	*x = TerrainState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_flightsim_v1_flightsim_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TerrainState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TerrainState) ProtoMessage() {}
+
+func (x *TerrainState) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsim_v1_flightsim_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TerrainState.ProtoReflect.Descriptor instead.
+func (*TerrainState) Descriptor() ([]byte, []int) {
+	return file_flightsim_v1_flightsim_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *TerrainState) GetElevation() float64 {
+	if x != nil {
+		return x.Elevation
+	}
+	return 0
+}
+
+func (x *TerrainState) GetHeightAgl() float64 {
+	if x != nil {
+		return x.HeightAgl
+	}
+	return 0
+}
+
+func (x *TerrainState) GetPullUp() bool {
+	if x != nil {
+		return x.PullUp
+	}
+	return false
+}
+
+// GeofenceBreach identifies a geofence zone the aircraft is violating.
+type GeofenceBreach struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ZoneId string `protobuf:"bytes,1,opt,name=zone_id,json=zoneId,proto3" json:"zone_id,omitempty"`
+	Name   string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Mode   string `protobuf:"bytes,3,opt,name=mode,proto3" json:"mode,omitempty"` // inclusion, exclusion
+}
+
+func (x *GeofenceBreach) Reset() {
+	*x = GeofenceBreach{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_flightsim_v1_flightsim_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GeofenceBreach) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeofenceBreach) ProtoMessage() {}
+
+func (x *GeofenceBreach) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsim_v1_flightsim_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeofenceBreach.ProtoReflect.Descriptor instead.
+func (*GeofenceBreach) Descriptor() ([]byte, []int) {
+	return file_flightsim_v1_flightsim_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GeofenceBreach) GetZoneId() string {
+	if x != nil {
+		return x.ZoneId
+	}
+	return ""
+}
+
+func (x *GeofenceBreach) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GeofenceBreach) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+// Command is a command to the aircraft. Only the parameters of the command
+// type are used; hold and stop take none, and rth and land may omit theirs.
+type Command struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id         string             `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`     // ignored on submission, the simulator assigns IDs
+	Type       string             `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"` // goto, trajectory, hold, stop, rth, takeoff, land
+	Goto       *GoToCommand       `protobuf:"bytes,3,opt,name=goto,proto3" json:"goto,omitempty"`
+	Trajectory *TrajectoryCommand `protobuf:"bytes,4,opt,name=trajectory,proto3" json:"trajectory,omitempty"`
+	Rth        *RTHCommand        `protobuf:"bytes,5,opt,name=rth,proto3" json:"rth,omitempty"`
+	Takeoff    *TakeoffCommand    `protobuf:"bytes,6,opt,name=takeoff,proto3" json:"takeoff,omitempty"`
+	Land       *LandCommand       `protobuf:"bytes,7,opt,name=land,proto3" json:"land,omitempty"`
+}
+
+func (x *Command) Reset() {
+	*x = Command{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_flightsim_v1_flightsim_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Command) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Command) ProtoMessage() {}
+
+func (x *Command) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsim_v1_flightsim_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Command.ProtoReflect.Descriptor instead.
+func (*Command) Descriptor() ([]byte, []int) {
+	return file_flightsim_v1_flightsim_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Command) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Command) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Command) GetGoto() *GoToCommand {
+	if x != nil {
+		return x.Goto
+	}
+	return nil
+}
+
+func (x *Command) GetTrajectory() *TrajectoryCommand {
+	if x != nil {
+		return x.Trajectory
+	}
+	return nil
+}
+
+func (x *Command) GetRth() *RTHCommand {
+	if x != nil {
+		return x.Rth
+	}
+	return nil
+}
+
+func (x *Command) GetTakeoff() *TakeoffCommand {
+	if x != nil {
+		return x.Takeoff
+	}
+	return nil
+}
+
+func (x *Command) GetLand() *LandCommand {
+	if x != nil {
+		return x.Land
+	}
+	return nil
+}
+
+// GoToCommand directs the aircraft to a specific point.
+type GoToCommand struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Target         *Position `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"`                                       // latitude and longitude may be omitted when fix is set
+	Speed          *float64  `protobuf:"fixed64,2,opt,name=speed,proto3,oneof" json:"speed,omitempty"`                                 // m/s
+	AltitudeRef    string    `protobuf:"bytes,3,opt,name=altitude_ref,json=altitudeRef,proto3" json:"altitude_ref,omitempty"`          // msl (default) or agl
+	Fix            string    `protobuf:"bytes,4,opt,name=fix,proto3" json:"fix,omitempty"`                                             // navdata identifier, instead of latitude/longitude
+	CallbackUrl    string    `protobuf:"bytes,5,opt,name=callback_url,json=callbackUrl,proto3" json:"callback_url,omitempty"`          // POSTed the event that ends the command
+	CallbackSecret string    `protobuf:"bytes,6,opt,name=callback_secret,json=callbackSecret,proto3" json:"callback_secret,omitempty"` // signs the callback, default webhooks.callback_secret
+}
+
+func (x *GoToCommand) Reset() {
+	*x = GoToCommand{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_flightsim_v1_flightsim_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GoToCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GoToCommand) ProtoMessage() {}
+
+func (x *GoToCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsim_v1_flightsim_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GoToCommand.ProtoReflect.Descriptor instead.
+func (*GoToCommand) Descriptor() ([]byte, []int) {
+	return file_flightsim_v1_flightsim_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GoToCommand) GetTarget() *Position {
+	if x != nil {
+		return x.Target
+	}
+	return nil
+}
+
+func (x *GoToCommand) GetSpeed() float64 {
+	if x != nil && x.Speed != nil {
+		return *x.Speed
+	}
+	return 0
+}
+
+func (x *GoToCommand) GetAltitudeRef() string {
+	if x != nil {
+		return x.AltitudeRef
+	}
+	return ""
+}
+
+func (x *GoToCommand) GetFix() string {
+	if x != nil {
+		return x.Fix
+	}
+	return ""
+}
+
+func (x *GoToCommand) GetCallbackUrl() string {
+	if x != nil {
+		return x.CallbackUrl
+	}
+	return ""
+}
+
+func (x *GoToCommand) GetCallbackSecret() string {
+	if x != nil {
+		return x.CallbackSecret
+	}
+	return ""
+}
+
+// TrajectoryCommand directs the aircraft to follow a sequence of waypoints.
+type TrajectoryCommand struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Waypoints      []*Waypoint `protobuf:"bytes,1,rep,name=waypoints,proto3" json:"waypoints,omitempty"`
+	Loop           bool        `protobuf:"varint,2,opt,name=loop,proto3" json:"loop,omitempty"`
+	ReturnToHome   bool        `protobuf:"varint,3,opt,name=return_to_home,json=returnToHome,proto3" json:"return_to_home,omitempty"`    // return home after the last waypoint (ignored when looping)
+	CallbackUrl    string      `protobuf:"bytes,4,opt,name=callback_url,json=callbackUrl,proto3" json:"callback_url,omitempty"`          // POSTed the event that ends the command
+	CallbackSecret string      `protobuf:"bytes,5,opt,name=callback_secret,json=callbackSecret,proto3" json:"callback_secret,omitempty"` // signs the callback, default webhooks.callback_secret
+}
+
+func (x *TrajectoryCommand) Reset() {
+	*x = TrajectoryCommand{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_flightsim_v1_flightsim_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TrajectoryCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrajectoryCommand) ProtoMessage() {}
+
+func (x *TrajectoryCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsim_v1_flightsim_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrajectoryCommand.ProtoReflect.Descriptor instead.
+func (*TrajectoryCommand) Descriptor() ([]byte, []int) {
+	return file_flightsim_v1_flightsim_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *TrajectoryCommand) GetWaypoints() []*Waypoint {
+	if x != nil {
+		return x.Waypoints
+	}
+	return nil
+}
+
+func (x *TrajectoryCommand) GetLoop() bool {
+	if x != nil {
+		return x.Loop
+	}
+	return false
+}
+
+func (x *TrajectoryCommand) GetReturnToHome() bool {
+	if x != nil {
+		return x.ReturnToHome
+	}
+	return false
+}
+
+func (x *TrajectoryCommand) GetCallbackUrl() string {
+	if x != nil {
+		return x.CallbackUrl
+	}
+	return ""
+}
+
+func (x *TrajectoryCommand) GetCallbackSecret() string {
+	if x != nil {
+		return x.CallbackSecret
+	}
+	return ""
+}
+
+// Waypoint represents a point in a trajectory.
+type Waypoint struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Position    *Position `protobuf:"bytes,1,opt,name=position,proto3" json:"position,omitempty"`                            // latitude and longitude may be omitted when fix is set
+	Speed       *float64  `protobuf:"fixed64,2,opt,name=speed,proto3,oneof" json:"speed,omitempty"`                          // m/s
+	AltitudeRef string    `protobuf:"bytes,3,opt,name=altitude_ref,json=altitudeRef,proto3" json:"altitude_ref,omitempty"`   // msl (default) or agl
+	Fix         string    `protobuf:"bytes,4,opt,name=fix,proto3" json:"fix,omitempty"`                                      // navdata identifier, instead of latitude/longitude
+	HoldSeconds float64   `protobuf:"fixed64,5,opt,name=hold_seconds,json=holdSeconds,proto3" json:"hold_seconds,omitempty"` // time to hold at the waypoint before continuing
+}
+
+func (x *Waypoint) Reset() {
+	*x = Waypoint{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_flightsim_v1_flightsim_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Waypoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Waypoint) ProtoMessage() {}
+
+func (x *Waypoint) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsim_v1_flightsim_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Waypoint.ProtoReflect.Descriptor instead.
+func (*Waypoint) Descriptor() ([]byte, []int) {
+	return file_flightsim_v1_flightsim_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Waypoint) GetPosition() *Position {
+	if x != nil {
+		return x.Position
+	}
+	return nil
+}
+
+func (x *Waypoint) GetSpeed() float64 {
+	if x != nil && x.Speed != nil {
+		return *x.Speed
+	}
+	return 0
+}
+
+func (x *Waypoint) GetAltitudeRef() string {
+	if x != nil {
+		return x.AltitudeRef
+	}
+	return ""
+}
+
+func (x *Waypoint) GetFix() string {
+	if x != nil {
+		return x.Fix
+	}
+	return ""
+}
+
+func (x *Waypoint) GetHoldSeconds() float64 {
+	if x != nil {
+		return x.HoldSeconds
+	}
+	return 0
+}
+
+// RTHCommand directs the aircraft to climb to a safe altitude and fly home.
+type RTHCommand struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Altitude *float64 `protobuf:"fixed64,1,opt,name=altitude,proto3,oneof" json:"altitude,omitempty"` // meters MSL (default: configured RTH altitude)
+}
+
+func (x *RTHCommand) Reset() {
+	*x = RTHCommand{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_flightsim_v1_flightsim_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RTHCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RTHCommand) ProtoMessage() {}
+
+func (x *RTHCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsim_v1_flightsim_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RTHCommand.ProtoReflect.Descriptor instead.
+func (*RTHCommand) Descriptor() ([]byte, []int) {
+	return file_flightsim_v1_flightsim_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RTHCommand) GetAltitude() float64 {
+	if x != nil && x.Altitude != nil {
+		return *x.Altitude
+	}
+	return 0
+}
+
+// Runway describes the runway used for a takeoff or landing.
+type Runway struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Threshold *Position `protobuf:"bytes,1,opt,name=threshold,proto3" json:"threshold,omitempty"` // altitude is the threshold elevation, meters MSL
+	Heading   float64   `protobuf:"fixed64,2,opt,name=heading,proto3" json:"heading,omitempty"`   // degrees true, direction of takeoff/landing
+}
+
+func (x *Runway) Reset() {
+	*x = Runway{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_flightsim_v1_flightsim_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Runway) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Runway) ProtoMessage() {}
+
+func (x *Runway) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsim_v1_flightsim_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Runway.ProtoReflect.Descriptor instead.
+func (*Runway) Descriptor() ([]byte, []int) {
+	return file_flightsim_v1_flightsim_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Runway) GetThreshold() *Position {
+	if x != nil {
+		return x.Threshold
+	}
+	return nil
+}
+
+func (x *Runway) GetHeading() float64 {
+	if x != nil {
+		return x.Heading
+	}
+	return 0
+}
+
+// TakeoffCommand directs a parked aircraft to take off and climb out.
+type TakeoffCommand struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Runway   *Runway  `protobuf:"bytes,1,opt,name=runway,proto3" json:"runway,omitempty"`       // default: current position and heading
+	Altitude float64  `protobuf:"fixed64,2,opt,name=altitude,proto3" json:"altitude,omitempty"` // climb-out altitude, meters MSL
+	Speed    *float64 `protobuf:"fixed64,3,opt,name=speed,proto3,oneof" json:"speed,omitempty"` // m/s
+}
+
+func (x *TakeoffCommand) Reset() {
+	*x = TakeoffCommand{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_flightsim_v1_flightsim_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TakeoffCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TakeoffCommand) ProtoMessage() {}
+
+func (x *TakeoffCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsim_v1_flightsim_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TakeoffCommand.ProtoReflect.Descriptor instead.
+func (*TakeoffCommand) Descriptor() ([]byte, []int) {
+	return file_flightsim_v1_flightsim_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *TakeoffCommand) GetRunway() *Runway {
+	if x != nil {
+		return x.Runway
+	}
+	return nil
+}
+
+func (x *TakeoffCommand) GetAltitude() float64 {
+	if x != nil {
+		return x.Altitude
+	}
+	return 0
+}
+
+func (x *TakeoffCommand) GetSpeed() float64 {
+	if x != nil && x.Speed != nil {
+		return *x.Speed
+	}
+	return 0
+}
+
+// LandCommand directs the aircraft to land. Without a runway the aircraft
+// descends vertically at its current position.
+type LandCommand struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Runway     *Runway `protobuf:"bytes,1,opt,name=runway,proto3" json:"runway,omitempty"`
+	GlideSlope float64 `protobuf:"fixed64,2,opt,name=glide_slope,json=glideSlope,proto3" json:"glide_slope,omitempty"` // degrees (default: configured glide slope)
+}
+
+func (x *LandCommand) Reset() {
+	*x = LandCommand{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_flightsim_v1_flightsim_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LandCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LandCommand) ProtoMessage() {}
+
+func (x *LandCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsim_v1_flightsim_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LandCommand.ProtoReflect.Descriptor instead.
+func (*LandCommand) Descriptor() ([]byte, []int) {
+	return file_flightsim_v1_flightsim_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *LandCommand) GetRunway() *Runway {
+	if x != nil {
+		return x.Runway
+	}
+	return nil
+}
+
+func (x *LandCommand) GetGlideSlope() float64 {
+	if x != nil {
+		return x.GlideSlope
+	}
+	return 0
+}
+
+// CommandResponse represents the response to a command submission.
+type CommandResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Status            string      `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	CommandId         string      `protobuf:"bytes,2,opt,name=command_id,json=commandId,proto3" json:"command_id,omitempty"`
+	Message           string      `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	Target            *Position   `protobuf:"bytes,4,opt,name=target,proto3" json:"target,omitempty"`
+	WaypointCount     int32       `protobuf:"varint,5,opt,name=waypoint_count,json=waypointCount,proto3" json:"waypoint_count,omitempty"`
+	Waypoints         []*Waypoint `protobuf:"bytes,6,rep,name=waypoints,proto3" json:"waypoints,omitempty"`
+	EtaSeconds        float64     `protobuf:"fixed64,7,opt,name=eta_seconds,json=etaSeconds,proto3" json:"eta_seconds,omitempty"`
+	HoldPosition      *Position   `protobuf:"bytes,8,opt,name=hold_position,json=holdPosition,proto3" json:"hold_position,omitempty"`
+	OrbitRadiusMeters float64     `protobuf:"fixed64,9,opt,name=orbit_radius_meters,json=orbitRadiusMeters,proto3" json:"orbit_radius_meters,omitempty"`
+	Warnings          []string    `protobuf:"bytes,10,rep,name=warnings,proto3" json:"warnings,omitempty"`
+}
+
+func (x *CommandResponse) Reset() {
+	*x = CommandResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_flightsim_v1_flightsim_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CommandResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommandResponse) ProtoMessage() {}
+
+func (x *CommandResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsim_v1_flightsim_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommandResponse.ProtoReflect.Descriptor instead.
+func (*CommandResponse) Descriptor() ([]byte, []int) {
+	return file_flightsim_v1_flightsim_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CommandResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *CommandResponse) GetCommandId() string {
+	if x != nil {
+		return x.CommandId
+	}
+	return ""
+}
+
+func (x *CommandResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *CommandResponse) GetTarget() *Position {
+	if x != nil {
+		return x.Target
+	}
+	return nil
+}
+
+func (x *CommandResponse) GetWaypointCount() int32 {
+	if x != nil {
+		return x.WaypointCount
+	}
+	return 0
+}
+
+func (x *CommandResponse) GetWaypoints() []*Waypoint {
+	if x != nil {
+		return x.Waypoints
+	}
+	return nil
+}
+
+func (x *CommandResponse) GetEtaSeconds() float64 {
+	if x != nil {
+		return x.EtaSeconds
+	}
+	return 0
+}
+
+func (x *CommandResponse) GetHoldPosition() *Position {
+	if x != nil {
+		return x.HoldPosition
+	}
+	return nil
+}
+
+func (x *CommandResponse) GetOrbitRadiusMeters() float64 {
+	if x != nil {
+		return x.OrbitRadiusMeters
+	}
+	return 0
+}
+
+func (x *CommandResponse) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+type GetStateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetStateRequest) Reset() {
+	*x = GetStateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_flightsim_v1_flightsim_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStateRequest) ProtoMessage() {}
+
+func (x *GetStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsim_v1_flightsim_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStateRequest.ProtoReflect.Descriptor instead.
+func (*GetStateRequest) Descriptor() ([]byte, []int) {
+	return file_flightsim_v1_flightsim_proto_rawDescGZIP(), []int{17}
+}
+
+type StreamStateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// What happens to states published while the client's buffer is full:
+	// drop_newest, drop_oldest, coalesce or disconnect. Empty uses the
+	// server's streaming.slow_policy.
+	SlowPolicy string `protobuf:"bytes,1,opt,name=slow_policy,json=slowPolicy,proto3" json:"slow_policy,omitempty"`
+	// disconnect: states dropped before the client is evicted. Unset uses the
+	// server's streaming.max_drops.
+	MaxDrops *int32 `protobuf:"varint,2,opt,name=max_drops,json=maxDrops,proto3,oneof" json:"max_drops,omitempty"`
+	// States buffered for the client, 0 uses the server's
+	// streaming.buffer_size.
+	BufferSize int32 `protobuf:"varint,3,opt,name=buffer_size,json=bufferSize,proto3" json:"buffer_size,omitempty"`
+}
+
+func (x *StreamStateRequest) Reset() {
+	*x = StreamStateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_flightsim_v1_flightsim_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamStateRequest) ProtoMessage() {}
+
+func (x *StreamStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsim_v1_flightsim_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamStateRequest.ProtoReflect.Descriptor instead.
+func (*StreamStateRequest) Descriptor() ([]byte, []int) {
+	return file_flightsim_v1_flightsim_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *StreamStateRequest) GetSlowPolicy() string {
+	if x != nil {
+		return x.SlowPolicy
+	}
+	return ""
+}
+
+func (x *StreamStateRequest) GetMaxDrops() int32 {
+	if x != nil && x.MaxDrops != nil {
+		return *x.MaxDrops
+	}
+	return 0
+}
+
+func (x *StreamStateRequest) GetBufferSize() int32 {
+	if x != nil {
+		return x.BufferSize
+	}
+	return 0
+}
+
+type StreamEventsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Replay the kept events after this event ID before streaming new ones.
+	// 0 streams new events only.
+	Since uint64 `protobuf:"varint,1,opt,name=since,proto3" json:"since,omitempty"`
+}
+
+func (x *StreamEventsRequest) Reset() {
+	*x = StreamEventsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_flightsim_v1_flightsim_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamEventsRequest) ProtoMessage() {}
+
+func (x *StreamEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsim_v1_flightsim_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamEventsRequest.ProtoReflect.Descriptor instead.
+func (*StreamEventsRequest) Descriptor() ([]byte, []int) {
+	return file_flightsim_v1_flightsim_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *StreamEventsRequest) GetSince() uint64 {
+	if x != nil {
+		return x.Since
+	}
+	return 0
+}
+
+// Event is a notable moment of the simulation. Events are numbered in the
+// order they were published, from 1; only the detail fields of the event's
+// type are set.
+type Event struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"` // command_accepted, command_started, ..., terrain_warning
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	SimTime       float64                `protobuf:"fixed64,4,opt,name=sim_time,json=simTime,proto3" json:"sim_time,omitempty"`
+	Position      *Position              `protobuf:"bytes,5,opt,name=position,proto3" json:"position,omitempty"`
+	Message       string                 `protobuf:"bytes,6,opt,name=message,proto3" json:"message,omitempty"`
+	Command       *EventCommand          `protobuf:"bytes,7,opt,name=command,proto3" json:"command,omitempty"`                                         // command events, waypoint_reached, trajectory_looped
+	WaypointIndex *int32                 `protobuf:"varint,8,opt,name=waypoint_index,json=waypointIndex,proto3,oneof" json:"waypoint_index,omitempty"` // waypoint_reached
+	Phase         *PhaseChange           `protobuf:"bytes,9,opt,name=phase,proto3" json:"phase,omitempty"`                                             // phase_changed
+	Conditions    *ConditionsUpdate      `protobuf:"bytes,10,opt,name=conditions,proto3" json:"conditions,omitempty"`                                  // environment_changed
+	Geofence      *GeofenceBreach        `protobuf:"bytes,11,opt,name=geofence,proto3" json:"geofence,omitempty"`                                      // geofence_breach, geofence_cleared
+	Terrain       *TerrainState          `protobuf:"bytes,12,opt,name=terrain,proto3" json:"terrain,omitempty"`                                        // terrain_warning
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_flightsim_v1_flightsim_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsim_v1_flightsim_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_flightsim_v1_flightsim_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *Event) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Event) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Event) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *Event) GetSimTime() float64 {
+	if x != nil {
+		return x.SimTime
+	}
+	return 0
+}
+
+func (x *Event) GetPosition() *Position {
+	if x != nil {
+		return x.Position
+	}
+	return nil
+}
+
+func (x *Event) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Event) GetCommand() *EventCommand {
+	if x != nil {
+		return x.Command
+	}
+	return nil
+}
+
+func (x *Event) GetWaypointIndex() int32 {
+	if x != nil && x.WaypointIndex != nil {
+		return *x.WaypointIndex
+	}
+	return 0
+}
+
+func (x *Event) GetPhase() *PhaseChange {
+	if x != nil {
+		return x.Phase
+	}
+	return nil
+}
+
+func (x *Event) GetConditions() *ConditionsUpdate {
+	if x != nil {
+		return x.Conditions
+	}
+	return nil
+}
+
+func (x *Event) GetGeofence() *GeofenceBreach {
+	if x != nil {
+		return x.Geofence
+	}
+	return nil
+}
+
+func (x *Event) GetTerrain() *TerrainState {
+	if x != nil {
+		return x.Terrain
+	}
+	return nil
+}
+
+// EventCommand identifies the command an event is about.
+type EventCommand struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id           string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type         string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	SupersededBy string `protobuf:"bytes,3,opt,name=superseded_by,json=supersededBy,proto3" json:"superseded_by,omitempty"` // ID of the replacing command
+}
+
+func (x *EventCommand) Reset() {
+	*x = EventCommand{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_flightsim_v1_flightsim_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EventCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventCommand) ProtoMessage() {}
+
+func (x *EventCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsim_v1_flightsim_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventCommand.ProtoReflect.Descriptor instead.
+func (*EventCommand) Descriptor() ([]byte, []int) {
+	return file_flightsim_v1_flightsim_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *EventCommand) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *EventCommand) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *EventCommand) GetSupersededBy() string {
+	if x != nil {
+		return x.SupersededBy
+	}
+	return ""
+}
+
+// PhaseChange is a flight phase transition.
+type PhaseChange struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	From string `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To   string `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+}
+
+func (x *PhaseChange) Reset() {
+	*x = PhaseChange{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_flightsim_v1_flightsim_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PhaseChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PhaseChange) ProtoMessage() {}
+
+func (x *PhaseChange) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsim_v1_flightsim_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PhaseChange.ProtoReflect.Descriptor instead.
+func (*PhaseChange) Descriptor() ([]byte, []int) {
+	return file_flightsim_v1_flightsim_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *PhaseChange) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *PhaseChange) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+// ConditionsUpdate is a change of the simulated conditions.
+type ConditionsUpdate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Wind          *WindVector `protobuf:"bytes,1,opt,name=wind,proto3" json:"wind,omitempty"`
+	EngineFailure *bool       `protobuf:"varint,2,opt,name=engine_failure,json=engineFailure,proto3,oneof" json:"engine_failure,omitempty"`
+}
+
+func (x *ConditionsUpdate) Reset() {
+	*x = ConditionsUpdate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_flightsim_v1_flightsim_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConditionsUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConditionsUpdate) ProtoMessage() {}
+
+func (x *ConditionsUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsim_v1_flightsim_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConditionsUpdate.ProtoReflect.Descriptor instead.
+func (*ConditionsUpdate) Descriptor() ([]byte, []int) {
+	return file_flightsim_v1_flightsim_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ConditionsUpdate) GetWind() *WindVector {
+	if x != nil {
+		return x.Wind
+	}
+	return nil
+}
+
+func (x *ConditionsUpdate) GetEngineFailure() bool {
+	if x != nil && x.EngineFailure != nil {
+		return *x.EngineFailure
+	}
+	return false
+}
+
+var File_flightsim_v1_flightsim_proto protoreflect.FileDescriptor
+
+var file_flightsim_v1_flightsim_proto_rawDesc = []byte{
+	0x0a, 0x1c, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x73, 0x69, 0x6d, 0x2f, 0x76, 0x31, 0x2f, 0x66,
+	0x6c, 0x69, 0x67, 0x68, 0x74, 0x73, 0x69, 0x6d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0c,
+	0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x73, 0x69, 0x6d, 0x2e, 0x76, 0x31, 0x1a, 0x1f, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x60, 0x0a,
+	0x08, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x61, 0x74,
+	0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x6c, 0x61, 0x74,
+	0x69, 0x74, 0x75, 0x64, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x6c, 0x6f, 0x6e, 0x67, 0x69, 0x74, 0x75,
+	0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x6c, 0x6f, 0x6e, 0x67, 0x69, 0x74,
+	0x75, 0x64, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x6c, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x61, 0x6c, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x22,
+	0x54, 0x0a, 0x08, 0x56, 0x65, 0x6c, 0x6f, 0x63, 0x69, 0x74, 0x79, 0x12, 0x21, 0x0a, 0x0c, 0x67,
+	0x72, 0x6f, 0x75, 0x6e, 0x64, 0x5f, 0x73, 0x70, 0x65, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x0b, 0x67, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x53, 0x70, 0x65, 0x65, 0x64, 0x12, 0x25,
+	0x0a, 0x0e, 0x76, 0x65, 0x72, 0x74, 0x69, 0x63, 0x61, 0x6c, 0x5f, 0x73, 0x70, 0x65, 0x65, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0d, 0x76, 0x65, 0x72, 0x74, 0x69, 0x63, 0x61, 0x6c,
+	0x53, 0x70, 0x65, 0x65, 0x64, 0x22, 0xe7, 0x04, 0x0a, 0x0d, 0x41, 0x69, 0x72, 0x63, 0x72, 0x61,
+	0x66, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x32, 0x0a, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x66, 0x6c, 0x69, 0x67,
+	0x68, 0x74, 0x73, 0x69, 0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x32, 0x0a, 0x08, 0x76,
+	0x65, 0x6c, 0x6f, 0x63, 0x69, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e,
+	0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x73, 0x69, 0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x65, 0x6c,
+	0x6f, 0x63, 0x69, 0x74, 0x79, 0x52, 0x08, 0x76, 0x65, 0x6c, 0x6f, 0x63, 0x69, 0x74, 0x79, 0x12,
+	0x18, 0x0a, 0x07, 0x68, 0x65, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x07, 0x68, 0x65, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x38, 0x0a, 0x09, 0x74, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x12, 0x19, 0x0a, 0x08, 0x73, 0x69, 0x6d, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x73, 0x69, 0x6d, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x40,
+	0x0a, 0x0e, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x73,
+	0x69, 0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x49, 0x6e, 0x66,
+	0x6f, 0x52, 0x0d, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
+	0x12, 0x40, 0x0a, 0x0b, 0x65, 0x6e, 0x76, 0x69, 0x72, 0x6f, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x73, 0x69,
+	0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6e, 0x76, 0x69, 0x72, 0x6f, 0x6e, 0x6d, 0x65, 0x6e, 0x74,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x0b, 0x65, 0x6e, 0x76, 0x69, 0x72, 0x6f, 0x6e, 0x6d, 0x65,
+	0x6e, 0x74, 0x12, 0x34, 0x0a, 0x07, 0x74, 0x65, 0x72, 0x72, 0x61, 0x69, 0x6e, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x73, 0x69, 0x6d, 0x2e,
+	0x76, 0x31, 0x2e, 0x54, 0x65, 0x72, 0x72, 0x61, 0x69, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52,
+	0x07, 0x74, 0x65, 0x72, 0x72, 0x61, 0x69, 0x6e, 0x12, 0x49, 0x0a, 0x11, 0x67, 0x65, 0x6f, 0x66,
+	0x65, 0x6e, 0x63, 0x65, 0x5f, 0x62, 0x72, 0x65, 0x61, 0x63, 0x68, 0x65, 0x73, 0x18, 0x09, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x73, 0x69, 0x6d, 0x2e,
+	0x76, 0x31, 0x2e, 0x47, 0x65, 0x6f, 0x66, 0x65, 0x6e, 0x63, 0x65, 0x42, 0x72, 0x65, 0x61, 0x63,
+	0x68, 0x52, 0x10, 0x67, 0x65, 0x6f, 0x66, 0x65, 0x6e, 0x63, 0x65, 0x42, 0x72, 0x65, 0x61, 0x63,
+	0x68, 0x65, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x69, 0x6e, 0x6b, 0x5f, 0x6c, 0x6f, 0x73, 0x74,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x6c, 0x69, 0x6e, 0x6b, 0x4c, 0x6f, 0x73, 0x74,
+	0x12, 0x25, 0x0a, 0x0e, 0x65, 0x6e, 0x67, 0x69, 0x6e, 0x65, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x75,
+	0x72, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x65, 0x6e, 0x67, 0x69, 0x6e, 0x65,
+	0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x68, 0x61, 0x73, 0x65,
+	0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x70, 0x68, 0x61, 0x73, 0x65, 0x12, 0x17, 0x0a,
+	0x04, 0x66, 0x75, 0x65, 0x6c, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x04, 0x66,
+	0x75, 0x65, 0x6c, 0x88, 0x01, 0x01, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x66, 0x75, 0x65, 0x6c, 0x22,
+	0xc1, 0x01, 0x0a, 0x0b, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x49, 0x6e, 0x66, 0x6f, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74,
+	0x79, 0x70, 0x65, 0x12, 0x2e, 0x0a, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x73, 0x69, 0x6d, 0x2e,
+	0x76, 0x31, 0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x74, 0x61, 0x72,
+	0x67, 0x65, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x65, 0x74, 0x61, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e,
+	0x64, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x65, 0x74, 0x61, 0x53, 0x65, 0x63,
+	0x6f, 0x6e, 0x64, 0x73, 0x12, 0x2a, 0x0a, 0x0e, 0x77, 0x61, 0x79, 0x70, 0x6f, 0x69, 0x6e, 0x74,
+	0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x0d,
+	0x77, 0x61, 0x79, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x88, 0x01, 0x01,
+	0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x77, 0x61, 0x79, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x5f, 0x69, 0x6e,
+	0x64, 0x65, 0x78, 0x22, 0xa2, 0x01, 0x0a, 0x10, 0x45, 0x6e, 0x76, 0x69, 0x72, 0x6f, 0x6e, 0x6d,
+	0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x2c, 0x0a, 0x04, 0x77, 0x69, 0x6e, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x73,
+	0x69, 0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x69, 0x6e, 0x64, 0x56, 0x65, 0x63, 0x74, 0x6f, 0x72,
+	0x52, 0x04, 0x77, 0x69, 0x6e, 0x64, 0x12, 0x23, 0x0a, 0x0a, 0x74, 0x75, 0x72, 0x62, 0x75, 0x6c,
+	0x65, 0x6e, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x0a, 0x74, 0x75,
+	0x72, 0x62, 0x75, 0x6c, 0x65, 0x6e, 0x63, 0x65, 0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a, 0x08, 0x68,
+	0x75, 0x6d, 0x69, 0x64, 0x69, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x48, 0x01, 0x52,
+	0x08, 0x68, 0x75, 0x6d, 0x69, 0x64, 0x69, 0x74, 0x79, 0x88, 0x01, 0x01, 0x42, 0x0d, 0x0a, 0x0b,
+	0x5f, 0x74, 0x75, 0x72, 0x62, 0x75, 0x6c, 0x65, 0x6e, 0x63, 0x65, 0x42, 0x0b, 0x0a, 0x09, 0x5f,
+	0x68, 0x75, 0x6d, 0x69, 0x64, 0x69, 0x74, 0x79, 0x22, 0x40, 0x0a, 0x0a, 0x57, 0x69, 0x6e, 0x64,
+	0x56, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x70, 0x65, 0x65, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x05, 0x73, 0x70, 0x65, 0x65, 0x64, 0x22, 0x64, 0x0a, 0x0c, 0x54, 0x65,
+	0x72, 0x72, 0x61, 0x69, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x65, 0x6c,
+	0x65, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x65,
+	0x6c, 0x65, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x68, 0x65, 0x69, 0x67,
+	0x68, 0x74, 0x5f, 0x61, 0x67, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x68, 0x65,
+	0x69, 0x67, 0x68, 0x74, 0x41, 0x67, 0x6c, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x75, 0x6c, 0x6c, 0x5f,
+	0x75, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x70, 0x75, 0x6c, 0x6c, 0x55, 0x70,
+	0x22, 0x51, 0x0a, 0x0e, 0x47, 0x65, 0x6f, 0x66, 0x65, 0x6e, 0x63, 0x65, 0x42, 0x72, 0x65, 0x61,
+	0x63, 0x68, 0x12, 0x17, 0x0a, 0x07, 0x7a, 0x6f, 0x6e, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x7a, 0x6f, 0x6e, 0x65, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x12, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6d,
+	0x6f, 0x64, 0x65, 0x22, 0xb0, 0x02, 0x0a, 0x07, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74,
+	0x79, 0x70, 0x65, 0x12, 0x2d, 0x0a, 0x04, 0x67, 0x6f, 0x74, 0x6f, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x19, 0x2e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x73, 0x69, 0x6d, 0x2e, 0x76, 0x31,
+	0x2e, 0x47, 0x6f, 0x54, 0x6f, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x04, 0x67, 0x6f,
+	0x74, 0x6f, 0x12, 0x3f, 0x0a, 0x0a, 0x74, 0x72, 0x61, 0x6a, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x73,
+	0x69, 0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x6a, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79,
+	0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x0a, 0x74, 0x72, 0x61, 0x6a, 0x65, 0x63, 0x74,
+	0x6f, 0x72, 0x79, 0x12, 0x2a, 0x0a, 0x03, 0x72, 0x74, 0x68, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x18, 0x2e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x73, 0x69, 0x6d, 0x2e, 0x76, 0x31, 0x2e,
+	0x52, 0x54, 0x48, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x03, 0x72, 0x74, 0x68, 0x12,
+	0x36, 0x0a, 0x07, 0x74, 0x61, 0x6b, 0x65, 0x6f, 0x66, 0x66, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1c, 0x2e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x73, 0x69, 0x6d, 0x2e, 0x76, 0x31, 0x2e,
+	0x54, 0x61, 0x6b, 0x65, 0x6f, 0x66, 0x66, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x07,
+	0x74, 0x61, 0x6b, 0x65, 0x6f, 0x66, 0x66, 0x12, 0x2d, 0x0a, 0x04, 0x6c, 0x61, 0x6e, 0x64, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x73, 0x69,
+	0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x61, 0x6e, 0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
+	0x52, 0x04, 0x6c, 0x61, 0x6e, 0x64, 0x22, 0xe3, 0x01, 0x0a, 0x0b, 0x47, 0x6f, 0x54, 0x6f, 0x43,
+	0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x2e, 0x0a, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x73,
+	0x69, 0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06,
+	0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x19, 0x0a, 0x05, 0x73, 0x70, 0x65, 0x65, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x05, 0x73, 0x70, 0x65, 0x65, 0x64, 0x88, 0x01,
+	0x01, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x6c, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x5f, 0x72, 0x65,
+	0x66, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x61, 0x6c, 0x74, 0x69, 0x74, 0x75, 0x64,
+	0x65, 0x52, 0x65, 0x66, 0x12, 0x10, 0x0a, 0x03, 0x66, 0x69, 0x78, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x66, 0x69, 0x78, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x61, 0x6c, 0x6c, 0x62, 0x61,
+	0x63, 0x6b, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x61,
+	0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x55, 0x72, 0x6c, 0x12, 0x27, 0x0a, 0x0f, 0x63, 0x61, 0x6c,
+	0x6c, 0x62, 0x61, 0x63, 0x6b, 0x5f, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0e, 0x63, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x53, 0x65, 0x63, 0x72,
+	0x65, 0x74, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x73, 0x70, 0x65, 0x65, 0x64, 0x22, 0xcf, 0x01, 0x0a,
+	0x11, 0x54, 0x72, 0x61, 0x6a, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x43, 0x6f, 0x6d, 0x6d, 0x61,
+	0x6e, 0x64, 0x12, 0x34, 0x0a, 0x09, 0x77, 0x61, 0x79, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x73, 0x69,
+	0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61, 0x79, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x09, 0x77,
+	0x61, 0x79, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x6f, 0x6f, 0x70,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x6c, 0x6f, 0x6f, 0x70, 0x12, 0x24, 0x0a, 0x0e,
+	0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x5f, 0x74, 0x6f, 0x5f, 0x68, 0x6f, 0x6d, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x54, 0x6f, 0x48, 0x6f,
+	0x6d, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x5f, 0x75,
+	0x72, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x61, 0x6c, 0x6c, 0x62, 0x61,
+	0x63, 0x6b, 0x55, 0x72, 0x6c, 0x12, 0x27, 0x0a, 0x0f, 0x63, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63,
+	0x6b, 0x5f, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e,
+	0x63, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x22, 0xbb,
+	0x01, 0x0a, 0x08, 0x57, 0x61, 0x79, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x32, 0x0a, 0x08, 0x70,
+	0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e,
+	0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x73, 0x69, 0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x73,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x19, 0x0a, 0x05, 0x73, 0x70, 0x65, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x48, 0x00,
+	0x52, 0x05, 0x73, 0x70, 0x65, 0x65, 0x64, 0x88, 0x01, 0x01, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x6c,
+	0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x5f, 0x72, 0x65, 0x66, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0b, 0x61, 0x6c, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x52, 0x65, 0x66, 0x12, 0x10, 0x0a,
+	0x03, 0x66, 0x69, 0x78, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x66, 0x69, 0x78, 0x12,
+	0x21, 0x0a, 0x0c, 0x68, 0x6f, 0x6c, 0x64, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x68, 0x6f, 0x6c, 0x64, 0x53, 0x65, 0x63, 0x6f, 0x6e,
+	0x64, 0x73, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x73, 0x70, 0x65, 0x65, 0x64, 0x22, 0x3a, 0x0a, 0x0a,
+	0x52, 0x54, 0x48, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x1f, 0x0a, 0x08, 0x61, 0x6c,
+	0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x08,
+	0x61, 0x6c, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x88, 0x01, 0x01, 0x42, 0x0b, 0x0a, 0x09, 0x5f,
+	0x61, 0x6c, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x22, 0x58, 0x0a, 0x06, 0x52, 0x75, 0x6e, 0x77,
+	0x61, 0x79, 0x12, 0x34, 0x0a, 0x09, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x73, 0x69,
+	0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x74,
+	0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x68, 0x65, 0x61, 0x64,
+	0x69, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x68, 0x65, 0x61, 0x64, 0x69,
+	0x6e, 0x67, 0x22, 0x7f, 0x0a, 0x0e, 0x54, 0x61, 0x6b, 0x65, 0x6f, 0x66, 0x66, 0x43, 0x6f, 0x6d,
+	0x6d, 0x61, 0x6e, 0x64, 0x12, 0x2c, 0x0a, 0x06, 0x72, 0x75, 0x6e, 0x77, 0x61, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x73, 0x69, 0x6d,
+	0x2e, 0x76, 0x31, 0x2e, 0x52, 0x75, 0x6e, 0x77, 0x61, 0x79, 0x52, 0x06, 0x72, 0x75, 0x6e, 0x77,
+	0x61, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x6c, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x61, 0x6c, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x12, 0x19,
+	0x0a, 0x05, 0x73, 0x70, 0x65, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x48, 0x00, 0x52,
+	0x05, 0x73, 0x70, 0x65, 0x65, 0x64, 0x88, 0x01, 0x01, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x73, 0x70,
+	0x65, 0x65, 0x64, 0x22, 0x5c, 0x0a, 0x0b, 0x4c, 0x61, 0x6e, 0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x61,
+	0x6e, 0x64, 0x12, 0x2c, 0x0a, 0x06, 0x72, 0x75, 0x6e, 0x77, 0x61, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x14, 0x2e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x73, 0x69, 0x6d, 0x2e, 0x76,
+	0x31, 0x2e, 0x52, 0x75, 0x6e, 0x77, 0x61, 0x79, 0x52, 0x06, 0x72, 0x75, 0x6e, 0x77, 0x61, 0x79,
+	0x12, 0x1f, 0x0a, 0x0b, 0x67, 0x6c, 0x69, 0x64, 0x65, 0x5f, 0x73, 0x6c, 0x6f, 0x70, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x67, 0x6c, 0x69, 0x64, 0x65, 0x53, 0x6c, 0x6f, 0x70,
+	0x65, 0x22, 0x99, 0x03, 0x0a, 0x0f, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1d, 0x0a,
+	0x0a, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07,
+	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x2e, 0x0a, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x73,
+	0x69, 0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06,
+	0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x77, 0x61, 0x79, 0x70, 0x6f, 0x69,
+	0x6e, 0x74, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d,
+	0x77, 0x61, 0x79, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x34, 0x0a,
+	0x09, 0x77, 0x61, 0x79, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x16, 0x2e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x73, 0x69, 0x6d, 0x2e, 0x76, 0x31, 0x2e,
+	0x57, 0x61, 0x79, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x09, 0x77, 0x61, 0x79, 0x70, 0x6f, 0x69,
+	0x6e, 0x74, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x65, 0x74, 0x61, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e,
+	0x64, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x65, 0x74, 0x61, 0x53, 0x65, 0x63,
+	0x6f, 0x6e, 0x64, 0x73, 0x12, 0x3b, 0x0a, 0x0d, 0x68, 0x6f, 0x6c, 0x64, 0x5f, 0x70, 0x6f, 0x73,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x66, 0x6c,
+	0x69, 0x67, 0x68, 0x74, 0x73, 0x69, 0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x0c, 0x68, 0x6f, 0x6c, 0x64, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x2e, 0x0a, 0x13, 0x6f, 0x72, 0x62, 0x69, 0x74, 0x5f, 0x72, 0x61, 0x64, 0x69, 0x75,
+	0x73, 0x5f, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x01, 0x52, 0x11,
+	0x6f, 0x72, 0x62, 0x69, 0x74, 0x52, 0x61, 0x64, 0x69, 0x75, 0x73, 0x4d, 0x65, 0x74, 0x65, 0x72,
+	0x73, 0x12, 0x1a, 0x0a, 0x08, 0x77, 0x61, 0x72, 0x6e, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x0a, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x08, 0x77, 0x61, 0x72, 0x6e, 0x69, 0x6e, 0x67, 0x73, 0x22, 0x11, 0x0a,
+	0x0f, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x22, 0x86, 0x01, 0x0a, 0x12, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x6c, 0x6f, 0x77, 0x5f,
+	0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x73, 0x6c,
+	0x6f, 0x77, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x20, 0x0a, 0x09, 0x6d, 0x61, 0x78, 0x5f,
+	0x64, 0x72, 0x6f, 0x70, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x08, 0x6d,
+	0x61, 0x78, 0x44, 0x72, 0x6f, 0x70, 0x73, 0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a, 0x0b, 0x62, 0x75,
+	0x66, 0x66, 0x65, 0x72, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x0a, 0x62, 0x75, 0x66, 0x66, 0x65, 0x72, 0x53, 0x69, 0x7a, 0x65, 0x42, 0x0c, 0x0a, 0x0a, 0x5f,
+	0x6d, 0x61, 0x78, 0x5f, 0x64, 0x72, 0x6f, 0x70, 0x73, 0x22, 0x2b, 0x0a, 0x13, 0x53, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x14, 0x0a, 0x05, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x05, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x22, 0xa4, 0x04, 0x0a, 0x05, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x69, 0x64,
+	0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x74, 0x79, 0x70, 0x65, 0x12, 0x38, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x19,
+	0x0a, 0x08, 0x73, 0x69, 0x6d, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x07, 0x73, 0x69, 0x6d, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x32, 0x0a, 0x08, 0x70, 0x6f, 0x73,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x66, 0x6c,
+	0x69, 0x67, 0x68, 0x74, 0x73, 0x69, 0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a,
+	0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x34, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61,
+	0x6e, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x66, 0x6c, 0x69, 0x67, 0x68,
+	0x74, 0x73, 0x69, 0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x6d,
+	0x6d, 0x61, 0x6e, 0x64, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x2a, 0x0a,
+	0x0e, 0x77, 0x61, 0x79, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x0d, 0x77, 0x61, 0x79, 0x70, 0x6f, 0x69, 0x6e,
+	0x74, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x88, 0x01, 0x01, 0x12, 0x2f, 0x0a, 0x05, 0x70, 0x68, 0x61,
+	0x73, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x66, 0x6c, 0x69, 0x67, 0x68,
+	0x74, 0x73, 0x69, 0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x68, 0x61, 0x73, 0x65, 0x43, 0x68, 0x61,
+	0x6e, 0x67, 0x65, 0x52, 0x05, 0x70, 0x68, 0x61, 0x73, 0x65, 0x12, 0x3e, 0x0a, 0x0a, 0x63, 0x6f,
+	0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e,
+	0x2e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x73, 0x69, 0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f,
+	0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x0a,
+	0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x38, 0x0a, 0x08, 0x67, 0x65,
+	0x6f, 0x66, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x66,
+	0x6c, 0x69, 0x67, 0x68, 0x74, 0x73, 0x69, 0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x6f, 0x66,
+	0x65, 0x6e, 0x63, 0x65, 0x42, 0x72, 0x65, 0x61, 0x63, 0x68, 0x52, 0x08, 0x67, 0x65, 0x6f, 0x66,
+	0x65, 0x6e, 0x63, 0x65, 0x12, 0x34, 0x0a, 0x07, 0x74, 0x65, 0x72, 0x72, 0x61, 0x69, 0x6e, 0x18,
+	0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x73, 0x69,
+	0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x65, 0x72, 0x72, 0x61, 0x69, 0x6e, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x52, 0x07, 0x74, 0x65, 0x72, 0x72, 0x61, 0x69, 0x6e, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x77,
+	0x61, 0x79, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x22, 0x57, 0x0a,
+	0x0c, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x0e, 0x0a,
+	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a,
+	0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70,
+	0x65, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x75, 0x70, 0x65, 0x72, 0x73, 0x65, 0x64, 0x65, 0x64, 0x5f,
+	0x62, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x75, 0x70, 0x65, 0x72, 0x73,
+	0x65, 0x64, 0x65, 0x64, 0x42, 0x79, 0x22, 0x31, 0x0a, 0x0b, 0x50, 0x68, 0x61, 0x73, 0x65, 0x43,
+	0x68, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x6f, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x74, 0x6f, 0x22, 0x7f, 0x0a, 0x10, 0x43, 0x6f, 0x6e,
+	0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x2c, 0x0a,
+	0x04, 0x77, 0x69, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x66, 0x6c,
+	0x69, 0x67, 0x68, 0x74, 0x73, 0x69, 0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x69, 0x6e, 0x64, 0x56,
+	0x65, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x04, 0x77, 0x69, 0x6e, 0x64, 0x12, 0x2a, 0x0a, 0x0e, 0x65,
+	0x6e, 0x67, 0x69, 0x6e, 0x65, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x0d, 0x65, 0x6e, 0x67, 0x69, 0x6e, 0x65, 0x46, 0x61, 0x69,
+	0x6c, 0x75, 0x72, 0x65, 0x88, 0x01, 0x01, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x65, 0x6e, 0x67, 0x69,
+	0x6e, 0x65, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x32, 0xba, 0x02, 0x0a, 0x0f, 0x46,
+	0x6c, 0x69, 0x67, 0x68, 0x74, 0x53, 0x69, 0x6d, 0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x45,
+	0x0a, 0x0d, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12,
+	0x15, 0x2e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x73, 0x69, 0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x43,
+	0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x1a, 0x1d, 0x2e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x73,
+	0x69, 0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x46, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x12, 0x1d, 0x2e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x73, 0x69, 0x6d, 0x2e, 0x76, 0x31,
+	0x2e, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x1b, 0x2e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x73, 0x69, 0x6d, 0x2e, 0x76, 0x31, 0x2e,
+	0x41, 0x69, 0x72, 0x63, 0x72, 0x61, 0x66, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x4e, 0x0a,
+	0x0b, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x20, 0x2e, 0x66,
+	0x6c, 0x69, 0x67, 0x68, 0x74, 0x73, 0x69, 0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b,
+	0x2e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x73, 0x69, 0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x69,
+	0x72, 0x63, 0x72, 0x61, 0x66, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x30, 0x01, 0x12, 0x48, 0x0a,
+	0x0c, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x21, 0x2e,
+	0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x73, 0x69, 0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x13, 0x2e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x73, 0x69, 0x6d, 0x2e, 0x76, 0x31, 0x2e,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x42, 0x47, 0x5a, 0x45, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6d, 0x65, 0x69, 0x72, 0x6f, 0x6e, 0x2d, 0x74, 0x7a, 0x68,
+	0x6f, 0x72, 0x69, 0x2f, 0x46, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x2d, 0x53, 0x69, 0x6d, 0x75, 0x6c,
+	0x61, 0x74, 0x6f, 0x72, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x73,
+	0x69, 0x6d, 0x76, 0x31, 0x3b, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x73, 0x69, 0x6d, 0x76, 0x31,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_flightsim_v1_flightsim_proto_rawDescOnce sync.Once
+	file_flightsim_v1_flightsim_proto_rawDescData = file_flightsim_v1_flightsim_proto_rawDesc
+)
+
+func file_flightsim_v1_flightsim_proto_rawDescGZIP() []byte {
+	file_flightsim_v1_flightsim_proto_rawDescOnce.Do(func() {
+		file_flightsim_v1_flightsim_proto_rawDescData = protoimpl.X.CompressGZIP(file_flightsim_v1_flightsim_proto_rawDescData)
+	})
+	return file_flightsim_v1_flightsim_proto_rawDescData
+}
+
+var file_flightsim_v1_flightsim_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
+var file_flightsim_v1_flightsim_proto_goTypes = []interface{}{
+	(*Position)(nil),              // 0: flightsim.v1.Position
+	(*Velocity)(nil),              // 1: flightsim.v1.Velocity
+	(*AircraftState)(nil),         // 2: flightsim.v1.AircraftState
+	(*CommandInfo)(nil),           // 3: flightsim.v1.CommandInfo
+	(*EnvironmentState)(nil),      // 4: flightsim.v1.EnvironmentState
+	(*WindVector)(nil),            // 5: flightsim.v1.WindVector
+	(*TerrainState)(nil),          // 6: flightsim.v1.TerrainState
+	(*GeofenceBreach)(nil),        // 7: flightsim.v1.GeofenceBreach
+	(*Command)(nil),               // 8: flightsim.v1.Command
+	(*GoToCommand)(nil),           // 9: flightsim.v1.GoToCommand
+	(*TrajectoryCommand)(nil),     // 10: flightsim.v1.TrajectoryCommand
+	(*Waypoint)(nil),              // 11: flightsim.v1.Waypoint
+	(*RTHCommand)(nil),            // 12: flightsim.v1.RTHCommand
+	(*Runway)(nil),                // 13: flightsim.v1.Runway
+	(*TakeoffCommand)(nil),        // 14: flightsim.v1.TakeoffCommand
+	(*LandCommand)(nil),           // 15: flightsim.v1.LandCommand
+	(*CommandResponse)(nil),       // 16: flightsim.v1.CommandResponse
+	(*GetStateRequest)(nil),       // 17: flightsim.v1.GetStateRequest
+	(*StreamStateRequest)(nil),    // 18: flightsim.v1.StreamStateRequest
+	(*StreamEventsRequest)(nil),   // 19: flightsim.v1.StreamEventsRequest
+	(*Event)(nil),                 // 20: flightsim.v1.Event
+	(*EventCommand)(nil),          // 21: flightsim.v1.EventCommand
+	(*PhaseChange)(nil),           // 22: flightsim.v1.PhaseChange
+	(*ConditionsUpdate)(nil),      // 23: flightsim.v1.ConditionsUpdate
+	(*timestamppb.Timestamp)(nil), // 24: google.protobuf.Timestamp
+}
+var file_flightsim_v1_flightsim_proto_depIdxs = []int32{
+	0,  // 0: flightsim.v1.AircraftState.position:type_name -> flightsim.v1.Position
+	1,  // 1: flightsim.v1.AircraftState.velocity:type_name -> flightsim.v1.Velocity
+	24, // 2: flightsim.v1.AircraftState.timestamp:type_name -> google.protobuf.Timestamp
+	3,  // 3: flightsim.v1.AircraftState.active_command:type_name -> flightsim.v1.CommandInfo
+	4,  // 4: flightsim.v1.AircraftState.environment:type_name -> flightsim.v1.EnvironmentState
+	6,  // 5: flightsim.v1.AircraftState.terrain:type_name -> flightsim.v1.TerrainState
+	7,  // 6: flightsim.v1.AircraftState.geofence_breaches:type_name -> flightsim.v1.GeofenceBreach
+	0,  // 7: flightsim.v1.CommandInfo.target:type_name -> flightsim.v1.Position
+	5,  // 8: flightsim.v1.EnvironmentState.wind:type_name -> flightsim.v1.WindVector
+	9,  // 9: flightsim.v1.Command.goto:type_name -> flightsim.v1.GoToCommand
+	10, // 10: flightsim.v1.Command.trajectory:type_name -> flightsim.v1.TrajectoryCommand
+	12, // 11: flightsim.v1.Command.rth:type_name -> flightsim.v1.RTHCommand
+	14, // 12: flightsim.v1.Command.takeoff:type_name -> flightsim.v1.TakeoffCommand
+	15, // 13: flightsim.v1.Command.land:type_name -> flightsim.v1.LandCommand
+	0,  // 14: flightsim.v1.GoToCommand.target:type_name -> flightsim.v1.Position
+	11, // 15: flightsim.v1.TrajectoryCommand.waypoints:type_name -> flightsim.v1.Waypoint
+	0,  // 16: flightsim.v1.Waypoint.position:type_name -> flightsim.v1.Position
+	0,  // 17: flightsim.v1.Runway.threshold:type_name -> flightsim.v1.Position
+	13, // 18: flightsim.v1.TakeoffCommand.runway:type_name -> flightsim.v1.Runway
+	13, // 19: flightsim.v1.LandCommand.runway:type_name -> flightsim.v1.Runway
+	0,  // 20: flightsim.v1.CommandResponse.target:type_name -> flightsim.v1.Position
+	11, // 21: flightsim.v1.CommandResponse.waypoints:type_name -> flightsim.v1.Waypoint
+	0,  // 22: flightsim.v1.CommandResponse.hold_position:type_name -> flightsim.v1.Position
+	24, // 23: flightsim.v1.Event.timestamp:type_name -> google.protobuf.Timestamp
+	0,  // 24: flightsim.v1.Event.position:type_name -> flightsim.v1.Position
+	21, // 25: flightsim.v1.Event.command:type_name -> flightsim.v1.EventCommand
+	22, // 26: flightsim.v1.Event.phase:type_name -> flightsim.v1.PhaseChange
+	23, // 27: flightsim.v1.Event.conditions:type_name -> flightsim.v1.ConditionsUpdate
+	7,  // 28: flightsim.v1.Event.geofence:type_name -> flightsim.v1.GeofenceBreach
+	6,  // 29: flightsim.v1.Event.terrain:type_name -> flightsim.v1.TerrainState
+	5,  // 30: flightsim.v1.ConditionsUpdate.wind:type_name -> flightsim.v1.WindVector
+	8,  // 31: flightsim.v1.FlightSimulator.SubmitCommand:input_type -> flightsim.v1.Command
+	17, // 32: flightsim.v1.FlightSimulator.GetState:input_type -> flightsim.v1.GetStateRequest
+	18, // 33: flightsim.v1.FlightSimulator.StreamState:input_type -> flightsim.v1.StreamStateRequest
+	19, // 34: flightsim.v1.FlightSimulator.StreamEvents:input_type -> flightsim.v1.StreamEventsRequest
+	16, // 35: flightsim.v1.FlightSimulator.SubmitCommand:output_type -> flightsim.v1.CommandResponse
+	2,  // 36: flightsim.v1.FlightSimulator.GetState:output_type -> flightsim.v1.AircraftState
+	2,  // 37: flightsim.v1.FlightSimulator.StreamState:output_type -> flightsim.v1.AircraftState
+	20, // 38: flightsim.v1.FlightSimulator.StreamEvents:output_type -> flightsim.v1.Event
+	35, // [35:39] is the sub-list for method output_type
+	31, // [31:35] is the sub-list for method input_type
+	31, // [31:31] is the sub-list for extension type_name
+	31, // [31:31] is the sub-list for extension extendee
+	0,  // [0:31] is the sub-list for field type_name
+}
+
+func init() { file_flightsim_v1_flightsim_proto_init() }
+func file_flightsim_v1_flightsim_proto_init() {
+	if File_flightsim_v1_flightsim_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_flightsim_v1_flightsim_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Position); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_flightsim_v1_flightsim_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Velocity); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_flightsim_v1_flightsim_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AircraftState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_flightsim_v1_flightsim_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CommandInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_flightsim_v1_flightsim_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EnvironmentState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_flightsim_v1_flightsim_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WindVector); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_flightsim_v1_flightsim_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TerrainState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_flightsim_v1_flightsim_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GeofenceBreach); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_flightsim_v1_flightsim_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Command); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_flightsim_v1_flightsim_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GoToCommand); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_flightsim_v1_flightsim_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TrajectoryCommand); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_flightsim_v1_flightsim_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Waypoint); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_flightsim_v1_flightsim_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RTHCommand); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_flightsim_v1_flightsim_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Runway); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_flightsim_v1_flightsim_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TakeoffCommand); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_flightsim_v1_flightsim_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LandCommand); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_flightsim_v1_flightsim_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CommandResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_flightsim_v1_flightsim_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetStateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_flightsim_v1_flightsim_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StreamStateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_flightsim_v1_flightsim_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StreamEventsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_flightsim_v1_flightsim_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Event); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_flightsim_v1_flightsim_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EventCommand); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_flightsim_v1_flightsim_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PhaseChange); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_flightsim_v1_flightsim_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConditionsUpdate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_flightsim_v1_flightsim_proto_msgTypes[2].OneofWrappers = []interface{}{}
+	file_flightsim_v1_flightsim_proto_msgTypes[3].OneofWrappers = []interface{}{}
+	file_flightsim_v1_flightsim_proto_msgTypes[4].OneofWrappers = []interface{}{}
+	file_flightsim_v1_flightsim_proto_msgTypes[9].OneofWrappers = []interface{}{}
+	file_flightsim_v1_flightsim_proto_msgTypes[11].OneofWrappers = []interface{}{}
+	file_flightsim_v1_flightsim_proto_msgTypes[12].OneofWrappers = []interface{}{}
+	file_flightsim_v1_flightsim_proto_msgTypes[14].OneofWrappers = []interface{}{}
+	file_flightsim_v1_flightsim_proto_msgTypes[18].OneofWrappers = []interface{}{}
+	file_flightsim_v1_flightsim_proto_msgTypes[20].OneofWrappers = []interface{}{}
+	file_flightsim_v1_flightsim_proto_msgTypes[23].OneofWrappers = []interface{}{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_flightsim_v1_flightsim_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   24,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_flightsim_v1_flightsim_proto_goTypes,
+		DependencyIndexes: file_flightsim_v1_flightsim_proto_depIdxs,
+		MessageInfos:      file_flightsim_v1_flightsim_proto_msgTypes,
+	}.Build()
+	File_flightsim_v1_flightsim_proto = out.File
+	file_flightsim_v1_flightsim_proto_rawDesc = nil
+	file_flightsim_v1_flightsim_proto_goTypes = nil
+	file_flightsim_v1_flightsim_proto_depIdxs = nil
+}
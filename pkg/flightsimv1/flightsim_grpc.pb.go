@@ -0,0 +1,310 @@
+// Flight Simulator gRPC API.
+//
+// The messages mirror the JSON models of the REST API (internal/models), with
+// the same field names, units and string values: flight phases, command
+// types, event types and geofence modes are the strings the REST API uses.
+// Regenerate the Go code with `make proto`.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             (unknown)
+// source: flightsim/v1/flightsim.proto
+
+package flightsimv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	FlightSimulator_SubmitCommand_FullMethodName = "/flightsim.v1.FlightSimulator/SubmitCommand"
+	FlightSimulator_GetState_FullMethodName      = "/flightsim.v1.FlightSimulator/GetState"
+	FlightSimulator_StreamState_FullMethodName   = "/flightsim.v1.FlightSimulator/StreamState"
+	FlightSimulator_StreamEvents_FullMethodName  = "/flightsim.v1.FlightSimulator/StreamEvents"
+)
+
+// FlightSimulatorClient is the client API for FlightSimulator service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// FlightSimulator commands the simulated aircraft and streams its state.
+type FlightSimulatorClient interface {
+	// SubmitCommand validates and submits a command with the same checks as
+	// the REST command endpoints. Rejected commands fail with the status of
+	// the REST error and an ErrorInfo detail whose reason is the REST error
+	// code.
+	SubmitCommand(ctx context.Context, in *Command, opts ...grpc.CallOption) (*CommandResponse, error)
+	// GetState returns the current aircraft state.
+	GetState(ctx context.Context, in *GetStateRequest, opts ...grpc.CallOption) (*AircraftState, error)
+	// StreamState streams every published aircraft state, at the simulation
+	// tick rate. States published while the client is lagging are handled by
+	// its slow subscriber policy; an evicted client's stream ends with
+	// RESOURCE_EXHAUSTED.
+	StreamState(ctx context.Context, in *StreamStateRequest, opts ...grpc.CallOption) (FlightSimulator_StreamStateClient, error)
+	// StreamEvents streams simulation events as they are published.
+	StreamEvents(ctx context.Context, in *StreamEventsRequest, opts ...grpc.CallOption) (FlightSimulator_StreamEventsClient, error)
+}
+
+type flightSimulatorClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFlightSimulatorClient(cc grpc.ClientConnInterface) FlightSimulatorClient {
+	return &flightSimulatorClient{cc}
+}
+
+func (c *flightSimulatorClient) SubmitCommand(ctx context.Context, in *Command, opts ...grpc.CallOption) (*CommandResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CommandResponse)
+	err := c.cc.Invoke(ctx, FlightSimulator_SubmitCommand_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *flightSimulatorClient) GetState(ctx context.Context, in *GetStateRequest, opts ...grpc.CallOption) (*AircraftState, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AircraftState)
+	err := c.cc.Invoke(ctx, FlightSimulator_GetState_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *flightSimulatorClient) StreamState(ctx context.Context, in *StreamStateRequest, opts ...grpc.CallOption) (FlightSimulator_StreamStateClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FlightSimulator_ServiceDesc.Streams[0], FlightSimulator_StreamState_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &flightSimulatorStreamStateClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FlightSimulator_StreamStateClient interface {
+	Recv() (*AircraftState, error)
+	grpc.ClientStream
+}
+
+type flightSimulatorStreamStateClient struct {
+	grpc.ClientStream
+}
+
+func (x *flightSimulatorStreamStateClient) Recv() (*AircraftState, error) {
+	m := new(AircraftState)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *flightSimulatorClient) StreamEvents(ctx context.Context, in *StreamEventsRequest, opts ...grpc.CallOption) (FlightSimulator_StreamEventsClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FlightSimulator_ServiceDesc.Streams[1], FlightSimulator_StreamEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &flightSimulatorStreamEventsClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FlightSimulator_StreamEventsClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type flightSimulatorStreamEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *flightSimulatorStreamEventsClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// FlightSimulatorServer is the server API for FlightSimulator service.
+// All implementations must embed UnimplementedFlightSimulatorServer
+// for forward compatibility
+//
+// FlightSimulator commands the simulated aircraft and streams its state.
+type FlightSimulatorServer interface {
+	// SubmitCommand validates and submits a command with the same checks as
+	// the REST command endpoints. Rejected commands fail with the status of
+	// the REST error and an ErrorInfo detail whose reason is the REST error
+	// code.
+	SubmitCommand(context.Context, *Command) (*CommandResponse, error)
+	// GetState returns the current aircraft state.
+	GetState(context.Context, *GetStateRequest) (*AircraftState, error)
+	// StreamState streams every published aircraft state, at the simulation
+	// tick rate. States published while the client is lagging are handled by
+	// its slow subscriber policy; an evicted client's stream ends with
+	// RESOURCE_EXHAUSTED.
+	StreamState(*StreamStateRequest, FlightSimulator_StreamStateServer) error
+	// StreamEvents streams simulation events as they are published.
+	StreamEvents(*StreamEventsRequest, FlightSimulator_StreamEventsServer) error
+	mustEmbedUnimplementedFlightSimulatorServer()
+}
+
+// UnimplementedFlightSimulatorServer must be embedded to have forward compatible implementations.
+type UnimplementedFlightSimulatorServer struct {
+}
+
+func (UnimplementedFlightSimulatorServer) SubmitCommand(context.Context, *Command) (*CommandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitCommand not implemented")
+}
+func (UnimplementedFlightSimulatorServer) GetState(context.Context, *GetStateRequest) (*AircraftState, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetState not implemented")
+}
+func (UnimplementedFlightSimulatorServer) StreamState(*StreamStateRequest, FlightSimulator_StreamStateServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamState not implemented")
+}
+func (UnimplementedFlightSimulatorServer) StreamEvents(*StreamEventsRequest, FlightSimulator_StreamEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamEvents not implemented")
+}
+func (UnimplementedFlightSimulatorServer) mustEmbedUnimplementedFlightSimulatorServer() {}
+
+// UnsafeFlightSimulatorServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FlightSimulatorServer will
+// result in compilation errors.
+type UnsafeFlightSimulatorServer interface {
+	mustEmbedUnimplementedFlightSimulatorServer()
+}
+
+func RegisterFlightSimulatorServer(s grpc.ServiceRegistrar, srv FlightSimulatorServer) {
+	s.RegisterService(&FlightSimulator_ServiceDesc, srv)
+}
+
+func _FlightSimulator_SubmitCommand_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Command)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlightSimulatorServer).SubmitCommand(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FlightSimulator_SubmitCommand_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlightSimulatorServer).SubmitCommand(ctx, req.(*Command))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FlightSimulator_GetState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlightSimulatorServer).GetState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FlightSimulator_GetState_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlightSimulatorServer).GetState(ctx, req.(*GetStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FlightSimulator_StreamState_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamStateRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FlightSimulatorServer).StreamState(m, &flightSimulatorStreamStateServer{ServerStream: stream})
+}
+
+type FlightSimulator_StreamStateServer interface {
+	Send(*AircraftState) error
+	grpc.ServerStream
+}
+
+type flightSimulatorStreamStateServer struct {
+	grpc.ServerStream
+}
+
+func (x *flightSimulatorStreamStateServer) Send(m *AircraftState) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _FlightSimulator_StreamEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FlightSimulatorServer).StreamEvents(m, &flightSimulatorStreamEventsServer{ServerStream: stream})
+}
+
+type FlightSimulator_StreamEventsServer interface {
+	Send(*Event) error
+	grpc.ServerStream
+}
+
+type flightSimulatorStreamEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *flightSimulatorStreamEventsServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// FlightSimulator_ServiceDesc is the grpc.ServiceDesc for FlightSimulator service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FlightSimulator_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "flightsim.v1.FlightSimulator",
+	HandlerType: (*FlightSimulatorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SubmitCommand",
+			Handler:    _FlightSimulator_SubmitCommand_Handler,
+		},
+		{
+			MethodName: "GetState",
+			Handler:    _FlightSimulator_GetState_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamState",
+			Handler:       _FlightSimulator_StreamState_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamEvents",
+			Handler:       _FlightSimulator_StreamEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "flightsim/v1/flightsim.proto",
+}
@@ -0,0 +1,27 @@
+package geo
+
+import "math"
+
+// Destination calculates the point reached by travelling the given distance
+// (meters) from a start point along an initial bearing (degrees).
+// Returns latitude and longitude in degrees.
+func Destination(lat, lon, bearing, distance float64) (float64, float64) {
+	// Convert to radians
+	latRad := toRadians(lat)
+	lonRad := toRadians(lon)
+	bearingRad := toRadians(bearing)
+	angularDist := distance / earthRadiusMeters
+
+	// Spherical destination formula
+	lat2 := math.Asin(math.Sin(latRad)*math.Cos(angularDist) +
+		math.Cos(latRad)*math.Sin(angularDist)*math.Cos(bearingRad))
+	lon2 := lonRad + math.Atan2(
+		math.Sin(bearingRad)*math.Sin(angularDist)*math.Cos(latRad),
+		math.Cos(angularDist)-math.Sin(latRad)*math.Sin(lat2),
+	)
+
+	// Normalize longitude to -180..180
+	lon2Deg := math.Mod(toDegrees(lon2)+540, 360) - 180
+
+	return toDegrees(lat2), lon2Deg
+}
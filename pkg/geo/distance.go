@@ -2,11 +2,12 @@ package geo
 
 import "math"
 
+// earthRadiusMeters is the mean Earth radius used by the spherical formulas.
+const earthRadiusMeters = 6371000.0
+
 // Haversine calculates the great-circle distance between two points on Earth.
 // Returns distance in meters.
 func Haversine(lat1, lon1, lat2, lon2 float64) float64 {
-	const earthRadiusMeters = 6371000.0
-
 	// Convert to radians
 	lat1Rad := toRadians(lat1)
 	lat2Rad := toRadians(lat2)
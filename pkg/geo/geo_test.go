@@ -190,6 +190,163 @@ func TestBearingReversibility(t *testing.T) {
 	}
 }
 
+func TestDestination(t *testing.T) {
+	tests := []struct {
+		name     string
+		lat      float64
+		lon      float64
+		bearing  float64
+		distance float64 // meters
+	}{
+		{name: "North 10km", lat: 32.0, lon: 34.0, bearing: 0, distance: 10000},
+		{name: "East 50km", lat: 32.0, lon: 34.0, bearing: 90, distance: 50000},
+		{name: "Southwest 1km", lat: 32.0853, lon: 34.7818, bearing: 225, distance: 1000},
+		{name: "Across date line", lat: 0, lon: 179.9, bearing: 90, distance: 50000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lat2, lon2 := Destination(tt.lat, tt.lon, tt.bearing, tt.distance)
+
+			// Distance back to the start should match the requested distance
+			dist := Haversine(tt.lat, tt.lon, lat2, lon2)
+			if math.Abs(dist-tt.distance) > 1 {
+				t.Errorf("Destination() distance = %.2f, expected %.2f", dist, tt.distance)
+			}
+
+			// Initial bearing should match the requested bearing
+			bearing := Bearing(tt.lat, tt.lon, lat2, lon2)
+			diff := math.Abs(bearing - tt.bearing)
+			if diff > 180 {
+				diff = 360 - diff
+			}
+			if diff > 0.5 {
+				t.Errorf("Destination() bearing = %.2f°, expected %.2f°", bearing, tt.bearing)
+			}
+
+			if lon2 < -180 || lon2 > 180 {
+				t.Errorf("Destination() longitude %.4f not normalized", lon2)
+			}
+		})
+	}
+}
+
+func TestPointInPolygon(t *testing.T) {
+	square := []Point{
+		{Lat: 32.0, Lon: 34.0},
+		{Lat: 32.0, Lon: 35.0},
+		{Lat: 33.0, Lon: 35.0},
+		{Lat: 33.0, Lon: 34.0},
+	}
+	concave := []Point{
+		{Lat: 0, Lon: 0},
+		{Lat: 0, Lon: 4},
+		{Lat: 4, Lon: 4},
+		{Lat: 4, Lon: 3},
+		{Lat: 1, Lon: 3},
+		{Lat: 1, Lon: 1},
+		{Lat: 4, Lon: 1},
+		{Lat: 4, Lon: 0},
+	}
+
+	tests := []struct {
+		name     string
+		point    Point
+		polygon  []Point
+		expected bool
+	}{
+		{name: "Inside square", point: Point{Lat: 32.5, Lon: 34.5}, polygon: square, expected: true},
+		{name: "Outside square", point: Point{Lat: 31.5, Lon: 34.5}, polygon: square, expected: false},
+		{name: "Inside concave arm", point: Point{Lat: 3, Lon: 0.5}, polygon: concave, expected: true},
+		{name: "Inside concave notch", point: Point{Lat: 3, Lon: 2}, polygon: concave, expected: false},
+		{name: "Degenerate polygon", point: Point{Lat: 0, Lon: 0}, polygon: square[:2], expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := PointInPolygon(tt.point, tt.polygon); result != tt.expected {
+				t.Errorf("PointInPolygon() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPointInCircle(t *testing.T) {
+	center := Point{Lat: 32.0, Lon: 34.0}
+
+	if !PointInCircle(Point{Lat: 32.005, Lon: 34.0}, center, 1000) {
+		t.Error("PointInCircle() = false for point ~555m from center, want true")
+	}
+	if PointInCircle(Point{Lat: 32.02, Lon: 34.0}, center, 1000) {
+		t.Error("PointInCircle() = true for point ~2.2km from center, want false")
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	lat, lon := Interpolate(32.0, 34.0, 33.0, 34.0, 0.5)
+	if math.Abs(lat-32.5) > 0.001 || math.Abs(lon-34.0) > 0.001 {
+		t.Errorf("Interpolate() midpoint = (%.4f, %.4f), want (32.5, 34.0)", lat, lon)
+	}
+
+	lat, lon = Interpolate(32.0, 34.0, 33.0, 34.0, 1.0)
+	if math.Abs(lat-33.0) > 0.001 || math.Abs(lon-34.0) > 0.001 {
+		t.Errorf("Interpolate() end = (%.4f, %.4f), want (33.0, 34.0)", lat, lon)
+	}
+}
+
+func TestSegmentDistance(t *testing.T) {
+	a := Point{Lat: 32.0, Lon: 34.0}
+	b := Point{Lat: 32.0, Lon: 34.1}
+
+	// 0.01 degrees north of the middle of the segment is ~1112m
+	if d := SegmentDistance(Point{Lat: 32.01, Lon: 34.05}, a, b); math.Abs(d-1112) > 5 {
+		t.Errorf("SegmentDistance() to middle = %.1f, want ~1112", d)
+	}
+	// Beyond the end of the segment the distance is to the end point
+	if d, want := SegmentDistance(Point{Lat: 32.0, Lon: 34.2}, a, b), Haversine(32.0, 34.1, 32.0, 34.2); math.Abs(d-want) > 5 {
+		t.Errorf("SegmentDistance() past end = %.1f, want %.1f", d, want)
+	}
+}
+
+func TestSimplify(t *testing.T) {
+	// A straight line with a small wobble and one large detour
+	points := []Point{
+		{Lat: 32.0, Lon: 34.00},
+		{Lat: 32.0001, Lon: 34.01}, // ~11m off the line
+		{Lat: 32.0, Lon: 34.02},
+		{Lat: 32.05, Lon: 34.03}, // ~5.5km off the line
+		{Lat: 32.0, Lon: 34.04},
+		{Lat: 32.0, Lon: 34.05}, // on the line
+		{Lat: 32.0, Lon: 34.06},
+	}
+
+	tests := []struct {
+		name      string
+		tolerance float64
+		maxPoints int
+		want      []int
+	}{
+		{"all shape points", 0, 0, []int{0, 1, 2, 3, 4, 6}},
+		{"drop wobble", 100, 0, []int{0, 2, 3, 4, 6}},
+		{"max points", 0, 3, []int{0, 3, 6}},
+		{"max points below two", 0, 1, []int{0, 6}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Simplify(points, tt.tolerance, tt.maxPoints)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Simplify() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Simplify() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
 func BenchmarkHaversine(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		Haversine(32.0853, 34.7818, 31.7683, 35.2137)
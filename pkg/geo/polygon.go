@@ -0,0 +1,51 @@
+package geo
+
+import "math"
+
+// Point represents a geographic coordinate in degrees.
+type Point struct {
+	Lat float64
+	Lon float64
+}
+
+// PointInPolygon reports whether a point lies inside a polygon using ray casting.
+// The polygon is given as a ring of vertices; closing the ring is optional.
+// Edges are treated as straight lines in latitude/longitude space, which is
+// accurate enough for zones a few tens of kilometers across.
+func PointInPolygon(p Point, polygon []Point) bool {
+	if len(polygon) < 3 {
+		return false
+	}
+
+	inside := false
+	j := len(polygon) - 1
+	for i := 0; i < len(polygon); i++ {
+		vi, vj := polygon[i], polygon[j]
+		// Does the edge straddle the point's latitude, and is the crossing east of it?
+		if (vi.Lat > p.Lat) != (vj.Lat > p.Lat) {
+			crossLon := vi.Lon + (p.Lat-vi.Lat)*(vj.Lon-vi.Lon)/(vj.Lat-vi.Lat)
+			if p.Lon < crossLon {
+				inside = !inside
+			}
+		}
+		j = i
+	}
+
+	return inside
+}
+
+// PointInCircle reports whether a point lies within radius meters of a center.
+func PointInCircle(p, center Point, radius float64) bool {
+	return Haversine(p.Lat, p.Lon, center.Lat, center.Lon) <= radius
+}
+
+// Interpolate returns the point at fraction f (0-1) along the great circle
+// from point 1 to point 2.
+func Interpolate(lat1, lon1, lat2, lon2, f float64) (float64, float64) {
+	distance := Haversine(lat1, lon1, lat2, lon2)
+	if distance == 0 {
+		return lat1, lon1
+	}
+	bearing := Bearing(lat1, lon1, lat2, lon2)
+	return Destination(lat1, lon1, bearing, distance*math.Max(0, math.Min(1, f)))
+}
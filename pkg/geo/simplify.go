@@ -0,0 +1,95 @@
+package geo
+
+import (
+	"math"
+	"sort"
+)
+
+// minSimplifyTolerance absorbs rounding error so collinear points are dropped
+// with a zero tolerance (meters).
+const minSimplifyTolerance = 1e-3
+
+// SegmentDistance returns the distance in meters from p to the segment a-b.
+// The points are projected onto a local flat plane around a, which is
+// accurate for segments up to a few hundred kilometers.
+func SegmentDistance(p, a, b Point) float64 {
+	metersPerDegreeLat := earthRadiusMeters * math.Pi / 180
+	metersPerDegreeLon := metersPerDegreeLat * math.Cos(toRadians(a.Lat))
+
+	px, py := (p.Lon-a.Lon)*metersPerDegreeLon, (p.Lat-a.Lat)*metersPerDegreeLat
+	bx, by := (b.Lon-a.Lon)*metersPerDegreeLon, (b.Lat-a.Lat)*metersPerDegreeLat
+
+	// Project p onto the segment, clamped to its ends
+	t := 0.0
+	if lengthSquared := bx*bx + by*by; lengthSquared > 0 {
+		t = math.Max(0, math.Min(1, (px*bx+py*by)/lengthSquared))
+	}
+	return math.Hypot(px-t*bx, py-t*by)
+}
+
+// Simplify thins a polyline with the Douglas-Peucker algorithm and returns the
+// indices of the points to keep, in order. The first and last points are
+// always kept. Points closer than tolerance meters to the simplified line are
+// dropped; if maxPoints is positive, only the maxPoints most significant
+// points are kept. A tolerance of zero keeps every point that changes the
+// shape of the line.
+func Simplify(points []Point, tolerance float64, maxPoints int) []int {
+	n := len(points)
+	if n <= 2 {
+		indices := make([]int, n)
+		for i := range indices {
+			indices[i] = i
+		}
+		return indices
+	}
+
+	// significance[i] is the largest tolerance at which point i is kept.
+	// A point is never more significant than the point that split its
+	// segment, so keeping the most significant points matches running
+	// Douglas-Peucker with a larger tolerance.
+	significance := make([]float64, n)
+	significance[0], significance[n-1] = math.Inf(1), math.Inf(1)
+
+	type span struct {
+		first, last int
+		limit       float64
+	}
+	stack := []span{{0, n - 1, math.Inf(1)}}
+	for len(stack) > 0 {
+		s := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if s.last-s.first < 2 {
+			continue
+		}
+
+		split, distance := -1, 0.0
+		for i := s.first + 1; i < s.last; i++ {
+			if d := SegmentDistance(points[i], points[s.first], points[s.last]); split < 0 || d > distance {
+				split, distance = i, d
+			}
+		}
+		significance[split] = math.Min(distance, s.limit)
+		stack = append(stack,
+			span{s.first, split, significance[split]},
+			span{split, s.last, significance[split]},
+		)
+	}
+
+	tolerance = math.Max(tolerance, minSimplifyTolerance)
+	var kept []int
+	for i, sig := range significance {
+		if sig > tolerance {
+			kept = append(kept, i)
+		}
+	}
+
+	if maxPoints > 0 && len(kept) > maxPoints {
+		sort.SliceStable(kept, func(i, j int) bool {
+			return significance[kept[i]] > significance[kept[j]]
+		})
+		kept = kept[:max(maxPoints, 2)]
+		sort.Ints(kept)
+	}
+
+	return kept
+}